@@ -0,0 +1,72 @@
+package admin
+
+import (
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+const DEFAULT_AUDIT_LIMIT = 100;
+
+// Query the audit log for this course.
+// All filters are optional.
+type AuditRequest struct {
+    core.APIRequestCourseUserContext
+    core.RequireViewAuditLog
+
+    // Bounds (inclusive) on the time of the records.
+    After common.Timestamp `json:"after"`
+    Before common.Timestamp `json:"before"`
+
+    Actor string `json:"actor"`
+    // An endpoint (e.g. "user/add") or command (e.g. "cmd/regrade").
+    Action string `json:"action"`
+
+    // The maximum number of (most recent) records to return.
+    // Defaults to DEFAULT_AUDIT_LIMIT.
+    Limit int `json:"limit"`
+}
+
+type AuditResponse struct {
+    Records []*model.AuditRecord `json:"records"`
+}
+
+func HandleAudit(request *AuditRequest) (*AuditResponse, *core.APIError) {
+    query := model.AuditQuery{
+        CourseID: request.Course.GetID(),
+        Actor: request.Actor,
+        Action: request.Action,
+        Limit: request.Limit,
+    };
+
+    if (query.Limit <= 0) {
+        query.Limit = DEFAULT_AUDIT_LIMIT;
+    }
+
+    var err error;
+
+    if (!request.After.IsZero()) {
+        query.After, err = request.After.Time();
+        if (err != nil) {
+            return nil, core.NewBadCourseRequestError("-209", &request.APIRequestCourseUserContext,
+                    "Invalid 'after' timestamp.").Err(err).Add("after", request.After);
+        }
+    }
+
+    if (!request.Before.IsZero()) {
+        query.Before, err = request.Before.Time();
+        if (err != nil) {
+            return nil, core.NewBadCourseRequestError("-210", &request.APIRequestCourseUserContext,
+                    "Invalid 'before' timestamp.").Err(err).Add("before", request.Before);
+        }
+    }
+
+    records, err := db.GetAuditRecords(query);
+    if (err != nil) {
+        return nil, core.NewInternalError("-211", &request.APIRequestCourseUserContext,
+                "Failed to get audit records.").Err(err);
+    }
+
+    return &AuditResponse{records}, nil;
+}
@@ -0,0 +1,169 @@
+package admin
+
+import (
+    "slices"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestAudit(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    // Privileged requests are recorded, student requests are not.
+    fields := map[string]any{
+        "keys": []string{},
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/auth/throttle/clear`), fields, nil, model.RoleAdmin);
+    if (!response.Success) {
+        test.Fatalf("Clear is not a success: '%v'.", response);
+    }
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/auth/throttle/list`), nil, nil, model.RoleOwner);
+    if (!response.Success) {
+        test.Fatalf("List is not a success: '%v'.", response);
+    }
+
+    // Graders cannot read the audit log.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/audit`), nil, nil, model.RoleGrader);
+    if (response.Success || (response.Locator != "-020")) {
+        test.Fatalf("Grader was not denied: '%v'.", response);
+    }
+
+    testCases := []struct{fields map[string]any; expected []string}{
+        {nil, []string{"admin/auth/throttle/clear", "admin/auth/throttle/list"}},
+        {map[string]any{"actor": "admin@test.com"}, []string{"admin/auth/throttle/clear"}},
+        {map[string]any{"action": "admin/auth/throttle/list"}, []string{"admin/auth/throttle/list"}},
+        {map[string]any{"actor": "owner@test.com", "limit": 1}, []string{"admin/auth/throttle/list"}},
+        {map[string]any{"after": "2100-01-01T00:00:00Z"}, []string{}},
+        {map[string]any{"before": "2000-01-01T00:00:00Z"}, []string{}},
+    };
+
+    for i, testCase := range testCases {
+        // Each query is also recorded, so only look at the throttle actions.
+        response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/audit`), testCase.fields, nil, model.RoleAdmin);
+        if (!response.Success) {
+            test.Fatalf("Case %d: Response is not a success: '%v'.", i, response);
+        }
+
+        var responseContent AuditResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        actions := make([]string, 0, len(responseContent.Records));
+        for _, record := range responseContent.Records {
+            if (record.Action == "admin/audit") {
+                continue;
+            }
+
+            actions = append(actions, record.Action);
+
+            if ((record.Source != model.AUDIT_SOURCE_API) || (record.CourseID != "course101") || !record.Success) {
+                test.Fatalf("Case %d: Unexpected record: '%+v'.", i, record);
+            }
+
+            if (record.Parameters["user-pass"] != model.AUDIT_REDACTED_VALUE) {
+                test.Fatalf("Case %d: Password was not redacted: '%+v'.", i, record.Parameters);
+            }
+        }
+
+        if (!slices.Equal(testCase.expected, actions)) {
+            test.Fatalf("Case %d: Unexpected actions. Expected: '%v', Actual: '%v'.", i, testCase.expected, actions);
+        }
+    }
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/audit`), map[string]any{"after": "ZZZ"}, nil, model.RoleAdmin);
+    if (response.Success || (response.Locator != "-209")) {
+        test.Fatalf("Bad timestamp was not rejected: '%v'.", response);
+    }
+}
+
+func TestAuditDenied(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    defer core.ResetAuthThrottles();
+
+    // Denied authorization.
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/audit`), nil, nil, model.RoleGrader);
+    if (response.Success || (response.Locator != "-020")) {
+        test.Fatalf("Grader was not denied: '%v'.", response);
+    }
+
+    // Failed authentication.
+    fields := map[string]any{
+        "keys": []string{},
+        "user-pass": util.Sha256HexFromString("ZZZ"),
+    };
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/auth/throttle/clear`), fields, nil, model.RoleAdmin);
+    if (response.Success) {
+        test.Fatalf("Bad password was not denied: '%v'.", response);
+    }
+
+    testCases := []struct{actor string; action string; locator string}{
+        {"grader@test.com", "admin/audit", "-020"},
+        {"admin@test.com", "admin/auth/throttle/clear", ""},
+    };
+
+    for i, testCase := range testCases {
+        records, err := db.GetAuditRecords(model.AuditQuery{Actor: testCase.actor});
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to get audit records: '%v'.", i, err);
+        }
+
+        if (len(records) != 1) {
+            test.Fatalf("Case %d: Unexpected number of records. Expected: 1, Actual: %d.", i, len(records));
+        }
+
+        record := records[0];
+        if ((record.Action != testCase.action) || record.Success) {
+            test.Fatalf("Case %d: Unexpected record: '%+v'.", i, record);
+        }
+
+        if ((testCase.locator != "") && (record.Locator != testCase.locator)) {
+            test.Fatalf("Case %d: Unexpected locator. Expected: '%s', Actual: '%s'.", i, testCase.locator, record.Locator);
+        }
+
+        if (record.Parameters["user-pass"] != model.AUDIT_REDACTED_VALUE) {
+            test.Fatalf("Case %d: Password was not redacted: '%+v'.", i, record.Parameters);
+        }
+    }
+}
+
+// Records use the validated course ID, so they can be found no matter how the request spelled it.
+func TestAuditCourseIDCase(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    fields := map[string]any{
+        "course-id": "COURSE101",
+        "keys": []string{},
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/auth/throttle/clear`), fields, nil, model.RoleAdmin);
+    if (!response.Success) {
+        test.Fatalf("Clear is not a success: '%v'.", response);
+    }
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/audit`), map[string]any{"action": "admin/auth/throttle/clear"}, nil, model.RoleAdmin);
+    if (!response.Success) {
+        test.Fatalf("Audit is not a success: '%v'.", response);
+    }
+
+    var responseContent AuditResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+    if (len(responseContent.Records) != 1) {
+        test.Fatalf("Unexpected number of records. Expected: 1, Actual: %d.", len(responseContent.Records));
+    }
+
+    record := responseContent.Records[0];
+    if ((record.CourseID != "course101") || (record.Actor != "admin@test.com")) {
+        test.Fatalf("Unexpected record: '%+v'.", record);
+    }
+}
@@ -0,0 +1,93 @@
+package admin
+
+import (
+    "slices"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+// Throttles are server-wide, but a course admin can only see (and clear) the throttles for users in their course.
+// IP throttles are not tied to any course, so they are only visible to server admins (model.CapabilityManageIPAuthThrottles).
+
+type AuthThrottleListRequest struct {
+    core.APIRequestCourseUserContext
+    core.RequireManageAuthThrottles
+}
+
+type AuthThrottleListResponse struct {
+    Records []*core.AuthThrottleRecord `json:"records"`
+}
+
+type AuthThrottleClearRequest struct {
+    core.APIRequestCourseUserContext
+    core.RequireManageAuthThrottles
+
+    // Keys of the records to clear (see AuthThrottleRecord.Key).
+    // All visible records will be cleared if empty.
+    Keys []string `json:"keys"`
+}
+
+type AuthThrottleClearResponse struct {
+    Cleared []string `json:"cleared"`
+}
+
+func HandleAuthThrottleList(request *AuthThrottleListRequest) (*AuthThrottleListResponse, *core.APIError) {
+    records, apiErr := getVisibleAuthThrottles(&request.APIRequestCourseUserContext);
+    if (apiErr != nil) {
+        return nil, apiErr;
+    }
+
+    return &AuthThrottleListResponse{records}, nil;
+}
+
+func HandleAuthThrottleClear(request *AuthThrottleClearRequest) (*AuthThrottleClearResponse, *core.APIError) {
+    records, apiErr := getVisibleAuthThrottles(&request.APIRequestCourseUserContext);
+    if (apiErr != nil) {
+        return nil, apiErr;
+    }
+
+    visibleKeys := make(map[string]bool, len(records));
+    for _, record := range records {
+        visibleKeys[record.Key] = true;
+    }
+
+    keys := make([]string, 0);
+    if (len(request.Keys) == 0) {
+        for key, _ := range visibleKeys {
+            keys = append(keys, key);
+        }
+
+        slices.Sort(keys);
+    } else {
+        for _, key := range request.Keys {
+            if (!visibleKeys[key]) {
+                return nil, core.NewBadCourseRequestError("-208", &request.APIRequestCourseUserContext,
+                        "Unknown throttle record.").Add("key", key);
+            }
+
+            keys = append(keys, key);
+        }
+    }
+
+    return &AuthThrottleClearResponse{core.ClearAuthThrottles(keys)}, nil;
+}
+
+func getVisibleAuthThrottles(request *core.APIRequestCourseUserContext) ([]*core.AuthThrottleRecord, *core.APIError) {
+    users, err := db.GetUsers(request.Course);
+    if (err != nil) {
+        return nil, core.NewInternalError("-207", request, "Failed to get users.").Err(err);
+    }
+
+    records := make([]*core.AuthThrottleRecord, 0);
+    for _, record := range core.GetAuthThrottles() {
+        if ((record.Kind == core.AUTH_THROTTLE_KIND_USER) && (users[record.Value] != nil)) {
+            records = append(records, record);
+        } else if ((record.Kind == core.AUTH_THROTTLE_KIND_IP) && request.HasServerCapability(model.CapabilityManageIPAuthThrottles)) {
+            records = append(records, record);
+        }
+    }
+
+    return records, nil;
+}
@@ -0,0 +1,127 @@
+package admin
+
+import (
+    "slices"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestAuthThrottle(test *testing.T) {
+    defer db.ResetForTesting();
+    defer core.ResetAuthThrottles();
+    defer config.AUTH_THROTTLE_USER_FAILURES.Set(0);
+    defer config.AUTH_THROTTLE_IP_FAILURES.Set(0);
+
+    core.ResetAuthThrottles();
+
+    // Count failures, but never lock anyone out.
+    config.AUTH_THROTTLE_USER_FAILURES.Set(100);
+    config.AUTH_THROTTLE_IP_FAILURES.Set(100);
+
+    for _, email := range []string{"student@test.com", "ZZZ@test.com"} {
+        fields := map[string]any{
+            "user-email": email,
+            "user-pass": "Z",
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/auth/throttle/list`), fields, nil, model.RoleAdmin);
+        if (response.Success) {
+            test.Fatalf("Request with a bad password succeeded: '%v'.", response);
+        }
+    }
+
+    // The owner is a server admin, but only when using their server password.
+    serverUser := model.NewServerUser("owner@test.com", "");
+    err := serverUser.SetPassword(util.Sha256HexFromString("server-owner"));
+    if (err != nil) {
+        test.Fatalf("Failed to set server password: '%v'.", err);
+    }
+
+    err = db.SaveServerUser(serverUser);
+    if (err != nil) {
+        test.Fatalf("Failed to save server user: '%v'.", err);
+    }
+
+    defer config.SERVER_ADMINS.Set("");
+    config.SERVER_ADMINS.Set("owner@test.com");
+
+    serverAdminFields := map[string]any{
+        "user-pass": util.Sha256HexFromString("server-owner"),
+    };
+
+    testCases := []struct{role model.UserRole; fields map[string]any; expected []string}{
+        // Unknown users only count against the IP, IPs are only visible to server admins.
+        {model.RoleAdmin, nil, []string{"user:student@test.com"}},
+        {model.RoleOwner, nil, []string{"user:student@test.com"}},
+        {model.RoleOwner, serverAdminFields, []string{"ip:127.0.0.1", "user:student@test.com"}},
+    };
+
+    for i, testCase := range testCases {
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/auth/throttle/list`), testCase.fields, nil, testCase.role);
+        if (!response.Success) {
+            test.Fatalf("Case %d: Response is not a success: '%v'.", i, response);
+        }
+
+        var responseContent AuthThrottleListResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        keys := make([]string, 0, len(responseContent.Records));
+        for _, record := range responseContent.Records {
+            keys = append(keys, record.Key);
+        }
+
+        if (!slices.Equal(testCase.expected, keys)) {
+            test.Fatalf("Case %d: Unexpected records. Expected: '%v', Actual: '%v'.", i, testCase.expected, keys);
+        }
+    }
+
+    // Graders cannot see throttles.
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/auth/throttle/list`), nil, nil, model.RoleGrader);
+    if (response.Success || (response.Locator != "-020")) {
+        test.Fatalf("Grader was not denied: '%v'.", response);
+    }
+
+    // Course staff cannot clear IP records.
+    fields := map[string]any{
+        "keys": []string{"ip:127.0.0.1"},
+    };
+
+    for _, role := range []model.UserRole{model.RoleAdmin, model.RoleOwner} {
+        response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/auth/throttle/clear`), fields, nil, role);
+        if (response.Success || (response.Locator != "-208")) {
+            test.Fatalf("Role '%s' cleared an IP record: '%v'.", model.GetRoleString(role), response);
+        }
+    }
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/auth/throttle/clear`), nil, nil, model.RoleAdmin);
+    if (!response.Success) {
+        test.Fatalf("Clear is not a success: '%v'.", response);
+    }
+
+    var responseContent AuthThrottleClearResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+    if (!slices.Equal([]string{"user:student@test.com"}, responseContent.Cleared)) {
+        test.Fatalf("Unexpected cleared records: '%v'.", responseContent.Cleared);
+    }
+
+    records := core.GetAuthThrottles();
+    if ((len(records) != 1) || (records[0].Key != "ip:127.0.0.1")) {
+        test.Fatalf("Unexpected remaining records: '%s'.", util.MustToJSON(records));
+    }
+
+    fields["user-pass"] = util.Sha256HexFromString("server-owner");
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/auth/throttle/clear`), fields, nil, model.RoleOwner);
+    if (!response.Success) {
+        test.Fatalf("Server admin could not clear an IP record: '%v'.", response);
+    }
+
+    if (len(core.GetAuthThrottles()) != 0) {
+        test.Fatalf("IP record was not cleared: '%s'.", util.MustToJSON(core.GetAuthThrottles()));
+    }
+}
@@ -0,0 +1,19 @@
+package admin
+
+import (
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/grader"
+)
+
+type QueueRequest struct {
+    core.APIRequestCourseUserContext
+    core.RequireViewQueue
+}
+
+type QueueResponse struct {
+    Stats *grader.QueueStats `json:"queue"`
+}
+
+func HandleQueue(request *QueueRequest) (*QueueResponse, *core.APIError) {
+    return &QueueResponse{grader.GetQueueStats(request.Course.GetID())}, nil;
+}
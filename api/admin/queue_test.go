@@ -0,0 +1,40 @@
+package admin
+
+import (
+    "testing"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestQueue(test *testing.T) {
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/queue`), nil, nil, model.RoleAdmin);
+    if (!response.Success) {
+        test.Fatalf("Response is not a success when it should be: '%v'.", response);
+    }
+
+    var responseContent QueueResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+    stats := responseContent.Stats;
+    if (stats == nil) {
+        test.Fatalf("Did not get queue stats.");
+    }
+
+    if (stats.MaxRunning != config.GRADING_WORKERS.Get()) {
+        test.Fatalf("Unexpected max running. Expected: %d, Actual: %d.", config.GRADING_WORKERS.Get(), stats.MaxRunning);
+    }
+
+    // Nothing should be grading.
+    if ((stats.Queued != 0) || (stats.Running != 0) || (len(stats.CourseJobs) != 0)) {
+        test.Fatalf("Unexpected queue stats: '%+v'.", stats);
+    }
+
+    // Graders cannot see the queue.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/queue`), nil, nil, model.RoleGrader);
+    if (response.Success) {
+        test.Fatalf("Response is a success when it should not be: '%v'.", response);
+    }
+}
@@ -0,0 +1,72 @@
+package admin
+
+import (
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/grader"
+)
+
+type RegradeRequest struct {
+    core.APIRequestAssignmentContext
+    core.RequireRegrade
+
+    // Only regrade these users (all users when empty).
+    Users []string `json:"users"`
+    AllSubmissions bool `json:"all-submissions"`
+    DryRun bool `json:"dry-run"`
+    Overwrite bool `json:"overwrite"`
+}
+
+// Regrades run in the background, use admin/regrade/status to get the results.
+type RegradeResponse struct {
+    RegradeID string `json:"regrade-id"`
+    JobIDs []string `json:"job-ids"`
+}
+
+type RegradeStatusRequest struct {
+    core.APIRequestAssignmentContext
+    core.RequireRegrade
+
+    RegradeID core.NonEmptyString `json:"regrade-id"`
+}
+
+type RegradeStatusResponse struct {
+    FoundRegrade bool `json:"found-regrade"`
+    Regrade *grader.RegradeStatus `json:"regrade"`
+}
+
+func HandleRegrade(request *RegradeRequest) (*RegradeResponse, *core.APIError) {
+    if (request.DryRun && request.Overwrite) {
+        return nil, core.NewBadRequestError("-206", &request.APIRequest, "A regrade cannot be both a dry run and an overwrite.");
+    }
+
+    options := grader.RegradeOptions{
+        GradeOptions: grader.GetDefaultGradeOptions(),
+        Users: request.Users,
+        AllSubmissions: request.AllSubmissions,
+        DryRun: request.DryRun,
+        Overwrite: request.Overwrite,
+    };
+
+    status, err := grader.StartRegrade(request.Assignment, options);
+    if (err != nil) {
+        return nil, core.NewInternalError("-205", &request.APIRequestCourseUserContext, "Failed to start regrade.").Err(err);
+    }
+
+    return &RegradeResponse{status.ID, status.JobIDs}, nil;
+}
+
+func HandleRegradeStatus(request *RegradeStatusRequest) (*RegradeStatusResponse, *core.APIError) {
+    response := RegradeStatusResponse{};
+
+    status := grader.GetRegradeStatus(string(request.RegradeID));
+
+    // Regrades from other assignments are treated as missing.
+    if ((status == nil) || (status.CourseID != request.Course.GetID()) || (status.AssignmentID != request.Assignment.GetID())) {
+        return &response, nil;
+    }
+
+    response.FoundRegrade = true;
+    response.Regrade = status;
+
+    return &response, nil;
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+    "testing"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestRegrade(test *testing.T) {
+    testCases := []struct{ role model.UserRole; fields map[string]any; permError bool; success bool }{
+        // No submissions to regrade.
+        {model.RoleAdmin, map[string]any{"users": []string{"ZZZ"}, "dry-run": true}, false, true},
+        {model.RoleOwner, map[string]any{"users": []string{"ZZZ"}}, false, true},
+
+        {model.RoleAdmin, map[string]any{"users": []string{"ZZZ"}, "dry-run": true, "overwrite": true}, false, false},
+
+        {model.RoleGrader, map[string]any{"users": []string{"ZZZ"}, "dry-run": true}, true, false},
+        {model.RoleStudent, map[string]any{"users": []string{"ZZZ"}, "dry-run": true}, true, false},
+    };
+
+    for i, testCase := range testCases {
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/regrade`), testCase.fields, nil, testCase.role);
+        if (!response.Success) {
+            if (testCase.permError) {
+                expectedLocator := "-020";
+                if (response.Locator != expectedLocator) {
+                    test.Errorf("Case %d: Incorrect error returned on permissions error. Expcted '%s', found '%s'.",
+                            i, expectedLocator, response.Locator);
+                }
+            } else if (testCase.success) {
+                test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            }
+
+            continue;
+        }
+
+        if (!testCase.success) {
+            test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response);
+            continue;
+        }
+
+        var responseContent RegradeResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if (responseContent.RegradeID == "") {
+            test.Errorf("Case %d: Missing regrade ID.", i);
+        }
+
+        if (len(responseContent.JobIDs) != 0) {
+            test.Errorf("Case %d: Unexpected jobs: '%s'.", i, util.MustToJSONIndent(responseContent.JobIDs));
+        }
+    }
+}
+
+func TestRegradeStatus(test *testing.T) {
+    fields := map[string]any{"users": []string{"ZZZ"}, "dry-run": true};
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/regrade`), fields, nil, model.RoleAdmin);
+    if (!response.Success) {
+        test.Fatalf("Failed to start regrade: '%v'.", response);
+    }
+
+    var regradeContent RegradeResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &regradeContent);
+
+    testCases := []struct{ role model.UserRole; id string; permError bool; found bool }{
+        {model.RoleAdmin, regradeContent.RegradeID, false, true},
+        {model.RoleOwner, regradeContent.RegradeID, false, true},
+        {model.RoleAdmin, "ZZZ", false, false},
+
+        {model.RoleGrader, regradeContent.RegradeID, true, false},
+        {model.RoleStudent, regradeContent.RegradeID, true, false},
+    };
+
+    for i, testCase := range testCases {
+        fields := map[string]any{"regrade-id": testCase.id};
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/regrade/status`), fields, nil, testCase.role);
+        if (!response.Success) {
+            if (testCase.permError) {
+                expectedLocator := "-020";
+                if (response.Locator != expectedLocator) {
+                    test.Errorf("Case %d: Incorrect error returned on permissions error. Expcted '%s', found '%s'.",
+                            i, expectedLocator, response.Locator);
+                }
+            } else {
+                test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            }
+
+            continue;
+        }
+
+        if (testCase.permError) {
+            test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response);
+            continue;
+        }
+
+        var responseContent RegradeStatusResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if (testCase.found != responseContent.FoundRegrade) {
+            test.Errorf("Case %d: Unexpected found. Expected '%v', found '%v'.", i, testCase.found, responseContent.FoundRegrade);
+            continue;
+        }
+
+        if (!testCase.found) {
+            continue;
+        }
+
+        // There are no submissions to regrade, so the regrade is already done.
+        if (!responseContent.Regrade.Done || (len(responseContent.Regrade.Results) != 0)) {
+            test.Errorf("Case %d: Unexpected regrade status: '%s'.", i, util.MustToJSONIndent(responseContent.Regrade));
+        }
+    }
+}
@@ -7,6 +7,12 @@ import (
 )
 
 var routes []*core.Route = []*core.Route{
+    core.NewAPIRoute(core.NewEndpoint(`admin/audit`), HandleAudit),
+    core.NewAPIRoute(core.NewEndpoint(`admin/auth/throttle/clear`), HandleAuthThrottleClear),
+    core.NewAPIRoute(core.NewEndpoint(`admin/auth/throttle/list`), HandleAuthThrottleList),
+    core.NewAPIRoute(core.NewEndpoint(`admin/queue`), HandleQueue),
+    core.NewAPIRoute(core.NewEndpoint(`admin/regrade`), HandleRegrade),
+    core.NewAPIRoute(core.NewEndpoint(`admin/regrade/status`), HandleRegradeStatus),
     core.NewAPIRoute(core.NewEndpoint(`admin/update/course`), HandleUpdateCourse),
 };
 
@@ -0,0 +1,290 @@
+package auth
+
+// Launch the autograder from an LMS as an LTI 1.3 tool.
+// The platform (LMS) starts with a third-party login initiation request to the login endpoint,
+// which redirects the browser back to the platform's authentication endpoint.
+// The platform then posts a signed ID token to the launch endpoint,
+// which validates the token, syncs the launching user into the course, and issues an API token for that user.
+// Platforms verify the autograder's service requests (e.g., grade passback) using the keys at the JWKS endpoint.
+
+import (
+    "fmt"
+    "net/http"
+    "net/url"
+    "strings"
+    "time"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/lms/lmssync"
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/lti"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    LTI_TOKEN_NAME = "lti-launch";
+
+    // A course can be selected by adding this parameter to the target link URI.
+    LTI_COURSE_ID_PARAM = "course-id";
+)
+
+type LTILaunchResponse struct {
+    CourseID string `json:"course-id"`
+    UserEmail string `json:"user-email"`
+    // Can be used as the "user-token" in other API requests.
+    UserToken string `json:"user-token"`
+    TokenID string `json:"token-id"`
+    ExpirationTime common.Timestamp `json:"expiration-time"`
+}
+
+// Login initiation may come in as a GET or POST, so all parameters are read as form values.
+func HandleLTILogin(response http.ResponseWriter, request *http.Request) (any, *core.APIError) {
+    endpoint := request.URL.Path;
+
+    // The launch URL is never built from the request (e.g. the Host header), since the request can be forged.
+    launchURL := config.LTI_LAUNCH_URL.Get();
+    if (launchURL == "") {
+        return nil, core.NewBareBadRequestError("-342", endpoint, "LTI login is not configured (no launch URL).");
+    }
+
+    issuer := request.FormValue("iss");
+    loginHint := request.FormValue("login_hint");
+    targetLinkURI := request.FormValue("target_link_uri");
+
+    if ((issuer == "") || (loginHint == "") || (targetLinkURI == "")) {
+        return nil, core.NewBareBadRequestError("-322", endpoint, "LTI login initiation is missing required parameters.").
+                Add("iss", issuer).Add("login_hint", loginHint).Add("target_link_uri", targetLinkURI);
+    }
+
+    course, err := findLTICourse(issuer, request.FormValue("client_id"), request.FormValue("lti_deployment_id"), targetLinkURI);
+    if (err != nil) {
+        return nil, core.NewBareBadRequestError("-323", endpoint, "Could not find the course for this LTI platform.").Err(err).
+                Add("iss", issuer).Add("target_link_uri", targetLinkURI);
+    }
+
+    platform := course.GetLMSAdapter().LTI;
+
+    state, err := util.RandHex(OIDC_STATE_LEN);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-324", endpoint, "Failed to generate login state.").Err(err);
+    }
+
+    nonce, err := util.RandHex(OIDC_NONCE_LEN);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-325", endpoint, "Failed to generate login nonce.").Err(err);
+    }
+
+    authURL, err := url.Parse(platform.AuthURL);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-326", endpoint, "Failed to parse the platform's auth URL.").Err(err).
+                Add("auth-url", platform.AuthURL);
+    }
+
+    query := authURL.Query();
+    query.Set("scope", "openid");
+    query.Set("response_type", "id_token");
+    query.Set("response_mode", "form_post");
+    query.Set("prompt", "none");
+    query.Set("client_id", platform.ClientID);
+    query.Set("redirect_uri", launchURL);
+    query.Set("login_hint", loginHint);
+    query.Set("state", state);
+    query.Set("nonce", nonce);
+
+    if (request.FormValue("lti_message_hint") != "") {
+        query.Set("lti_message_hint", request.FormValue("lti_message_hint"));
+    }
+
+    authURL.RawQuery = query.Encode();
+
+    addPendingLogin(state, &pendingLogin{
+        courseID: course.GetID(),
+        nonce: nonce,
+        redirectURL: launchURL,
+        targetLinkURI: targetLinkURI,
+        expiration: time.Now().Add(OIDC_LOGIN_TIMEOUT),
+    });
+
+    http.Redirect(response, request, authURL.String(), http.StatusFound);
+
+    return nil, nil;
+}
+
+// The platform will post the launch (as a form) to this endpoint.
+func HandleLTILaunch(response http.ResponseWriter, request *http.Request) (any, *core.APIError) {
+    endpoint := request.URL.Path;
+
+    // The state is always removed, so a launch can never be replayed.
+    login := popPendingLogin(request.FormValue("state"));
+    if (login == nil) {
+        return nil, core.NewBareBadRequestError("-327", endpoint, "Unknown or expired LTI launch, try launching again.");
+    }
+
+    if (request.FormValue("error") != "") {
+        return nil, core.NewBareBadRequestError("-328", endpoint, "LTI platform returned an error.").
+                Add("error", request.FormValue("error")).Add("error-description", request.FormValue("error_description"));
+    }
+
+    idToken := request.FormValue("id_token");
+    if (idToken == "") {
+        return nil, core.NewBareBadRequestError("-329", endpoint, "LTI platform did not return an ID token.");
+    }
+
+    course, err := db.GetCourse(login.courseID);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-330", endpoint, "Unable to get course.").Err(err).Add("course-id", login.courseID);
+    }
+
+    if ((course == nil) || !course.HasLMSAdapter() || (course.GetLMSAdapter().LTI == nil)) {
+        return nil, core.NewBareBadRequestError("-331", endpoint, "Could not find an LTI course.").Add("course-id", login.courseID);
+    }
+
+    claims, err := lti.VerifyLaunch(course.GetLMSAdapter().LTI, course.GetLMSAdapter().LMSCourseID, idToken, login.nonce);
+    if (err != nil) {
+        return nil, core.NewBareBadRequestError("-332", endpoint, "Failed to verify LTI launch.").Err(err).
+                Add("course-id", login.courseID);
+    }
+
+    // The course was selected using the (unsigned) login request, so make sure the signed launch selects the same course.
+    if (getTargetCourseID(claims.TargetLinkURI) != getTargetCourseID(login.targetLinkURI)) {
+        return nil, core.NewBareBadRequestError("-340", endpoint, "LTI launch target does not match the login target.").
+                Add("course-id", login.courseID).Add("target_link_uri", claims.TargetLinkURI);
+    }
+
+    lmsUser := claims.ToLMSType();
+    if (lmsUser.Email == "") {
+        return nil, core.NewBareBadRequestError("-333", endpoint, "LTI launch does not have an email.").
+                Add("course-id", login.courseID).Add("subject", claims.Subject);
+    }
+
+    // Failing to remember the service endpoints should not stop the user from logging in.
+    err = lti.SaveServiceEndpoints(course, claims);
+    if (err != nil) {
+        log.Warn().Err(err).Str("course-id", course.GetID()).Msg("Failed to save LTI service endpoints.");
+    }
+
+    // The course's LMS sync options decide if the user will be added/updated.
+    _, err = lmssync.SyncProvidedLMSUsers(course, []*lmstypes.User{lmsUser}, false, false);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-334", endpoint, "Failed to sync LTI user.").Err(err).
+                Add("course-id", login.courseID).Add("email", lmsUser.Email);
+    }
+
+    user, err := db.GetUser(course, lmsUser.Email);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-335", endpoint, "Unable to get user.").Err(err).
+                Add("course-id", login.courseID).Add("email", lmsUser.Email);
+    }
+
+    if (user == nil) {
+        return nil, core.NewBareBadRequestError("-336", endpoint, "No user with this email is enrolled in the course.").
+                Add("course-id", login.courseID).Add("email", lmsUser.Email);
+    }
+
+    duration := time.Duration(config.LTI_SESSION_HOURS.Get()) * time.Hour;
+    token, cleartext, err := model.NewAPIToken(user.Email, LTI_TOKEN_NAME, duration);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-337", endpoint, "Failed to create token.").Err(err);
+    }
+
+    err = db.SaveAPIToken(course, token);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-338", endpoint, "Failed to save token.").Err(err).Add("token-id", token.ID);
+    }
+
+    log.Info().Str("course-id", course.GetID()).Str("email", user.Email).Str("token-id", token.ID).Msg("LTI launch.");
+
+    launchResponse := LTILaunchResponse{
+        CourseID: course.GetID(),
+        UserEmail: user.Email,
+        UserToken: cleartext,
+        TokenID: token.ID,
+        ExpirationTime: token.ExpirationTime,
+    };
+
+    return &launchResponse, nil;
+}
+
+// Serve the tool's public keys as a raw JWKS document (not wrapped in an API response).
+func HandleLTIJWKS(response http.ResponseWriter, request *http.Request) (any, *core.APIError) {
+    endpoint := request.URL.Path;
+
+    jwks, err := lti.GetToolJWKS();
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-339", endpoint, "Failed to load the LTI tool key.").Err(err);
+    }
+
+    response.Header().Set("Content-Type", "application/json");
+    _, err = response.Write([]byte(util.MustToJSON(jwks)));
+    if (err != nil) {
+        log.Error().Err(err).Str("endpoint", endpoint).Msg("Failed to write LTI JWKS.");
+    }
+
+    return nil, nil;
+}
+
+// Find the single course that is registered with the given platform/client/deployment.
+// If the target link URI has a course ID, then only that course will be considered.
+func findLTICourse(issuer string, clientID string, deploymentID string, targetLinkURI string) (*model.Course, error) {
+    courseID := getTargetCourseID(targetLinkURI);
+
+    courses, err := db.GetCourses();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get courses: '%w'.", err);
+    }
+
+    matches := make([]*model.Course, 0);
+    for _, course := range courses {
+        if ((courseID != "") && (course.GetID() != courseID)) {
+            continue;
+        }
+
+        adapter := course.GetLMSAdapter();
+        if ((adapter == nil) || (adapter.Type != model.LMS_TYPE_LTI) || (adapter.LTI == nil)) {
+            continue;
+        }
+
+        platform := adapter.LTI;
+
+        if (strings.TrimRight(platform.Issuer, "/") != strings.TrimRight(issuer, "/")) {
+            continue;
+        }
+
+        if ((clientID != "") && (platform.ClientID != clientID)) {
+            continue;
+        }
+
+        if ((deploymentID != "") && (platform.DeploymentID != "") && (platform.DeploymentID != deploymentID)) {
+            continue;
+        }
+
+        matches = append(matches, course);
+    }
+
+    if (len(matches) == 0) {
+        return nil, fmt.Errorf("No course is registered with LTI issuer '%s'.", issuer);
+    }
+
+    if (len(matches) > 1) {
+        return nil, fmt.Errorf("Found %d courses registered with LTI issuer '%s', add a '%s' parameter to the target link URI.",
+                len(matches), issuer, LTI_COURSE_ID_PARAM);
+    }
+
+    return matches[0], nil;
+}
+
+// Get the course ID (if any) selected by a target link URI.
+func getTargetCourseID(targetLinkURI string) string {
+    targetLink, err := url.Parse(targetLinkURI);
+    if (err != nil) {
+        return "";
+    }
+
+    return targetLink.Query().Get(LTI_COURSE_ID_PARAM);
+}
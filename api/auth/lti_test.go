@@ -0,0 +1,290 @@
+package auth
+
+import (
+    "net/url"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/lti"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestLTILaunch(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := startTestPlatform(test);
+    defer platform.Close();
+
+    testCases := []struct{identity string; syncAdds bool; email string; role model.UserRole; locator string}{
+        {"student-id", false, "student@test.com", model.RoleStudent, ""},
+        {"admin-id", false, "admin@test.com", model.RoleAdmin, ""},
+
+        // Adds are controlled by the course's LMS options.
+        {"new-id", false, "new@test.com", model.RoleUnknown, "-336"},
+        {"new-id", true, "new@test.com", model.RoleStudent, ""},
+    };
+
+    for i, testCase := range testCases {
+        db.ResetForTesting();
+        setTestPlatform(test, platform, testCase.syncAdds);
+        platform.SetIdentity(testCase.identity);
+
+        response := launch(test, platform, "https://autograder.test/");
+        if (testCase.locator != "") {
+            if (response.Success) {
+                test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response);
+            } else if (response.Locator != testCase.locator) {
+                test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator);
+            }
+
+            continue;
+        }
+
+        if (!response.Success) {
+            test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            continue;
+        }
+
+        var responseContent LTILaunchResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if ((responseContent.CourseID != "course101") || (responseContent.UserEmail != testCase.email)) {
+            test.Errorf("Case %d: Unexpected launch response: '%+v'.", i, responseContent);
+            continue;
+        }
+
+        user, err := db.GetUser(db.MustGetTestCourse(), testCase.email);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to get user: '%v'.", i, err);
+            continue;
+        }
+
+        if ((user == nil) || (user.Role != testCase.role) || (user.LMSID != testCase.identity)) {
+            test.Errorf("Case %d: Unexpected user after launch: '%+v'.", i, user);
+            continue;
+        }
+
+        token, err := db.GetAPIToken(db.MustGetTestCourse(), testCase.email, responseContent.TokenID);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to get token: '%v'.", i, err);
+            continue;
+        }
+
+        if ((token == nil) || !token.Check(responseContent.UserToken) || token.IsExpired()) {
+            test.Errorf("Case %d: Issued token is not valid: '%+v'.", i, token);
+            continue;
+        }
+    }
+
+    // The service endpoints from the launch are remembered for the LMS backend.
+    endpoints, err := lti.GetServiceEndpoints(db.MustGetTestCourse());
+    if (err != nil) {
+        test.Fatalf("Failed to get service endpoints: '%v'.", err);
+    }
+
+    if ((endpoints.LineItemsURL != platform.LineItemsURL()) || (endpoints.MembershipsURL != platform.MembershipsURL())) {
+        test.Fatalf("Unexpected service endpoints: '%+v'.", endpoints);
+    }
+}
+
+func TestLTILaunchFailures(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := startTestPlatform(test);
+    defer platform.Close();
+
+    // No course is registered with the platform.
+    _, response := core.SendTestBrowserForm(test, core.NewEndpoint(`auth/lti/login`), toValues(platform.LoginParams("https://autograder.test/")));
+    if ((response == nil) || response.Success || (response.Locator != "-323")) {
+        test.Fatalf("Unexpected response for an unknown platform: '%v'.", response);
+    }
+
+    setTestPlatform(test, platform, false);
+
+    // Missing parameters.
+    _, response = core.SendTestBrowserForm(test, core.NewEndpoint(`auth/lti/login`), url.Values{"iss": []string{platform.Issuer()}});
+    if ((response == nil) || response.Success || (response.Locator != "-322")) {
+        test.Fatalf("Unexpected response for missing parameters: '%v'.", response);
+    }
+
+    // The target link selects a course that is not registered with the platform.
+    _, response = core.SendTestBrowserForm(test, core.NewEndpoint(`auth/lti/login`), toValues(platform.LoginParams("https://autograder.test/?course-id=ZZZ")));
+    if ((response == nil) || response.Success || (response.Locator != "-323")) {
+        test.Fatalf("Unexpected response for an unknown course: '%v'.", response);
+    }
+
+    // Unknown state.
+    _, response = core.SendTestBrowserForm(test, core.NewEndpoint(`auth/lti/launch`), url.Values{"state": []string{"ZZZ"}, "id_token": []string{"ZZZ"}});
+    if ((response == nil) || response.Success || (response.Locator != "-327")) {
+        test.Fatalf("Unexpected response for an unknown state: '%v'.", response);
+    }
+
+    // A token signed for a different nonce.
+    location, _ := core.SendTestBrowserForm(test, core.NewEndpoint(`auth/lti/login`), toValues(platform.LoginParams("https://autograder.test/")));
+    launchURL, form, err := platform.Authorize(location);
+    if (err != nil) {
+        test.Fatalf("Failed to authorize: '%v'.", err);
+    }
+
+    claims, err := platform.LaunchClaims("ZZZ", "https://autograder.test/");
+    if (err != nil) {
+        test.Fatalf("Failed to get launch claims: '%v'.", err);
+    }
+
+    idToken, err := platform.Sign(claims);
+    if (err != nil) {
+        test.Fatalf("Failed to sign launch claims: '%v'.", err);
+    }
+
+    form.Set("id_token", idToken);
+
+    _, response = core.SendTestBrowserForm(test, mustGetPath(test, launchURL), form);
+    if ((response == nil) || response.Success || (response.Locator != "-332")) {
+        test.Fatalf("Unexpected response for a bad nonce: '%v'.", response);
+    }
+
+    // The signed launch selects a different course than the login did.
+    location, _ = core.SendTestBrowserForm(test, core.NewEndpoint(`auth/lti/login`), toValues(platform.LoginParams("https://autograder.test/?course-id=course101")));
+    launchURL, form, err = platform.Authorize(location);
+    if (err != nil) {
+        test.Fatalf("Failed to authorize: '%v'.", err);
+    }
+
+    claims, err = platform.LaunchClaims(mustGetLoginNonce(test, location), "https://autograder.test/?course-id=ZZZ");
+    if (err != nil) {
+        test.Fatalf("Failed to get launch claims: '%v'.", err);
+    }
+
+    idToken, err = platform.Sign(claims);
+    if (err != nil) {
+        test.Fatalf("Failed to sign launch claims: '%v'.", err);
+    }
+
+    form.Set("id_token", idToken);
+
+    _, response = core.SendTestBrowserForm(test, mustGetPath(test, launchURL), form);
+    if ((response == nil) || response.Success || (response.Locator != "-340")) {
+        test.Fatalf("Unexpected response for a mismatched target: '%v'.", response);
+    }
+
+    // No launch URL (the Host header is never used in its place).
+    config.LTI_LAUNCH_URL.Set("");
+
+    _, response = core.SendTestBrowserForm(test, core.NewEndpoint(`auth/lti/login`), toValues(platform.LoginParams("https://autograder.test/")));
+    if ((response == nil) || response.Success || (response.Locator != "-342")) {
+        test.Fatalf("Unexpected response for no launch URL: '%v'.", response);
+    }
+}
+
+// A launch from another context (course) on the same platform cannot be used to get into this course.
+func TestLTILaunchWrongContext(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := startTestPlatform(test);
+    defer platform.Close();
+
+    setTestPlatform(test, platform, false);
+
+    course := db.MustGetTestCourse();
+    course.GetLMSAdapter().LMSCourseID = "other-context";
+
+    err := db.SaveCourse(course);
+    if (err != nil) {
+        test.Fatalf("Failed to save course: '%v'.", err);
+    }
+
+    platform.SetIdentity("admin-id");
+
+    response := launch(test, platform, "https://autograder.test/?course-id=course101");
+    if (response.Success || (response.Locator != "-332")) {
+        test.Fatalf("Unexpected response for a launch from the wrong context: '%v'.", response);
+    }
+
+    // The platform's service endpoints should not have been saved.
+    endpoints, err := lti.GetServiceEndpoints(db.MustGetTestCourse());
+    if ((err == nil) && (endpoints != nil) && (endpoints.LineItemsURL == platform.LineItemsURL())) {
+        test.Fatalf("Service endpoints were saved from a launch in the wrong context: '%+v'.", endpoints);
+    }
+}
+
+// Do a full launch (login initiation, platform authorization, and the launch post) for the platform's current identity.
+func launch(test *testing.T, platform *lti.MockPlatform, targetLinkURI string) *core.APIResponse {
+    location, response := core.SendTestBrowserForm(test, core.NewEndpoint(`auth/lti/login`), toValues(platform.LoginParams(targetLinkURI)));
+    if (response != nil) {
+        return response;
+    }
+
+    launchURL, form, err := platform.Authorize(location);
+    if (err != nil) {
+        test.Fatalf("Failed to authorize: '%v'.", err);
+    }
+
+    _, response = core.SendTestBrowserForm(test, mustGetPath(test, launchURL), form);
+    if (response == nil) {
+        test.Fatalf("Launch was unexpectedly redirected.");
+    }
+
+    return response;
+}
+
+func mustGetLoginNonce(test *testing.T, location string) string {
+    parsed, err := url.Parse(location);
+    if (err != nil) {
+        test.Fatalf("Failed to parse URL '%s': '%v'.", location, err);
+    }
+
+    return parsed.Query().Get("nonce");
+}
+
+func startTestPlatform(test *testing.T) *lti.MockPlatform {
+    platform, err := lti.NewMockPlatform(TEST_CLIENT_ID);
+    if (err != nil) {
+        test.Fatalf("Failed to start mock platform: '%v'.", err);
+    }
+
+    oldLaunchURL := config.LTI_LAUNCH_URL.Get();
+    test.Cleanup(func() {
+        config.LTI_LAUNCH_URL.Set(oldLaunchURL);
+    });
+
+    config.LTI_LAUNCH_URL.Set(core.GetTestServerURL() + core.NewEndpoint(`auth/lti/launch`));
+
+    return platform;
+}
+
+// Register the test course with the platform.
+func setTestPlatform(test *testing.T, platform *lti.MockPlatform, syncAdds bool) {
+    course := db.MustGetTestCourse();
+    course.LMS = &model.LMSAdapter{
+        Type: model.LMS_TYPE_LTI,
+        LMSCourseID: lti.MOCK_CONTEXT_ID,
+        LTI: platform.Platform(),
+        SyncUserAdds: syncAdds,
+    };
+
+    err := db.SaveCourse(course);
+    if (err != nil) {
+        test.Fatalf("Failed to save course: '%v'.", err);
+    }
+}
+
+func mustGetPath(test *testing.T, uri string) string {
+    parsed, err := url.Parse(uri);
+    if (err != nil) {
+        test.Fatalf("Failed to parse URL '%s': '%v'.", uri, err);
+    }
+
+    return parsed.Path;
+}
+
+func toValues(params map[string]string) url.Values {
+    values := url.Values{};
+    for key, value := range params {
+        values.Set(key, value);
+    }
+
+    return values;
+}
@@ -0,0 +1,251 @@
+package auth
+
+// Log in with an OpenID Connect provider (authorization code flow).
+// A browser visits the login endpoint with a course ID and is redirected to the provider.
+// After the user logs in, the provider redirects back to the callback endpoint,
+// which maps the identity's email to a user in the course and issues an API token for that user.
+
+import (
+    "net/http"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/oidc"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    // How long a user has to finish logging in with the provider.
+    OIDC_LOGIN_TIMEOUT = 10 * time.Minute;
+
+    // The most logins that can be pending at once (the oldest are dropped first).
+    // Logins can be started without authentication, so this bounds the memory they can use.
+    MAX_PENDING_LOGINS = 10000;
+
+    OIDC_STATE_LEN = 32;
+    OIDC_NONCE_LEN = 32;
+
+    OIDC_TOKEN_NAME = "oidc-login";
+)
+
+type OIDCLoginResponse struct {
+    CourseID string `json:"course-id"`
+    UserEmail string `json:"user-email"`
+    // Can be used as the "user-token" in other API requests.
+    UserToken string `json:"user-token"`
+    TokenID string `json:"token-id"`
+    ExpirationTime common.Timestamp `json:"expiration-time"`
+}
+
+// A login that has been sent to the provider, but has not come back yet.
+type pendingLogin struct {
+    courseID string
+    nonce string
+    codeVerifier string
+    redirectURL string
+    // Only used for LTI launches.
+    targetLinkURI string
+    expiration time.Time
+}
+
+var pendingLogins map[string]*pendingLogin = make(map[string]*pendingLogin);
+var pendingLoginsLock sync.Mutex;
+
+func HandleOIDCLogin(response http.ResponseWriter, request *http.Request) (any, *core.APIError) {
+    endpoint := request.URL.Path;
+
+    provider, err := oidc.GetDefaultProvider();
+    if (err != nil) {
+        return nil, core.NewBareBadRequestError("-301", endpoint, "OIDC login is not configured.").Err(err);
+    }
+
+    courseID := request.URL.Query().Get("course-id");
+    if (courseID == "") {
+        return nil, core.NewBareBadRequestError("-302", endpoint, "No course ID specified.");
+    }
+
+    course, err := db.GetCourse(courseID);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-303", endpoint, "Unable to get course.").Err(err).Add("course-id", courseID);
+    }
+
+    if (course == nil) {
+        return nil, core.NewBareBadRequestError("-304", endpoint, "Could not find course.").Add("course-id", courseID);
+    }
+
+    state, err := util.RandHex(OIDC_STATE_LEN);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-305", endpoint, "Failed to generate login state.").Err(err);
+    }
+
+    nonce, err := util.RandHex(OIDC_NONCE_LEN);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-306", endpoint, "Failed to generate login nonce.").Err(err);
+    }
+
+    codeVerifier, codeChallenge, err := oidc.NewPKCE();
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-307", endpoint, "Failed to generate login code challenge.").Err(err);
+    }
+
+    // The callback URL is never built from the request (e.g. the Host header), since the request can be forged.
+    redirectURL := config.OIDC_REDIRECT_URL.Get();
+    if (redirectURL == "") {
+        return nil, core.NewBareBadRequestError("-341", endpoint, "OIDC login is not configured (no redirect URL).");
+    }
+
+    authURL, err := provider.AuthURL(redirectURL, state, nonce, codeChallenge);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-308", endpoint, "Failed to contact OIDC provider.").Err(err);
+    }
+
+    addPendingLogin(state, &pendingLogin{
+        courseID: course.GetID(),
+        nonce: nonce,
+        codeVerifier: codeVerifier,
+        redirectURL: redirectURL,
+        expiration: time.Now().Add(OIDC_LOGIN_TIMEOUT),
+    });
+
+    http.Redirect(response, request, authURL, http.StatusFound);
+
+    return nil, nil;
+}
+
+func HandleOIDCCallback(response http.ResponseWriter, request *http.Request) (any, *core.APIError) {
+    endpoint := request.URL.Path;
+    query := request.URL.Query();
+
+    // The state is always removed, so a callback can never be replayed.
+    login := popPendingLogin(query.Get("state"));
+    if (login == nil) {
+        return nil, core.NewBareBadRequestError("-309", endpoint, "Unknown or expired login, try logging in again.");
+    }
+
+    if (query.Get("error") != "") {
+        return nil, core.NewBareBadRequestError("-310", endpoint, "OIDC provider returned an error.").
+                Add("error", query.Get("error")).Add("error-description", query.Get("error_description"));
+    }
+
+    code := query.Get("code");
+    if (code == "") {
+        return nil, core.NewBareBadRequestError("-311", endpoint, "OIDC provider did not return an authorization code.");
+    }
+
+    provider, err := oidc.GetDefaultProvider();
+    if (err != nil) {
+        return nil, core.NewBareBadRequestError("-312", endpoint, "OIDC login is not configured.").Err(err);
+    }
+
+    claims, err := provider.Exchange(code, login.redirectURL, login.codeVerifier, login.nonce);
+    if (err != nil) {
+        return nil, core.NewBareBadRequestError("-313", endpoint, "Failed to verify OIDC login.").Err(err);
+    }
+
+    email := strings.TrimSpace(claims.Email);
+    if (email == "") {
+        return nil, core.NewBareBadRequestError("-314", endpoint, "OIDC identity does not have an email.").Add("subject", claims.Subject);
+    }
+
+    if (!claims.IsEmailVerified(config.OIDC_ALLOW_MISSING_EMAIL_VERIFIED.Get())) {
+        return nil, core.NewBareBadRequestError("-315", endpoint, "OIDC identity's email is not verified.").Add("email", email);
+    }
+
+    course, err := db.GetCourse(login.courseID);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-316", endpoint, "Unable to get course.").Err(err).Add("course-id", login.courseID);
+    }
+
+    if (course == nil) {
+        return nil, core.NewBareBadRequestError("-317", endpoint, "Could not find course.").Add("course-id", login.courseID);
+    }
+
+    user, err := db.GetUser(course, email);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-318", endpoint, "Unable to get user.").Err(err).
+                Add("course-id", login.courseID).Add("email", email);
+    }
+
+    if (user == nil) {
+        return nil, core.NewBareBadRequestError("-319", endpoint, "No user with this email is enrolled in the course.").
+                Add("course-id", login.courseID).Add("email", email);
+    }
+
+    duration := time.Duration(config.OIDC_SESSION_HOURS.Get()) * time.Hour;
+    token, cleartext, err := model.NewAPIToken(user.Email, OIDC_TOKEN_NAME, duration);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-320", endpoint, "Failed to create token.").Err(err);
+    }
+
+    err = db.SaveAPIToken(course, token);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-321", endpoint, "Failed to save token.").Err(err).Add("token-id", token.ID);
+    }
+
+    log.Info().Str("course-id", course.GetID()).Str("email", user.Email).Str("token-id", token.ID).Msg("OIDC login.");
+
+    loginResponse := OIDCLoginResponse{
+        CourseID: course.GetID(),
+        UserEmail: user.Email,
+        UserToken: cleartext,
+        TokenID: token.ID,
+        ExpirationTime: token.ExpirationTime,
+    };
+
+    return &loginResponse, nil;
+}
+
+func addPendingLogin(state string, login *pendingLogin) {
+    pendingLoginsLock.Lock();
+    defer pendingLoginsLock.Unlock();
+
+    // Clean up any logins that were never finished.
+    now := time.Now();
+    for key, value := range pendingLogins {
+        if (now.After(value.expiration)) {
+            delete(pendingLogins, key);
+        }
+    }
+
+    // Drop the oldest logins to make room.
+    for (len(pendingLogins) >= MAX_PENDING_LOGINS) {
+        oldestKey := "";
+        for key, value := range pendingLogins {
+            if ((oldestKey == "") || value.expiration.Before(pendingLogins[oldestKey].expiration)) {
+                oldestKey = key;
+            }
+        }
+
+        delete(pendingLogins, oldestKey);
+    }
+
+    pendingLogins[state] = login;
+}
+
+// Remove and return a pending login.
+// Returns nil if the login does not exist or has expired.
+func popPendingLogin(state string) *pendingLogin {
+    pendingLoginsLock.Lock();
+    defer pendingLoginsLock.Unlock();
+
+    login, ok := pendingLogins[state];
+    if (!ok) {
+        return nil;
+    }
+
+    delete(pendingLogins, state);
+
+    if (time.Now().After(login.expiration)) {
+        return nil;
+    }
+
+    return login;
+}
@@ -0,0 +1,196 @@
+package auth
+
+import (
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/oidc"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    TEST_CLIENT_ID = "autograder-test";
+    TEST_CLIENT_SECRET = "secret";
+)
+
+func TestOIDCLogin(test *testing.T) {
+    defer db.ResetForTesting();
+
+    provider := startTestProvider(test, TEST_CLIENT_SECRET);
+    defer provider.Close();
+
+    testCases := []struct{courseID string; email string; emailVerified bool; locator string}{
+        {"course101", "student@test.com", true, ""},
+        {"course101", "admin@test.com", true, ""},
+
+        {"course101", "ZZZ@test.com", true, "-319"},
+        {"course101", "student@test.com", false, "-315"},
+        {"course101", "", true, "-314"},
+
+        {"ZZZ", "student@test.com", true, "-304"},
+        {"", "student@test.com", true, "-302"},
+    };
+
+    for i, testCase := range testCases {
+        db.ResetForTesting();
+        provider.SetIdentity(testCase.email, testCase.emailVerified);
+
+        response := core.SendTestBrowserRequest(test, core.NewEndpoint(`auth/oidc/login`), map[string]string{"course-id": testCase.courseID});
+        if (testCase.locator != "") {
+            if (response.Success) {
+                test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response);
+            } else if (response.Locator != testCase.locator) {
+                test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator);
+            }
+
+            continue;
+        }
+
+        if (!response.Success) {
+            test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            continue;
+        }
+
+        var responseContent OIDCLoginResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if ((responseContent.CourseID != testCase.courseID) || (responseContent.UserEmail != testCase.email)) {
+            test.Errorf("Case %d: Unexpected login response: '%+v'.", i, responseContent);
+            continue;
+        }
+
+        token, err := db.GetAPIToken(db.MustGetTestCourse(), testCase.email, responseContent.TokenID);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to get token: '%v'.", i, err);
+            continue;
+        }
+
+        if ((token == nil) || !token.Check(responseContent.UserToken) || token.IsExpired()) {
+            test.Errorf("Case %d: Issued token is not valid: '%+v'.", i, token);
+            continue;
+        }
+    }
+}
+
+func TestOIDCLoginFailures(test *testing.T) {
+    // Not configured.
+    response := core.SendTestBrowserRequest(test, core.NewEndpoint(`auth/oidc/login`), map[string]string{"course-id": "course101"});
+    if (response.Success || (response.Locator != "-301")) {
+        test.Fatalf("Unexpected response when OIDC is not configured: '%v'.", response);
+    }
+
+    // The provider will reject the client.
+    provider := startTestProvider(test, "ZZZ");
+    defer provider.Close();
+
+    config.OIDC_CLIENT_SECRET.Set(TEST_CLIENT_SECRET);
+
+    response = core.SendTestBrowserRequest(test, core.NewEndpoint(`auth/oidc/login`), map[string]string{"course-id": "course101"});
+    if (response.Success || (response.Locator != "-313")) {
+        test.Fatalf("Unexpected response with a bad client secret: '%v'.", response);
+    }
+
+    // No redirect URL.
+    config.OIDC_REDIRECT_URL.Set("");
+
+    response = core.SendTestBrowserRequest(test, core.NewEndpoint(`auth/oidc/login`), map[string]string{"course-id": "course101"});
+    if (response.Success || (response.Locator != "-341")) {
+        test.Fatalf("Unexpected response with no redirect URL: '%v'.", response);
+    }
+
+    // Unknown state.
+    response = core.SendTestBrowserRequest(test, core.NewEndpoint(`auth/oidc/callback`), map[string]string{"state": "ZZZ", "code": "ZZZ"});
+    if (response.Success || (response.Locator != "-309")) {
+        test.Fatalf("Unexpected response with an unknown state: '%v'.", response);
+    }
+}
+
+// Start a mock provider and point the config at it.
+// Config options will be reset when the test is done.
+func startTestProvider(test *testing.T, clientSecret string) *oidc.MockProvider {
+    provider, err := oidc.NewMockProvider(TEST_CLIENT_ID, clientSecret, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to start mock provider: '%v'.", err);
+    }
+
+    oldIssuer := config.OIDC_ISSUER.Get();
+    oldClientID := config.OIDC_CLIENT_ID.Get();
+    oldClientSecret := config.OIDC_CLIENT_SECRET.Get();
+    oldRedirectURL := config.OIDC_REDIRECT_URL.Get();
+
+    test.Cleanup(func() {
+        config.OIDC_ISSUER.Set(oldIssuer);
+        config.OIDC_CLIENT_ID.Set(oldClientID);
+        config.OIDC_CLIENT_SECRET.Set(oldClientSecret);
+        config.OIDC_REDIRECT_URL.Set(oldRedirectURL);
+    });
+
+    config.OIDC_ISSUER.Set(provider.Issuer());
+    config.OIDC_CLIENT_ID.Set(TEST_CLIENT_ID);
+    config.OIDC_CLIENT_SECRET.Set(clientSecret);
+    config.OIDC_REDIRECT_URL.Set(core.GetTestServerURL() + core.NewEndpoint(`auth/oidc/callback`));
+
+    return provider;
+}
+
+func TestPendingLoginExpiration(test *testing.T) {
+    clearPendingLogins();
+    defer clearPendingLogins();
+
+    addPendingLogin("expired", &pendingLogin{expiration: time.Now().Add(-time.Second)});
+    addPendingLogin("active", &pendingLogin{expiration: time.Now().Add(OIDC_LOGIN_TIMEOUT)});
+
+    // The expired login should have been swept when the second one was added.
+    if (countPendingLogins() != 1) {
+        test.Fatalf("Unexpected number of pending logins. Expected: 1, Actual: %d.", countPendingLogins());
+    }
+
+    if (popPendingLogin("expired") != nil) {
+        test.Fatalf("Got an expired login.");
+    }
+
+    if (popPendingLogin("active") == nil) {
+        test.Fatalf("Did not get an active login.");
+    }
+}
+
+func TestPendingLoginMax(test *testing.T) {
+    clearPendingLogins();
+    defer clearPendingLogins();
+
+    start := time.Now().Add(OIDC_LOGIN_TIMEOUT);
+    for i := 0; i < (MAX_PENDING_LOGINS + 10); i++ {
+        addPendingLogin(fmt.Sprintf("%d", i), &pendingLogin{expiration: start.Add(time.Duration(i) * time.Millisecond)});
+    }
+
+    if (countPendingLogins() != MAX_PENDING_LOGINS) {
+        test.Fatalf("Unexpected number of pending logins. Expected: %d, Actual: %d.", MAX_PENDING_LOGINS, countPendingLogins());
+    }
+
+    // The oldest logins are dropped first.
+    if (popPendingLogin("0") != nil) {
+        test.Fatalf("Got a login that should have been dropped.");
+    }
+
+    if (popPendingLogin(fmt.Sprintf("%d", MAX_PENDING_LOGINS + 9)) == nil) {
+        test.Fatalf("Did not get the newest login.");
+    }
+}
+
+func clearPendingLogins() {
+    pendingLoginsLock.Lock();
+    defer pendingLoginsLock.Unlock();
+
+    pendingLogins = make(map[string]*pendingLogin);
+}
+
+func countPendingLogins() int {
+    pendingLoginsLock.Lock();
+    defer pendingLoginsLock.Unlock();
+
+    return len(pendingLogins);
+}
@@ -0,0 +1,20 @@
+package auth
+
+// All the API endpoints handled by this package.
+
+import (
+    "github.com/eriq-augustine/autograder/api/core"
+)
+
+var routes []*core.Route = []*core.Route{
+    core.NewBrowserRoute(core.NewEndpoint(`auth/oidc/login`), HandleOIDCLogin),
+    core.NewBrowserRoute(core.NewEndpoint(`auth/oidc/callback`), HandleOIDCCallback),
+    core.NewBrowserRoute(core.NewEndpoint(`auth/lti/login`), HandleLTILogin),
+    core.NewBrowserRouteWithMethod("POST", core.NewEndpoint(`auth/lti/login`), HandleLTILogin),
+    core.NewBrowserRouteWithMethod("POST", core.NewEndpoint(`auth/lti/launch`), HandleLTILaunch),
+    core.NewBrowserRoute(core.NewEndpoint(`auth/lti/jwks`), HandleLTIJWKS),
+};
+
+func GetRoutes() *[]*core.Route {
+    return &routes;
+}
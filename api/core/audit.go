@@ -0,0 +1,180 @@
+package core
+
+// Privileged API requests are recorded in the audit log.
+// A request is privileged if it requires a capability students do not have by default,
+// or if it targets a user other than the one making the request.
+// Both successful and failed handler calls are recorded,
+// as are requests that were denied before reaching their handler (failed authentication or authorization).
+
+import (
+    "net/http"
+    "reflect"
+    "strings"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+// Request fields (in order of preference) that identify the target of a request.
+var auditTargetKeys []string = []string{"target-email", "assignment-id"};
+
+func auditAPIRequest(request *http.Request, apiRequest ValidAPIRequest, apiErr *APIError) {
+    if (!isPrivilegedRequest(apiRequest)) {
+        return;
+    }
+
+    endpoint := request.URL.Path;
+
+    // The raw content has already been successfully parsed once (when creating the request).
+    content, err := util.JSONMapFromString(request.PostFormValue(API_REQUEST_CONTENT_KEY));
+    if (err != nil) {
+        // Do not log the error, it contains the (unredacted) content.
+        log.Error().Str("api-endpoint", endpoint).Msg("Failed to parse request content for the audit log.");
+        content = make(map[string]any);
+    }
+
+    action := strings.TrimPrefix(endpoint, CURRENT_PREFIX + "/");
+
+    courseID, actor := getAuditActor(apiRequest);
+
+    record := model.NewAuditRecord(model.AUDIT_SOURCE_API, courseID, actor, action, getAuditTarget(content), content);
+
+    if (apiErr != nil) {
+        record.Success = false;
+        record.Locator = apiErr.Locator;
+    }
+
+    err = db.AppendAuditRecord(record);
+    if (err != nil) {
+        log.Error().Err(err).Str("api-endpoint", endpoint).Msg("Failed to write audit record.");
+    }
+}
+
+// Is this an error from failing to authenticate (or being throttled) or failing to authorize?
+func isAccessDeniedError(apiErr *APIError) bool {
+    switch (apiErr.HTTPStatus) {
+        case HTTP_STATUS_AUTH_ERROR, HTTP_PERMISSIONS_ERROR, HTTP_STATUS_TOO_MANY_REQUESTS:
+            return true;
+        default:
+            return false;
+    }
+}
+
+func isPrivilegedRequest(apiRequest ValidAPIRequest) bool {
+    capabilities, _ := getRequiredCapabilities(apiRequest);
+    for _, capability := range capabilities {
+        if (!model.RoleHasCapability(model.RoleStudent, capability)) {
+            return true;
+        }
+    }
+
+    _, actor := getAuditActor(apiRequest);
+    for _, email := range getRequestTargetEmails(apiRequest) {
+        if ((email != "") && (email != actor)) {
+            return true;
+        }
+    }
+
+    return false;
+}
+
+// Get the course and user of a request from its validated context
+// (so that the IDs match the ones used in audit queries).
+// Requests that failed authentication have no user, so the email they claimed is used instead.
+func getAuditActor(apiRequest ValidAPIRequest) (string, string) {
+    reflectValue := reflect.ValueOf(apiRequest);
+    if (reflectValue.Kind() == reflect.Pointer) {
+        reflectValue = reflectValue.Elem();
+    }
+
+    if (reflectValue.Kind() != reflect.Struct) {
+        return "", "";
+    }
+
+    field := reflectValue.FieldByName("APIRequestCourseUserContext");
+    if (field.IsValid()) {
+        context := field.Interface().(APIRequestCourseUserContext);
+
+        courseID := "";
+        if (context.Course != nil) {
+            courseID = context.Course.GetID();
+        }
+
+        email := context.UserEmail;
+        if (context.User != nil) {
+            email = context.User.Email;
+        }
+
+        return courseID, email;
+    }
+
+    field = reflectValue.FieldByName("APIRequestUserContext");
+    if (field.IsValid()) {
+        context := field.Interface().(APIRequestUserContext);
+
+        email := context.UserEmail;
+        if (context.ServerUser != nil) {
+            email = context.ServerUser.Email;
+        }
+
+        return "", email;
+    }
+
+    return "", "";
+}
+
+// Get the emails of the users targeted by a request (from any TargetUser* fields).
+// An empty email targets the context user.
+func getRequestTargetEmails(apiRequest ValidAPIRequest) []string {
+    emails := make([]string, 0);
+
+    reflectValue := reflect.ValueOf(apiRequest);
+    if (reflectValue.Kind() == reflect.Pointer) {
+        reflectValue = reflectValue.Elem();
+    }
+
+    if (reflectValue.Kind() != reflect.Struct) {
+        return emails;
+    }
+
+    for i := 0; i < reflectValue.NumField(); i++ {
+        field := reflectValue.Field(i);
+        if (!field.CanInterface()) {
+            continue;
+        }
+
+        switch target := field.Interface().(type) {
+            case TargetUser:
+                emails = append(emails, target.Email);
+            case TargetUserSelfOrGrader:
+                emails = append(emails, target.Email);
+            case TargetUserSelfOrAdmin:
+                emails = append(emails, target.Email);
+        }
+    }
+
+    return emails;
+}
+
+func getAuditTarget(content map[string]any) string {
+    for _, key := range auditTargetKeys {
+        target := getAuditString(content, key);
+        if (target != "") {
+            return target;
+        }
+    }
+
+    return "";
+}
+
+func getAuditString(content map[string]any, key string) string {
+    value, ok := content[key].(string);
+    if (!ok) {
+        return "";
+    }
+
+    return value;
+}
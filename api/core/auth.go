@@ -9,16 +9,31 @@ import (
 )
 
 // Return a user only in the case that the authentication is successful.
+// A request can authenticate with either a password or an API token.
 // If any error is retuturned, then the request should end and the response sent based on the error.
 // This assumes basic validation has already been done on the request.
+// Repeated password failures will lock out the user/IP for a time (see throttle.go).
+// Tokens cannot be guessed, so a user's password lockout does not apply to tokens
+// (and bad tokens only count against the IP, whose lockout always applies).
 func (this *APIRequestCourseUserContext) Auth() (*model.User, *APIError) {
+    throttleEmail := this.UserEmail;
+    if (this.UserToken != "") {
+        throttleEmail = "";
+    }
+
+    apiErr := this.checkThrottle(throttleEmail);
+    if (apiErr != nil) {
+        return nil, apiErr;
+    }
+
     user, err := db.GetUser(this.Course, this.UserEmail);
     if (err != nil) {
         return nil, NewAuthBadRequestError("-012", this, "Cannot Get User").Err(err);
     }
 
+    // Unknown users only count against the IP (so arbitrary emails do not get throttle entries).
     if (user == nil) {
-        return nil, NewAuthBadRequestError("-013", this, "Unknown User");
+        return nil, this.authFailure("", NewAuthBadRequestError("-013", this, "Unknown User"));
     }
 
     if (config.NO_AUTH.Get()) {
@@ -26,9 +41,121 @@ func (this *APIRequestCourseUserContext) Auth() (*model.User, *APIError) {
         return user, nil;
     }
 
+    // Tokens take precedence over passwords.
+    if (this.UserToken != "") {
+        apiErr := this.authToken();
+        if (apiErr != nil) {
+            return nil, apiErr;
+        }
+
+        // A token does not clear password failures (it is not evidence that the password guesser is the user).
+        return user, nil;
+    }
+
+    // Users with a server account can use their server password (once they have set one) in any course they are enrolled in.
+    // Course passwords still work for their own course.
+    serverUser, err := db.GetServerUser(this.UserEmail);
+    if (err != nil) {
+        return nil, NewAuthBadRequestError("-051", this, "Cannot Get Server User").Err(err);
+    }
+
+    if ((serverUser != nil) && serverUser.CheckPassword(this.UserPass)) {
+        this.ServerUser = serverUser;
+        recordAuthSuccess(this.UserEmail);
+        return user, nil;
+    }
+
+    // Skip checking the same hash twice.
+    if ((serverUser != nil) && serverUser.HasPassword() && serverUser.SamePassword(user)) {
+        return nil, this.authFailure(this.UserEmail, NewAuthBadRequestError("-054", this, "Bad Password"));
+    }
+
+    if (!user.CheckPassword(this.UserPass)) {
+        return nil, this.authFailure(this.UserEmail, NewAuthBadRequestError("-014", this, "Bad Password"));
+    }
+
+    recordAuthSuccess(this.UserEmail);
+    return user, nil;
+}
+
+// Return a server user only in the case that the authentication is successful.
+// Only server passwords are accepted (API tokens belong to a course).
+func (this *APIRequestUserContext) Auth() (*model.ServerUser, *APIError) {
+    apiErr := this.checkThrottle(this.UserEmail);
+    if (apiErr != nil) {
+        return nil, apiErr;
+    }
+
+    user, err := db.GetServerUser(this.UserEmail);
+    if (err != nil) {
+        return nil, NewUserAuthBadRequestError("-048", this, "Cannot Get Server User").Err(err);
+    }
+
+    if (user == nil) {
+        return nil, this.authFailure("", NewUserAuthBadRequestError("-049", this, "Unknown Server User"));
+    }
+
+    if (config.NO_AUTH.Get()) {
+        log.Debug().Str("email", this.UserEmail).Msg("Authentication Disabled.");
+        return user, nil;
+    }
+
     if (!user.CheckPassword(this.UserPass)) {
-        return nil, NewAuthBadRequestError("-014", this, "Bad Password");
+        return nil, this.authFailure(this.UserEmail, NewUserAuthBadRequestError("-050", this, "Bad Password"));
     }
 
+    recordAuthSuccess(this.UserEmail);
     return user, nil;
 }
+
+// Check the request's token and set the context token on success.
+// Failures only count against the request's IP.
+func (this *APIRequestCourseUserContext) authToken() *APIError {
+    tokenID, err := model.ParseAPITokenID(this.UserToken);
+    if (err != nil) {
+        return this.authFailure("", NewAuthBadRequestError("-041", this, "Malformed Token").Err(err));
+    }
+
+    token, err := db.GetAPIToken(this.Course, this.UserEmail, tokenID);
+    if (err != nil) {
+        return NewAuthBadRequestError("-042", this, "Cannot Get Token").Err(err).Add("token-id", tokenID);
+    }
+
+    if ((token == nil) || !token.Check(this.UserToken)) {
+        return this.authFailure("", NewAuthBadRequestError("-043", this, "Bad Token").Add("token-id", tokenID));
+    }
+
+    if (token.IsExpired()) {
+        return this.authFailure("", NewAuthBadRequestError("-044", this, "Expired Token").Add("token-id", tokenID));
+    }
+
+    this.Token = token;
+
+    return nil;
+}
+
+// Return an error if the user or the request's IP is locked out.
+// An empty email only checks the request's IP.
+func (this *APIRequest) checkThrottle(email string) *APIError {
+    if (config.NO_AUTH.Get()) {
+        return nil;
+    }
+
+    lockedUntil, locked := checkAuthThrottle(email, this.ClientIP);
+    if (locked) {
+        return NewAuthThrottledError("-052", this, email, lockedUntil);
+    }
+
+    return nil;
+}
+
+// Record a failed authentication attempt (for throttling) and pass the error through.
+// An empty email only counts the failure against the request's IP.
+// Failures caused by the server (e.g. database errors) should not be recorded.
+func (this *APIRequest) authFailure(email string, apiErr *APIError) *APIError {
+    if (!config.NO_AUTH.Get()) {
+        recordAuthFailure(email, this.ClientIP);
+    }
+
+    return apiErr;
+}
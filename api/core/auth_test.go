@@ -2,15 +2,18 @@ package core
 
 import (
     "testing"
+    "time"
 
     "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
     "github.com/eriq-augustine/autograder/util"
 )
 
 func TestAuth(test *testing.T) {
     type baseAPIRequest struct {
         APIRequestCourseUserContext
-        MinRoleOther
+        RequireNone
     }
 
     testCases := []struct{email string; pass string; noauth bool; locator string}{
@@ -70,3 +73,167 @@ func TestAuth(test *testing.T) {
         }
     }
 }
+
+func TestAuthToken(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    type baseAPIRequest struct {
+        APIRequestCourseUserContext
+        RequireNone
+    }
+
+    course := db.MustGetTestCourse();
+
+    token, cleartext, err := model.NewAPIToken("student@test.com", "test", time.Hour);
+    if (err != nil) {
+        test.Fatalf("Failed to create token: '%v'.", err);
+    }
+
+    expiredToken, expiredCleartext, err := model.NewAPIToken("student@test.com", "expired", -time.Hour);
+    if (err != nil) {
+        test.Fatalf("Failed to create expired token: '%v'.", err);
+    }
+
+    for _, apiToken := range []*model.APIToken{token, expiredToken} {
+        err = db.SaveAPIToken(course, apiToken);
+        if (err != nil) {
+            test.Fatalf("Failed to save token: '%v'.", err);
+        }
+    }
+
+    testCases := []struct{email string; pass string; token string; locator string}{
+        {"student@test.com", "",        cleartext, ""},
+        // Tokens take precedence over passwords.
+        {"student@test.com", "Zstudent", cleartext, ""},
+        {"student@test.com", "student", "ZZZ",     "-041"},
+
+        {"student@test.com", "", "",                      "-017"},
+        {"student@test.com", "", "ZZZ",                   "-041"},
+        {"student@test.com", "", cleartext + "Z",         "-043"},
+        {"student@test.com", "", "ZZZ" + cleartext,       "-043"},
+        {"student@test.com", "", token.ID + ".ZZZ",       "-043"},
+        {"student@test.com", "", expiredCleartext,        "-044"},
+
+        // Tokens are tied to a user.
+        {"grader@test.com",  "", cleartext, "-043"},
+        {"Zstudent@test.com", "", cleartext, "-013"},
+    };
+
+    for i, testCase := range testCases {
+        pass := "";
+        if (testCase.pass != "") {
+            pass = util.Sha256HexFromString(testCase.pass);
+        }
+
+        request := baseAPIRequest{
+            APIRequestCourseUserContext: APIRequestCourseUserContext{
+                CourseID: "course101",
+                UserEmail: testCase.email,
+                UserPass: pass,
+                UserToken: testCase.token,
+            },
+        };
+
+        apiErr := ValidateAPIRequest(nil, &request, "");
+
+        if ((apiErr == nil) && (testCase.locator != "")) {
+            test.Errorf("Case %d: Expecting error '%s', but got no error.", i, testCase.locator);
+        } else if ((apiErr != nil) && (testCase.locator == "")) {
+            test.Errorf("Case %d: Expecting no error, but got '%s': '%v'.", i, apiErr.Locator, apiErr);
+        } else if ((apiErr != nil) && (testCase.locator != "") && (apiErr.Locator != testCase.locator)) {
+            test.Errorf("Case %d: Got a different error than expected. Expected: '%s', actual: '%s' -- '%v'.",
+                    i, testCase.locator, apiErr.Locator, apiErr);
+        }
+
+        if ((apiErr == nil) && ((request.Token == nil) || (request.Token.ID != token.ID))) {
+            test.Errorf("Case %d: Context token was not set: '%+v'.", i, request.Token);
+        }
+    }
+}
+
+func TestAuthServerUser(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    type baseAPIRequest struct {
+        APIRequestCourseUserContext
+        RequireNone
+    }
+
+    type userAPIRequest struct {
+        APIRequestUserContext
+    }
+
+    // The student gets a server password that differs from their course password.
+    serverUser := model.NewServerUser("student@test.com", "");
+    err := serverUser.SetPassword(util.Sha256HexFromString("server"));
+    if (err != nil) {
+        test.Fatalf("Failed to set password: '%v'.", err);
+    }
+
+    err = db.SaveServerUser(serverUser);
+    if (err != nil) {
+        test.Fatalf("Failed to save server user: '%v'.", err);
+    }
+
+    // The grader has a server account, but has not set a server password yet.
+    err = db.SaveServerUser(model.NewServerUser("grader@test.com", ""));
+    if (err != nil) {
+        test.Fatalf("Failed to save server user: '%v'.", err);
+    }
+
+    testCases := []struct{email string; pass string; courseLocator string; userLocator string}{
+        {"student@test.com", "server",  "",     ""},
+        // Course passwords only work in their course.
+        {"student@test.com", "student", "",     "-050"},
+        {"student@test.com", "Zserver", "-014", "-050"},
+
+        // No server password.
+        {"grader@test.com",  "grader",  "",     "-050"},
+        {"grader@test.com",  "",        "-014", "-050"},
+
+        // No server user.
+        {"other@test.com",   "other",   "",     "-049"},
+        {"Zstudent@test.com", "server", "-013", "-049"},
+    };
+
+    for i, testCase := range testCases {
+        courseRequest := baseAPIRequest{
+            APIRequestCourseUserContext: APIRequestCourseUserContext{
+                CourseID: "course101",
+                UserEmail: testCase.email,
+                UserPass: util.Sha256HexFromString(testCase.pass),
+            },
+        };
+
+        userRequest := userAPIRequest{
+            APIRequestUserContext: APIRequestUserContext{
+                UserEmail: testCase.email,
+                UserPass: util.Sha256HexFromString(testCase.pass),
+            },
+        };
+
+        checks := []struct{name string; request any; locator string}{
+            {"course", &courseRequest, testCase.courseLocator},
+            {"user", &userRequest, testCase.userLocator},
+        };
+
+        for _, check := range checks {
+            apiErr := ValidateAPIRequest(nil, check.request, "");
+
+            if ((apiErr == nil) && (check.locator != "")) {
+                test.Errorf("Case %d (%s): Expecting error '%s', but got no error.", i, check.name, check.locator);
+            } else if ((apiErr != nil) && (check.locator == "")) {
+                test.Errorf("Case %d (%s): Expecting no error, but got '%s': '%v'.", i, check.name, apiErr.Locator, apiErr);
+            } else if ((apiErr != nil) && (check.locator != "") && (apiErr.Locator != check.locator)) {
+                test.Errorf("Case %d (%s): Got a different error than expected. Expected: '%s', actual: '%s' -- '%v'.",
+                        i, check.name, check.locator, apiErr.Locator, apiErr);
+            }
+        }
+
+        if ((testCase.userLocator == "") && ((userRequest.ServerUser == nil) || (userRequest.ServerUser.Email != testCase.email))) {
+            test.Errorf("Case %d: Context server user was not set: '%+v'.", i, userRequest.ServerUser);
+        }
+    }
+}
@@ -0,0 +1,50 @@
+package core
+
+// Support for endpoints that are visited directly by a browser (e.g. as part of a login redirect flow).
+// These endpoints are (usually) GET requests that take URL query or form parameters instead of the standard POST API request,
+// so they do no automatic validation or authentication.
+
+import (
+    "net/http"
+    "regexp"
+
+    "github.com/rs/zerolog/log"
+)
+
+// A handler for browser endpoints.
+// The handler may write its own response (e.g. a redirect) and return (nil, nil),
+// otherwise any content or error will be sent as a standard API response.
+type BrowserHandler func(response http.ResponseWriter, request *http.Request) (any, *APIError);
+
+func NewBrowserRoute(pattern string, browserHandler BrowserHandler) *Route {
+    return NewBrowserRouteWithMethod("GET", pattern, browserHandler);
+}
+
+// Some flows (e.g. LTI launches) have the browser POST a form instead.
+func NewBrowserRouteWithMethod(method string, pattern string, browserHandler BrowserHandler) *Route {
+    handler := func(response http.ResponseWriter, request *http.Request) (err error) {
+        // Recover from any panic.
+        defer func() {
+            value := recover();
+            if (value == nil) {
+                return;
+            }
+
+            log.Error().Any("value", value).Str("endpoint", request.URL.Path).
+                    Msg("Recovered from a panic when handling a browser endpoint.");
+            apiErr := NewBareInternalError("-045", request.URL.Path, "Recovered from a panic when handling a browser endpoint.").
+                    Add("value", value);
+
+            err = sendAPIResponse(nil, response, nil, apiErr, false);
+        }();
+
+        content, apiErr := browserHandler(response, request);
+        if ((content == nil) && (apiErr == nil)) {
+            return nil;
+        }
+
+        return sendAPIResponse(nil, response, content, apiErr, false);
+    }
+
+    return &Route{method, pattern, regexp.MustCompile("^" + pattern + "$"), handler};
+}
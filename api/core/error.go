@@ -4,6 +4,7 @@ import (
     "errors"
     "fmt"
     "net/http"
+    "time"
 
     "github.com/rs/zerolog/log"
 
@@ -32,6 +33,10 @@ const (
     // The users role is not high enough for the specific operation.
     // Can happen at the validation or handling phases.
     HTTP_PERMISSIONS_ERROR = http.StatusForbidden;
+    // Too many failed authentication attempts.
+    HTTP_STATUS_TOO_MANY_REQUESTS = http.StatusTooManyRequests;
+    // The request body is larger than the server allows (see config.WEB_MAX_REQUEST_MB).
+    HTTP_STATUS_REQUEST_TOO_LARGE = http.StatusRequestEntityTooLarge;
 )
 
 // This is technically an error,
@@ -149,6 +154,14 @@ func NewBareBadRequestError(locator string, endpoint string, message string) *AP
     };
 }
 
+// The request body was larger than the server allows (before the request was parsed).
+func NewRequestTooLargeError(locator string, endpoint string, limitBytes int64) *APIError {
+    err := NewBareBadRequestError(locator, endpoint,
+            fmt.Sprintf("Request is larger than the maximum allowed size (%d bytes).", limitBytes));
+    err.HTTPStatus = HTTP_STATUS_REQUEST_TOO_LARGE;
+    return err;
+}
+
 func NewAuthBadRequestError(locator string, request *APIRequestCourseUserContext, internalMessage string) *APIError {
     err := &APIError{
         RequestID: request.RequestID,
@@ -157,7 +170,7 @@ func NewAuthBadRequestError(locator string, request *APIRequestCourseUserContext
         Timestamp: request.Timestamp,
         HTTPStatus: HTTP_STATUS_AUTH_ERROR,
         InternalText: fmt.Sprintf("Authentication failure: '%s'.", internalMessage),
-        ResponseText: "Authentication failure, check course, email, and password.",
+        ResponseText: "Authentication failure, check course, email, and password (or token).",
     };
 
     err.Add("course", request.CourseID);
@@ -166,6 +179,41 @@ func NewAuthBadRequestError(locator string, request *APIRequestCourseUserContext
     return err;
 }
 
+func NewUserAuthBadRequestError(locator string, request *APIRequestUserContext, internalMessage string) *APIError {
+    err := &APIError{
+        RequestID: request.RequestID,
+        Locator: locator,
+        Endpoint: request.Endpoint,
+        Timestamp: request.Timestamp,
+        HTTPStatus: HTTP_STATUS_AUTH_ERROR,
+        InternalText: fmt.Sprintf("Authentication failure: '%s'.", internalMessage),
+        ResponseText: "Authentication failure, check email and password.",
+    };
+
+    err.Add("email", request.UserEmail);
+
+    return err;
+}
+
+// A user or IP that is temporarily locked out because of failed authentication attempts.
+func NewAuthThrottledError(locator string, request *APIRequest, email string, lockedUntil time.Time) *APIError {
+    err := &APIError{
+        RequestID: request.RequestID,
+        Locator: locator,
+        Endpoint: request.Endpoint,
+        Timestamp: request.Timestamp,
+        HTTPStatus: HTTP_STATUS_TOO_MANY_REQUESTS,
+        InternalText: "Authentication throttled.",
+        ResponseText: fmt.Sprintf("Too many failed authentication attempts, try again after %s.", lockedUntil.Format(time.RFC3339)),
+    };
+
+    err.Add("email", email);
+    err.Add("ip", request.ClientIP);
+    err.Add("locked-until", common.TimestampFromTime(lockedUntil));
+
+    return err;
+}
+
 func NewBadPermissionsError(locator string, request *APIRequestCourseUserContext, minRole model.UserRole, internalMessage string) *APIError {
     err := &APIError{
         RequestID: request.RequestID,
@@ -186,6 +234,26 @@ func NewBadPermissionsError(locator string, request *APIRequestCourseUserContext
     return err;
 }
 
+func NewMissingCapabilityError(locator string, request *APIRequestCourseUserContext, capability model.Capability, internalMessage string) *APIError {
+    err := &APIError{
+        RequestID: request.RequestID,
+        Locator: locator,
+        Endpoint: request.Endpoint,
+        Timestamp: request.Timestamp,
+        HTTPStatus: HTTP_PERMISSIONS_ERROR,
+        InternalText: fmt.Sprintf("Missing Capability: '%s'.", internalMessage),
+        ResponseText: "You have insufficient permissions for the requested operation.",
+    };
+
+    err.Add("course", request.CourseID);
+    err.Add("email", request.UserEmail);
+
+    err.Add("actual-role", request.User.Role);
+    err.Add("required-capability", capability);
+
+    return err;
+}
+
 func NewInternalError(locator string, request *APIRequestCourseUserContext, internalMessage string) *APIError {
     err := &APIError{
         RequestID: request.RequestID,
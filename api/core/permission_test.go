@@ -0,0 +1,122 @@
+package core
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestRequestCapabilities(test *testing.T) {
+    defer db.ResetForTesting();
+
+    type viewUsersRequest struct {
+        APIRequestCourseUserContext
+        RequireViewUsers
+    }
+
+    type viewSubmissionsRequest struct {
+        APIRequestAssignmentContext
+        RequireViewSubmissions
+    }
+
+    type uploadScoresRequest struct {
+        APIRequestAssignmentContext
+        RequireUploadLMSScores
+    }
+
+    testCases := []struct{permissions *model.CoursePermissions; email string; request string; locator string}{
+        // Role defaults.
+        {nil, "grader@test.com", "view-users", ""},
+        {nil, "grader@test.com", "view-submissions", ""},
+        {nil, "grader@test.com", "upload-scores", ""},
+        {nil, "student@test.com", "view-users", "-020"},
+        {nil, "student@test.com", "view-submissions", "-020"},
+
+        // Grants and revokes.
+        {
+            &model.CoursePermissions{Users: map[string]*model.PermissionOverride{
+                "grader@test.com": &model.PermissionOverride{Revoke: []model.Capability{model.CapabilityUploadLMSScores}},
+            }},
+            "grader@test.com", "upload-scores", "-020",
+        },
+        {
+            &model.CoursePermissions{Users: map[string]*model.PermissionOverride{
+                "grader@test.com": &model.PermissionOverride{Revoke: []model.Capability{model.CapabilityUploadLMSScores}},
+            }},
+            "grader@test.com", "view-submissions", "",
+        },
+        {
+            &model.CoursePermissions{Users: map[string]*model.PermissionOverride{
+                "student@test.com": &model.PermissionOverride{Grant: []model.Capability{model.CapabilityViewUsers}},
+            }},
+            "student@test.com", "view-users", "",
+        },
+
+        // Assignment limits only apply to assignment capabilities.
+        {
+            &model.CoursePermissions{Users: map[string]*model.PermissionOverride{
+                "grader@test.com": &model.PermissionOverride{Assignments: []string{"hw0"}},
+            }},
+            "grader@test.com", "view-submissions", "",
+        },
+        {
+            &model.CoursePermissions{Users: map[string]*model.PermissionOverride{
+                "grader@test.com": &model.PermissionOverride{Assignments: []string{"hw1"}},
+            }},
+            "grader@test.com", "view-submissions", "-020",
+        },
+        {
+            &model.CoursePermissions{Users: map[string]*model.PermissionOverride{
+                "grader@test.com": &model.PermissionOverride{Assignments: []string{"hw1"}},
+            }},
+            "grader@test.com", "view-users", "",
+        },
+    };
+
+    for i, testCase := range testCases {
+        db.ResetForTesting();
+
+        course := db.MustGetTestCourse();
+        course.Permissions = testCase.permissions;
+        err := db.SaveCourse(course);
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to save course: '%v'.", i, err);
+        }
+
+        courseContext := APIRequestCourseUserContext{
+            CourseID: "course101",
+            UserEmail: testCase.email,
+            // Test users' passwords are their role.
+            UserPass: util.Sha256HexFromString(strings.TrimSuffix(testCase.email, "@test.com")),
+        };
+
+        assignmentContext := APIRequestAssignmentContext{
+            APIRequestCourseUserContext: courseContext,
+            AssignmentID: "hw0",
+        };
+
+        var apiErr *APIError;
+        switch testCase.request {
+            case "view-users":
+                apiErr = ValidateAPIRequest(nil, &viewUsersRequest{APIRequestCourseUserContext: courseContext}, "");
+            case "view-submissions":
+                apiErr = ValidateAPIRequest(nil, &viewSubmissionsRequest{APIRequestAssignmentContext: assignmentContext}, "");
+            case "upload-scores":
+                apiErr = ValidateAPIRequest(nil, &uploadScoresRequest{APIRequestAssignmentContext: assignmentContext}, "");
+            default:
+                test.Fatalf("Case %d: Unknown request type '%s'.", i, testCase.request);
+        }
+
+        if ((apiErr == nil) && (testCase.locator != "")) {
+            test.Errorf("Case %d: Expecting error '%s', but got no error.", i, testCase.locator);
+        } else if ((apiErr != nil) && (testCase.locator == "")) {
+            test.Errorf("Case %d: Expecting no error, but got '%s': '%v'.", i, apiErr.Locator, apiErr);
+        } else if ((apiErr != nil) && (apiErr.Locator != testCase.locator)) {
+            test.Errorf("Case %d: Got a different error than expected. Expected: '%s', actual: '%s' -- '%v'.",
+                    i, testCase.locator, apiErr.Locator, apiErr);
+        }
+    }
+}
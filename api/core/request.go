@@ -21,11 +21,26 @@ type APIRequest struct {
     RequestID string `json:"-"`
     Endpoint string `json:"-"`
     Timestamp common.Timestamp `json:"-"`
+    // Empty if the request did not come over HTTP (e.g. in tests).
+    ClientIP string `json:"-"`
 
     // This request is being used as part of a test.
     TestingMode bool `json:"-"`
 }
 
+// Context for a request that has a server user, but no course.
+// These requests are for things that span courses (e.g. listing a user's courses),
+// and are authenticated with the user's server password.
+type APIRequestUserContext struct {
+    APIRequest
+
+    UserEmail string `json:"user-email"`
+    UserPass string `json:"user-pass"`
+
+    // Filled out as the request is parsed.
+    ServerUser *model.ServerUser
+}
+
 // Context for a request that has a course and user (pretty much the lowest level of request).
 type APIRequestCourseUserContext struct {
     APIRequest
@@ -33,11 +48,17 @@ type APIRequestCourseUserContext struct {
     CourseID string `json:"course-id"`
     UserEmail string `json:"user-email"`
     UserPass string `json:"user-pass"`
+    // An API token can be used in place of a password.
+    UserToken string `json:"user-token"`
 
     // These fields are filled out as the request is parsed,
     // before being sent to the handler.
     Course *model.Course
     User *model.User
+    // Only set if the request was authenticated with a token.
+    Token *model.APIToken
+    // Only set if the request was authenticated with the user's server password.
+    ServerUser *model.ServerUser
 }
 
 //Context for requests that need an assignment on top of a user/course.
@@ -59,12 +80,45 @@ func (this *APIRequest) Validate(request any, endpoint string) *APIError {
     return nil;
 }
 
+// Validate and authenticate a course-less request.
+func (this *APIRequestUserContext) Validate(request any, endpoint string) *APIError {
+    apiErr := this.APIRequest.Validate(request, endpoint);
+    if (apiErr != nil) {
+        return apiErr;
+    }
+
+    if (this.UserEmail == "") {
+        return NewBadRequestError("-046", &this.APIRequest, "No user email specified.");
+    }
+
+    if (this.UserPass == "") {
+        return NewBadRequestError("-047", &this.APIRequest, "No user password specified.");
+    }
+
+    this.ServerUser, apiErr = this.Auth();
+    if (apiErr != nil) {
+        return apiErr;
+    }
+
+    return nil;
+}
+
 // Validate that all the fields are populated correctly and
 // that they are valid in the context of this server,
 // Additionally, all context fields will be populated.
-// This means that this request will be authenticated here.
+// This means that this request will be authenticated (and authorized) here.
 // The full request (object that this is embedded in) is also sent.
 func (this *APIRequestCourseUserContext) Validate(request any, endpoint string) *APIError {
+    apiErr := this.validateAuth(request, endpoint);
+    if (apiErr != nil) {
+        return apiErr;
+    }
+
+    return this.checkCapabilities(request, "");
+}
+
+// Validate and authenticate the request, but do not check for capabilities.
+func (this *APIRequestCourseUserContext) validateAuth(request any, endpoint string) *APIError {
     apiErr := this.APIRequest.Validate(request, endpoint);
     if (apiErr != nil) {
         return apiErr;
@@ -78,8 +132,8 @@ func (this *APIRequestCourseUserContext) Validate(request any, endpoint string)
         return NewBadRequestError("-016", &this.APIRequest, "No user email specified.");
     }
 
-    if (this.UserPass == "") {
-        return NewBadRequestError("-017", &this.APIRequest, "No user password specified.");
+    if ((this.UserPass == "") && (this.UserToken == "")) {
+        return NewBadRequestError("-017", &this.APIRequest, "No user password or token specified.");
     }
 
     var err error;
@@ -98,21 +152,43 @@ func (this *APIRequestCourseUserContext) Validate(request any, endpoint string)
         return apiErr;
     }
 
-    minRole, foundRole := getMaxRole(request);
-    if (!foundRole) {
-        return NewInternalError("-019", this, "No role found for request. All request structs require a minimum role.");
+    return nil;
+}
+
+// Ensure that the context user has all the capabilities required by the request.
+// |assignmentID| is the assignment the request is acting on (empty if none).
+func (this *APIRequestCourseUserContext) checkCapabilities(request any, assignmentID string) *APIError {
+    capabilities, found := getRequiredCapabilities(request);
+    if (!found) {
+        return NewInternalError("-019", this, "No capability found for request. All request structs require capabilities (or RequireNone).");
     }
 
-    if (this.User.Role < minRole) {
-        return NewBadPermissionsError("-020", this, minRole, "Base API Request");
+    for _, capability := range capabilities {
+        if (!this.HasCapability(capability, assignmentID)) {
+            return NewMissingCapabilityError("-020", this, capability, "Base API Request");
+        }
     }
 
     return nil;
 }
 
+// Check if the context user has a capability in the context course.
+// See model.Course.HasCapability().
+func (this *APIRequestCourseUserContext) HasCapability(capability model.Capability, assignmentID string) bool {
+    return this.Course.HasCapability(this.User, capability, assignmentID);
+}
+
+// Check if the context user has a server capability.
+// Course staff control their course's users (and passwords/tokens),
+// so server capabilities are only available to requests authenticated with a server password.
+func (this *APIRequestCourseUserContext) HasServerCapability(capability model.Capability) bool {
+    return ((this.ServerUser != nil) && this.ServerUser.HasServerCapability(capability));
+}
+
 // See APIRequestCourseUserContext.Validate().
+// Capabilities are checked after the assignment is validated, since capabilities may be limited to specific assignments.
 func (this *APIRequestAssignmentContext) Validate(request any, endpoint string) *APIError {
-    apiErr := this.APIRequestCourseUserContext.Validate(request, endpoint);
+    apiErr := this.APIRequestCourseUserContext.validateAuth(request, endpoint);
     if (apiErr != nil) {
         return apiErr;
     }
@@ -135,7 +211,7 @@ func (this *APIRequestAssignmentContext) Validate(request any, endpoint string)
             Add("course-id", this.CourseID).Add("assignment-id", this.AssignmentID);
     }
 
-    return nil;
+    return this.checkCapabilities(request, this.AssignmentID);
 }
 
 // Take in a pointer to an API request.
@@ -147,6 +223,9 @@ func ValidateAPIRequest(request *http.Request, apiRequest any, endpoint string)
                 Add("kind", reflectPointer.Kind().String());
     }
 
+    // Set before validation, since authentication is throttled by IP.
+    setRequestClientIP(request, apiRequest);
+
     // Ensure the request has an request type embedded, and validate it.
     foundRequestStruct, apiErr := validateRequestStruct(apiRequest, endpoint);
     if (apiErr != nil) {
@@ -211,6 +290,17 @@ func validateRequestStruct(request any, endpoint string) (bool, *APIError) {
             }
 
             fieldValue.Set(reflect.ValueOf(apiRequest));
+        } else if (fieldValue.Type() == reflect.TypeOf((*APIRequestUserContext)(nil)).Elem()) {
+            // APIRequestUserContext
+            userRequest := fieldValue.Interface().(APIRequestUserContext);
+            foundRequestStruct = true;
+
+            apiErr := userRequest.Validate(request, endpoint);
+            if (apiErr != nil) {
+                return false, apiErr;
+            }
+
+            fieldValue.Set(reflect.ValueOf(userRequest));
         } else if (fieldValue.Type() == reflect.TypeOf((*APIRequestCourseUserContext)(nil)).Elem()) {
             // APIRequestCourseUserContext
             courseUserRequest := fieldValue.Interface().(APIRequestCourseUserContext);
@@ -240,10 +330,10 @@ func validateRequestStruct(request any, endpoint string) (bool, *APIError) {
 }
 
 // Take a request (or any object),
-// go through all the fields and look for fields typed as the encoded MinRole* fields.
-// Return the maximum amongst the found roles.
-// Return: (role, found role).
-func getMaxRole(request any) (model.UserRole, bool) {
+// go through all the fields and look for fields typed as the encoded Require* fields.
+// Return all the required capabilities (RequireNone does not add a capability, but still counts as found).
+// Return: (capabilities, found any Require* field).
+func getRequiredCapabilities(request any) ([]model.Capability, bool) {
     reflectValue := reflect.ValueOf(request);
 
     // Dereference any pointer.
@@ -251,39 +341,38 @@ func getMaxRole(request any) (model.UserRole, bool) {
         reflectValue = reflectValue.Elem();
     }
 
-    foundRole := false;
-    role := model.RoleUnknown;
+    found := false;
+    capabilities := make([]model.Capability, 0);
 
     for i := 0; i < reflectValue.NumField(); i++ {
-        fieldValue := reflectValue.Field(i);
+        fieldType := reflectValue.Field(i).Type();
 
-        if (fieldValue.Type() == reflect.TypeOf((*MinRoleOwner)(nil)).Elem()) {
-            foundRole = true;
-            if (role < model.RoleOwner) {
-                role = model.RoleOwner;
-            }
-        } else if (fieldValue.Type() == reflect.TypeOf((*MinRoleAdmin)(nil)).Elem()) {
-            foundRole = true;
-            if (role < model.RoleAdmin) {
-                role = model.RoleAdmin;
-            }
-        } else if (fieldValue.Type() == reflect.TypeOf((*MinRoleGrader)(nil)).Elem()) {
-            foundRole = true;
-            if (role < model.RoleGrader) {
-                role = model.RoleGrader;
-            }
-        } else if (fieldValue.Type() == reflect.TypeOf((*MinRoleStudent)(nil)).Elem()) {
-            foundRole = true;
-            if (role < model.RoleStudent) {
-                role = model.RoleStudent;
-            }
-        } else if (fieldValue.Type() == reflect.TypeOf((*MinRoleOther)(nil)).Elem()) {
-            foundRole = true;
-            if (role < model.RoleOther) {
-                role = model.RoleOther;
-            }
+        if (fieldType == reflect.TypeOf((*RequireNone)(nil)).Elem()) {
+            found = true;
+            continue;
+        }
+
+        capability, ok := requiredCapabilityTypes[fieldType];
+        if (ok) {
+            found = true;
+            capabilities = append(capabilities, capability);
         }
     }
 
-    return role, foundRole;
+    return capabilities, found;
+}
+
+// Get the ID of the assignment a request is acting on (empty if the request is not for an assignment).
+func getRequestAssignmentID(request any) string {
+    reflectValue := reflect.ValueOf(request);
+    if (reflectValue.Kind() == reflect.Pointer) {
+        reflectValue = reflectValue.Elem();
+    }
+
+    field := reflectValue.FieldByName("APIRequestAssignmentContext");
+    if (!field.IsValid()) {
+        return "";
+    }
+
+    return field.Interface().(APIRequestAssignmentContext).AssignmentID;
 }
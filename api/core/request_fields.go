@@ -16,12 +16,37 @@ import (
     "github.com/eriq-augustine/autograder/util"
 )
 
-// The minimum user roles required encoded as a type so it can be embedded into a request struct.
-type MinRoleOwner bool;
-type MinRoleAdmin bool;
-type MinRoleGrader bool;
-type MinRoleStudent bool;
-type MinRoleOther bool;
+// The capabilities required by a request encoded as types so they can be embedded into a request struct.
+// All the embedded capabilities are required (see model.Capability for what each one allows).
+// Requests that any user in the course can make (e.g. managing their own tokens) embed RequireNone.
+type RequireNone bool;
+type RequireSubmit bool;
+type RequireViewUsers bool;
+type RequireManageUsers bool;
+type RequireViewSubmissions bool;
+type RequireRemoveSubmissions bool;
+type RequireUploadLMSScores bool;
+type RequireSyncLMS bool;
+type RequireRegrade bool;
+type RequireUpdateCourse bool;
+type RequireViewQueue bool;
+type RequireManageAuthThrottles bool;
+type RequireViewAuditLog bool;
+
+var requiredCapabilityTypes map[reflect.Type]model.Capability = map[reflect.Type]model.Capability{
+    reflect.TypeOf((*RequireSubmit)(nil)).Elem(): model.CapabilitySubmit,
+    reflect.TypeOf((*RequireViewUsers)(nil)).Elem(): model.CapabilityViewUsers,
+    reflect.TypeOf((*RequireManageUsers)(nil)).Elem(): model.CapabilityManageUsers,
+    reflect.TypeOf((*RequireViewSubmissions)(nil)).Elem(): model.CapabilityViewSubmissions,
+    reflect.TypeOf((*RequireRemoveSubmissions)(nil)).Elem(): model.CapabilityRemoveSubmissions,
+    reflect.TypeOf((*RequireUploadLMSScores)(nil)).Elem(): model.CapabilityUploadLMSScores,
+    reflect.TypeOf((*RequireSyncLMS)(nil)).Elem(): model.CapabilitySyncLMS,
+    reflect.TypeOf((*RequireRegrade)(nil)).Elem(): model.CapabilityRegrade,
+    reflect.TypeOf((*RequireUpdateCourse)(nil)).Elem(): model.CapabilityUpdateCourse,
+    reflect.TypeOf((*RequireViewQueue)(nil)).Elem(): model.CapabilityViewQueue,
+    reflect.TypeOf((*RequireManageAuthThrottles)(nil)).Elem(): model.CapabilityManageAuthThrottles,
+    reflect.TypeOf((*RequireViewAuditLog)(nil)).Elem(): model.CapabilityViewAuditLog,
+};
 
 // A request having a field of this type indicates that the users for the course should be automatically fetched.
 // The existence of this type in a struct also indicates that the request is at least a APIRequestCourseUserContext.
@@ -47,7 +72,7 @@ type TargetUser struct {
 // A request having a field of this type indicates that the request is targeting a specific user.
 // This type serializes to/from a string.
 // If no user is specified, then the context user is the target.
-// If a user is specified, then the context user must be a grader
+// If a user is specified, then the context user must have the view-submissions capability (graders have it by default)
 // (any user can acces their own resources, but higher permissions are required to access another user's resources).
 // No error is generated if the user is not found.
 // The existence of this type in a struct also indicates that the request is at least a APIRequestCourseUserContext.
@@ -55,7 +80,7 @@ type TargetUserSelfOrGrader struct {
     TargetUser
 }
 
-// Same as TargetUserSelfOrGrader, but for an admin context user.
+// Same as TargetUserSelfOrGrader, but requires the manage-users capability (admins have it by default).
 type TargetUserSelfOrAdmin struct {
     TargetUser
 }
@@ -150,14 +175,14 @@ func checkRequestTargetUser(endpoint string, apiRequest any, fieldIndex int) *AP
 }
 
 func checkRequestTargetUserSelfOrGrader(endpoint string, apiRequest any, fieldIndex int) *APIError {
-    return checkRequestTargetUserSelfOrRole(endpoint, apiRequest, fieldIndex, model.RoleGrader);
+    return checkRequestTargetUserSelfOrCapability(endpoint, apiRequest, fieldIndex, model.CapabilityViewSubmissions);
 }
 
 func checkRequestTargetUserSelfOrAdmin(endpoint string, apiRequest any, fieldIndex int) *APIError {
-    return checkRequestTargetUserSelfOrRole(endpoint, apiRequest, fieldIndex, model.RoleAdmin);
+    return checkRequestTargetUserSelfOrCapability(endpoint, apiRequest, fieldIndex, model.CapabilityManageUsers);
 }
 
-func checkRequestTargetUserSelfOrRole(endpoint string, apiRequest any, fieldIndex int, minRole model.UserRole) *APIError {
+func checkRequestTargetUserSelfOrCapability(endpoint string, apiRequest any, fieldIndex int, capability model.Capability) *APIError {
     courseContext, users, apiErr := baseCheckRequestUsersField(endpoint, apiRequest, fieldIndex);
     if (apiErr != nil) {
         return apiErr;
@@ -172,8 +197,8 @@ func checkRequestTargetUserSelfOrRole(endpoint string, apiRequest any, fieldInde
     }
 
     // Operations not on self require higher permissions.
-    if ((field.Email != courseContext.User.Email) && (courseContext.User.Role < minRole)) {
-        return NewBadPermissionsError("-033", courseContext, minRole, "Non-Self Target User");
+    if ((field.Email != courseContext.User.Email) && !courseContext.HasCapability(capability, getRequestAssignmentID(apiRequest))) {
+        return NewMissingCapabilityError("-033", courseContext, capability, "Non-Self Target User");
     }
 
     user := users[field.Email];
@@ -43,7 +43,7 @@ func TestBadUsersFieldNoContext(test *testing.T) {
 func TestBadUsersFieldNotExported(test *testing.T) {
     testCases := []struct{ request any;  }{
         {
-            &struct{ APIRequestCourseUserContext; MinRoleStudent; users CourseUsers }{
+            &struct{ APIRequestCourseUserContext; RequireSubmit; users CourseUsers }{
                 APIRequestCourseUserContext: APIRequestCourseUserContext{
                     CourseID: "course101",
                     UserEmail: "student@test.com",
@@ -52,7 +52,7 @@ func TestBadUsersFieldNotExported(test *testing.T) {
             },
         },
         {
-            &struct{ APIRequestCourseUserContext; MinRoleStudent; targetUser TargetUserSelfOrGrader }{
+            &struct{ APIRequestCourseUserContext; RequireSubmit; targetUser TargetUserSelfOrGrader }{
                 APIRequestCourseUserContext: APIRequestCourseUserContext{
                     CourseID: "course101",
                     UserEmail: "student@test.com",
@@ -61,7 +61,7 @@ func TestBadUsersFieldNotExported(test *testing.T) {
             },
         },
         {
-            &struct{ APIRequestCourseUserContext; MinRoleStudent; targetUser TargetUserSelfOrAdmin }{
+            &struct{ APIRequestCourseUserContext; RequireSubmit; targetUser TargetUserSelfOrAdmin }{
                 APIRequestCourseUserContext: APIRequestCourseUserContext{
                     CourseID: "course101",
                     UserEmail: "student@test.com",
@@ -129,7 +129,7 @@ func TestGoodPostFiles(test *testing.T) {
 
     type requestType struct {
         APIRequestCourseUserContext
-        MinRoleStudent
+        RequireSubmit
 
         Files POSTFiles
     }
@@ -183,7 +183,7 @@ func TestBadPostFilesFieldNotExported(test *testing.T) {
     // Files are not exported.
     type badRequestType struct {
         APIRequestCourseUserContext
-        MinRoleStudent
+        RequireSubmit
 
         files POSTFiles
     }
@@ -209,7 +209,7 @@ func TestBadPostFilesNoFiles(test *testing.T) {
 
     type requestType struct {
         APIRequestCourseUserContext
-        MinRoleStudent
+        RequireSubmit
 
         Files POSTFiles
     }
@@ -239,7 +239,7 @@ func TestBadPostFilesStoreFail(test *testing.T) {
 
     type requestType struct {
         APIRequestCourseUserContext
-        MinRoleStudent
+        RequireSubmit
 
         Files POSTFiles
     }
@@ -357,7 +357,7 @@ func TestTargetUserSelfOrGrader(test *testing.T) {
 
 type testTargetUserSelfOrGraderRequestType struct {
     APIRequestCourseUserContext
-    MinRoleOther
+    RequireNone
 
     User TargetUserSelfOrGrader
 }
@@ -395,7 +395,7 @@ func TestTargetUserSelfOrAdmin(test *testing.T) {
 
 type testTargetUserSelfOrAdminRequestType struct {
     APIRequestCourseUserContext
-    MinRoleOther
+    RequireNone
 
     User TargetUserSelfOrAdmin
 }
@@ -475,7 +475,7 @@ func testTargetUser[T comparable, V userGetter](test *testing.T,
 func TestTargetUser(test *testing.T) {
     type requestType struct {
         APIRequestCourseUserContext
-        MinRoleOther
+        RequireNone
 
         User TargetUser
     }
@@ -2,6 +2,7 @@ package core
 
 import (
     "fmt"
+    "slices"
     "testing"
 
     "github.com/eriq-augustine/autograder/model"
@@ -52,41 +53,31 @@ func TestInvalidJSON(test *testing.T) {
     }
 }
 
-func TestGetMaxRole(test *testing.T) {
-    testCases := []struct{value any; role model.UserRole}{
-        {struct{}{}, model.RoleUnknown},
-        {struct{int}{}, model.RoleUnknown},
-
-        {struct{MinRoleOwner}{}, model.RoleOwner},
-        {struct{MinRoleAdmin}{}, model.RoleAdmin},
-        {struct{MinRoleGrader}{}, model.RoleGrader},
-        {struct{MinRoleStudent}{}, model.RoleStudent},
-        {struct{MinRoleOther}{}, model.RoleOther},
-
-        {struct{MinRoleOwner; MinRoleOther}{}, model.RoleOwner},
-        {struct{MinRoleAdmin; MinRoleOther}{}, model.RoleAdmin},
-        {struct{MinRoleGrader; MinRoleOther}{}, model.RoleGrader},
-        {struct{MinRoleStudent; MinRoleOther}{}, model.RoleStudent},
-
-        {struct{MinRoleOther; MinRoleOwner}{}, model.RoleOwner},
-        {struct{MinRoleOther; MinRoleAdmin}{}, model.RoleAdmin},
-        {struct{MinRoleOther; MinRoleGrader}{}, model.RoleGrader},
-        {struct{MinRoleOther; MinRoleStudent}{}, model.RoleStudent},
+func TestGetRequiredCapabilities(test *testing.T) {
+    testCases := []struct{value any; found bool; capabilities []model.Capability}{
+        {struct{}{}, false, []model.Capability{}},
+        {struct{int}{}, false, []model.Capability{}},
+
+        {struct{RequireNone}{}, true, []model.Capability{}},
+        {struct{RequireSubmit}{}, true, []model.Capability{model.CapabilitySubmit}},
+        {struct{RequireViewSubmissions}{}, true, []model.Capability{model.CapabilityViewSubmissions}},
+        {&struct{RequireRegrade}{}, true, []model.Capability{model.CapabilityRegrade}},
+
+        {struct{RequireNone; RequireSubmit}{}, true, []model.Capability{model.CapabilitySubmit}},
+        {struct{RequireViewUsers; RequireUploadLMSScores}{}, true,
+                []model.Capability{model.CapabilityViewUsers, model.CapabilityUploadLMSScores}},
     };
 
     for i, testCase := range testCases {
-        role, hasRole := getMaxRole(testCase.value);
-
-        if (testCase.role == model.RoleUnknown) {
-            if (hasRole) {
-                test.Errorf("Case %d: Found a role ('%s') when none was specified.", i, role);
-            }
+        capabilities, found := getRequiredCapabilities(testCase.value);
 
+        if (found != testCase.found) {
+            test.Errorf("Case %d: Found mismatch. Expected: '%v', Actual: '%v'.", i, testCase.found, found);
             continue;
         }
 
-        if (role != testCase.role) {
-            test.Errorf("Case %d: Role mismatch. Expected: '%s', Actual: '%s'.", i, testCase.role, role);
+        if (!slices.Equal(testCase.capabilities, capabilities)) {
+            test.Errorf("Case %d: Capabilities mismatch. Expected: '%v', Actual: '%v'.", i, testCase.capabilities, capabilities);
         }
     }
 }
@@ -128,7 +119,7 @@ type baseAPIRequestTestCase struct {
 
 type baseCourseUserAPIRequest struct {
     APIRequestCourseUserContext
-    MinRoleStudent
+    RequireSubmit
     testValues
 }
 
@@ -138,7 +129,7 @@ func (this *baseCourseUserAPIRequest) GetTestValues() testValues {
 
 type baseAssignmentAPIRequest struct {
     APIRequestAssignmentContext
-    MinRoleStudent
+    RequireSubmit
     testValues
 }
 
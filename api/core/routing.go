@@ -4,15 +4,18 @@ package core
 // mostly API requests.
 
 import (
+    "errors"
     "fmt"
     "net/http"
     "reflect"
     "regexp"
     "runtime"
     "strings"
+    "time"
 
     "github.com/rs/zerolog/log"
 
+    "github.com/eriq-augustine/autograder/metrics"
     "github.com/eriq-augustine/autograder/util"
 )
 
@@ -36,12 +39,16 @@ const API_REQUEST_CONTENT_KEY = "content";
 // Inspired by https://benhoyt.com/writings/go-routing/
 type Route struct {
     method string
+    pattern string
     regex *regexp.Regexp
     handler RouteHandler
 }
 
 const MAX_FORM_MEM_SIZE_BYTES = 10 << 20  // 20 MB
 
+// The endpoint label used in metrics for requests that do not match any route.
+const UNMATCHED_ROUTE_LABEL = "<unmatched>";
+
 // Get a function to pass to http.HandlerFunc().
 func GetRouteServer(routes *[]*Route) http.HandlerFunc {
     return func(response http.ResponseWriter, request *http.Request) {
@@ -55,8 +62,19 @@ func ServeRoutes(routes *[]*Route, response http.ResponseWriter, request *http.R
         Str("url", request.URL.Path).
         Msg("Incoming Request");
 
+    startTime := time.Now();
+    recorder := &statusRecorder{ResponseWriter: response, status: http.StatusOK};
+    response = recorder;
+
+    // Label metrics with the route's pattern (instead of the full path) to keep the number of labels bounded.
+    endpoint := UNMATCHED_ROUTE_LABEL;
+    defer func() {
+        metrics.ObserveAPIRequest(endpoint, request.Method, recorder.status, time.Since(startTime));
+    }();
+
     if (routes == nil) {
         http.NotFound(response, request);
+        return;
     }
 
     var i int;
@@ -66,6 +84,7 @@ func ServeRoutes(routes *[]*Route, response http.ResponseWriter, request *http.R
     for i, route = range *routes {
         if (route == nil) {
             log.Warn().Int("index", i).Msg("Found nil route.");
+            continue;
         }
 
         if (route.method != request.Method) {
@@ -77,6 +96,8 @@ func ServeRoutes(routes *[]*Route, response http.ResponseWriter, request *http.R
             continue;
         }
 
+        endpoint = route.pattern;
+
         err := route.handler(response, request);
         if (err != nil) {
             log.Error().Err(err).Str("path", request.URL.Path).Msg("Handler had an error.");
@@ -89,8 +110,36 @@ func ServeRoutes(routes *[]*Route, response http.ResponseWriter, request *http.R
     http.NotFound(response, request);
 }
 
+// Records the status code written to a response (for metrics).
+type statusRecorder struct {
+    http.ResponseWriter
+    status int
+    wroteHeader bool
+}
+
+func (this *statusRecorder) WriteHeader(status int) {
+    if (!this.wroteHeader) {
+        this.status = status;
+        this.wroteHeader = true;
+    }
+
+    this.ResponseWriter.WriteHeader(status);
+}
+
+// Streaming responses need to be able to flush.
+func (this *statusRecorder) Flush() {
+    flusher, ok := this.ResponseWriter.(http.Flusher);
+    if (ok) {
+        flusher.Flush();
+    }
+}
+
+func (this *statusRecorder) Unwrap() http.ResponseWriter {
+    return this.ResponseWriter;
+}
+
 func NewRoute(method string, pattern string, handler RouteHandler) *Route {
-    return &Route{method, regexp.MustCompile("^" + pattern + "$"), handler};
+    return &Route{method, pattern, regexp.MustCompile("^" + pattern + "$"), handler};
 }
 
 func NewRedirect(method string, pattern string, target string) *Route {
@@ -98,7 +147,7 @@ func NewRedirect(method string, pattern string, target string) *Route {
         return handleRedirect(target, response, request);
     };
 
-    return &Route{method, regexp.MustCompile("^" + pattern + "$"), redirectFunc};
+    return &Route{method, pattern, regexp.MustCompile("^" + pattern + "$"), redirectFunc};
 }
 
 func NewAPIRoute(pattern string, apiHandler any) *Route {
@@ -123,7 +172,7 @@ func NewAPIRoute(pattern string, apiHandler any) *Route {
         return err;
     }
 
-    return &Route{"POST", regexp.MustCompile("^" + pattern + "$"), handler};
+    return &Route{"POST", pattern, regexp.MustCompile("^" + pattern + "$"), handler};
 }
 
 func handleRedirect(target string, response http.ResponseWriter, request *http.Request) error {
@@ -147,6 +196,7 @@ func handleAPIEndpoint(response http.ResponseWriter, request *http.Request, apiH
 
     // Execute the handler.
     apiResponse, apiErr := callHandler(apiHandler, apiRequest);
+    auditAPIRequest(request, apiRequest, apiErr);
 
     return sendAPIResponse(apiRequest, response, apiResponse, apiErr, false);
 }
@@ -206,14 +256,23 @@ func createAPIRequest(request *http.Request, apiHandler ValidAPIHandler) (ValidA
         return nil, apiErr;
     }
 
-    // If this request is multipart, then parse the form.
+    // Parse the form (multipart if necessary).
+    var err error;
     if (strings.Contains(strings.Join(request.Header["Content-Type"], " "), "multipart/form-data")) {
-        err := request.ParseMultipartForm(MAX_FORM_MEM_SIZE_BYTES);
-        if (err != nil) {
-            return nil, NewBareBadRequestError("-003", endpoint,
-                    fmt.Sprintf("POST request is improperly formatted.")).
-                    Err(err);
+        err = request.ParseMultipartForm(MAX_FORM_MEM_SIZE_BYTES);
+    } else {
+        err = request.ParseForm();
+    }
+
+    if (err != nil) {
+        var maxBytesErr *http.MaxBytesError;
+        if (errors.As(err, &maxBytesErr)) {
+            return nil, NewRequestTooLargeError("-053", endpoint, maxBytesErr.Limit).Err(err);
         }
+
+        return nil, NewBareBadRequestError("-003", endpoint,
+                fmt.Sprintf("POST request is improperly formatted.")).
+                Err(err);
     }
 
     // Get the text from the POST.
@@ -224,7 +283,7 @@ func createAPIRequest(request *http.Request, apiHandler ValidAPIHandler) (ValidA
     }
 
     // Unmarshal the JSON.
-    err := util.JSONFromString(textContent, apiRequest);
+    err = util.JSONFromString(textContent, apiRequest);
     if (err != nil) {
         return nil, NewBareBadRequestError("-005", endpoint,
                 fmt.Sprintf("JSON payload for POST form key '%s' is not valid JSON.", API_REQUEST_CONTENT_KEY)).
@@ -234,6 +293,10 @@ func createAPIRequest(request *http.Request, apiHandler ValidAPIHandler) (ValidA
     // Validate the request.
     apiErr = ValidateAPIRequest(request, apiRequest, endpoint);
     if (apiErr != nil) {
+        if (isAccessDeniedError(apiErr)) {
+            auditAPIRequest(request, apiRequest, apiErr);
+        }
+
         return nil, apiErr;
     }
 
@@ -3,6 +3,10 @@ package core
 import (
     "fmt"
     "math"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strings"
     "testing"
 
     "github.com/eriq-augustine/autograder/common"
@@ -12,7 +16,7 @@ import (
 // The most simple authenticating request.
 type BaseTestRequest struct {
     APIRequestCourseUserContext
-    MinRoleStudent
+    RequireSubmit
 }
 
 // Force a panic from an API handler.
@@ -125,3 +129,35 @@ func TestNonMarshalableResponse(test *testing.T) {
         test.Fatalf("Response does not locator of '-531', actual locator: '%s'.", response.Locator);
     }
 }
+
+// Send a request larger than the server's body limit.
+func TestRequestTooLarge(test *testing.T) {
+    endpoint := `/test/api/bad-request/too-large`;
+    handler := func(request *BaseTestRequest) (*any, *APIError) { return nil, nil };
+    routes = append(routes, NewAPIRoute(endpoint, handler));
+
+    server := http.MaxBytesHandler(GetRouteServer(&routes), 16);
+
+    form := url.Values{};
+    form.Set(API_REQUEST_CONTENT_KEY, strings.Repeat("Z", 32));
+
+    request := httptest.NewRequest("POST", endpoint, strings.NewReader(form.Encode()));
+    request.Header.Set("Content-Type", "application/x-www-form-urlencoded");
+
+    recorder := httptest.NewRecorder();
+    server.ServeHTTP(recorder, request);
+
+    if (recorder.Code != HTTP_STATUS_REQUEST_TOO_LARGE) {
+        test.Fatalf("Unexpected status code. Expected: %d, Actual: %d.", HTTP_STATUS_REQUEST_TOO_LARGE, recorder.Code);
+    }
+
+    var response APIResponse;
+    err := util.JSONFromString(recorder.Body.String(), &response);
+    if (err != nil) {
+        test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", recorder.Body.String(), err);
+    }
+
+    if (response.Locator != "-053") {
+        test.Fatalf("Expected response locator of '-053', found response locator of '%s'. Response: [%v]", response.Locator, response);
+    }
+}
@@ -0,0 +1,168 @@
+package core
+
+// Support for API endpoints that stream their response as Server-Sent Events.
+// Streaming endpoints take the same POST requests (and go through the same validation) as normal API endpoints.
+// Any error before the first event is sent will be returned as a normal API response.
+
+import (
+    "fmt"
+    "net/http"
+    "reflect"
+    "regexp"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/util"
+)
+
+// A handler for streaming API endpoints.
+// The handler should take in an APIRequest derived type and an *EventStream.
+// Like APIHandler, this alias is not actually used and just shows the structure.
+type APIStreamHandler func(*any, *EventStream) *APIError;
+
+// Event name for errors that happen after the stream has started.
+const STREAM_EVENT_ERROR = "error";
+
+type EventStream struct {
+    response http.ResponseWriter
+    request *http.Request
+    started bool
+}
+
+// Send an event to the client.
+// The data will be encoded as JSON.
+func (this *EventStream) Send(event string, data any) error {
+    payload, err := util.ToJSON(data);
+    if (err != nil) {
+        return fmt.Errorf("Could not serialize '%s' event: '%w'.", event, err);
+    }
+
+    if (!this.started) {
+        this.response.Header().Set("Content-Type", "text/event-stream");
+        this.response.Header().Set("Cache-Control", "no-cache");
+        this.response.WriteHeader(HTTP_STATUS_GOOD);
+        this.started = true;
+    }
+
+    _, err = fmt.Fprintf(this.response, "event: %s\ndata: %s\n\n", event, payload);
+    if (err != nil) {
+        return fmt.Errorf("Could not write '%s' event: '%w'.", event, err);
+    }
+
+    flusher, ok := this.response.(http.Flusher);
+    if (ok) {
+        flusher.Flush();
+    }
+
+    return nil;
+}
+
+// Closed when the client goes away.
+func (this *EventStream) Done() <-chan struct{} {
+    return this.request.Context().Done();
+}
+
+func NewAPIStreamRoute(pattern string, apiHandler any) *Route {
+    handler := func(response http.ResponseWriter, request *http.Request) (err error) {
+        stream := &EventStream{
+            response: response,
+            request: request,
+        };
+
+        // Recover from any panic.
+        defer func() {
+            value := recover();
+            if (value == nil) {
+                return;
+            }
+
+            log.Error().Any("value", value).Str("endpoint", request.URL.Path).
+                    Msg("Recovered from a panic when handling a streaming API endpoint.");
+            apiErr := NewBareInternalError("-036", request.URL.Path, "Recovered from a panic when handling a streaming API endpoint.").
+                    Add("value", value);
+
+            err = sendStreamError(nil, stream, apiErr);
+        }();
+
+        err = handleAPIStreamEndpoint(stream, apiHandler);
+
+        return err;
+    }
+
+    return &Route{"POST", pattern, regexp.MustCompile("^" + pattern + "$"), handler};
+}
+
+func handleAPIStreamEndpoint(stream *EventStream, apiHandler any) error {
+    request := stream.request;
+
+    // Ensure the handler looks good.
+    validAPIHandler, apiErr := validateAPIStreamHandler(request.URL.Path, apiHandler);
+    if (apiErr != nil) {
+        return sendAPIResponse(nil, stream.response, nil, apiErr, false);
+    }
+
+    // Get the actual request.
+    apiRequest, apiErr := createAPIRequest(request, validAPIHandler);
+    if (apiErr != nil) {
+        return sendAPIResponse(nil, stream.response, nil, apiErr, false);
+    }
+    defer CleanupAPIrequest(apiRequest);
+
+    // Execute the handler.
+    input := []reflect.Value{reflect.ValueOf(apiRequest), reflect.ValueOf(stream)};
+    output := reflect.ValueOf(apiHandler).Call(input);
+
+    apiErr = output[0].Interface().(*APIError);
+    auditAPIRequest(request, apiRequest, apiErr);
+
+    if (apiErr != nil) {
+        return sendStreamError(apiRequest, stream, apiErr);
+    }
+
+    return nil;
+}
+
+// Send an error as a normal response if the stream has not started,
+// or as an error event if it has.
+func sendStreamError(apiRequest ValidAPIRequest, stream *EventStream, apiErr *APIError) error {
+    if (!stream.started) {
+        return sendAPIResponse(apiRequest, stream.response, nil, apiErr, false);
+    }
+
+    apiErr.Log();
+
+    return stream.Send(STREAM_EVENT_ERROR, apiErr.ToResponse());
+}
+
+// Reflexively ensure that the api handler looks like APIStreamHandler.
+func validateAPIStreamHandler(endpoint string, apiHandler any) (ValidAPIHandler, *APIError) {
+    reflectValue := reflect.ValueOf(apiHandler);
+    reflectType := reflect.TypeOf(apiHandler);
+
+    if (reflectValue.Kind() != reflect.Func) {
+        return nil, NewBareInternalError("-037", endpoint, "API stream handler is not a function.").
+                Add("kind", reflectValue.Kind().String());
+    }
+
+    funcInfo := getFuncInfo(apiHandler);
+
+    if ((reflectType.NumIn() != 2) || (reflectType.In(0).Kind() != reflect.Pointer)) {
+        return nil, NewBareInternalError("-038", endpoint, "API stream handler does not take a request pointer and a stream.").
+                Add("num-in", reflectType.NumIn()).
+                Add("function-info", funcInfo);
+    }
+
+    if (reflectType.In(1) != reflect.TypeOf((*EventStream)(nil))) {
+        return nil, NewBareInternalError("-039", endpoint, "API stream handler's second argument is not a *EventStream.").
+                Add("type", reflectType.In(1).String()).
+                Add("function-info", funcInfo);
+    }
+
+    if ((reflectType.NumOut() != 1) || (reflectType.Out(0) != reflect.TypeOf((*APIError)(nil)))) {
+        return nil, NewBareInternalError("-040", endpoint, "API stream handler does not return exactly one *APIError.").
+                Add("num-out", reflectType.NumOut()).
+                Add("function-info", funcInfo);
+    }
+
+    return ValidAPIHandler(apiHandler), nil;
+}
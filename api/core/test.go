@@ -1,8 +1,12 @@
 package core
 
 import (
+    "io"
+    "net/http"
     "net/http/httptest"
+    "net/url"
     "os"
+    "strings"
     "testing"
 
     "github.com/eriq-augustine/autograder/common"
@@ -33,6 +37,11 @@ func stopTestServer() {
     }
 }
 
+// Get the base URL of the running test server.
+func GetTestServerURL() string {
+    return serverURL;
+}
+
 // Common setup for all API tests.
 func APITestingMain(suite *testing.M, routes *[]*Route) {
     // Run inside a func so defers will run before os.Exit().
@@ -42,6 +51,10 @@ func APITestingMain(suite *testing.M, routes *[]*Route) {
 
         config.NO_AUTH.Set(false);
 
+        // Tests make a lot of bad requests on purpose, throttling is tested explicitly.
+        config.AUTH_THROTTLE_USER_FAILURES.Set(0);
+        config.AUTH_THROTTLE_IP_FAILURES.Set(0);
+
         startTestServer(routes);
         defer stopTestServer();
 
@@ -61,7 +74,80 @@ func SendTestAPIRequest(test *testing.T, endpoint string, fields map[string]any)
 // The given role will choose the user (the test course has one user per role).
 func SendTestAPIRequestFull(test *testing.T, endpoint string, fields map[string]any, paths []string, role model.UserRole) *APIResponse {
     url := serverURL + endpoint;
+    form := getTestRequestForm(fields, role);
+
+    var responseText string;
+    var err error;
+
+    if (len(paths) == 0) {
+        responseText, err = common.PostNoCheck(url, form);
+    } else {
+        responseText, err = common.PostFiles(url, form, paths, false);
+    }
+
+    if (err != nil) {
+        test.Fatalf("API POST returned an error: '%v'.", err);
+    }
+
+    var response APIResponse;
+    err = util.JSONFromString(responseText, &response);
+    if (err != nil) {
+        test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", responseText, err);
+    }
+
+    return &response;
+}
+
+type TestStreamEvent struct {
+    Event string
+    // The raw JSON data.
+    Data string
+}
+
+// Make a request to a streaming endpoint on the test server (see SendTestAPIRequestFull()),
+// and wait for the stream to finish.
+// If the stream never started (e.g. there was a validation error), then the normal API response will be returned instead of events.
+func SendTestAPIStreamRequest(test *testing.T, endpoint string, fields map[string]any, role model.UserRole) ([]*TestStreamEvent, *APIResponse) {
+    url := serverURL + endpoint;
+    form := getTestRequestForm(fields, role);
+
+    responseText, headers, err := common.PostWithHeadersNoCheck(url, form, make(map[string][]string));
+    if (err != nil) {
+        test.Fatalf("API POST returned an error: '%v'.", err);
+    }
+
+    if (!strings.Contains(strings.Join(headers["Content-Type"], " "), "text/event-stream")) {
+        var response APIResponse;
+        err = util.JSONFromString(responseText, &response);
+        if (err != nil) {
+            test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", responseText, err);
+        }
+
+        return nil, &response;
+    }
 
+    events := make([]*TestStreamEvent, 0);
+    for _, block := range strings.Split(responseText, "\n\n") {
+        if (strings.TrimSpace(block) == "") {
+            continue;
+        }
+
+        event := TestStreamEvent{};
+        for _, line := range strings.Split(block, "\n") {
+            if (strings.HasPrefix(line, "event: ")) {
+                event.Event = strings.TrimPrefix(line, "event: ");
+            } else if (strings.HasPrefix(line, "data: ")) {
+                event.Data = strings.TrimPrefix(line, "data: ");
+            }
+        }
+
+        events = append(events, &event);
+    }
+
+    return events, nil;
+}
+
+func getTestRequestForm(fields map[string]any, role model.UserRole) map[string]string {
     email := model.GetRoleString(role) + "@test.com";
     pass := util.Sha256HexFromString(model.GetRoleString(role));
 
@@ -76,28 +162,73 @@ func SendTestAPIRequestFull(test *testing.T, endpoint string, fields map[string]
         content[key] = value;
     }
 
-    form := map[string]string{
+    return map[string]string{
         API_REQUEST_CONTENT_KEY: util.MustToJSON(content),
     };
+}
 
-    var responseText string;
-    var err error;
+// Make a GET request to a browser endpoint on the test server (following any redirects),
+// and parse the final API response.
+func SendTestBrowserRequest(test *testing.T, endpoint string, query map[string]string) *APIResponse {
+    values := url.Values{};
+    for key, value := range query {
+        values.Set(key, value);
+    }
 
-    if (len(paths) == 0) {
-        responseText, err = common.PostNoCheck(url, form);
-    } else {
-        responseText, err = common.PostFiles(url, form, paths, false);
+    uri := serverURL + endpoint;
+    if (len(values) > 0) {
+        uri += "?" + values.Encode();
     }
 
+    response, err := http.Get(uri);
     if (err != nil) {
-        test.Fatalf("API POST returned an error: '%v'.", err);
+        test.Fatalf("Browser GET returned an error: '%v'.", err);
     }
+    defer response.Body.Close();
 
-    var response APIResponse;
-    err = util.JSONFromString(responseText, &response);
+    body, err := io.ReadAll(response.Body);
     if (err != nil) {
-        test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", responseText, err);
+        test.Fatalf("Failed to read browser response: '%v'.", err);
     }
 
-    return &response;
+    var apiResponse APIResponse;
+    err = util.JSONFromString(string(body), &apiResponse);
+    if (err != nil) {
+        test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", string(body), err);
+    }
+
+    return &apiResponse;
+}
+
+// Post a form to a browser endpoint without following redirects.
+// Returns the redirect location (if the response was a redirect) or the parsed API response (otherwise).
+func SendTestBrowserForm(test *testing.T, endpoint string, form url.Values) (string, *APIResponse) {
+    client := &http.Client{
+        CheckRedirect: func(request *http.Request, via []*http.Request) error {
+            return http.ErrUseLastResponse;
+        },
+    };
+
+    response, err := client.PostForm(serverURL + endpoint, form);
+    if (err != nil) {
+        test.Fatalf("Browser POST returned an error: '%v'.", err);
+    }
+    defer response.Body.Close();
+
+    if ((response.StatusCode >= 300) && (response.StatusCode < 400)) {
+        return response.Header.Get("Location"), nil;
+    }
+
+    body, err := io.ReadAll(response.Body);
+    if (err != nil) {
+        test.Fatalf("Failed to read browser response: '%v'.", err);
+    }
+
+    var apiResponse APIResponse;
+    err = util.JSONFromString(string(body), &apiResponse);
+    if (err != nil) {
+        test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", string(body), err);
+    }
+
+    return "", &apiResponse;
 }
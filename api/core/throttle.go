@@ -0,0 +1,354 @@
+package core
+
+// Throttling for failed authentication attempts.
+// Failed passwords are counted per user (email), and all failures are counted per client IP address.
+// Once a counter reaches its threshold, further password attempts are locked out for a time that doubles with each additional failure.
+// Counters are only held in memory, and are forgotten (and periodically pruned) after a period without failures.
+
+import (
+    "math"
+    "net"
+    "net/http"
+    "net/netip"
+    "reflect"
+    "slices"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/config"
+)
+
+const (
+    AUTH_THROTTLE_KIND_USER = "user";
+    AUTH_THROTTLE_KIND_IP = "ip";
+)
+
+const AUTH_THROTTLE_PRUNE_INTERVAL = 5 * time.Minute;
+
+type AuthThrottleRecord struct {
+    // "<kind>:<value>", used to clear the record.
+    Key string `json:"key"`
+    Kind string `json:"kind"`
+    Value string `json:"value"`
+    Failures int `json:"failures"`
+    LastFailure common.Timestamp `json:"last-failure"`
+    // Empty if not locked.
+    LockedUntil common.Timestamp `json:"locked-until"`
+}
+
+type authThrottleEntry struct {
+    kind string
+    value string
+    failures int
+    lastFailure time.Time
+    lockedUntil time.Time
+}
+
+var authThrottles map[string]*authThrottleEntry = make(map[string]*authThrottleEntry);
+var authThrottlesLock sync.Mutex;
+var authThrottlesPruning bool = false;
+
+func AuthThrottleKey(kind string, value string) string {
+    return kind + ":" + value;
+}
+
+// Check if a user or IP is currently locked out.
+// Returns: (the time the lockout ends, is locked).
+func checkAuthThrottle(email string, ip string) (time.Time, bool) {
+    authThrottlesLock.Lock();
+    defer authThrottlesLock.Unlock();
+
+    now := time.Now();
+    lockedUntil := time.Time{};
+
+    for _, key := range getAuthThrottleKeys(email, ip) {
+        entry := getAuthThrottleEntry(key, now);
+        if ((entry != nil) && now.Before(entry.lockedUntil) && entry.lockedUntil.After(lockedUntil)) {
+            lockedUntil = entry.lockedUntil;
+        }
+    }
+
+    return lockedUntil, !lockedUntil.IsZero();
+}
+
+func recordAuthFailure(email string, ip string) {
+    authThrottlesLock.Lock();
+    defer authThrottlesLock.Unlock();
+
+    if (!authThrottlesPruning) {
+        go pruneAuthThrottlesLoop();
+        authThrottlesPruning = true;
+    }
+
+    now := time.Now();
+
+    thresholds := map[string]int{
+        AUTH_THROTTLE_KIND_USER: config.AUTH_THROTTLE_USER_FAILURES.Get(),
+        AUTH_THROTTLE_KIND_IP: config.AUTH_THROTTLE_IP_FAILURES.Get(),
+    };
+
+    values := map[string]string{
+        AUTH_THROTTLE_KIND_USER: email,
+        AUTH_THROTTLE_KIND_IP: ip,
+    };
+
+    for kind, value := range values {
+        threshold := thresholds[kind];
+        if ((value == "") || (threshold <= 0)) {
+            continue;
+        }
+
+        key := AuthThrottleKey(kind, value);
+
+        entry := getAuthThrottleEntry(key, now);
+        if (entry == nil) {
+            entry = &authThrottleEntry{kind: kind, value: value};
+            authThrottles[key] = entry;
+        }
+
+        entry.failures++;
+        entry.lastFailure = now;
+
+        if (entry.failures >= threshold) {
+            entry.lockedUntil = now.Add(getAuthLockoutDuration(entry.failures - threshold));
+
+            log.Warn().Str("kind", kind).Str("value", value).Int("failures", entry.failures).
+                    Time("locked-until", entry.lockedUntil).Msg("Authentication locked out.");
+        }
+    }
+}
+
+// A successful authentication clears the user's failures (but not the IP's).
+func recordAuthSuccess(email string) {
+    authThrottlesLock.Lock();
+    defer authThrottlesLock.Unlock();
+
+    delete(authThrottles, AuthThrottleKey(AUTH_THROTTLE_KIND_USER, email));
+}
+
+// Get all the current throttle records (ordered by key).
+func GetAuthThrottles() []*AuthThrottleRecord {
+    authThrottlesLock.Lock();
+    defer authThrottlesLock.Unlock();
+
+    now := time.Now();
+    records := make([]*AuthThrottleRecord, 0, len(authThrottles));
+
+    for key, _ := range authThrottles {
+        entry := getAuthThrottleEntry(key, now);
+        if (entry == nil) {
+            continue;
+        }
+
+        record := &AuthThrottleRecord{
+            Key: key,
+            Kind: entry.kind,
+            Value: entry.value,
+            Failures: entry.failures,
+            LastFailure: common.TimestampFromTime(entry.lastFailure),
+        };
+
+        if (now.Before(entry.lockedUntil)) {
+            record.LockedUntil = common.TimestampFromTime(entry.lockedUntil);
+        }
+
+        records = append(records, record);
+    }
+
+    slices.SortFunc(records, func(a *AuthThrottleRecord, b *AuthThrottleRecord) int {
+        return strings.Compare(a.Key, b.Key);
+    });
+
+    return records;
+}
+
+// Clear the given throttle records.
+// Returns the keys that existed and were cleared.
+func ClearAuthThrottles(keys []string) []string {
+    authThrottlesLock.Lock();
+    defer authThrottlesLock.Unlock();
+
+    cleared := make([]string, 0, len(keys));
+    for _, key := range keys {
+        _, ok := authThrottles[key];
+        if (!ok) {
+            continue;
+        }
+
+        delete(authThrottles, key);
+        cleared = append(cleared, key);
+    }
+
+    return cleared;
+}
+
+// Clear all throttle records.
+func ResetAuthThrottles() {
+    authThrottlesLock.Lock();
+    defer authThrottlesLock.Unlock();
+
+    authThrottles = make(map[string]*authThrottleEntry);
+}
+
+func pruneAuthThrottlesLoop() {
+    ticker := time.NewTicker(AUTH_THROTTLE_PRUNE_INTERVAL);
+    defer ticker.Stop();
+
+    for range ticker.C {
+        authThrottlesLock.Lock();
+        pruneAuthThrottles(time.Now());
+        authThrottlesLock.Unlock();
+    }
+}
+
+// Drop all the entries whose failures are old enough to be forgotten.
+// The caller should already hold the lock.
+func pruneAuthThrottles(now time.Time) {
+    for key, _ := range authThrottles {
+        getAuthThrottleEntry(key, now);
+    }
+}
+
+// Get an entry, dropping it if its failures are old enough to be forgotten.
+// The caller should already hold the lock.
+func getAuthThrottleEntry(key string, now time.Time) *authThrottleEntry {
+    entry := authThrottles[key];
+    if (entry == nil) {
+        return nil;
+    }
+
+    resetDuration := time.Duration(config.AUTH_THROTTLE_RESET_MINS.Get()) * time.Minute;
+    if (now.Before(entry.lockedUntil) || (now.Sub(entry.lastFailure) < resetDuration)) {
+        return entry;
+    }
+
+    delete(authThrottles, key);
+    return nil;
+}
+
+func getAuthThrottleKeys(email string, ip string) []string {
+    keys := make([]string, 0, 2);
+
+    if (email != "") {
+        keys = append(keys, AuthThrottleKey(AUTH_THROTTLE_KIND_USER, email));
+    }
+
+    if (ip != "") {
+        keys = append(keys, AuthThrottleKey(AUTH_THROTTLE_KIND_IP, ip));
+    }
+
+    return keys;
+}
+
+// The lockout for the nth failure past the threshold (starting at 0).
+func getAuthLockoutDuration(extraFailures int) time.Duration {
+    base := float64(config.AUTH_THROTTLE_BASE_SECS.Get());
+    max := float64(config.AUTH_THROTTLE_MAX_SECS.Get());
+
+    seconds := math.Min(base * math.Pow(2, float64(extraFailures)), max);
+
+    return time.Duration(seconds) * time.Second;
+}
+
+// Get the client's IP address.
+// X-Forwarded-For is only used when the connection comes from a trusted proxy (see web.proxies.trusted),
+// and then the right-most address that is not a trusted proxy is used
+// (anything further left could have been sent by the client).
+func getClientIP(request *http.Request) string {
+    ip := request.RemoteAddr;
+
+    host, _, err := net.SplitHostPort(request.RemoteAddr);
+    if (err == nil) {
+        ip = host;
+    }
+
+    proxies := getTrustedProxies();
+    if (!isTrustedProxy(ip, proxies)) {
+        return ip;
+    }
+
+    addresses := strings.Split(strings.Join(request.Header.Values("X-Forwarded-For"), ","), ",");
+    for i := len(addresses) - 1; i >= 0; i-- {
+        address := strings.TrimSpace(addresses[i]);
+        if (address == "") {
+            continue;
+        }
+
+        ip = address;
+        if (!isTrustedProxy(ip, proxies)) {
+            break;
+        }
+    }
+
+    return ip;
+}
+
+// Parse the trusted proxies (IP addresses or CIDR ranges) from the config.
+// Invalid entries are logged and skipped.
+func getTrustedProxies() []netip.Prefix {
+    proxies := make([]netip.Prefix, 0);
+
+    for _, entry := range strings.Split(config.WEB_TRUSTED_PROXIES.Get(), ",") {
+        entry = strings.TrimSpace(entry);
+        if (entry == "") {
+            continue;
+        }
+
+        if (strings.Contains(entry, "/")) {
+            prefix, err := netip.ParsePrefix(entry);
+            if (err != nil) {
+                log.Warn().Err(err).Str("proxy", entry).Msg("Invalid trusted proxy range.");
+                continue;
+            }
+
+            proxies = append(proxies, prefix.Masked());
+            continue;
+        }
+
+        address, err := netip.ParseAddr(entry);
+        if (err != nil) {
+            log.Warn().Err(err).Str("proxy", entry).Msg("Invalid trusted proxy address.");
+            continue;
+        }
+
+        address = address.Unmap();
+        proxies = append(proxies, netip.PrefixFrom(address, address.BitLen()));
+    }
+
+    return proxies;
+}
+
+func isTrustedProxy(ip string, proxies []netip.Prefix) bool {
+    address, err := netip.ParseAddr(ip);
+    if (err != nil) {
+        return false;
+    }
+
+    address = address.Unmap();
+
+    for _, proxy := range proxies {
+        if (proxy.Contains(address)) {
+            return true;
+        }
+    }
+
+    return false;
+}
+
+// Set the client IP on a request (before it is validated).
+func setRequestClientIP(request *http.Request, apiRequest any) {
+    if (request == nil) {
+        return;
+    }
+
+    field := reflect.ValueOf(apiRequest).Elem().FieldByName("ClientIP");
+    if (!field.IsValid() || !field.CanSet() || (field.Kind() != reflect.String)) {
+        return;
+    }
+
+    field.SetString(getClientIP(request));
+}
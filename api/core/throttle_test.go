@@ -0,0 +1,319 @@
+package core
+
+import (
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestAuthThrottleUser(test *testing.T) {
+    defer setAuthThrottleConfig(0, 0);
+    defer ResetAuthThrottles();
+
+    ResetAuthThrottles();
+    setAuthThrottleConfig(3, 0);
+
+    for i := 0; i < 3; i++ {
+        apiErr := validateThrottleTestRequest("student@test.com", "Zstudent", "1.2.3.4");
+        if ((apiErr == nil) || (apiErr.Locator != "-014")) {
+            test.Fatalf("Attempt %d: Expected a bad password, got: '%v'.", i, apiErr);
+        }
+    }
+
+    // Now even the correct password is locked out (from any IP).
+    apiErr := validateThrottleTestRequest("student@test.com", "student", "5.6.7.8");
+    if ((apiErr == nil) || (apiErr.Locator != "-052") || (apiErr.HTTPStatus != HTTP_STATUS_TOO_MANY_REQUESTS)) {
+        test.Fatalf("Expected a lockout, got: '%v'.", apiErr);
+    }
+
+    // Other users are fine.
+    apiErr = validateThrottleTestRequest("grader@test.com", "grader", "1.2.3.4");
+    if (apiErr != nil) {
+        test.Fatalf("Other user was locked out: '%v'.", apiErr);
+    }
+
+    records := GetAuthThrottles();
+    if ((len(records) != 1) || (records[0].Key != "user:student@test.com") ||
+            (records[0].Failures != 3) || (records[0].LockedUntil == "")) {
+        test.Fatalf("Unexpected throttle records: '%s'.", util.MustToJSON(records));
+    }
+
+    cleared := ClearAuthThrottles([]string{"user:student@test.com", "user:ZZZ"});
+    if ((len(cleared) != 1) || (cleared[0] != "user:student@test.com")) {
+        test.Fatalf("Unexpected cleared keys: '%v'.", cleared);
+    }
+
+    apiErr = validateThrottleTestRequest("student@test.com", "student", "1.2.3.4");
+    if (apiErr != nil) {
+        test.Fatalf("User is still locked out after clearing: '%v'.", apiErr);
+    }
+
+    // A success resets the count.
+    validateThrottleTestRequest("student@test.com", "Zstudent", "1.2.3.4");
+    validateThrottleTestRequest("student@test.com", "student", "1.2.3.4");
+
+    if (len(GetAuthThrottles()) != 0) {
+        test.Fatalf("Success did not clear failures: '%s'.", util.MustToJSON(GetAuthThrottles()));
+    }
+}
+
+func TestAuthThrottleIP(test *testing.T) {
+    defer setAuthThrottleConfig(0, 0);
+    defer ResetAuthThrottles();
+
+    ResetAuthThrottles();
+    setAuthThrottleConfig(0, 2);
+
+    // Unknown users count against the IP.
+    for _, email := range []string{"ZZZ@test.com", "student@test.com"} {
+        apiErr := validateThrottleTestRequest(email, "Z", "1.2.3.4");
+        if ((apiErr == nil) || (apiErr.HTTPStatus != HTTP_STATUS_AUTH_ERROR)) {
+            test.Fatalf("Expected an auth error for '%s', got: '%v'.", email, apiErr);
+        }
+    }
+
+    apiErr := validateThrottleTestRequest("grader@test.com", "grader", "1.2.3.4");
+    if ((apiErr == nil) || (apiErr.Locator != "-052")) {
+        test.Fatalf("Expected an IP lockout, got: '%v'.", apiErr);
+    }
+
+    apiErr = validateThrottleTestRequest("grader@test.com", "grader", "5.6.7.8");
+    if (apiErr != nil) {
+        test.Fatalf("Other IP was locked out: '%v'.", apiErr);
+    }
+}
+
+func TestAuthLockoutDuration(test *testing.T) {
+    oldBase := config.AUTH_THROTTLE_BASE_SECS.Get();
+    oldMax := config.AUTH_THROTTLE_MAX_SECS.Get();
+    defer config.AUTH_THROTTLE_BASE_SECS.Set(oldBase);
+    defer config.AUTH_THROTTLE_MAX_SECS.Set(oldMax);
+
+    config.AUTH_THROTTLE_BASE_SECS.Set(30);
+    config.AUTH_THROTTLE_MAX_SECS.Set(100);
+
+    testCases := []struct{extraFailures int; expected time.Duration}{
+        {0, 30 * time.Second},
+        {1, 60 * time.Second},
+        {2, 100 * time.Second},
+        {5000, 100 * time.Second},
+    };
+
+    for i, testCase := range testCases {
+        actual := getAuthLockoutDuration(testCase.extraFailures);
+        if (actual != testCase.expected) {
+            test.Errorf("Case %d: Unexpected duration. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual);
+        }
+    }
+}
+
+func TestAuthThrottleToken(test *testing.T) {
+    defer db.ResetForTesting();
+    defer setAuthThrottleConfig(0, 0);
+    defer ResetAuthThrottles();
+
+    db.ResetForTesting();
+    ResetAuthThrottles();
+    setAuthThrottleConfig(2, 0);
+
+    token, cleartext, err := model.NewAPIToken("student@test.com", "test", time.Hour);
+    if (err != nil) {
+        test.Fatalf("Failed to create token: '%v'.", err);
+    }
+
+    err = db.SaveAPIToken(db.MustGetTestCourse(), token);
+    if (err != nil) {
+        test.Fatalf("Failed to save token: '%v'.", err);
+    }
+
+    // Bad tokens do not count against the user.
+    for i := 0; i < 3; i++ {
+        apiErr := validateThrottleTestTokenRequest("student@test.com", token.ID + ".ZZZ", "1.2.3.4");
+        if ((apiErr == nil) || (apiErr.Locator != "-043")) {
+            test.Fatalf("Attempt %d: Expected a bad token, got: '%v'.", i, apiErr);
+        }
+    }
+
+    if (len(GetAuthThrottles()) != 0) {
+        test.Fatalf("Bad tokens were counted against the user: '%s'.", util.MustToJSON(GetAuthThrottles()));
+    }
+
+    for i := 0; i < 2; i++ {
+        validateThrottleTestRequest("student@test.com", "Zstudent", "1.2.3.4");
+    }
+
+    apiErr := validateThrottleTestRequest("student@test.com", "student", "1.2.3.4");
+    if ((apiErr == nil) || (apiErr.Locator != "-052")) {
+        test.Fatalf("Expected a lockout, got: '%v'.", apiErr);
+    }
+
+    // A valid token still works (and does not clear the password lockout).
+    apiErr = validateThrottleTestTokenRequest("student@test.com", cleartext, "1.2.3.4");
+    if (apiErr != nil) {
+        test.Fatalf("Valid token was locked out: '%v'.", apiErr);
+    }
+
+    apiErr = validateThrottleTestRequest("student@test.com", "student", "1.2.3.4");
+    if ((apiErr == nil) || (apiErr.Locator != "-052")) {
+        test.Fatalf("Expected a lockout after token use, got: '%v'.", apiErr);
+    }
+}
+
+// Bad tokens lock out the IP, and then even valid tokens from that IP are refused.
+func TestAuthThrottleTokenIP(test *testing.T) {
+    defer db.ResetForTesting();
+    defer setAuthThrottleConfig(0, 0);
+    defer ResetAuthThrottles();
+
+    db.ResetForTesting();
+    ResetAuthThrottles();
+    setAuthThrottleConfig(0, 2);
+
+    token, cleartext, err := model.NewAPIToken("student@test.com", "test", time.Hour);
+    if (err != nil) {
+        test.Fatalf("Failed to create token: '%v'.", err);
+    }
+
+    err = db.SaveAPIToken(db.MustGetTestCourse(), token);
+    if (err != nil) {
+        test.Fatalf("Failed to save token: '%v'.", err);
+    }
+
+    for i := 0; i < 2; i++ {
+        apiErr := validateThrottleTestTokenRequest("student@test.com", token.ID + ".ZZZ", "1.2.3.4");
+        if ((apiErr == nil) || (apiErr.Locator != "-043")) {
+            test.Fatalf("Attempt %d: Expected a bad token, got: '%v'.", i, apiErr);
+        }
+    }
+
+    apiErr := validateThrottleTestTokenRequest("student@test.com", token.ID + ".ZZZ", "1.2.3.4");
+    if ((apiErr == nil) || (apiErr.Locator != "-052")) {
+        test.Fatalf("Expected an IP lockout for a bad token, got: '%v'.", apiErr);
+    }
+
+    apiErr = validateThrottleTestTokenRequest("student@test.com", cleartext, "1.2.3.4");
+    if ((apiErr == nil) || (apiErr.Locator != "-052")) {
+        test.Fatalf("Expected an IP lockout for a valid token, got: '%v'.", apiErr);
+    }
+
+    // The user is not locked out from other IPs.
+    apiErr = validateThrottleTestTokenRequest("student@test.com", cleartext, "5.6.7.8");
+    if (apiErr != nil) {
+        test.Fatalf("Valid token from another IP was locked out: '%v'.", apiErr);
+    }
+}
+
+func TestPruneAuthThrottles(test *testing.T) {
+    defer setAuthThrottleConfig(0, 0);
+    defer ResetAuthThrottles();
+
+    ResetAuthThrottles();
+    setAuthThrottleConfig(0, 100);
+
+    validateThrottleTestRequest("student@test.com", "Zstudent", "1.2.3.4");
+    validateThrottleTestRequest("student@test.com", "Zstudent", "5.6.7.8");
+
+    authThrottlesLock.Lock();
+    pruneAuthThrottles(time.Now());
+    numEntries := len(authThrottles);
+    authThrottlesLock.Unlock();
+
+    if (numEntries != 2) {
+        test.Fatalf("Recent entries were pruned. Expected: 2, Actual: %d.", numEntries);
+    }
+
+    resetDuration := time.Duration(config.AUTH_THROTTLE_RESET_MINS.Get()) * time.Minute;
+
+    authThrottlesLock.Lock();
+    pruneAuthThrottles(time.Now().Add(resetDuration + time.Minute));
+    numEntries = len(authThrottles);
+    authThrottlesLock.Unlock();
+
+    if (numEntries != 0) {
+        test.Fatalf("Old entries were not pruned. Expected: 0, Actual: %d.", numEntries);
+    }
+}
+
+func TestGetClientIP(test *testing.T) {
+    oldProxies := config.WEB_TRUSTED_PROXIES.Get();
+    defer config.WEB_TRUSTED_PROXIES.Set(oldProxies);
+
+    testCases := []struct{proxies string; remoteAddr string; forwardedFor []string; expected string}{
+        {"", "1.2.3.4:5678", nil, "1.2.3.4"},
+        {"", "[::1]:5678", nil, "::1"},
+        {"", "1.2.3.4", nil, "1.2.3.4"},
+
+        // Without a trusted proxy, the header is ignored.
+        {"", "1.2.3.4:5678", []string{"5.6.7.8"}, "1.2.3.4"},
+        {"10.0.0.1", "1.2.3.4:5678", []string{"5.6.7.8"}, "1.2.3.4"},
+
+        // Trusted proxies.
+        {"10.0.0.1", "10.0.0.1:5678", []string{"5.6.7.8"}, "5.6.7.8"},
+        {"10.0.0.0/8", "10.0.0.1:5678", []string{"5.6.7.8"}, "5.6.7.8"},
+        {"ZZZ, 10.0.0.1", "10.0.0.1:5678", []string{"5.6.7.8"}, "5.6.7.8"},
+        {"10.0.0.1", "10.0.0.1:5678", nil, "10.0.0.1"},
+
+        // Only the right-most untrusted address is used (the client can forge anything to its left).
+        {"10.0.0.1", "10.0.0.1:5678", []string{"9.9.9.9, 5.6.7.8"}, "5.6.7.8"},
+        {"10.0.0.1", "10.0.0.1:5678", []string{"9.9.9.9", "5.6.7.8"}, "5.6.7.8"},
+        {"10.0.0.0/8", "10.0.0.1:5678", []string{"9.9.9.9, 5.6.7.8, 10.0.0.2"}, "5.6.7.8"},
+        {"10.0.0.0/8", "10.0.0.1:5678", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+    };
+
+    for i, testCase := range testCases {
+        config.WEB_TRUSTED_PROXIES.Set(testCase.proxies);
+
+        request := httptest.NewRequest("GET", "/", nil);
+        request.RemoteAddr = testCase.remoteAddr;
+        for _, value := range testCase.forwardedFor {
+            request.Header.Add("X-Forwarded-For", value);
+        }
+
+        actual := getClientIP(request);
+        if (actual != testCase.expected) {
+            test.Errorf("Case %d: Unexpected client IP. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual);
+        }
+    }
+}
+
+func validateThrottleTestRequest(email string, pass string, ip string) *APIError {
+    request := struct {
+        APIRequestCourseUserContext
+        RequireNone
+    }{
+        APIRequestCourseUserContext: APIRequestCourseUserContext{
+            APIRequest: APIRequest{ClientIP: ip},
+            CourseID: "course101",
+            UserEmail: email,
+            UserPass: util.Sha256HexFromString(pass),
+        },
+    };
+
+    return ValidateAPIRequest(nil, &request, "");
+}
+
+func validateThrottleTestTokenRequest(email string, token string, ip string) *APIError {
+    request := struct {
+        APIRequestCourseUserContext
+        RequireNone
+    }{
+        APIRequestCourseUserContext: APIRequestCourseUserContext{
+            APIRequest: APIRequest{ClientIP: ip},
+            CourseID: "course101",
+            UserEmail: email,
+            UserToken: token,
+        },
+    };
+
+    return ValidateAPIRequest(nil, &request, "");
+}
+
+func setAuthThrottleConfig(userFailures int, ipFailures int) {
+    config.AUTH_THROTTLE_USER_FAILURES.Set(userFailures);
+    config.AUTH_THROTTLE_IP_FAILURES.Set(ipFailures);
+}
@@ -90,6 +90,7 @@ func CompareUserInfo(a UserInfo, b UserInfo) int {
 
 // An API-friendly version of model.UserSyncResult.
 type SyncUsersInfo struct {
+    Strategy string `json:"strategy,omitempty"`
     Add []*UserInfo `json:"add-users"`
     Mod []*UserInfo `json:"mod-users"`
     Del []*UserInfo `json:"del-users"`
@@ -99,6 +100,7 @@ type SyncUsersInfo struct {
 
 func NewSyncUsersInfo(syncResult *model.UserSyncResult) *SyncUsersInfo {
     info := SyncUsersInfo{
+        Strategy: syncResult.Strategy,
         Add: NewUserInfos(syncResult.Add),
         Mod: NewUserInfos(syncResult.Mod),
         Del: NewUserInfos(syncResult.Del),
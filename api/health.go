@@ -0,0 +1,81 @@
+package api
+
+// Endpoints for load balancers and watchdogs.
+// These do not require authentication and are not under the API prefix.
+
+import (
+    "fmt"
+    "net/http"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/procedures"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+var startTime time.Time = time.Now();
+
+// The public readiness response only has the overall status.
+// The details of each check (which can include course/assignment IDs and errors) are only logged (see procedures.GetReadiness()).
+type readyResponse struct {
+    Ready bool `json:"ready"`
+    Status string `json:"status"`
+    Timestamp common.Timestamp `json:"timestamp"`
+}
+
+type healthResponse struct {
+    Status string `json:"status"`
+    Version string `json:"version"`
+    Timestamp common.Timestamp `json:"timestamp"`
+    UptimeSecs int64 `json:"uptime-secs"`
+}
+
+// The process is alive and serving requests.
+func handleHealth(response http.ResponseWriter, request *http.Request) error {
+    health := healthResponse{
+        Status: procedures.CHECK_STATUS_OK,
+        Version: util.GetAutograderVersion(),
+        Timestamp: common.NowTimestamp(),
+        UptimeSecs: int64(time.Since(startTime).Seconds()),
+    };
+
+    return writeHealthJSON(response, http.StatusOK, health);
+}
+
+// The server can handle requests (see procedures.GetReadiness()).
+// Responds with a 503 if any check failed.
+func handleReady(response http.ResponseWriter, request *http.Request) error {
+    report := procedures.GetReadiness();
+
+    ready := readyResponse{
+        Ready: report.Ready,
+        Status: procedures.CHECK_STATUS_OK,
+        Timestamp: report.Timestamp,
+    };
+
+    status := http.StatusOK;
+    if (!report.Ready) {
+        ready.Status = procedures.CHECK_STATUS_FAILED;
+        status = http.StatusServiceUnavailable;
+    }
+
+    return writeHealthJSON(response, status, ready);
+}
+
+func writeHealthJSON(response http.ResponseWriter, status int, data any) error {
+    payload, err := util.ToJSONIndent(data);
+    if (err != nil) {
+        return fmt.Errorf("Failed to serialize health response: '%w'.", err);
+    }
+
+    response.Header().Set("Content-Type", "application/json");
+    response.Header().Set("Cache-Control", "no-store");
+    response.WriteHeader(status);
+
+    _, err = fmt.Fprintln(response, payload);
+    if (err != nil) {
+        return fmt.Errorf("Failed to write health response: '%w'.", err);
+    }
+
+    return nil;
+}
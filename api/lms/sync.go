@@ -8,7 +8,7 @@ import (
 
 type SyncRequest struct {
     core.APIRequestCourseUserContext
-    core.MinRoleAdmin
+    core.RequireSyncLMS
 
     DryRun bool `json:"dry-run"`
     SkipEmails bool `json:"skip-emails"`
@@ -0,0 +1,54 @@
+package api
+
+// Metrics are unauthenticated, so they are served on their own listener (config.WEB_METRICS_ADDRESS)
+// that can be kept off the public network, instead of on the main web port.
+
+import (
+    "context"
+    "errors"
+    "net/http"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/metrics"
+)
+
+// Start serving metrics in the background (if metrics are enabled).
+// Returns nil if metrics are disabled.
+func startMetricsServer() *http.Server {
+    if (!config.WEB_METRICS_ENABLED.Get()) {
+        return nil;
+    }
+
+    mux := http.NewServeMux();
+    mux.Handle("/metrics", metrics.Handler());
+
+    server := &http.Server{
+        Addr: config.WEB_METRICS_ADDRESS.Get(),
+        Handler: mux,
+        ReadHeaderTimeout: secondsOption(config.WEB_READ_HEADER_TIMEOUT_SECS),
+    };
+
+    go func() {
+        log.Info().Str("address", server.Addr).Msg("Serving metrics.");
+
+        err := server.ListenAndServe();
+        if ((err != nil) && !errors.Is(err, http.ErrServerClosed)) {
+            log.Error().Err(err).Str("address", server.Addr).Msg("Metrics server failed.");
+        }
+    }();
+
+    return server;
+}
+
+func stopMetricsServer(server *http.Server) {
+    if (server == nil) {
+        return;
+    }
+
+    err := server.Shutdown(context.Background());
+    if (err != nil) {
+        log.Warn().Err(err).Msg("Failed to stop metrics server.");
+    }
+}
@@ -4,6 +4,7 @@ package api
 
 import (
     "github.com/eriq-augustine/autograder/api/admin"
+    "github.com/eriq-augustine/autograder/api/auth"
     "github.com/eriq-augustine/autograder/api/core"
     "github.com/eriq-augustine/autograder/api/lms"
     "github.com/eriq-augustine/autograder/api/submission"
@@ -17,12 +18,16 @@ var baseRoutes = []*core.Route{
 
     core.NewRoute("GET", `/static`, handleStatic),
     core.NewRoute("GET", `/static/.*`, handleStatic),
+
+    core.NewRoute("GET", `/health`, handleHealth),
+    core.NewRoute("GET", `/ready`, handleReady),
 }
 
 func GetRoutes() *[]*core.Route {
     routes := make([]*core.Route, 0);
 
     routes = append(routes, baseRoutes...);
+    routes = append(routes, *(auth.GetRoutes())...);
     routes = append(routes, *(lms.GetRoutes())...);
     routes = append(routes, *(user.GetRoutes())...);
     routes = append(routes, *(submission.GetRoutes())...);
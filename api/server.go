@@ -1,20 +1,145 @@
 package api
 
 // Run the standard API server.
+// The server runs until it receives SIGINT or SIGTERM,
+// at which point it stops accepting new requests and waits for in-flight requests and gradings to finish.
+// When serving TLS, SIGHUP reloads the certificate and key.
 
 import (
+    "context"
+    "crypto/tls"
+    "errors"
     "fmt"
     "net/http"
+    "os"
+    "os/signal"
+    "syscall"
+    "time"
 
     "github.com/rs/zerolog/log"
 
     "github.com/eriq-augustine/autograder/api/core"
     "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/grader"
 )
 
 func StartServer() error {
     var port = config.WEB_PORT.Get();
 
-    log.Info().Msgf("Serving on %d.", port);
-    return http.ListenAndServe(fmt.Sprintf(":%d", port), core.GetRouteServer(GetRoutes()));
+    server, certs, err := newServer(port);
+    if (err != nil) {
+        return err;
+    }
+
+    shutdownSignals := make(chan os.Signal, 1);
+    signal.Notify(shutdownSignals, syscall.SIGINT, syscall.SIGTERM);
+    defer signal.Stop(shutdownSignals);
+
+    if (certs != nil) {
+        reloadSignals := make(chan os.Signal, 1);
+        signal.Notify(reloadSignals, syscall.SIGHUP);
+        defer signal.Stop(reloadSignals);
+
+        go handleReloadSignals(reloadSignals, certs);
+    }
+
+    metricsServer := startMetricsServer();
+    defer stopMetricsServer(metricsServer);
+
+    serverErrors := make(chan error, 1);
+    go func() {
+        if (certs != nil) {
+            log.Info().Msgf("Serving (TLS) on %d.", port);
+            serverErrors <- server.ListenAndServeTLS("", "");
+        } else {
+            log.Info().Msgf("Serving on %d.", port);
+            serverErrors <- server.ListenAndServe();
+        }
+    }();
+
+    select {
+        case err = <-serverErrors:
+            return err;
+        case sig := <-shutdownSignals:
+            log.Info().Str("signal", sig.String()).Msg("Received shutdown signal, shutting down server.");
+    }
+
+    return shutdown(server);
+}
+
+func newServer(port int) (*http.Server, *certificateLoader, error) {
+    var handler http.Handler = core.GetRouteServer(GetRoutes());
+
+    maxRequestMB := config.WEB_MAX_REQUEST_MB.Get();
+    if (maxRequestMB > 0) {
+        handler = http.MaxBytesHandler(handler, int64(maxRequestMB) << 20);
+    }
+
+    server := &http.Server{
+        Addr: fmt.Sprintf(":%d", port),
+        Handler: handler,
+        ReadHeaderTimeout: secondsOption(config.WEB_READ_HEADER_TIMEOUT_SECS),
+        ReadTimeout: secondsOption(config.WEB_READ_TIMEOUT_SECS),
+        WriteTimeout: secondsOption(config.WEB_WRITE_TIMEOUT_SECS),
+        IdleTimeout: secondsOption(config.WEB_IDLE_TIMEOUT_SECS),
+        MaxHeaderBytes: config.WEB_MAX_HEADER_KB.Get() << 10,
+    };
+
+    certPath := config.WEB_TLS_CERT.Get();
+    keyPath := config.WEB_TLS_KEY.Get();
+
+    if ((certPath == "") && (keyPath == "")) {
+        return server, nil, nil;
+    }
+
+    if ((certPath == "") || (keyPath == "")) {
+        return nil, nil, fmt.Errorf("Both a TLS certificate ('%s') and key ('%s') must be provided to serve TLS.",
+                config.WEB_TLS_CERT.Key, config.WEB_TLS_KEY.Key);
+    }
+
+    certs, err := newCertificateLoader(certPath, keyPath);
+    if (err != nil) {
+        return nil, nil, err;
+    }
+
+    server.TLSConfig = &tls.Config{
+        MinVersion: tls.VersionTLS12,
+        GetCertificate: certs.getCertificate,
+    };
+
+    return server, certs, nil;
+}
+
+func handleReloadSignals(signals chan os.Signal, certs *certificateLoader) {
+    for range signals {
+        err := certs.reload();
+        if (err != nil) {
+            log.Error().Err(err).Msg("Failed to reload TLS certificate, keeping the current certificate.");
+        }
+    }
+}
+
+// Stop accepting requests, wait for in-flight requests to complete, and then wait for any remaining gradings.
+// Both waits share the same deadline (config.WEB_SHUTDOWN_TIMEOUT_SECS).
+func shutdown(server *http.Server) error {
+    deadline := time.Now().Add(secondsOption(config.WEB_SHUTDOWN_TIMEOUT_SECS));
+
+    ctx, cancel := context.WithDeadline(context.Background(), deadline);
+    defer cancel();
+
+    err := server.Shutdown(ctx);
+    if ((err != nil) && !errors.Is(err, http.ErrServerClosed)) {
+        log.Warn().Err(err).Msg("Timed out waiting for in-flight requests to finish.");
+    }
+
+    log.Info().Msg("Waiting for in-flight gradings to finish.");
+    if (!grader.DrainQueue(time.Until(deadline))) {
+        return fmt.Errorf("Timed out waiting for in-flight gradings to finish.");
+    }
+
+    return nil;
+}
+
+func secondsOption(option *config.IntOption) time.Duration {
+    return time.Duration(option.Get()) * time.Second;
 }
@@ -8,7 +8,7 @@ import (
 
 type FetchScoresRequest struct {
     core.APIRequestAssignmentContext
-    core.MinRoleGrader
+    core.RequireViewSubmissions
 
     // Filter results to only users with this role.
     FilterRole model.UserRole `json:"filter-role"`
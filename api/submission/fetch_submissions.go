@@ -8,7 +8,7 @@ import (
 
 type FetchSubmissionsRequest struct {
     core.APIRequestAssignmentContext
-    core.MinRoleGrader
+    core.RequireViewSubmissions
 
     FilterRole model.UserRole `json:"filter-role"`
 }
@@ -8,7 +8,7 @@ import (
 
 type HistoryRequest struct {
     core.APIRequestAssignmentContext
-    core.MinRoleStudent
+    core.RequireSubmit
 
     TargetUser core.TargetUserSelfOrGrader `json:"target-email"`
 }
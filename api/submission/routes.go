@@ -13,6 +13,9 @@ var routes []*core.Route = []*core.Route{
     core.NewAPIRoute(core.NewEndpoint(`submission/fetch/submission`), HandleFetchSubmission),
     core.NewAPIRoute(core.NewEndpoint(`submission/fetch/submissions`), HandleFetchSubmissions),
     core.NewAPIRoute(core.NewEndpoint(`submission/submit`), HandleSubmit),
+    core.NewAPIRoute(core.NewEndpoint(`submission/submit-async`), HandleSubmitAsync),
+    core.NewAPIRoute(core.NewEndpoint(`submission/status`), HandleStatus),
+    core.NewAPIStreamRoute(core.NewEndpoint(`submission/stream`), HandleStream),
     core.NewAPIRoute(core.NewEndpoint(`submission/remove`), HandleRemoveSubmission),
 };
 
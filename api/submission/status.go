@@ -0,0 +1,74 @@
+package submission
+
+import (
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/grader"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+type StatusRequest struct {
+    core.APIRequestAssignmentContext
+    core.RequireSubmit
+
+    JobID core.NonEmptyString `json:"job-id"`
+}
+
+type StatusResponse struct {
+    FoundJob bool `json:"found-job"`
+    Job *grader.GradingJob `json:"job"`
+
+    // The following fields are only set once the job is done.
+    Rejected bool `json:"rejected"`
+    Message string `json:"message"`
+
+    GradingSuccess bool `json:"grading-success"`
+    GradingInfo *model.GradingInfo `json:"result"`
+}
+
+func HandleStatus(request *StatusRequest) (*StatusResponse, *core.APIError) {
+    job := getVisibleJob(&request.APIRequestAssignmentContext, string(request.JobID));
+    if (job == nil) {
+        return &StatusResponse{}, nil;
+    }
+
+    return newStatusResponse(job), nil;
+}
+
+// Get a job that the requesting user is allowed to see, or nil.
+func getVisibleJob(request *core.APIRequestAssignmentContext, jobID string) *grader.GradingJob {
+    job := grader.GetGradingJob(jobID);
+    if (job == nil) {
+        return nil;
+    }
+
+    // Jobs are only visible from their own assignment.
+    if ((job.CourseID != request.Course.GetID()) || (job.AssignmentID != request.Assignment.GetID())) {
+        return nil;
+    }
+
+    // Only users that can view submissions (e.g. graders) can see other user's jobs.
+    if ((job.User != request.User.Email) && !request.HasCapability(model.CapabilityViewSubmissions, request.Assignment.GetID())) {
+        return nil;
+    }
+
+    return job;
+}
+
+func newStatusResponse(job *grader.GradingJob) *StatusResponse {
+    response := StatusResponse{
+        FoundJob: true,
+        Job: job,
+    };
+
+    if (job.Reject != nil) {
+        response.Rejected = true;
+        response.Message = job.Reject.String();
+    } else if ((job.Status == grader.JOB_STATUS_DONE) && (job.Result != nil)) {
+        response.GradingSuccess = true;
+        response.GradingInfo = job.Result.Info;
+    } else if ((job.Status == grader.JOB_STATUS_FAILED) && (job.Result != nil) && (job.Result.FailureReason != "")) {
+        response.Message = job.Error.Error();
+    }
+
+    return &response;
+}
@@ -0,0 +1,71 @@
+package submission
+
+import (
+    "path/filepath"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestStatusVisibility(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    // Disable testing mode so the submission gets quickly rejected.
+    config.TESTING_MODE.Set(false);
+    defer config.TESTING_MODE.Set(true);
+
+    assignment := db.MustGetTestAssignment();
+    paths := []string{filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)};
+
+    fields := map[string]any{
+        "course-id": "course101-with-zero-limit",
+        "assignment-id": "hw0",
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/submit-async`), fields, paths, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Response is not a success when it should be: '%v'.", response);
+    }
+
+    var submitContent SubmitAsyncResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &submitContent);
+
+    testCases := []struct{ role model.UserRole; courseID string; jobID string; found bool }{
+        {model.RoleStudent, "course101-with-zero-limit", submitContent.JobID, true},
+        {model.RoleGrader, "course101-with-zero-limit", submitContent.JobID, true},
+        {model.RoleAdmin, "course101-with-zero-limit", submitContent.JobID, true},
+
+        // The job is not visible from other courses.
+        {model.RoleStudent, "course101", submitContent.JobID, false},
+
+        // Missing job.
+        {model.RoleStudent, "course101-with-zero-limit", "ZZZ", false},
+    };
+
+    for i, testCase := range testCases {
+        fields := map[string]any{
+            "course-id": testCase.courseID,
+            "assignment-id": "hw0",
+            "job-id": testCase.jobID,
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/status`), fields, nil, testCase.role);
+        if (!response.Success) {
+            test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            continue;
+        }
+
+        var responseContent StatusResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if (testCase.found != responseContent.FoundJob) {
+            test.Errorf("Case %d: Unexpected found value. Expected: '%v', Actual: '%v'.", i, testCase.found, responseContent.FoundJob);
+            continue;
+        }
+    }
+}
@@ -0,0 +1,83 @@
+package submission
+
+import (
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/grader"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+const (
+    STREAM_EVENT_OUTPUT = "output"
+    STREAM_EVENT_DONE = "done"
+)
+
+type StreamRequest struct {
+    core.APIRequestAssignmentContext
+    core.RequireSubmit
+
+    JobID core.NonEmptyString `json:"job-id"`
+}
+
+// Stream the stdout of a grading job (see submission/submit-async) while it is being graded.
+// Each line of output is sent as an "output" event,
+// and a final "done" event has the same content as a submission/status response.
+// Students can only stream output when the assignment allows it
+// (users that can view submissions, e.g. graders, can always stream).
+func HandleStream(request *StreamRequest, stream *core.EventStream) *core.APIError {
+    if (!request.Assignment.StreamOutput && !request.HasCapability(model.CapabilityViewSubmissions, request.Assignment.GetID())) {
+        return core.NewMissingCapabilityError("-608", &request.APIRequestCourseUserContext, model.CapabilityViewSubmissions,
+                "Assignment does not allow students to stream output.");
+    }
+
+    jobID := string(request.JobID);
+
+    var output *grader.OutputStream = nil;
+    if (getVisibleJob(&request.APIRequestAssignmentContext, jobID) != nil) {
+        output = grader.GetGradingJobOutput(jobID);
+    }
+
+    if (output == nil) {
+        return core.NewBadRequestError("-609", &request.APIRequest, "Could not find grading job.").
+                Add("job-id", jobID);
+    }
+
+    index := 0;
+    for {
+        lines, closed, changed := output.Lines(index);
+
+        for _, line := range lines {
+            err := stream.Send(STREAM_EVENT_OUTPUT, line);
+            if (err != nil) {
+                log.Debug().Err(err).Str("job-id", jobID).Msg("Stopped streaming grading output.");
+                return nil;
+            }
+        }
+
+        index += len(lines);
+
+        if (closed) {
+            break;
+        }
+
+        select {
+            case <-changed:
+            case <-stream.Done():
+                return nil;
+        }
+    }
+
+    job := grader.GetGradingJob(jobID);
+    if (job == nil) {
+        return core.NewInternalError("-610", &request.APIRequestCourseUserContext, "Grading job went away while streaming.").
+                Add("job-id", jobID);
+    }
+
+    err := stream.Send(STREAM_EVENT_DONE, newStatusResponse(job));
+    if (err != nil) {
+        log.Debug().Err(err).Str("job-id", jobID).Msg("Failed to send final grading status.");
+    }
+
+    return nil;
+}
@@ -0,0 +1,108 @@
+package submission
+
+import (
+    "path/filepath"
+    "slices"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/grader"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestStream(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    // Courses are reloaded for each request, so changes to the assignment need to be saved.
+    // Register the runtime first, since the course is validated when it is saved.
+    grader.RegisterRuntime("test-stream", &grader.MockRuntime{Stdout: "line 1\nline 2\nline 3"});
+
+    assignment := db.MustGetTestAssignment();
+    assignment.ImageInfo.Runtime = "test-stream";
+    saveTestCourse(test, assignment);
+
+    paths := []string{filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)};
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/submit-async`), nil, paths, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Submit response is not a success when it should be: '%v'.", response);
+    }
+
+    var submitContent SubmitAsyncResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &submitContent);
+
+    testCases := []struct{role model.UserRole; streamOutput bool; jobID string; permError bool; badRequest bool}{
+        {model.RoleStudent, true, submitContent.JobID, false, false},
+        {model.RoleGrader, false, submitContent.JobID, false, false},
+        {model.RoleStudent, false, submitContent.JobID, true, false},
+        {model.RoleStudent, true, "ZZZ", false, true},
+        // Another student cannot see the job.
+        {model.RoleOther, true, submitContent.JobID, true, false},
+    };
+
+    for i, testCase := range testCases {
+        assignment.StreamOutput = testCase.streamOutput;
+        saveTestCourse(test, assignment);
+
+        fields := map[string]any{"job-id": testCase.jobID};
+        events, response := core.SendTestAPIStreamRequest(test, core.NewEndpoint(`submission/stream`), fields, testCase.role);
+
+        if (testCase.permError || testCase.badRequest) {
+            if (response == nil) {
+                test.Errorf("Case %d: Got a stream when an error was expected: '%v'.", i, events);
+                continue;
+            }
+
+            expectedStatus := core.HTTP_STATUS_BAD_REQUEST;
+            if (testCase.permError) {
+                expectedStatus = core.HTTP_PERMISSIONS_ERROR;
+            }
+
+            if (response.HTTPStatus != expectedStatus) {
+                test.Errorf("Case %d: Unexpected status. Expected: %d, Actual: %d.", i, expectedStatus, response.HTTPStatus);
+            }
+
+            continue;
+        }
+
+        if (response != nil) {
+            test.Errorf("Case %d: Did not get a stream: '%v'.", i, response);
+            continue;
+        }
+
+        lines := make([]string, 0);
+        var status StatusResponse;
+
+        for _, event := range events {
+            if (event.Event == STREAM_EVENT_OUTPUT) {
+                var line string;
+                util.MustJSONFromString(event.Data, &line);
+                lines = append(lines, line);
+            } else if (event.Event == STREAM_EVENT_DONE) {
+                util.MustJSONFromString(event.Data, &status);
+            }
+        }
+
+        expectedLines := []string{"line 1", "line 2", "line 3"};
+        if (!slices.Equal(expectedLines, lines)) {
+            test.Errorf("Case %d: Unexpected lines. Expected: '%v', Actual: '%v'.", i, expectedLines, lines);
+        }
+
+        if (!status.FoundJob || !status.GradingSuccess) {
+            test.Errorf("Case %d: Unexpected final status: '%+v'.", i, status);
+        }
+
+        if (events[len(events) - 1].Event != STREAM_EVENT_DONE) {
+            test.Errorf("Case %d: Last event was not done: '%s'.", i, events[len(events) - 1].Event);
+        }
+    }
+}
+
+func saveTestCourse(test *testing.T, assignment *model.Assignment) {
+    err := db.SaveCourse(assignment.GetCourse());
+    if (err != nil) {
+        test.Fatalf("Failed to save course: '%v'.", err);
+    }
+}
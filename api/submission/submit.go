@@ -10,7 +10,7 @@ import (
 
 type SubmitRequest struct {
     core.APIRequestAssignmentContext
-    core.MinRoleStudent
+    core.RequireSubmit
     Files core.POSTFiles
 
     Message string `json:"message"`
@@ -39,6 +39,11 @@ func HandleSubmit(request *SubmitRequest) (*SubmitResponse, *core.APIError) {
 
         log.Debug().Err(err).Str("stdout", stdout).Str("stderr", stderr).Msg("Submission grading failed.");
 
+        // Let the user know if their submission went over a limit.
+        if ((result != nil) && (result.FailureReason != "")) {
+            response.Message = err.Error();
+        }
+
         return &response, nil;
     }
 
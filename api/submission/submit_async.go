@@ -0,0 +1,28 @@
+package submission
+
+import (
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/grader"
+)
+
+type SubmitAsyncRequest struct {
+    core.APIRequestAssignmentContext
+    core.RequireSubmit
+    Files core.POSTFiles
+
+    Message string `json:"message"`
+}
+
+type SubmitAsyncResponse struct {
+    JobID string `json:"job-id"`
+}
+
+func HandleSubmitAsync(request *SubmitAsyncRequest) (*SubmitAsyncResponse, *core.APIError) {
+    jobID, err := grader.GradeAsyncDefault(request.Assignment, request.Files.TempDir, request.User.Email, request.Message);
+    if (err != nil) {
+        return nil, core.NewInternalError("-607", &request.APIRequestCourseUserContext, "Failed to queue submission for grading.").
+                Err(err);
+    }
+
+    return &SubmitAsyncResponse{jobID}, nil;
+}
@@ -0,0 +1,89 @@
+package submission
+
+import (
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/grader"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+// Rejections for async submissions are reported through the job status.
+func TestSubmitAsyncRejectMaxAttempts(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    // Disable testing mode to check for rejection.
+    config.TESTING_MODE.Set(false);
+    defer config.TESTING_MODE.Set(true);
+
+    // Note that we are using a submission from a different assignment.
+    assignment := db.MustGetTestAssignment();
+    paths := []string{filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)};
+
+    fields := map[string]any{
+        "course-id": "course101-with-zero-limit",
+        "assignment-id": "hw0",
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/submit-async`), fields, paths, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Response is not a success when it should be: '%v'.", response);
+    }
+
+    var responseContent SubmitAsyncResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+    if (responseContent.JobID == "") {
+        test.Fatalf("Did not get a job ID.");
+    }
+
+    fields["job-id"] = responseContent.JobID;
+    statusContent := waitForJob(test, fields, model.RoleStudent);
+
+    if (statusContent.GradingSuccess) {
+        test.Fatalf("Response is a grading success when it should not be: '%v'.", statusContent);
+    }
+
+    if (!statusContent.Rejected) {
+        test.Fatalf("Response is not rejected when it should be: '%v'.", statusContent);
+    }
+
+    expected := (&grader.RejectMaxAttempts{Max: 0}).String();
+    if (expected != statusContent.Message) {
+        test.Fatalf("Did not get the expected rejection reason. Expected: '%s', Actual: '%s'.",
+            expected, statusContent.Message);
+    }
+}
+
+// Poll the status endpoint until the job is finished.
+func waitForJob(test *testing.T, fields map[string]any, role model.UserRole) *StatusResponse {
+    for i := 0; i < 100; i++ {
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/status`), fields, nil, role);
+        if (!response.Success) {
+            test.Fatalf("Status response is not a success when it should be: '%v'.", response);
+        }
+
+        var responseContent StatusResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if (!responseContent.FoundJob) {
+            test.Fatalf("Could not find job: '%v'.", fields["job-id"]);
+        }
+
+        status := responseContent.Job.Status;
+        if ((status == grader.JOB_STATUS_DONE) || (status == grader.JOB_STATUS_FAILED)) {
+            return &responseContent;
+        }
+
+        time.Sleep(50 * time.Millisecond);
+    }
+
+    test.Fatalf("Job did not finish in time: '%v'.", fields["job-id"]);
+    return nil;
+}
@@ -0,0 +1,60 @@
+package api
+
+// Serving TLS certificates that can be reloaded without restarting the server.
+
+import (
+    "crypto/tls"
+    "fmt"
+    "sync"
+
+    "github.com/rs/zerolog/log"
+)
+
+type certificateLoader struct {
+    certPath string
+    keyPath string
+
+    lock sync.RWMutex
+    cert *tls.Certificate
+}
+
+// Create a loader and load the initial certificate.
+func newCertificateLoader(certPath string, keyPath string) (*certificateLoader, error) {
+    loader := &certificateLoader{
+        certPath: certPath,
+        keyPath: keyPath,
+    };
+
+    err := loader.reload();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return loader, nil;
+}
+
+// Load the certificate and key from disk.
+// On failure, the current certificate is kept.
+func (this *certificateLoader) reload() error {
+    cert, err := tls.LoadX509KeyPair(this.certPath, this.keyPath);
+    if (err != nil) {
+        return fmt.Errorf("Failed to load TLS certificate '%s' and key '%s': '%w'.", this.certPath, this.keyPath, err);
+    }
+
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    this.cert = &cert;
+
+    log.Info().Str("cert", this.certPath).Str("key", this.keyPath).Msg("Loaded TLS certificate.");
+
+    return nil;
+}
+
+// Suitable for tls.Config.GetCertificate.
+func (this *certificateLoader) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+    this.lock.RLock();
+    defer this.lock.RUnlock();
+
+    return this.cert, nil;
+}
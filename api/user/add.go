@@ -12,7 +12,7 @@ import (
 
 type AddRequest struct {
     core.APIRequestCourseUserContext
-    core.MinRoleAdmin
+    core.RequireManageUsers
     Users core.CourseUsers `json:"-"`
 
     NewUsers []*core.UserInfoWithPass `json:"new-users"`
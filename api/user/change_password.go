@@ -8,7 +8,7 @@ import (
 
 type ChangePasswordRequest struct {
     core.APIRequestCourseUserContext
-    core.MinRoleOther
+    core.RequireNone
 
     TargetUser core.TargetUserSelfOrAdmin `json:"target-email"`
     NewPass string `json:"new-pass"`
@@ -52,6 +52,16 @@ func HandleChangePassword(request *ChangePasswordRequest) (*ChangePasswordRespon
                 "Failed to save user.").Err(err).Add("email", request.TargetUser.Email);
     }
 
+    // Users changing their own password also change their server password (if they have a server account
+    // and authenticated with it).
+    // Otherwise, server passwords are left alone so an admin of one course cannot take over an account in another.
+    if (request.TargetUser.Email == request.User.Email) {
+        apiErr := updateServerPassword(request);
+        if (apiErr != nil) {
+            return nil, apiErr;
+        }
+    }
+
     if (pass != "") {
         err = model.SendUserAddEmail(request.Course, request.TargetUser.User, pass, true, true, false, false);
         if (err != nil) {
@@ -62,3 +72,27 @@ func HandleChangePassword(request *ChangePasswordRequest) (*ChangePasswordRespon
 
     return &response, nil;
 }
+
+func updateServerPassword(request *ChangePasswordRequest) *core.APIError {
+    serverUser, err := db.GetServerUser(request.User.Email);
+    if (err != nil) {
+        return core.NewInternalError("-817", &request.APIRequestCourseUserContext,
+                "Failed to get server user.").Err(err);
+    }
+
+    if ((serverUser == nil) || (request.UserPass == "") || !serverUser.CheckPassword(request.UserPass)) {
+        return nil;
+    }
+
+    // The course user was just given a fresh hash, share it.
+    serverUser.Pass = request.TargetUser.User.Pass;
+    serverUser.Salt = request.TargetUser.User.Salt;
+
+    err = db.SaveServerUser(serverUser);
+    if (err != nil) {
+        return core.NewInternalError("-818", &request.APIRequestCourseUserContext,
+                "Failed to save server user.").Err(err);
+    }
+
+    return nil;
+}
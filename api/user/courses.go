@@ -0,0 +1,26 @@
+package user
+
+import (
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+type CoursesRequest struct {
+    core.APIRequestUserContext
+}
+
+type CoursesResponse struct {
+    Courses []*model.Enrollment `json:"courses"`
+}
+
+// List the courses the server user is enrolled in.
+func HandleCourses(request *CoursesRequest) (*CoursesResponse, *core.APIError) {
+    enrollments, err := db.GetEnrollments(request.ServerUser.Email);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-816", request.Endpoint, "Failed to get enrollments.").
+                Err(err).Add("email", request.ServerUser.Email);
+    }
+
+    return &CoursesResponse{enrollments}, nil;
+}
@@ -0,0 +1,110 @@
+package user
+
+import (
+    "slices"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestUserCourses(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    // Without a server account, there is nothing to log into.
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/courses`), nil, nil, model.RoleStudent);
+    if (response.Success || (response.HTTPStatus != core.HTTP_STATUS_AUTH_ERROR)) {
+        test.Fatalf("Request without a server user did not fail authentication: '%v'.", response);
+    }
+
+    _, err := db.MigrateServerUsers(false);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate server users: '%v'.", err);
+    }
+
+    // Migrated accounts do not have a password until the user sets one.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/courses`), nil, nil, model.RoleStudent);
+    if (response.Success || (response.HTTPStatus != core.HTTP_STATUS_AUTH_ERROR)) {
+        test.Fatalf("Request without a server password did not fail authentication: '%v'.", response);
+    }
+
+    // Set the server password (as a password reset would).
+    serverUser, err := db.GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    err = serverUser.SetPassword(util.Sha256HexFromString("student"));
+    if (err != nil) {
+        test.Fatalf("Failed to set server password: '%v'.", err);
+    }
+
+    err = db.SaveServerUser(serverUser);
+    if (err != nil) {
+        test.Fatalf("Failed to save server user: '%v'.", err);
+    }
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/courses`), nil, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Response is not a success: '%v'.", response);
+    }
+
+    var responseContent CoursesResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+    expected := []string{"course-languages", "course-with-lms", "course-without-source", "course101", "course101-with-zero-limit"};
+    actual := make([]string, 0, len(responseContent.Courses));
+    for _, enrollment := range responseContent.Courses {
+        if (enrollment.Role != model.RoleStudent) {
+            test.Fatalf("Unexpected enrollment role: '%+v'.", enrollment);
+        }
+
+        actual = append(actual, enrollment.CourseID);
+    }
+
+    if (!slices.Equal(expected, actual)) {
+        test.Fatalf("Unexpected courses. Expected: '%v', Actual: '%v'.", expected, actual);
+    }
+
+    // A user changing their own password also changes their server password.
+    fields := map[string]any{
+        "new-pass": util.Sha256HexFromString("new-pass"),
+    };
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/change/pass`), fields, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Failed to change password: '%v'.", response);
+    }
+
+    serverUser, err = db.GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    if (!serverUser.CheckPassword(util.Sha256HexFromString("new-pass"))) {
+        test.Fatalf("Server password was not changed.");
+    }
+
+    // An admin changing someone else's password only changes the course password.
+    fields = map[string]any{
+        "target-email": "student@test.com",
+        "new-pass": util.Sha256HexFromString("admin-pass"),
+    };
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/change/pass`), fields, nil, model.RoleAdmin);
+    if (!response.Success) {
+        test.Fatalf("Failed to change password as admin: '%v'.", response);
+    }
+
+    serverUser, err = db.GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    if (!serverUser.CheckPassword(util.Sha256HexFromString("new-pass"))) {
+        test.Fatalf("Server password was changed by an admin.");
+    }
+}
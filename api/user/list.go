@@ -8,7 +8,7 @@ import (
 
 type ListRequest struct {
     core.APIRequestCourseUserContext
-    core.MinRoleGrader
+    core.RequireViewUsers
     Users core.CourseUsers `json:"-"`
 }
 
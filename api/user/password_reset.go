@@ -0,0 +1,139 @@
+package user
+
+// Users that forgot their password can request a reset token be sent to their email,
+// and then use that token to set a new password.
+// Reset tokens are single-use, expire, and are only held in memory (a server restart invalidates outstanding tokens).
+// Requests are rate limited per user and every use is logged.
+
+import (
+    "fmt"
+    "net/url"
+    "sync"
+    "time"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/email"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+const PASSWORD_RESET_TOKEN_NAME = "password-reset";
+
+// Outstanding reset tokens (at most one per user) and the last time a reset was requested for each user.
+// Both are keyed by passwordResetKey().
+var passwordResetTokens map[string]*model.APIToken = make(map[string]*model.APIToken);
+var passwordResetRequests map[string]time.Time = make(map[string]time.Time);
+var passwordResetLock sync.Mutex;
+
+func passwordResetKey(courseID string, email string) string {
+    return courseID + "::" + email;
+}
+
+// Record a reset request for a user.
+// Returns false if the user has made a request too recently.
+func checkPasswordResetRateLimit(courseID string, email string) bool {
+    passwordResetLock.Lock();
+    defer passwordResetLock.Unlock();
+
+    now := time.Now();
+    cooldown := time.Duration(config.PASSWORD_RESET_COOLDOWN_SECS.Get()) * time.Second;
+
+    // Drop any old entries while we are here.
+    for key, lastRequest := range passwordResetRequests {
+        if (now.Sub(lastRequest) >= cooldown) {
+            delete(passwordResetRequests, key);
+        }
+    }
+
+    for key, token := range passwordResetTokens {
+        if (token.IsExpired()) {
+            delete(passwordResetTokens, key);
+        }
+    }
+
+    key := passwordResetKey(courseID, email);
+
+    _, ok := passwordResetRequests[key];
+    if (ok) {
+        return false;
+    }
+
+    passwordResetRequests[key] = now;
+    return true;
+}
+
+// Create a new reset token for a user (replacing any existing one) and return the cleartext token.
+func newPasswordResetToken(courseID string, email string) (string, error) {
+    duration := time.Duration(config.PASSWORD_RESET_MINS.Get()) * time.Minute;
+
+    token, cleartext, err := model.NewAPIToken(email, PASSWORD_RESET_TOKEN_NAME, duration);
+    if (err != nil) {
+        return "", err;
+    }
+
+    passwordResetLock.Lock();
+    defer passwordResetLock.Unlock();
+
+    passwordResetTokens[passwordResetKey(courseID, email)] = token;
+
+    return cleartext, nil;
+}
+
+// Check a reset token and remove it on success (so it cannot be used again).
+// Returns true if the token is valid.
+func usePasswordResetToken(courseID string, email string, cleartext string) bool {
+    passwordResetLock.Lock();
+    defer passwordResetLock.Unlock();
+
+    key := passwordResetKey(courseID, email);
+
+    token := passwordResetTokens[key];
+    if ((token == nil) || !token.Check(cleartext)) {
+        return false;
+    }
+
+    // Used or expired, the token is done either way.
+    delete(passwordResetTokens, key);
+
+    return !token.IsExpired();
+}
+
+// Clear all reset state (for testing).
+func clearPasswordResets() {
+    passwordResetLock.Lock();
+    defer passwordResetLock.Unlock();
+
+    passwordResetTokens = make(map[string]*model.APIToken);
+    passwordResetRequests = make(map[string]time.Time);
+}
+
+func sendPasswordResetEmail(course *model.Course, address string, cleartext string) error {
+    subject := fmt.Sprintf("Autograder %s -- Password Reset", course.GetID());
+    body :=
+        "Hello,\n" +
+        fmt.Sprintf("\nA password reset was requested for '%s' in the course '%s'.\n", address, course.GetDisplayName()) +
+        fmt.Sprintf("Your reset token is '%s' (no quotes).\n", cleartext);
+
+    if (config.PASSWORD_RESET_URL.Get() != "") {
+        query := url.Values{};
+        query.Set("course-id", course.GetID());
+        query.Set("user-email", address);
+        query.Set("reset-token", cleartext);
+
+        body += fmt.Sprintf("You can also reset your password at: %s?%s\n", config.PASSWORD_RESET_URL.Get(), query.Encode());
+    }
+
+    body +=
+        fmt.Sprintf("The token can only be used once, and expires in %d minutes.\n", config.PASSWORD_RESET_MINS.Get()) +
+        "If you did not request a password reset, you can ignore this email.\n";
+
+    err := email.Send([]string{address}, subject, body, false);
+    if (err != nil) {
+        return err;
+    }
+
+    log.Info().Str("course-id", course.GetID()).Str("email", address).Msg("Password reset email sent.");
+
+    return nil;
+}
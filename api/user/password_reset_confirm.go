@@ -0,0 +1,90 @@
+package user
+
+import (
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+// Confirm requests are authenticated with the reset token instead of a password.
+type PasswordResetConfirmRequest struct {
+    core.APIRequest
+
+    CourseID core.NonEmptyString `json:"course-id"`
+    UserEmail core.NonEmptyString `json:"user-email"`
+    ResetToken core.NonEmptyString `json:"reset-token"`
+    NewPass core.NonEmptyString `json:"new-pass"`
+}
+
+type PasswordResetConfirmResponse struct {}
+
+// Set a new password for a user that has a valid reset token.
+// Since the user has proven they own the email, their server password is also set
+// (creating their server account if they do not have one).
+func HandlePasswordResetConfirm(request *PasswordResetConfirmRequest) (*PasswordResetConfirmResponse, *core.APIError) {
+    courseID := string(request.CourseID);
+    email := string(request.UserEmail);
+
+    course, err := db.GetCourse(courseID);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-825", request.Endpoint, "Unable to get course.").Err(err).Add("course-id", courseID);
+    }
+
+    if (course == nil) {
+        return nil, core.NewBadRequestError("-826", &request.APIRequest, "Could not find course.").Add("course-id", courseID);
+    }
+
+    if (!usePasswordResetToken(course.GetID(), email, string(request.ResetToken))) {
+        log.Warn().Str("course-id", courseID).Str("email", email).Msg("Password reset attempted with a bad token.");
+        return nil, core.NewBadRequestError("-827", &request.APIRequest, "Invalid or expired reset token.").
+                Add("course-id", courseID).Add("email", email);
+    }
+
+    user, err := db.GetUser(course, email);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-828", request.Endpoint, "Failed to get user.").
+                Err(err).Add("course-id", courseID).Add("email", email);
+    }
+
+    if (user == nil) {
+        return nil, core.NewBadRequestError("-829", &request.APIRequest, "User no longer exists.").
+                Add("course-id", courseID).Add("email", email);
+    }
+
+    err = user.SetPassword(string(request.NewPass));
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-830", request.Endpoint, "Failed to set password.").
+                Err(err).Add("course-id", courseID).Add("email", email);
+    }
+
+    err = db.SaveUser(course, user);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-831", request.Endpoint, "Failed to save user.").
+                Err(err).Add("course-id", courseID).Add("email", email);
+    }
+
+    serverUser, err := db.GetServerUser(email);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-832", request.Endpoint, "Failed to get server user.").
+                Err(err).Add("email", email);
+    }
+
+    if (serverUser == nil) {
+        serverUser = model.NewServerUser(email, user.Name);
+    }
+
+    serverUser.Pass = user.Pass;
+    serverUser.Salt = user.Salt;
+
+    err = db.SaveServerUser(serverUser);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-833", request.Endpoint, "Failed to save server user.").
+                Err(err).Add("email", email);
+    }
+
+    log.Info().Str("course-id", courseID).Str("email", email).Msg("Password reset.");
+
+    return &PasswordResetConfirmResponse{}, nil;
+}
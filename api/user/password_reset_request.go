@@ -0,0 +1,75 @@
+package user
+
+import (
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+)
+
+// Reset requests are not authenticated (the user does not know their password).
+type PasswordResetRequestRequest struct {
+    core.APIRequest
+
+    CourseID core.NonEmptyString `json:"course-id"`
+    UserEmail core.NonEmptyString `json:"user-email"`
+}
+
+// The response is the same whether or not the user exists,
+// so this endpoint cannot be used to discover users.
+type PasswordResetRequestResponse struct {}
+
+func HandlePasswordResetRequest(request *PasswordResetRequestRequest) (*PasswordResetRequestResponse, *core.APIError) {
+    courseID := string(request.CourseID);
+    email := string(request.UserEmail);
+
+    log.Info().Str("course-id", courseID).Str("email", email).Msg("Password reset requested.");
+
+    course, err := db.GetCourse(courseID);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-820", request.Endpoint, "Unable to get course.").Err(err).Add("course-id", courseID);
+    }
+
+    if (course == nil) {
+        return nil, core.NewBadRequestError("-821", &request.APIRequest, "Could not find course.").Add("course-id", courseID);
+    }
+
+    user, err := db.GetUser(course, email);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-822", request.Endpoint, "Failed to get user.").
+                Err(err).Add("course-id", courseID).Add("email", email);
+    }
+
+    // Rate limit on the stored IDs (the same ones the reset token uses),
+    // so that differently written IDs for the same user cannot get around the limit.
+    // Unknown users are also limited, so that they get the same responses as known users.
+    rateLimitEmail := email;
+    if (user != nil) {
+        rateLimitEmail = user.Email;
+    }
+
+    if (!checkPasswordResetRateLimit(course.GetID(), rateLimitEmail)) {
+        log.Warn().Str("course-id", course.GetID()).Str("email", rateLimitEmail).Msg("Password reset request rate limited.");
+        return nil, core.NewBadRequestError("-819", &request.APIRequest, "Too many password reset requests, try again later.").
+                Add("course-id", course.GetID()).Add("email", rateLimitEmail);
+    }
+
+    if (user == nil) {
+        log.Info().Str("course-id", courseID).Str("email", email).Msg("Password reset requested for unknown user.");
+        return &PasswordResetRequestResponse{}, nil;
+    }
+
+    cleartext, err := newPasswordResetToken(course.GetID(), user.Email);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-823", request.Endpoint, "Failed to create reset token.").
+                Err(err).Add("course-id", courseID).Add("email", email);
+    }
+
+    err = sendPasswordResetEmail(course, user.Email, cleartext);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-824", request.Endpoint, "Failed to send reset email.").
+                Err(err).Add("course-id", courseID).Add("email", email);
+    }
+
+    return &PasswordResetRequestResponse{}, nil;
+}
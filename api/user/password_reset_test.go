@@ -0,0 +1,136 @@
+package user
+
+import (
+    "regexp"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/email"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+var resetTokenRegex *regexp.Regexp = regexp.MustCompile(`reset token is '([^']+)'`);
+
+func TestPasswordReset(test *testing.T) {
+    defer db.ResetForTesting();
+    defer clearPasswordResets();
+
+    db.ResetForTesting();
+    clearPasswordResets();
+    email.ClearTestMessages();
+
+    // The student does not have a server account yet, the reset will create one.
+    requestFields := map[string]any{
+        "user-email": "student@test.com",
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/pass/reset/request`), requestFields, nil, model.RoleOther);
+    if (!response.Success) {
+        test.Fatalf("Reset request is not a success: '%v'.", response);
+    }
+
+    messages := email.GetTestMessages();
+    if ((len(messages) != 1) || (messages[0].To[0] != "student@test.com")) {
+        test.Fatalf("Unexpected reset emails: '%+v'.", messages);
+    }
+
+    match := resetTokenRegex.FindStringSubmatch(messages[0].Body);
+    if (match == nil) {
+        test.Fatalf("Could not find reset token in email: '%s'.", messages[0].Body);
+    }
+
+    resetToken := match[1];
+
+    // Requests are rate limited.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/pass/reset/request`), requestFields, nil, model.RoleOther);
+    if (response.Success || (response.Locator != "-819")) {
+        test.Fatalf("Second reset request was not rate limited: '%v'.", response);
+    }
+
+    // Writing the course ID differently is still the same course.
+    otherCaseFields := map[string]any{
+        "course-id": "COURSE101",
+        "user-email": "student@test.com",
+    };
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/pass/reset/request`), otherCaseFields, nil, model.RoleOther);
+    if (response.Success || (response.Locator != "-819")) {
+        test.Fatalf("Reset request with a differently cased course ID was not rate limited: '%v'.", response);
+    }
+
+    if (len(email.GetTestMessages()) != 1) {
+        test.Fatalf("Unexpected number of reset emails. Expected: 1, Actual: %d.", len(email.GetTestMessages()));
+    }
+
+    confirmFields := map[string]any{
+        "user-email": "student@test.com",
+        "reset-token": resetToken + "Z",
+        "new-pass": util.Sha256HexFromString("reset"),
+    };
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/pass/reset/confirm`), confirmFields, nil, model.RoleOther);
+    if (response.Success || (response.Locator != "-827")) {
+        test.Fatalf("Reset with a bad token was not rejected: '%v'.", response);
+    }
+
+    confirmFields["reset-token"] = resetToken;
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/pass/reset/confirm`), confirmFields, nil, model.RoleOther);
+    if (!response.Success) {
+        test.Fatalf("Reset confirm is not a success: '%v'.", response);
+    }
+
+    user, err := db.GetUser(db.MustGetTestCourse(), "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    if (!user.CheckPassword(util.Sha256HexFromString("reset"))) {
+        test.Fatalf("Course password was not reset.");
+    }
+
+    serverUser, err := db.GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    if (!serverUser.CheckPassword(util.Sha256HexFromString("reset"))) {
+        test.Fatalf("Server password was not reset.");
+    }
+
+    // Tokens are single-use.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/pass/reset/confirm`), confirmFields, nil, model.RoleOther);
+    if (response.Success || (response.Locator != "-827")) {
+        test.Fatalf("Reset token was used twice: '%v'.", response);
+    }
+}
+
+func TestPasswordResetUnknownUser(test *testing.T) {
+    defer clearPasswordResets();
+
+    clearPasswordResets();
+    email.ClearTestMessages();
+
+    fields := map[string]any{
+        "user-email": "ZZZ@test.com",
+    };
+
+    // Unknown users get the same response, but no email.
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/pass/reset/request`), fields, nil, model.RoleOther);
+    if (!response.Success) {
+        test.Fatalf("Reset request is not a success: '%v'.", response);
+    }
+
+    if (len(email.GetTestMessages()) != 0) {
+        test.Fatalf("Email sent for unknown user: '%+v'.", email.GetTestMessages());
+    }
+
+    fields["course-id"] = "ZZZ";
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/pass/reset/request`), fields, nil, model.RoleOther);
+    if (response.Success || (response.Locator != "-821")) {
+        test.Fatalf("Reset request for unknown course did not fail: '%v'.", response);
+    }
+}
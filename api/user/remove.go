@@ -7,7 +7,7 @@ import (
 
 type RemoveRequest struct {
     core.APIRequestCourseUserContext
-    core.MinRoleAdmin
+    core.RequireManageUsers
 
     TargetUser core.TargetUser `json:"target-email"`
 }
@@ -10,9 +10,15 @@ var routes []*core.Route = []*core.Route{
     core.NewAPIRoute(core.NewEndpoint(`user/add`), HandleAdd),
     core.NewAPIRoute(core.NewEndpoint(`user/auth`), HandleAuth),
     core.NewAPIRoute(core.NewEndpoint(`user/change/pass`), HandleChangePassword),
+    core.NewAPIRoute(core.NewEndpoint(`user/courses`), HandleCourses),
     core.NewAPIRoute(core.NewEndpoint(`user/get`), HandleUserGet),
     core.NewAPIRoute(core.NewEndpoint(`user/list`), HandleList),
+    core.NewAPIRoute(core.NewEndpoint(`user/pass/reset/confirm`), HandlePasswordResetConfirm),
+    core.NewAPIRoute(core.NewEndpoint(`user/pass/reset/request`), HandlePasswordResetRequest),
     core.NewAPIRoute(core.NewEndpoint(`user/remove`), HandleRemove),
+    core.NewAPIRoute(core.NewEndpoint(`user/token/create`), HandleTokenCreate),
+    core.NewAPIRoute(core.NewEndpoint(`user/token/list`), HandleTokenList),
+    core.NewAPIRoute(core.NewEndpoint(`user/token/revoke`), HandleTokenRevoke),
 };
 
 func GetRoutes() *[]*core.Route {
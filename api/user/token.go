@@ -0,0 +1,49 @@
+package user
+
+// How to represent API tokens in API responses.
+// The token hash is never sent out.
+
+import (
+    "slices"
+    "strings"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+type TokenInfo struct {
+    ID string `json:"id"`
+    Name string `json:"name"`
+    CreationTime common.Timestamp `json:"creation-time"`
+    ExpirationTime common.Timestamp `json:"expiration-time"`
+    Expired bool `json:"expired"`
+}
+
+func NewTokenInfo(token *model.APIToken) *TokenInfo {
+    return &TokenInfo{
+        ID: token.ID,
+        Name: token.Name,
+        CreationTime: token.CreationTime,
+        ExpirationTime: token.ExpirationTime,
+        Expired: token.IsExpired(),
+    };
+}
+
+// Get infos for a set of tokens, ordered by creation time.
+func NewTokenInfos(tokens map[string]*model.APIToken) []*TokenInfo {
+    infos := make([]*TokenInfo, 0, len(tokens));
+    for _, token := range tokens {
+        infos = append(infos, NewTokenInfo(token));
+    }
+
+    slices.SortFunc(infos, func(a *TokenInfo, b *TokenInfo) int {
+        result := strings.Compare(string(a.CreationTime), string(b.CreationTime));
+        if (result != 0) {
+            return result;
+        }
+
+        return strings.Compare(a.ID, b.ID);
+    });
+
+    return infos;
+}
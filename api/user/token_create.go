@@ -0,0 +1,56 @@
+package user
+
+import (
+    "time"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+type TokenCreateRequest struct {
+    core.APIRequestCourseUserContext
+    core.RequireNone
+
+    Name core.NonEmptyString `json:"name"`
+    // The number of days until the token expires (defaults to the api.token.days option).
+    Days int `json:"days"`
+}
+
+type TokenCreateResponse struct {
+    // The cleartext token, this is the only time it will be available.
+    Token string `json:"token"`
+    Info *TokenInfo `json:"info"`
+}
+
+func HandleTokenCreate(request *TokenCreateRequest) (*TokenCreateResponse, *core.APIError) {
+    // A leaked token should not be able to extend its own life.
+    if (request.Token != nil) {
+        return nil, core.NewBadRequestError("-809", &request.APIRequest, "New tokens can only be created using a password.").
+                Add("token-id", request.Token.ID);
+    }
+
+    days := request.Days;
+    if (days == 0) {
+        days = config.API_TOKEN_DAYS.Get();
+    }
+
+    if ((days < 0) || (days > config.API_TOKEN_MAX_DAYS.Get())) {
+        return nil, core.NewBadRequestError("-810", &request.APIRequest, "Invalid number of days for token.").
+                Add("days", days).Add("max-days", config.API_TOKEN_MAX_DAYS.Get());
+    }
+
+    token, cleartext, err := model.NewAPIToken(request.User.Email, string(request.Name), time.Duration(days) * 24 * time.Hour);
+    if (err != nil) {
+        return nil, core.NewInternalError("-811", &request.APIRequestCourseUserContext, "Failed to create token.").Err(err);
+    }
+
+    err = db.SaveAPIToken(request.Course, token);
+    if (err != nil) {
+        return nil, core.NewInternalError("-812", &request.APIRequestCourseUserContext, "Failed to save token.").
+                Err(err).Add("token-id", token.ID);
+    }
+
+    return &TokenCreateResponse{cleartext, NewTokenInfo(token)}, nil;
+}
@@ -0,0 +1,40 @@
+package user
+
+import (
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+)
+
+type TokenListRequest struct {
+    core.APIRequestCourseUserContext
+    core.RequireNone
+
+    TargetUser core.TargetUserSelfOrAdmin `json:"target-email"`
+}
+
+type TokenListResponse struct {
+    FoundUser bool `json:"found-user"`
+    Tokens []*TokenInfo `json:"tokens"`
+}
+
+func HandleTokenList(request *TokenListRequest) (*TokenListResponse, *core.APIError) {
+    response := TokenListResponse{
+        Tokens: make([]*TokenInfo, 0),
+    };
+
+    if (!request.TargetUser.Found) {
+        return &response, nil;
+    }
+
+    response.FoundUser = true;
+
+    tokens, err := db.GetAPITokens(request.Course, request.TargetUser.Email);
+    if (err != nil) {
+        return nil, core.NewInternalError("-813", &request.APIRequestCourseUserContext, "Failed to get tokens.").
+                Err(err).Add("email", request.TargetUser.Email);
+    }
+
+    response.Tokens = NewTokenInfos(tokens);
+
+    return &response, nil;
+}
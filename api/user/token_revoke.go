@@ -0,0 +1,44 @@
+package user
+
+import (
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+)
+
+type TokenRevokeRequest struct {
+    core.APIRequestCourseUserContext
+    core.RequireNone
+
+    TargetUser core.TargetUserSelfOrAdmin `json:"target-email"`
+    TokenID core.NonEmptyString `json:"token-id"`
+}
+
+type TokenRevokeResponse struct {
+    FoundUser bool `json:"found-user"`
+    FoundToken bool `json:"found-token"`
+}
+
+func HandleTokenRevoke(request *TokenRevokeRequest) (*TokenRevokeResponse, *core.APIError) {
+    response := TokenRevokeResponse{};
+
+    if (!request.TargetUser.Found) {
+        return &response, nil;
+    }
+
+    response.FoundUser = true;
+
+    if (request.TargetUser.User.Role > request.User.Role) {
+        return nil, core.NewBadPermissionsError("-814", &request.APIRequestCourseUserContext, request.TargetUser.User.Role,
+                "Cannot revoke tokens for a user with a higher role.").Add("target-user", request.TargetUser.User.Email);
+    }
+
+    exists, err := db.RemoveAPIToken(request.Course, request.TargetUser.Email, string(request.TokenID));
+    if (err != nil) {
+        return nil, core.NewInternalError("-815", &request.APIRequestCourseUserContext, "Failed to revoke token.").
+                Err(err).Add("email", request.TargetUser.Email).Add("token-id", string(request.TokenID));
+    }
+
+    response.FoundToken = exists;
+
+    return &response, nil;
+}
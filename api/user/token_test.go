@@ -0,0 +1,162 @@
+package user
+
+import (
+    "testing"
+
+    "github.com/eriq-augustine/autograder/api/core"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestTokenLifecycle(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/create`), map[string]any{"name": "test"}, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Failed to create token: '%v'.", response);
+    }
+
+    var createContent TokenCreateResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &createContent);
+
+    if ((createContent.Token == "") || (createContent.Info == nil) || (createContent.Info.Name != "test") || createContent.Info.Expired) {
+        test.Fatalf("Unexpected created token: '%s'.", util.MustToJSONIndent(createContent));
+    }
+
+    tokenFields := map[string]any{
+        "user-pass": "",
+        "user-token": createContent.Token,
+    };
+
+    // List using the token instead of a password.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/list`), tokenFields, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Failed to list tokens using a token: '%v'.", response);
+    }
+
+    var listContent TokenListResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &listContent);
+
+    if ((len(listContent.Tokens) != 1) || (listContent.Tokens[0].ID != createContent.Info.ID)) {
+        test.Fatalf("Unexpected token list: '%s'.", util.MustToJSONIndent(listContent));
+    }
+
+    // Tokens cannot create more tokens.
+    fields := map[string]any{"name": "another"};
+    for key, value := range tokenFields {
+        fields[key] = value;
+    }
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/create`), fields, nil, model.RoleStudent);
+    if (response.Success || (response.Locator != "-809")) {
+        test.Fatalf("Unexpected response when creating a token with a token: '%v'.", response);
+    }
+
+    // Graders cannot see a student's tokens, but admins can.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/list`), map[string]any{"target-email": "student@test.com"}, nil, model.RoleGrader);
+    if (response.Success || (response.Locator != "-033")) {
+        test.Fatalf("Unexpected response when a grader lists a student's tokens: '%v'.", response);
+    }
+
+    revokeFields := map[string]any{
+        "target-email": "student@test.com",
+        "token-id": createContent.Info.ID,
+    };
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/revoke`), revokeFields, nil, model.RoleAdmin);
+    if (!response.Success) {
+        test.Fatalf("Failed to revoke token: '%v'.", response);
+    }
+
+    var revokeContent TokenRevokeResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &revokeContent);
+
+    if (!revokeContent.FoundUser || !revokeContent.FoundToken) {
+        test.Fatalf("Unexpected revoke response: '%+v'.", revokeContent);
+    }
+
+    // A revoked token no longer works.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/list`), tokenFields, nil, model.RoleStudent);
+    if (response.Success || (response.HTTPStatus != core.HTTP_STATUS_AUTH_ERROR)) {
+        test.Fatalf("Unexpected response when using a revoked token: '%v'.", response);
+    }
+}
+
+func TestTokenCreateDays(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    testCases := []struct{days int; locator string}{
+        {0, ""},
+        {1, ""},
+        {365, ""},
+        {-1, "-810"},
+        {366, "-810"},
+    };
+
+    for i, testCase := range testCases {
+        fields := map[string]any{
+            "name": "test",
+            "days": testCase.days,
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/create`), fields, nil, model.RoleStudent);
+        if (testCase.locator == "") {
+            if (!response.Success) {
+                test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            }
+        } else if (response.Success || (response.Locator != testCase.locator)) {
+            test.Errorf("Case %d: Unexpected response. Expected locator '%s', found: '%v'.", i, testCase.locator, response);
+        }
+    }
+}
+
+// Revoking another user's token is recorded in the audit log (even though it needs no capability),
+// but revoking your own token is not.
+func TestTokenRevokeAudit(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    tokenIDs := make([]string, 0, 2);
+    for i := 0; i < 2; i++ {
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/create`), map[string]any{"name": "test"}, nil, model.RoleStudent);
+        if (!response.Success) {
+            test.Fatalf("Failed to create token: '%v'.", response);
+        }
+
+        var createContent TokenCreateResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &createContent);
+        tokenIDs = append(tokenIDs, createContent.Info.ID);
+    }
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/revoke`), map[string]any{"token-id": tokenIDs[0]}, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Failed to revoke own token: '%v'.", response);
+    }
+
+    revokeFields := map[string]any{
+        "target-email": "student@test.com",
+        "token-id": tokenIDs[1],
+    };
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/revoke`), revokeFields, nil, model.RoleAdmin);
+    if (!response.Success) {
+        test.Fatalf("Failed to revoke another user's token: '%v'.", response);
+    }
+
+    records, err := db.GetAuditRecords(model.AuditQuery{Action: "user/token/revoke"});
+    if (err != nil) {
+        test.Fatalf("Failed to get audit records: '%v'.", err);
+    }
+
+    if (len(records) != 1) {
+        test.Fatalf("Unexpected number of records. Expected: 1, Actual: %d.", len(records));
+    }
+
+    record := records[0];
+    if ((record.Actor != "admin@test.com") || (record.Target != "student@test.com") || (record.CourseID != "course101") || !record.Success) {
+        test.Fatalf("Unexpected record: '%+v'.", record);
+    }
+}
@@ -47,6 +47,14 @@ func main() {
     }
 
     courseIDs, err := db.AddCoursesFromDir(tempDir, spec);
+    for _, courseID := range courseIDs {
+        db.AuditCommand("add-course-from-source", courseID, "", args, err);
+    }
+
+    if (len(courseIDs) == 0) {
+        db.AuditCommand("add-course-from-source", "", "", args, err);
+    }
+
     if (err != nil) {
         log.Fatal().Err(err).Msg("Failed to add course dir.");
     }
@@ -30,7 +30,14 @@ func main() {
 
     courseIDs := make([]string, 0);
     for _, path := range args.Path {
-        course := db.MustAddCourse(path);
+        course, err := db.AddCourse(path, nil);
+        if (err != nil) {
+            db.AuditCommand("add-courses", "", path, args, err);
+            log.Fatal().Err(err).Str("path", path).Msg("Failed to add course.");
+        }
+
+        db.AuditCommand("add-courses", course.GetID(), path, args, nil);
+
         fmt.Printf("Added course %s ('%s').\n", course.GetID(), path);
         courseIDs = append(courseIDs, course.GetID());
     }
@@ -25,9 +25,10 @@ func main() {
     db.MustOpen();
     defer db.MustClose();
 
+    // The audit log is cleared with the rest of the database, so this record will be the first in the new log.
     err = db.Clear();
+    db.AuditCommand("clear-db", "", "", args, err);
     if (err != nil) {
         log.Fatal().Err(err).Msg("Failed to clear database.");
     }
-
 }
@@ -0,0 +1,116 @@
+package main
+
+import (
+    "fmt"
+
+    "github.com/alecthomas/kong"
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+)
+
+var args struct {
+    config.ConfigArgs
+
+    SourceType string `help:"Type of the database to migrate from (disk, sqlite, postgres)." arg:""`
+    TargetType string `help:"Type of the database to migrate to (disk, sqlite, postgres)." arg:""`
+
+    SourcePGURI string `help:"Postgres connection URI for the source database (defaults to db.pg.uri)."`
+    SourceSQLitePath string `help:"Path to the source SQLite database (defaults to db.sqlite.path)."`
+    TargetPGURI string `help:"Postgres connection URI for the target database (defaults to db.pg.uri)."`
+    TargetSQLitePath string `help:"Path to the target SQLite database (defaults to db.sqlite.path)."`
+
+    SkipVerify bool `help:"Do not check that the target matches the source after migrating." default:"false"`
+}
+
+func main() {
+    kong.Parse(&args,
+        kong.Description("Copy all courses (including users, submissions, and task completions) from one database to another." +
+                " Existing data in the target for migrated courses will be replaced."),
+    );
+
+    err := config.HandleConfigArgs(args.ConfigArgs);
+    if (err != nil) {
+        log.Fatal().Err(err).Msg("Could not load config options.");
+    }
+
+    if (isSameDatabase()) {
+        log.Fatal().Str("type", args.SourceType).Msg("The source and target are the same database.");
+    }
+
+    source := mustOpenBackend(args.SourceType, args.SourcePGURI, args.SourceSQLitePath);
+    defer source.Close();
+
+    target := mustOpenBackend(args.TargetType, args.TargetPGURI, args.TargetSQLitePath);
+    defer target.Close();
+
+    courseIDs, err := db.MigrateBackend(source, target);
+    if (err != nil) {
+        log.Fatal().Err(err).Msg("Failed to migrate database.");
+    }
+
+    fmt.Printf("Migrated %d courses:\n", len(courseIDs));
+    for _, courseID := range courseIDs {
+        fmt.Printf("    %s\n", courseID);
+    }
+
+    if (args.SkipVerify) {
+        return;
+    }
+
+    summary, err := db.VerifyMigration(source, target);
+    if (err != nil) {
+        log.Fatal().Err(err).Msg("Target database does not match the source.");
+    }
+
+    fmt.Printf("Verified %d courses, %d assignments, %d users, %d submissions, %d task completions, %d API tokens, %d server users, and %d audit records.\n",
+            summary.Courses, summary.Assignments, summary.Users, summary.Submissions, summary.TaskCompletions, summary.APITokens,
+            summary.ServerUsers, summary.AuditRecords);
+}
+
+func mustOpenBackend(dbType string, pgURI string, sqlitePath string) db.Backend {
+    oldPGURI := config.DB_PG_URI.Get();
+    oldSQLitePath := config.DB_SQLITE_PATH.Get();
+
+    defer config.DB_PG_URI.Set(oldPGURI);
+    defer config.DB_SQLITE_PATH.Set(oldSQLitePath);
+
+    if (pgURI != "") {
+        config.DB_PG_URI.Set(pgURI);
+    }
+
+    if (sqlitePath != "") {
+        config.DB_SQLITE_PATH.Set(sqlitePath);
+    }
+
+    backend, err := db.OpenBackend(dbType);
+    if (err != nil) {
+        log.Fatal().Err(err).Str("type", dbType).Msg("Failed to open database.");
+    }
+
+    return backend;
+}
+
+func isSameDatabase() bool {
+    if (args.SourceType != args.TargetType) {
+        return false;
+    }
+
+    switch args.SourceType {
+        case db.DB_TYPE_POSTGRES:
+            return (getDefault(args.SourcePGURI, config.DB_PG_URI.Get()) == getDefault(args.TargetPGURI, config.DB_PG_URI.Get()));
+        case db.DB_TYPE_SQLITE:
+            return (getDefault(args.SourceSQLitePath, config.DB_SQLITE_PATH.Get()) == getDefault(args.TargetSQLitePath, config.DB_SQLITE_PATH.Get()));
+        default:
+            return true;
+    }
+}
+
+func getDefault(value string, defaultValue string) string {
+    if (value == "") {
+        return defaultValue;
+    }
+
+    return value;
+}
@@ -37,6 +37,7 @@ func main() {
     }
 
     err = scoring.FullAssignmentScoringAndUpload(assignment, args.DryRun);
+    db.AuditCommand("lms-assignment-score-upload", args.Course, assignment.GetID(), args, err);
     if (err != nil) {
         log.Fatal().Err(err).Msg("Failed to score and upload assignment.");
     }
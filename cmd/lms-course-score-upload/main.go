@@ -33,6 +33,7 @@ func main() {
     course := db.MustGetCourse(args.Course);
 
     err = scoring.FullCourseScoringAndUpload(course, args.DryRun);
+    db.AuditCommand("lms-course-score-upload", course.GetID(), "", args, err);
     if (err != nil) {
         log.Fatal().Err(err).Msg("Failed to score and upload assignment.");
     }
@@ -35,6 +35,7 @@ func main() {
     course := db.MustGetCourse(args.Course);
 
     result, err := lmssync.SyncLMS(course, args.DryRun, !args.SkipEmails);
+    db.AuditCommand("lms-sync", course.GetID(), "", args, err);
     if (err != nil) {
         log.Fatal().Err(err).Msg("Failed to sync LMS.");
     }
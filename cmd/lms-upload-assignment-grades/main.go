@@ -61,6 +61,7 @@ func main() {
         fmt.Println("Dry Run: Skipping upload.");
     } else {
         err = lms.UpdateAssignmentScores(course, assignment.GetLMSID(), grades);
+        db.AuditCommand("lms-upload-assignment-grades", course.GetID(), assignment.GetID(), args, err);
         if (err != nil) {
             log.Fatal().Err(err).Msg("Could not upload grades.");
         }
@@ -0,0 +1,56 @@
+package main
+
+import (
+    "fmt"
+
+    "github.com/alecthomas/kong"
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+var args struct {
+    config.ConfigArgs
+
+    DryRun bool `help:"Do not create any server users, just state what would be done." default:"false"`
+    JSON bool `help:"Output the full result as JSON." default:"false"`
+}
+
+func main() {
+    kong.Parse(&args,
+        kong.Description("Create a server user for every course user that does not already have one." +
+                " New server users do not have a password, users set their own server password with a password reset." +
+                " Existing server users are never modified."),
+    );
+
+    err := config.HandleConfigArgs(args.ConfigArgs);
+    if (err != nil) {
+        log.Fatal().Err(err).Msg("Could not load config options.");
+    }
+
+    db.MustOpen();
+    defer db.MustClose();
+
+    result, err := db.MigrateServerUsers(args.DryRun);
+    db.AuditCommand("migrate-server-users", "", "", args, err);
+    if (err != nil) {
+        log.Fatal().Err(err).Msg("Failed to migrate server users.");
+    }
+
+    if (args.JSON) {
+        fmt.Println(util.MustToJSONIndent(result));
+        return;
+    }
+
+    verb := "Created";
+    if (args.DryRun) {
+        verb = "Would create";
+    }
+
+    fmt.Printf("%s %d server users (%d users already had one).\n", verb, len(result.Added), len(result.Existing));
+    for _, email := range result.Added {
+        fmt.Printf("    %s\n", email);
+    }
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+    "fmt"
+
+    "github.com/alecthomas/kong"
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/grader"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+var args struct {
+    config.ConfigArgs
+    Course string `help:"ID of the course." arg:""`
+    Assignment string `help:"ID of the assignment." arg:""`
+    User []string `help:"Only regrade submissions from these users (may be repeated). Defaults to all users."`
+    AllSubmissions bool `help:"Regrade all of each user's submissions instead of just their most recent one." default:"false"`
+    DryRun bool `help:"Grade and show the differences, but do not store the new results." default:"false"`
+    Overwrite bool `help:"Replace the old submissions instead of storing the new results as new submissions." default:"false"`
+    JSON bool `help:"Output the full results as JSON." default:"false"`
+}
+
+func main() {
+    kong.Parse(&args,
+        kong.Description("Rerun an assignment's grader over stored submissions."),
+    );
+
+    err := config.HandleConfigArgs(args.ConfigArgs);
+    if (err != nil) {
+        log.Fatal().Err(err).Msg("Could not load config options.");
+    }
+
+    if (args.DryRun && args.Overwrite) {
+        log.Fatal().Msg("Cannot use --dry-run and --overwrite together.");
+    }
+
+    db.MustOpen();
+    defer db.MustClose();
+
+    assignment := db.MustGetAssignment(args.Course, args.Assignment);
+
+    options := grader.RegradeOptions{
+        GradeOptions: grader.GetDefaultGradeOptions(),
+        Users: args.User,
+        AllSubmissions: args.AllSubmissions,
+        DryRun: args.DryRun,
+        Overwrite: args.Overwrite,
+    };
+
+    results, err := grader.Regrade(assignment, options);
+    db.AuditCommand("regrade", args.Course, args.Assignment, args, err);
+    if (err != nil) {
+        log.Fatal().Err(err).Str("assignment", assignment.FullID()).Msg("Failed to regrade.");
+    }
+
+    if (args.JSON) {
+        fmt.Println(util.MustToJSONIndent(results));
+        return;
+    }
+
+    numChanged := 0;
+    numErrors := 0;
+
+    for _, result := range results {
+        if (result.Error != "") {
+            numErrors++;
+            fmt.Printf("%s (%s): ERROR: %s\n", result.User, result.OldSubmissionID, result.Error);
+            continue;
+        }
+
+        if (!result.Changed) {
+            fmt.Printf("%s (%s): unchanged (%.2f)\n", result.User, result.OldSubmissionID, result.OldScore);
+            continue;
+        }
+
+        numChanged++;
+        fmt.Printf("%s (%s): %.2f -> %.2f\n", result.User, result.OldSubmissionID, result.OldScore, result.NewScore);
+        for _, question := range result.Questions {
+            if ((question.OldScore == question.NewScore) && (question.OldMaxPoints == question.NewMaxPoints)) {
+                continue;
+            }
+
+            fmt.Printf("    %s: %.2f / %.2f -> %.2f / %.2f\n", question.Name,
+                    question.OldScore, question.OldMaxPoints, question.NewScore, question.NewMaxPoints);
+        }
+    }
+
+    fmt.Printf("Regraded %d submissions (%d changed, %d errors).\n", len(results), numChanged, numErrors);
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+    "strings"
+
     "github.com/alecthomas/kong"
     "github.com/rs/zerolog/log"
 
     "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
     "github.com/eriq-augustine/autograder/email"
 )
 
@@ -25,7 +28,11 @@ func main() {
         log.Fatal().Err(err).Msg("Could not load config options.");
     }
 
+    db.MustOpen();
+    defer db.MustClose();
+
     err = email.Send(args.To, args.Subject, args.Body, false);
+    db.AuditCommand("send-email", "", strings.Join(args.To, ","), args, err);
     if (err != nil) {
         log.Fatal().Err(err).Msg("Could not send email.");
     }
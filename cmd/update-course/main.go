@@ -56,6 +56,7 @@ func main() {
     }
 
     updated, err := procedures.UpdateCourse(course, false);
+    db.AuditCommand("update-course", course.GetID(), "", args, err);
     if (err != nil) {
         log.Fatal().Err(err).Msg("Failed to update course.");
     }
@@ -43,6 +43,7 @@ func (this *AddUser) Run(course *model.Course) error {
     }
 
     result, err := db.SyncUser(course, newUser, this.Force, this.DryRun, this.SendEmail);
+    db.AuditCommand("users/add", course.GetID(), this.Email, this, err);
     if (err != nil) {
         return err;
     }
@@ -83,6 +84,7 @@ func (this *AddTSV) Run(course *model.Course) error {
     }
 
     result, err := db.SyncUsers(course, newUsers, this.Force, this.DryRun, this.SendEmail);
+    db.AuditCommand("users/add-tsv", course.GetID(), "", this, err);
     if (err != nil) {
         return err;
     }
@@ -209,6 +211,7 @@ func (this *ChangePassword) Run(course *model.Course) error {
     user.Pass = this.Pass;
 
     result, err := db.SyncUser(course, user, true, false, this.SendEmail);
+    db.AuditCommand("users/change-password", course.GetID(), this.Email, this, err);
     if (err != nil) {
         return fmt.Errorf("Failed to sync user: '%w'.", err);
     }
@@ -227,6 +230,7 @@ type RmUser struct {
 
 func (this *RmUser) Run(course *model.Course) error {
     exists, err := db.RemoveUser(course, this.Email);
+    db.AuditCommand("users/rm", course.GetID(), this.Email, this, err);
     if (err != nil) {
         return fmt.Errorf("Failed to remove user '%s': '%w'.", this.Email, err);
     }
@@ -124,6 +124,32 @@ func postPutWithHeaders(verb string, uri string, form map[string]string, headers
     return doRequest(uri, request, verb, checkResult);
 }
 
+// Send a request with a JSON body (e.g. for PATCH or PUT).
+// Returns: (body, headers (response), error)
+func SendJSONWithHeaders(verb string, uri string, payload any, headers map[string][]string) (string, map[string][]string, error) {
+    content, err := util.ToJSON(payload);
+    if (err != nil) {
+        return "", nil, fmt.Errorf("Failed to serialize JSON body for %s request on URL '%s': '%w'.", verb, uri, err);
+    }
+
+    request, err := http.NewRequest(verb, uri, strings.NewReader(content));
+    if (err != nil) {
+        return "", nil, fmt.Errorf("Failed to create %s request on URL '%s': '%w'.", verb, uri, err);
+    }
+
+    request.Header.Set("Content-Type", "application/json");
+
+    // Passed headers replace the defaults (e.g. a more specific JSON content type).
+    for key, values := range headers {
+        request.Header.Del(key);
+        for _, value := range values {
+            request.Header.Add(key, value);
+        }
+    }
+
+    return doRequest(uri, request, verb, true);
+}
+
 func PostFiles(uri string, form map[string]string, paths []string, checkResult bool) (string, error) {
     var buffer bytes.Buffer;
 
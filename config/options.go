@@ -31,6 +31,18 @@ var (
 
     // Docker
     DOCKER_DISABLE = MustNewBoolOption("docker.disable", false, "Disable the use of docker (usually for testing).");
+    DOCKER_LIMITS_TIMEOUT_SECS = MustNewIntOption("docker.limits.timeout", 0,
+            "The grading timeout (in seconds) for assignments that do not set their own (0 for no timeout).");
+    DOCKER_LIMITS_MAX_OUTPUT_KB = MustNewIntOption("docker.limits.maxoutput", 0,
+            "The max grading output (stdout and stderr combined, in KB) for assignments that do not set their own (0 for no limit).");
+    PODMAN_HOST = MustNewStringOption("podman.host", "",
+            "The docker-compatible API socket for podman, e.g. 'unix:///run/user/1000/podman/podman.sock'." +
+            " Defaults to the rootless socket for the current user.");
+
+    // Grading
+    GRADING_WORKERS = MustNewIntOption("grading.workers", 4, "The maximum number of submissions that can be graded at the same time.");
+    GRADING_COURSE_MAX = MustNewIntOption("grading.course.max", 0,
+            "The maximum number of submissions from a single course that can be graded at the same time (0 for no limit).");
 
     // Tasks
     NO_TASKS = MustNewBoolOption("tasks.disable", false, "Disable all scheduled tasks.");
@@ -41,8 +53,87 @@ var (
 
     // Server
     WEB_PORT = MustNewIntOption("web.port", 8080, "The port for the web interface to serve on.");
+    WEB_TLS_CERT = MustNewStringOption("web.tls.cert", "",
+            "Path to a PEM certificate (chain) to serve HTTPS with (requires web.tls.key)." +
+            " The certificate and key are reloaded when the server receives SIGHUP.");
+    WEB_TLS_KEY = MustNewStringOption("web.tls.key", "", "Path to the PEM private key for web.tls.cert.");
+    WEB_READ_HEADER_TIMEOUT_SECS = MustNewIntOption("web.timeout.header", 10, "The maximum time (in seconds) to read a request's headers.");
+    WEB_READ_TIMEOUT_SECS = MustNewIntOption("web.timeout.read", 300,
+            "The maximum time (in seconds) to read an entire request (including uploaded files, 0 for no limit).");
+    WEB_WRITE_TIMEOUT_SECS = MustNewIntOption("web.timeout.write", 0,
+            "The maximum time (in seconds) to write a response (0 for no limit)." +
+            " Synchronous submissions are graded before their response is written, so this should be longer than any grading.");
+    WEB_IDLE_TIMEOUT_SECS = MustNewIntOption("web.timeout.idle", 120,
+            "The maximum time (in seconds) to keep an idle keep-alive connection open.");
+    WEB_MAX_REQUEST_MB = MustNewIntOption("web.request.maxmb", 100, "The maximum size (in MB) of a request body (0 for no limit).");
+    WEB_MAX_HEADER_KB = MustNewIntOption("web.header.maxkb", 1024, "The maximum size (in KB) of a request's headers.");
+    WEB_SHUTDOWN_TIMEOUT_SECS = MustNewIntOption("web.shutdown.timeout", 600,
+            "When shutting down, the maximum time (in seconds) to wait for in-flight requests and gradings to finish.");
+    WEB_TRUSTED_PROXIES = MustNewStringOption("web.proxies.trusted", "",
+            "A comma-separated list of IP addresses/CIDR ranges of reverse proxies whose X-Forwarded-For header is trusted for the client's IP." +
+            " By default, the connection's address is always used.");
+    WEB_READY_CACHE_SECS = MustNewIntOption("web.ready.cache", 10,
+            "The number of seconds that the result of the readiness checks (/ready) is reused for (0 to run the checks on every request).");
+    WEB_METRICS_ENABLED = MustNewBoolOption("web.metrics.enabled", false, "Serve Prometheus metrics at /metrics on web.metrics.address.");
+    WEB_METRICS_ADDRESS = MustNewStringOption("web.metrics.address", "127.0.0.1:9090",
+            "The address (host:port) to serve metrics on (separate from the main web port)." +
+            " Metrics are not authenticated (and include course/assignment IDs), so this should not be publicly reachable.");
+
+    // API Tokens
+    API_TOKEN_DAYS = MustNewIntOption("api.token.days", 90, "The default number of days before a new API token expires.");
+    API_TOKEN_MAX_DAYS = MustNewIntOption("api.token.maxdays", 365, "The maximum number of days that an API token can be valid for.");
+
+    // OIDC
+    OIDC_ISSUER = MustNewStringOption("oidc.issuer", "",
+            "The issuer URL of the OpenID Connect provider to log in with (empty to disable OIDC login).");
+    OIDC_CLIENT_ID = MustNewStringOption("oidc.client.id", "", "The client ID for the autograder registered with the OIDC provider.");
+    OIDC_CLIENT_SECRET = MustNewStringOption("oidc.client.secret", "", "The client secret for the autograder registered with the OIDC provider.");
+    OIDC_REDIRECT_URL = MustNewStringOption("oidc.redirect.url", "",
+            "The full URL of the OIDC callback endpoint, as registered with the provider (required for OIDC login).");
+    OIDC_SCOPES = MustNewStringOption("oidc.scopes", "openid email profile", "Space-separated scopes to request from the OIDC provider.");
+    OIDC_SESSION_HOURS = MustNewIntOption("oidc.session.hours", 24, "The number of hours that a token issued by an OIDC login is valid for.");
+    OIDC_ALLOW_MISSING_EMAIL_VERIFIED = MustNewBoolOption("oidc.email.allowmissingverified", false,
+            "Accept OIDC identities that do not have an email_verified claim." +
+            " Only enable for providers that never issue unverified emails.");
+
+    // LTI
+    LTI_KEY_PATH = MustNewStringOption("lti.key.path", "",
+            "Path to the PEM-encoded RSA private key that the autograder signs LTI service requests with." +
+            " If empty, a key will be generated and stored in the work directory.");
+    LTI_LAUNCH_URL = MustNewStringOption("lti.launch.url", "",
+            "The full URL of the LTI launch endpoint, as registered with the platform (required for LTI logins).");
+    LTI_SESSION_HOURS = MustNewIntOption("lti.session.hours", 24, "The number of hours that a token issued by an LTI launch is valid for.");
+
+    // LMS
+    LMS_SYNC_FULL_HOURS = MustNewIntOption("lms.sync.full.hours", 24,
+            "For courses using incremental user syncs, the number of hours before a full user sync is forced (0 to never force one).");
+
+    // Server Users
+    SERVER_ADMINS = MustNewStringOption("server.admins", "",
+            "A comma-separated list of the emails of server admins." +
+            " Server admins hold the server capabilities (e.g., managing IP authentication throttles) when logged in with their server password.");
+
+    // Authentication Throttling
+    AUTH_THROTTLE_USER_FAILURES = MustNewIntOption("auth.throttle.user.failures", 5,
+            "The number of failed authentication attempts for a user before they are temporarily locked out (0 to disable).");
+    AUTH_THROTTLE_IP_FAILURES = MustNewIntOption("auth.throttle.ip.failures", 20,
+            "The number of failed authentication attempts from an IP address before it is temporarily locked out (0 to disable).");
+    AUTH_THROTTLE_BASE_SECS = MustNewIntOption("auth.throttle.base", 30,
+            "The number of seconds of the first lockout, each additional failure doubles the lockout.");
+    AUTH_THROTTLE_MAX_SECS = MustNewIntOption("auth.throttle.max", 60 * 60, "The maximum number of seconds that a lockout can last.");
+    AUTH_THROTTLE_RESET_MINS = MustNewIntOption("auth.throttle.reset", 60,
+            "The number of minutes without a failure before failed authentication attempts are forgotten.");
+
+    // Password Reset
+    PASSWORD_RESET_MINS = MustNewIntOption("password.reset.mins", 60, "The number of minutes that an emailed password reset token is valid for.");
+    PASSWORD_RESET_COOLDOWN_SECS = MustNewIntOption("password.reset.cooldown", 5 * 60,
+            "The minimum number of seconds between password reset requests for the same user.");
+    PASSWORD_RESET_URL = MustNewStringOption("password.reset.url", "",
+            "A page to link to in password reset emails (the course, email, and reset token are added as query parameters)." +
+            " If empty, the email will only contain the reset token.");
 
     // Database
     DB_TYPE = MustNewStringOption("db.type", "disk", "The type of database to use.");
     DB_PG_URI = MustNewStringOption("db.pg.uri", "", "Connection string to connect to a Postgres Databse. Empty if not using Postgres.");
+    DB_SQLITE_PATH = MustNewStringOption("db.sqlite.path", "", "Path to the SQLite database file. Defaults to inside the database dir.");
 )
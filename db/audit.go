@@ -0,0 +1,63 @@
+package db
+
+import (
+    "fmt"
+    "os/user"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func AppendAuditRecord(record *model.AuditRecord) error {
+    if (backend == nil) {
+        return fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.AppendAuditRecord(record);
+}
+
+func GetAuditRecords(query model.AuditQuery) ([]*model.AuditRecord, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetAuditRecords(query);
+}
+
+// Record an action taken by a command-line tool.
+// The actor is the system user running the tool, and the parameters are the tool's parsed arguments (redacted).
+// The action is recorded as failed if the tool's operation returned an error.
+// Failing to write the record is logged, but does not stop the tool (the action has already happened).
+func AuditCommand(command string, courseID string, target string, args any, commandErr error) {
+    parameters := make(map[string]any);
+
+    text, err := util.ToJSON(args);
+    if (err == nil) {
+        parameters, err = util.JSONMapFromString(text);
+    }
+
+    if (err != nil) {
+        // Do not log the error, it may contain the (unredacted) arguments.
+        log.Warn().Str("command", command).Msg("Failed to convert command arguments for the audit log.");
+        parameters = make(map[string]any);
+    }
+
+    record := model.NewAuditRecord(model.AUDIT_SOURCE_CMD, courseID, getCommandActor(), "cmd/" + command, target, parameters);
+    record.Success = (commandErr == nil);
+
+    err = AppendAuditRecord(record);
+    if (err != nil) {
+        log.Error().Err(err).Str("command", command).Msg("Failed to write audit record.");
+    }
+}
+
+func getCommandActor() string {
+    currentUser, err := user.Current();
+    if (err != nil) {
+        return "cmd:unknown";
+    }
+
+    return "cmd:" + currentUser.Username;
+}
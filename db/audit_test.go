@@ -0,0 +1,77 @@
+package db
+
+import (
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+func (this *DBTests) DBTestAuditLog(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC);
+
+    inputs := []struct{courseID string; actor string; action string; offsetMins int}{
+        {TEST_COURSE_ID, "admin@test.com", "user/add", 0},
+        {TEST_COURSE_ID, "owner@test.com", "user/remove", 10},
+        {"other", "admin@test.com", "user/add", 20},
+        {TEST_COURSE_ID, "admin@test.com", "admin/regrade", 30},
+        {"", "cmd:root", "cmd/migrate-server-users", 40},
+    };
+
+    for i, input := range inputs {
+        record := model.NewAuditRecord(model.AUDIT_SOURCE_API, input.courseID, input.actor, input.action,
+                "", map[string]any{"index": float64(i), "user-pass": "secret"});
+        record.Timestamp = common.TimestampFromTime(base.Add(time.Duration(input.offsetMins) * time.Minute));
+
+        err := AppendAuditRecord(record);
+        if (err != nil) {
+            test.Fatalf("Failed to append audit record %d: '%v'.", i, err);
+        }
+    }
+
+    // Clearing a course should not touch the audit log.
+    err := ClearCourse(MustGetTestCourse());
+    if (err != nil) {
+        test.Fatalf("Failed to clear course: '%v'.", err);
+    }
+
+    testCases := []struct{query model.AuditQuery; expected []int}{
+        {model.AuditQuery{}, []int{0, 1, 2, 3, 4}},
+        {model.AuditQuery{CourseID: TEST_COURSE_ID}, []int{0, 1, 3}},
+        {model.AuditQuery{Actor: "admin@test.com"}, []int{0, 2, 3}},
+        {model.AuditQuery{Action: "user/add"}, []int{0, 2}},
+        {model.AuditQuery{CourseID: TEST_COURSE_ID, Actor: "admin@test.com", Action: "user/add"}, []int{0}},
+        {model.AuditQuery{After: base.Add(10 * time.Minute)}, []int{1, 2, 3, 4}},
+        {model.AuditQuery{Before: base.Add(10 * time.Minute)}, []int{0, 1}},
+        {model.AuditQuery{After: base.Add(5 * time.Minute), Before: base.Add(25 * time.Minute)}, []int{1, 2}},
+        {model.AuditQuery{Limit: 2}, []int{3, 4}},
+        {model.AuditQuery{CourseID: TEST_COURSE_ID, Limit: 1}, []int{3}},
+        {model.AuditQuery{Actor: "ZZZ"}, []int{}},
+    };
+
+    for i, testCase := range testCases {
+        records, err := GetAuditRecords(testCase.query);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to get audit records: '%v'.", i, err);
+            continue;
+        }
+
+        actual := make([]int, 0, len(records));
+        for _, record := range records {
+            actual = append(actual, int(record.Parameters["index"].(float64)));
+
+            if (record.Parameters["user-pass"] != model.AUDIT_REDACTED_VALUE) {
+                test.Errorf("Case %d: Password was not redacted: '%v'.", i, record.Parameters);
+            }
+        }
+
+        if (!slices.Equal(testCase.expected, actual)) {
+            test.Errorf("Case %d: Unexpected records. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual);
+        }
+    }
+}
@@ -1,10 +1,13 @@
 package db
 
 import (
+    "path/filepath"
     "testing"
+    "time"
 
     "github.com/eriq-augustine/autograder/common"
     "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
 )
 
 // Update a course from a path source.
@@ -98,6 +101,122 @@ func (this *DBTests) DBTestCourseUpdateCourseFromSourceSkip(test *testing.T) {
     }
 }
 
+// Dump a course and make sure the dump can be loaded back with the same contents.
+func (this *DBTests) DBTestCourseDump(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    course := MustGetTestCourse();
+
+    taskInstance := time.Now().Truncate(time.Second);
+    err := LogTaskCompletion(course.GetID(), "dump-task", taskInstance);
+    if (err != nil) {
+        test.Fatalf("Failed to log task completion: '%v'.", err);
+    }
+
+    tempDir, err := util.MkDirTemp("autograder-test-db-dump-");
+    if (err != nil) {
+        test.Fatalf("Failed to create temp dir: '%v'.", err);
+    }
+
+    err = DumpCourse(course, tempDir);
+    if (err != nil) {
+        test.Fatalf("Failed to dump course: '%v'.", err);
+    }
+
+    dumpCourse, users, submissions, err := model.FullLoadCourseFromPath(filepath.Join(tempDir, model.COURSE_CONFIG_FILENAME));
+    if (err != nil) {
+        test.Fatalf("Failed to load dumped course: '%v'.", err);
+    }
+
+    if (dumpCourse.GetID() != course.GetID()) {
+        test.Fatalf("Dumped course ID does not match. Expected: '%s', Actual: '%s'.", course.GetID(), dumpCourse.GetID());
+    }
+
+    if (len(dumpCourse.Assignments) != len(course.Assignments)) {
+        test.Fatalf("Unexpected number of dumped assignments. Expected %d, found %d.", len(course.Assignments), len(dumpCourse.Assignments));
+    }
+
+    count := countUsers(test, course);
+    if (len(users) != count) {
+        test.Fatalf("Unexpected number of dumped users. Expected %d, found %d.", count, len(users));
+    }
+
+    history, err := GetSubmissionHistory(MustGetTestAssignment(), "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get submission history: '%v'.", err);
+    }
+
+    if (len(submissions) != len(history)) {
+        test.Fatalf("Unexpected number of dumped submissions. Expected %d, found %d.", len(history), len(submissions));
+    }
+
+    // Loading the dump back should restore the task log.
+    err = ClearCourse(course);
+    if (err != nil) {
+        test.Fatalf("Failed to clear course: '%v'.", err);
+    }
+
+    _, err = loadCourse(filepath.Join(tempDir, model.COURSE_CONFIG_FILENAME));
+    if (err != nil) {
+        test.Fatalf("Failed to load dumped course: '%v'.", err);
+    }
+
+    lastTime, err := GetLastTaskCompletion(course.GetID(), "dump-task");
+    if (err != nil) {
+        test.Fatalf("Failed to get last task completion: '%v'.", err);
+    }
+
+    if (!taskInstance.Equal(lastTime)) {
+        test.Fatalf("Task completion was not loaded. Expected: '%v', Actual: '%v'.", taskInstance, lastTime);
+    }
+}
+
+func (this *DBTests) DBTestCourseLoadStaleTaskLog(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    course := MustGetTestCourse();
+
+    oldInstance := time.Now().Add(-1 * time.Hour).Truncate(time.Second);
+    newInstance := time.Now().Truncate(time.Second);
+
+    err := LogTaskCompletion(course.GetID(), "dump-task", oldInstance);
+    if (err != nil) {
+        test.Fatalf("Failed to log old task completion: '%v'.", err);
+    }
+
+    tempDir, err := util.MkDirTemp("autograder-test-db-stale-dump-");
+    if (err != nil) {
+        test.Fatalf("Failed to create temp dir: '%v'.", err);
+    }
+
+    err = DumpCourse(course, tempDir);
+    if (err != nil) {
+        test.Fatalf("Failed to dump course: '%v'.", err);
+    }
+
+    err = LogTaskCompletion(course.GetID(), "dump-task", newInstance);
+    if (err != nil) {
+        test.Fatalf("Failed to log new task completion: '%v'.", err);
+    }
+
+    // Loading the (now stale) dump should not move the completion back.
+    _, err = loadCourse(filepath.Join(tempDir, model.COURSE_CONFIG_FILENAME));
+    if (err != nil) {
+        test.Fatalf("Failed to load dumped course: '%v'.", err);
+    }
+
+    lastTime, err := GetLastTaskCompletion(course.GetID(), "dump-task");
+    if (err != nil) {
+        test.Fatalf("Failed to get last task completion: '%v'.", err);
+    }
+
+    if (!newInstance.Equal(lastTime)) {
+        test.Fatalf("Task completion was moved back by a stale dump. Expected: '%v', Actual: '%v'.", newInstance, lastTime);
+    }
+}
+
 func countUsers(test *testing.T, course *model.Course) int {
     users, err := GetUsers(course);
     if (err != nil) {
@@ -15,6 +15,8 @@ import (
 
     "github.com/eriq-augustine/autograder/config"
     "github.com/eriq-augustine/autograder/db/disk"
+    "github.com/eriq-augustine/autograder/db/pg"
+    "github.com/eriq-augustine/autograder/db/sqlite"
     "github.com/eriq-augustine/autograder/model"
 )
 
@@ -71,10 +73,46 @@ type Backend interface {
     // Upsert the given users.
     SaveUsers(course *model.Course, users map[string]*model.User) error;
 
-    // Remove a user.
+    // Remove a user (and all their API tokens).
     // Do nothing and return nil if the user does not exist.
     RemoveUser(course *model.Course, email string) error;
 
+    // Get all of a user's API tokens: {id: token, ...}.
+    GetAPITokens(course *model.Course, email string) (map[string]*model.APIToken, error);
+
+    // Get a specific API token.
+    // Returns nil if no matching token exists.
+    GetAPIToken(course *model.Course, email string, tokenID string) (*model.APIToken, error);
+
+    // Upsert an API token.
+    SaveAPIToken(course *model.Course, token *model.APIToken) error;
+
+    // Remove an API token.
+    // Do nothing and return nil if the token does not exist.
+    RemoveAPIToken(course *model.Course, email string, tokenID string) error;
+
+    // Get all server users (users that exist independently of any course).
+    GetServerUsers() (map[string]*model.ServerUser, error);
+
+    // Get a specific server user.
+    // Returns nil if no matching user exists.
+    GetServerUser(email string) (*model.ServerUser, error);
+
+    // Upsert the given server users.
+    SaveServerUsers(users map[string]*model.ServerUser) error;
+
+    // Remove a server user (course users with the same email are not touched).
+    // Do nothing and return nil if the user does not exist.
+    RemoveServerUser(email string) error;
+
+    // Append a record to the audit log.
+    // Records are never modified or removed (except by Clear()).
+    AppendAuditRecord(record *model.AuditRecord) error;
+
+    // Get the audit records that match the query (ordered oldest to newest).
+    // If the query has a limit, then only the most recent records are returned.
+    GetAuditRecords(query model.AuditQuery) ([]*model.AuditRecord, error);
+
     // Remove a submission.
     // Return a bool indicating whether the submission exists or not and an error if there is one.
     RemoveSubmission(assignment *model.Assignment, email string, submissionID string) (bool, error);
@@ -86,6 +124,10 @@ type Backend interface {
     // Get the next short submission ID.
     GetNextSubmissionID(assignment *model.Assignment, email string) (string, error);
 
+    // Get the emails of all users with at least one stored submission for this assignment (sorted).
+    // This includes users that are no longer in the course.
+    GetSubmissionUsers(assignment *model.Assignment) ([]string, error);
+
     // Get a history of all submissions for this assignment and user.
     GetSubmissionHistory(assignment *model.Assignment, email string) ([]*model.SubmissionHistoryItem, error);
 
@@ -128,6 +170,9 @@ type Backend interface {
     // Get the last time a task with the given course/ID was completed.
     // Will return a zero time (time.Time{}).
     GetLastTaskCompletion(courseID string, taskID string) (time.Time, error);
+
+    // Get the last completion time of every task in a course: {taskID: time, ...}.
+    GetTaskCompletions(courseID string) (map[string]time.Time, error);
 }
 
 func Open() error {
@@ -139,20 +184,39 @@ func Open() error {
     }
 
     var err error;
-    dbType := config.DB_TYPE.Get();
+    backend, err = OpenBackend(config.DB_TYPE.Get());
+    return err;
+}
+
+// Open a backend without making it the active database.
+// Most callers want Open(), this is for tools that work with multiple databases at once.
+// Backends will still pull their connection settings (e.g. db.pg.uri) from the config.
+func OpenBackend(dbType string) (Backend, error) {
+    var newBackend Backend;
+    var err error;
 
     switch dbType {
         case DB_TYPE_DISK:
-            backend, err = disk.Open();
+            newBackend, err = disk.Open();
+        case DB_TYPE_SQLITE:
+            newBackend, err = sqlite.Open();
+        case DB_TYPE_POSTGRES:
+            newBackend, err = pg.Open();
         default:
             err = fmt.Errorf("Unknown database type: '%s'.", dbType);
     }
 
     if (err != nil) {
-        return fmt.Errorf("Failed to open database: %w.", err);
+        return nil, fmt.Errorf("Failed to open database: %w.", err);
+    }
+
+    err = newBackend.EnsureTables();
+    if (err != nil) {
+        newBackend.Close();
+        return nil, err;
     }
 
-    return backend.EnsureTables();
+    return newBackend, nil;
 }
 
 func Close() error {
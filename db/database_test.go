@@ -2,6 +2,7 @@ package db
 
 import (
     "fmt"
+    "os"
     "reflect"
     "testing"
 
@@ -11,8 +12,22 @@ import (
 // Backends to put through the standard tests.
 var testBackends []string = []string{
     DB_TYPE_DISK,
+    DB_TYPE_SQLITE,
 };
 
+// The environmental variable that holds the connection URI for a Postgres database to test against.
+// Postgres will only be tested when this is set.
+// The database will be cleared during testing.
+const TEST_PG_URI_ENV = config.ENV_PREFIX + "DB" + config.ENV_DOT_REPLACEMENT + "PG" + config.ENV_DOT_REPLACEMENT + "URI";
+
+func init() {
+    uri := os.Getenv(TEST_PG_URI_ENV);
+    if (uri != "") {
+        config.DB_PG_URI.Set(uri);
+        testBackends = append(testBackends, DB_TYPE_POSTGRES);
+    }
+}
+
 // Methods attatched to this struct will be called for each backend in testBackends.
 type DBTests struct {
 }
@@ -0,0 +1,92 @@
+package disk
+
+import (
+    "bufio"
+    "fmt"
+    "os"
+    "path/filepath"
+
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+// The audit log is not tied to a course, so it lives in a single file at the base of the database.
+// Each line is a single JSON record, and the file is only ever appended to.
+const DISK_DB_AUDIT_LOG_FILENAME = "audit-log.jsonl";
+
+func (this *backend) AppendAuditRecord(record *model.AuditRecord) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    line, err := util.ToJSON(record);
+    if (err != nil) {
+        return fmt.Errorf("Failed to serialize audit record: '%w'.", err);
+    }
+
+    path := this.getAuditLogPath();
+
+    file, err := os.OpenFile(path, os.O_APPEND | os.O_CREATE | os.O_WRONLY, 0644);
+    if (err != nil) {
+        return fmt.Errorf("Failed to open audit log '%s': '%w'.", path, err);
+    }
+    defer file.Close();
+
+    _, err = file.WriteString(line + "\n");
+    if (err != nil) {
+        return fmt.Errorf("Failed to write to audit log '%s': '%w'.", path, err);
+    }
+
+    return nil;
+}
+
+func (this *backend) GetAuditRecords(query model.AuditQuery) ([]*model.AuditRecord, error) {
+    this.lock.RLock();
+    defer this.lock.RUnlock();
+
+    records := make([]*model.AuditRecord, 0);
+
+    path := this.getAuditLogPath();
+    if (!util.PathExists(path)) {
+        return records, nil;
+    }
+
+    file, err := os.Open(path);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to open audit log '%s': '%w'.", path, err);
+    }
+    defer file.Close();
+
+    scanner := bufio.NewScanner(file);
+    scanner.Buffer(make([]byte, 0, 64 * 1024), 16 * 1024 * 1024);
+
+    for scanner.Scan() {
+        if (len(scanner.Bytes()) == 0) {
+            continue;
+        }
+
+        var record model.AuditRecord;
+        err = util.JSONFromString(scanner.Text(), &record);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read audit record from '%s': '%w'.", path, err);
+        }
+
+        if (query.Matches(&record)) {
+            records = append(records, &record);
+        }
+    }
+
+    err = scanner.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read audit log '%s': '%w'.", path, err);
+    }
+
+    if ((query.Limit > 0) && (len(records) > query.Limit)) {
+        records = records[len(records) - query.Limit:];
+    }
+
+    return records, nil;
+}
+
+func (this *backend) getAuditLogPath() string {
+    return filepath.Join(this.baseDir, DISK_DB_AUDIT_LOG_FILENAME);
+}
@@ -51,6 +51,11 @@ func (this *backend) LoadCourse(path string) (*model.Course, error) {
         return nil, err;
     }
 
+    err = this.loadStaticTaskLog(course, path);
+    if (err != nil) {
+        return nil, err;
+    }
+
     return course, nil;
 }
 
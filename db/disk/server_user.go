@@ -0,0 +1,94 @@
+package disk
+
+import (
+    "fmt"
+    "path/filepath"
+
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+// Server users are not tied to a course, so they live in a single file at the base of the database.
+const DISK_DB_SERVER_USERS_FILENAME = "server-users.json";
+
+func (this *backend) GetServerUsers() (map[string]*model.ServerUser, error) {
+    this.lock.RLock();
+    defer this.lock.RUnlock();
+
+    return this.getServerUsers();
+}
+
+func (this *backend) GetServerUser(email string) (*model.ServerUser, error) {
+    users, err := this.GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get server users when searching for '%s': '%w'.", email, err);
+    }
+
+    return users[email], nil;
+}
+
+func (this *backend) SaveServerUsers(newUsers map[string]*model.ServerUser) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    users, err := this.getServerUsers();
+    if (err != nil) {
+        return fmt.Errorf("Failed to get server users to merge before saving: '%w'.", err);
+    }
+
+    for email, user := range newUsers {
+        users[email] = user;
+    }
+
+    return this.writeServerUsers(users);
+}
+
+func (this *backend) RemoveServerUser(email string) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    users, err := this.getServerUsers();
+    if (err != nil) {
+        return fmt.Errorf("Failed to get server users when removing '%s': '%w'.", email, err);
+    }
+
+    _, ok := users[email];
+    if (!ok) {
+        return nil;
+    }
+
+    delete(users, email);
+
+    return this.writeServerUsers(users);
+}
+
+func (this *backend) getServerUsersPath() string {
+    return filepath.Join(this.baseDir, DISK_DB_SERVER_USERS_FILENAME);
+}
+
+func (this *backend) getServerUsers() (map[string]*model.ServerUser, error) {
+    path := this.getServerUsersPath();
+
+    users := make(map[string]*model.ServerUser);
+    if (!util.PathExists(path)) {
+        return users, nil;
+    }
+
+    err := util.JSONFromFile(path, &users);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read server users '%s': '%w'.", path, err);
+    }
+
+    return users, nil;
+}
+
+func (this *backend) writeServerUsers(users map[string]*model.ServerUser) error {
+    path := this.getServerUsersPath();
+
+    err := util.ToJSONFileIndent(users, path);
+    if (err != nil) {
+        return fmt.Errorf("Failed to write server users '%s': '%w'.", path, err);
+    }
+
+    return nil;
+}
@@ -4,6 +4,7 @@ import (
     "fmt"
     "os"
     "path/filepath"
+    "strings"
     "time"
 
     "github.com/eriq-augustine/autograder/common"
@@ -18,37 +19,80 @@ func (this *backend) saveSubmissionsLock(course *model.Course, submissions []*mo
     }
 
     for _, submission := range submissions {
-        baseDir := this.getSubmissionDirFromResult(submission.Info);
-        err := util.MkDir(baseDir);
+        err := this.saveSubmission(submission);
         if (err != nil) {
-            return fmt.Errorf("Failed to make submission dir '%s': '%w'.", baseDir, err);
+            return err;
         }
+    }
 
-        resultPath := filepath.Join(baseDir, model.SUBMISSION_RESULT_FILENAME);
-        err = util.ToJSONFileIndent(submission.Info, resultPath);
-        if (err != nil) {
-            return fmt.Errorf("Failed to write submission result '%s': '%w'.", resultPath, err);
-        }
+    return nil;
+}
 
-        err = util.GzipBytesToDirectory(filepath.Join(baseDir, common.GRADING_INPUT_DIRNAME), submission.InputFilesGZip);
-        if (err != nil) {
-            return fmt.Errorf("Failed to write submission input files: '%w'.", err);
-        }
+// Save a single submission.
+// An existing submission with the same ID is replaced,
+// but only after the new submission has been completely written (so a failed save leaves the old submission alone).
+// The caller should hold the lock.
+func (this *backend) saveSubmission(submission *model.GradingResult) error {
+    submissionDir := this.getSubmissionDirFromResult(submission.Info);
+    if (!util.PathExists(submissionDir)) {
+        return writeSubmission(submissionDir, submission);
+    }
 
-        err = util.GzipBytesToDirectory(filepath.Join(baseDir, common.GRADING_OUTPUT_DIRNAME), submission.OutputFilesGZip);
-        if (err != nil) {
-            return fmt.Errorf("Failed to write submission input files: '%w'.", err);
-        }
+    // Hidden dirs are skipped when listing submissions.
+    tempDir, err := os.MkdirTemp(filepath.Dir(submissionDir), "." + filepath.Base(submissionDir) + "-");
+    if (err != nil) {
+        return fmt.Errorf("Failed to make temp dir for submission '%s': '%w'.", submission.Info.ID, err);
+    }
+    defer util.RemoveDirent(tempDir);
 
-        err = util.WriteFile(submission.Stdout, filepath.Join(baseDir, common.SUBMISSION_STDOUT_FILENAME));
-        if (err != nil) {
-            return fmt.Errorf("Failed to write submission stdout file: '%w'.", err);
-        }
+    err = writeSubmission(tempDir, submission);
+    if (err != nil) {
+        return err;
+    }
 
-        err = util.WriteFile(submission.Stderr, filepath.Join(baseDir, common.SUBMISSION_STDERR_FILENAME));
-        if (err != nil) {
-            return fmt.Errorf("Failed to write submission stderr file: '%w'.", err);
-        }
+    err = util.RemoveDirent(submissionDir);
+    if (err != nil) {
+        return fmt.Errorf("Failed to remove old submission dir '%s': '%w'.", submissionDir, err);
+    }
+
+    err = os.Rename(tempDir, submissionDir);
+    if (err != nil) {
+        return fmt.Errorf("Failed to move new submission into place '%s': '%w'.", submissionDir, err);
+    }
+
+    return nil;
+}
+
+func writeSubmission(baseDir string, submission *model.GradingResult) error {
+    err := util.MkDir(baseDir);
+    if (err != nil) {
+        return fmt.Errorf("Failed to make submission dir '%s': '%w'.", baseDir, err);
+    }
+
+    resultPath := filepath.Join(baseDir, model.SUBMISSION_RESULT_FILENAME);
+    err = util.ToJSONFileIndent(submission.Info, resultPath);
+    if (err != nil) {
+        return fmt.Errorf("Failed to write submission result '%s': '%w'.", resultPath, err);
+    }
+
+    err = util.GzipBytesToDirectory(filepath.Join(baseDir, common.GRADING_INPUT_DIRNAME), submission.InputFilesGZip);
+    if (err != nil) {
+        return fmt.Errorf("Failed to write submission input files: '%w'.", err);
+    }
+
+    err = util.GzipBytesToDirectory(filepath.Join(baseDir, common.GRADING_OUTPUT_DIRNAME), submission.OutputFilesGZip);
+    if (err != nil) {
+        return fmt.Errorf("Failed to write submission input files: '%w'.", err);
+    }
+
+    err = util.WriteFile(submission.Stdout, filepath.Join(baseDir, common.SUBMISSION_STDOUT_FILENAME));
+    if (err != nil) {
+        return fmt.Errorf("Failed to write submission stdout file: '%w'.", err);
+    }
+
+    err = util.WriteFile(submission.Stderr, filepath.Join(baseDir, common.SUBMISSION_STDERR_FILENAME));
+    if (err != nil) {
+        return fmt.Errorf("Failed to write submission stderr file: '%w'.", err);
     }
 
     return nil;
@@ -105,6 +149,30 @@ func (this *backend) GetSubmissionResult(assignment *model.Assignment, email str
     return &gradingInfo, nil;
 }
 
+func (this *backend) GetSubmissionUsers(assignment *model.Assignment) ([]string, error) {
+    emails := make([]string, 0);
+
+    assignmentDir := filepath.Join(this.getCourseDirFromID(assignment.GetCourse().GetID()), model.SUBMISSIONS_DIRNAME, assignment.GetID());
+    if (!util.PathExists(assignmentDir)) {
+        return emails, nil;
+    }
+
+    dirents, err := os.ReadDir(assignmentDir);
+    if (err != nil) {
+        return nil, fmt.Errorf("Unable to read assignment submissions dir '%s': '%w'.", assignmentDir, err);
+    }
+
+    for _, dirent := range dirents {
+        if (!dirent.IsDir()) {
+            continue;
+        }
+
+        emails = append(emails, dirent.Name());
+    }
+
+    return emails, nil;
+}
+
 func (this *backend) GetSubmissionHistory(assignment *model.Assignment, email string) ([]*model.SubmissionHistoryItem, error) {
     history := make([]*model.SubmissionHistoryItem, 0);
 
@@ -123,6 +191,10 @@ func (this *backend) GetSubmissionHistory(assignment *model.Assignment, email st
     }
 
     for _, dirent := range dirents {
+        if (isTempSubmissionDir(dirent.Name())) {
+            continue;
+        }
+
         resultPath := filepath.Join(submissionsDir, dirent.Name(), model.SUBMISSION_RESULT_FILENAME);
 
         var gradingInfo model.GradingInfo;
@@ -288,11 +360,18 @@ func (this *backend) getMostRecentSubmissionID(assignment *model.Assignment, ema
         return "", fmt.Errorf("Unable to read user submissions dir '%s': '%w'.", submissionsDir, err);
     }
 
-    if (len(dirents) == 0) {
-        return "", nil;
+    for i := len(dirents) - 1; i >= 0; i-- {
+        if (!isTempSubmissionDir(dirents[i].Name())) {
+            return dirents[i].Name(), nil;
+        }
     }
 
-    return dirents[len(dirents) - 1].Name(), nil;
+    return "", nil;
+}
+
+// Submissions that are being replaced are written to a hidden dir first (see saveSubmission()).
+func isTempSubmissionDir(name string) bool {
+    return strings.HasPrefix(name, ".");
 }
 
 func (this *backend) RemoveSubmission(assignment *model.Assignment, email string, shortSubmissionID string) (bool, error) {
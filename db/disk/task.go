@@ -5,10 +5,11 @@ import (
     "path/filepath"
     "time"
 
+    "github.com/eriq-augustine/autograder/model"
     "github.com/eriq-augustine/autograder/util"
 )
 
-const DISK_DB_TASKS_FILENAME = "tasks.json";
+const DISK_DB_TASKS_FILENAME = model.TASKS_FILENAME;
 
 func (this *backend) LogTaskCompletion(courseID string, taskID string, instance time.Time) error {
     this.lock.Lock();
@@ -46,6 +47,40 @@ func (this *backend) GetLastTaskCompletion(courseID string, taskID string) (time
     return instance, nil;
 }
 
+func (this *backend) GetTaskCompletions(courseID string) (map[string]time.Time, error) {
+    this.lock.RLock();
+    defer this.lock.RUnlock();
+
+    return this.getTaskLog(courseID);
+}
+
+// Merge the task log adjacent to a course config (if it exists) into the course's log.
+// The later completion of each task is kept, so a stale dump will not move completions back.
+// The caller must hold the write lock.
+func (this *backend) loadStaticTaskLog(course *model.Course, courseConfigPath string) error {
+    staticLog, err := model.LoadStaticTaskLog(courseConfigPath);
+    if (err != nil) {
+        return fmt.Errorf("Failed to load static task log for course config '%s': '%w'.", courseConfigPath, err);
+    }
+
+    if (len(staticLog) == 0) {
+        return nil;
+    }
+
+    log, err := this.getTaskLog(course.GetID());
+    if (err != nil) {
+        return err;
+    }
+
+    for taskID, instance := range staticLog {
+        if (instance.After(log[taskID])) {
+            log[taskID] = instance;
+        }
+    }
+
+    return this.writeTaskLog(course.GetID(), log);
+}
+
 func (this *backend) getTasksPathFromID(courseID string) string {
     return filepath.Join(this.getCourseDirFromID(courseID), DISK_DB_TASKS_FILENAME);
 }
@@ -0,0 +1,133 @@
+package disk
+
+import (
+    "fmt"
+    "path/filepath"
+
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const DISK_DB_API_TOKENS_FILENAME = "api-tokens.json";
+
+// Tokens are stored in a single file per course: {email: {id: token, ...}, ...}.
+type apiTokens map[string]map[string]*model.APIToken;
+
+func (this *backend) GetAPITokens(course *model.Course, email string) (map[string]*model.APIToken, error) {
+    this.lock.RLock();
+    defer this.lock.RUnlock();
+
+    allTokens, err := this.getAPITokens(course);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    tokens := allTokens[email];
+    if (tokens == nil) {
+        tokens = make(map[string]*model.APIToken);
+    }
+
+    return tokens, nil;
+}
+
+func (this *backend) GetAPIToken(course *model.Course, email string, tokenID string) (*model.APIToken, error) {
+    tokens, err := this.GetAPITokens(course, email);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get tokens when searching for '%s': '%w'.", tokenID, err);
+    }
+
+    return tokens[tokenID], nil;
+}
+
+func (this *backend) SaveAPIToken(course *model.Course, token *model.APIToken) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    allTokens, err := this.getAPITokens(course);
+    if (err != nil) {
+        return err;
+    }
+
+    tokens := allTokens[token.User];
+    if (tokens == nil) {
+        tokens = make(map[string]*model.APIToken);
+        allTokens[token.User] = tokens;
+    }
+
+    tokens[token.ID] = token;
+
+    return this.writeAPITokens(course, allTokens);
+}
+
+func (this *backend) RemoveAPIToken(course *model.Course, email string, tokenID string) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    allTokens, err := this.getAPITokens(course);
+    if (err != nil) {
+        return err;
+    }
+
+    _, ok := allTokens[email][tokenID];
+    if (!ok) {
+        return nil;
+    }
+
+    delete(allTokens[email], tokenID);
+
+    return this.writeAPITokens(course, allTokens);
+}
+
+// Remove all of a user's tokens.
+// The caller should already hold the write lock.
+func (this *backend) removeUserAPITokens(course *model.Course, email string) error {
+    allTokens, err := this.getAPITokens(course);
+    if (err != nil) {
+        return err;
+    }
+
+    _, ok := allTokens[email];
+    if (!ok) {
+        return nil;
+    }
+
+    delete(allTokens, email);
+
+    return this.writeAPITokens(course, allTokens);
+}
+
+func (this *backend) getAPITokensPath(course *model.Course) string {
+    return filepath.Join(this.getCourseDir(course), DISK_DB_API_TOKENS_FILENAME);
+}
+
+func (this *backend) getAPITokens(course *model.Course) (apiTokens, error) {
+    path := this.getAPITokensPath(course);
+
+    tokens := make(apiTokens);
+    if (!util.PathExists(path)) {
+        return tokens, nil;
+    }
+
+    err := util.JSONFromFile(path, &tokens);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read API tokens '%s': '%w'.", path, err);
+    }
+
+    return tokens, nil;
+}
+
+func (this *backend) writeAPITokens(course *model.Course, tokens apiTokens) error {
+    path := this.getAPITokensPath(course);
+
+    err := util.MkDir(filepath.Dir(path));
+    if (err != nil) {
+        return fmt.Errorf("Failed to create directory for API tokens '%s': '%w'.", path, err);
+    }
+
+    err = util.ToJSONFileIndent(tokens, path);
+    if (err != nil) {
+        return fmt.Errorf("Failed to write API tokens '%s': '%w'.", path, err);
+    }
+
+    return nil;
+}
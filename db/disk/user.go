@@ -90,6 +90,11 @@ func (this *backend) RemoveUser(course *model.Course, email string) error {
         return fmt.Errorf("Unable to save user's file: '%w'.", err);
     }
 
+    err = this.removeUserAPITokens(course, email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to remove API tokens for '%s': '%w'.", email, err);
+    }
+
     return nil;
 }
 
@@ -0,0 +1,355 @@
+package db
+
+import (
+    "errors"
+    "fmt"
+    "slices"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/model"
+)
+
+// A summary of the records for a set of courses in a backend.
+type MigrationSummary struct {
+    Courses int `json:"courses"`
+    Assignments int `json:"assignments"`
+    Users int `json:"users"`
+    Submissions int `json:"submissions"`
+    TaskCompletions int `json:"task-completions"`
+    APITokens int `json:"api-tokens"`
+    // Server users are not tied to a course, so all of them are counted.
+    ServerUsers int `json:"server-users"`
+    // The audit log spans courses, so all records are counted.
+    AuditRecords int `json:"audit-records"`
+
+    // Full submission IDs (sorted).
+    SubmissionIDs []string `json:"-"`
+}
+
+// Copy all server users, the audit log, and courses (with their users, submissions, task completions, and API tokens) from one backend to another.
+// Any existing data for a migrated course in the target will be cleared first,
+// server users are upserted.
+// The audit log is append-only, so it is only copied if the target's log is empty
+// (re-running a migration will not duplicate records).
+// Submissions are copied one at a time, so large courses do not need to fit in memory.
+// Returns the IDs of the migrated courses.
+func MigrateBackend(source Backend, target Backend) ([]string, error) {
+    serverUsers, err := source.GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source server users: '%w'.", err);
+    }
+
+    err = target.SaveServerUsers(serverUsers);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to save server users: '%w'.", err);
+    }
+
+    err = migrateAuditLog(source, target);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to migrate audit log: '%w'.", err);
+    }
+
+    courses, err := source.GetCourses();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source courses: '%w'.", err);
+    }
+
+    courseIDs := make([]string, 0, len(courses));
+    for _, course := range courses {
+        err = migrateCourse(source, target, course);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to migrate course '%s': '%w'.", course.GetID(), err);
+        }
+
+        courseIDs = append(courseIDs, course.GetID());
+    }
+
+    slices.Sort(courseIDs);
+
+    return courseIDs, nil;
+}
+
+func migrateAuditLog(source Backend, target Backend) error {
+    existing, err := target.GetAuditRecords(model.AuditQuery{Limit: 1});
+    if (err != nil) {
+        return fmt.Errorf("Failed to get target audit records: '%w'.", err);
+    }
+
+    if (len(existing) > 0) {
+        log.Warn().Msg("Target already has an audit log, skipping audit log migration.");
+        return nil;
+    }
+
+    records, err := source.GetAuditRecords(model.AuditQuery{});
+    if (err != nil) {
+        return fmt.Errorf("Failed to get source audit records: '%w'.", err);
+    }
+
+    for _, record := range records {
+        err = target.AppendAuditRecord(record);
+        if (err != nil) {
+            return fmt.Errorf("Failed to append audit record: '%w'.", err);
+        }
+    }
+
+    log.Debug().Int("records", len(records)).Msg("Migrated audit log.");
+
+    return nil;
+}
+
+func migrateCourse(source Backend, target Backend, course *model.Course) error {
+    err := target.ClearCourse(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to clear target course: '%w'.", err);
+    }
+
+    err = target.SaveCourse(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save course: '%w'.", err);
+    }
+
+    users, err := source.GetUsers(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get users: '%w'.", err);
+    }
+
+    err = target.SaveUsers(course, users);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save users: '%w'.", err);
+    }
+
+    numTokens := 0;
+    for email, _ := range users {
+        tokens, err := source.GetAPITokens(course, email);
+        if (err != nil) {
+            return fmt.Errorf("Failed to get API tokens for '%s': '%w'.", email, err);
+        }
+
+        for _, token := range tokens {
+            err = target.SaveAPIToken(course, token);
+            if (err != nil) {
+                return fmt.Errorf("Failed to save API token '%s' for '%s': '%w'.", token.ID, email, err);
+            }
+        }
+
+        numTokens += len(tokens);
+    }
+
+    // Users who have been removed from the course can still have submissions.
+    count := 0;
+    for _, assignment := range course.Assignments {
+        emails, err := source.GetSubmissionUsers(assignment);
+        if (err != nil) {
+            return fmt.Errorf("Failed to get submission users on '%s': '%w'.", assignment.GetID(), err);
+        }
+
+        for _, email := range emails {
+            history, err := source.GetSubmissionHistory(assignment, email);
+            if (err != nil) {
+                return fmt.Errorf("Failed to get submission history for '%s' on '%s': '%w'.", email, assignment.GetID(), err);
+            }
+
+            for _, item := range history {
+                submission, err := source.GetSubmissionContents(assignment, email, item.ShortID);
+                if (err != nil) {
+                    return fmt.Errorf("Failed to get submission '%s': '%w'.", item.ID, err);
+                }
+
+                if (submission == nil) {
+                    return fmt.Errorf("Submission '%s' is in the history, but could not be found.", item.ID);
+                }
+
+                err = target.SaveSubmissions(course, []*model.GradingResult{submission});
+                if (err != nil) {
+                    return fmt.Errorf("Failed to save submission '%s': '%w'.", item.ID, err);
+                }
+
+                count++;
+            }
+        }
+    }
+
+    completions, err := source.GetTaskCompletions(course.GetID());
+    if (err != nil) {
+        return fmt.Errorf("Failed to get task completions: '%w'.", err);
+    }
+
+    for taskID, instance := range completions {
+        err = target.LogTaskCompletion(course.GetID(), taskID, instance);
+        if (err != nil) {
+            return fmt.Errorf("Failed to log task completion for '%s': '%w'.", taskID, err);
+        }
+    }
+
+    log.Debug().Str("course-id", course.GetID()).Int("users", len(users)).Int("submissions", count).
+            Int("task-completions", len(completions)).Int("api-tokens", numTokens).Msg("Migrated course.");
+
+    return nil;
+}
+
+// Check that all the courses in the source have been fully migrated to the target.
+// Courses (and server users) that only exist in the target are ignored.
+// Returns the source summary (on success) and an error describing any mismatches.
+func VerifyMigration(source Backend, target Backend) (*MigrationSummary, error) {
+    courses, err := source.GetCourses();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source courses: '%w'.", err);
+    }
+
+    courseIDs := make([]string, 0, len(courses));
+    for courseID, _ := range courses {
+        courseIDs = append(courseIDs, courseID);
+    }
+
+    sourceSummary, err := GetMigrationSummary(source, courseIDs);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to summarize source: '%w'.", err);
+    }
+
+    targetSummary, err := GetMigrationSummary(target, courseIDs);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to summarize target: '%w'.", err);
+    }
+
+    var errs error = nil;
+
+    checks := []struct{name string; source int; target int}{
+        {"courses", sourceSummary.Courses, targetSummary.Courses},
+        {"assignments", sourceSummary.Assignments, targetSummary.Assignments},
+        {"users", sourceSummary.Users, targetSummary.Users},
+        {"submissions", sourceSummary.Submissions, targetSummary.Submissions},
+        {"task completions", sourceSummary.TaskCompletions, targetSummary.TaskCompletions},
+        {"API tokens", sourceSummary.APITokens, targetSummary.APITokens},
+    };
+
+    for _, check := range checks {
+        if (check.source != check.target) {
+            errs = errors.Join(errs, fmt.Errorf("Number of %s does not match. Source: %d, Target: %d.", check.name, check.source, check.target));
+        }
+    }
+
+    sourceServerUsers, err := source.GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source server users: '%w'.", err);
+    }
+
+    for email, _ := range sourceServerUsers {
+        targetUser, err := target.GetServerUser(email);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get target server user '%s': '%w'.", email, err);
+        }
+
+        if (targetUser == nil) {
+            errs = errors.Join(errs, fmt.Errorf("Server user '%s' is missing from the target.", email));
+        }
+    }
+
+    // The target may have its own audit records, but should have at least everything from the source.
+    if (targetSummary.AuditRecords < sourceSummary.AuditRecords) {
+        errs = errors.Join(errs, fmt.Errorf("Target is missing audit records. Source: %d, Target: %d.",
+                sourceSummary.AuditRecords, targetSummary.AuditRecords));
+    }
+
+    for _, id := range sourceSummary.SubmissionIDs {
+        _, found := slices.BinarySearch(targetSummary.SubmissionIDs, id);
+        if (!found) {
+            errs = errors.Join(errs, fmt.Errorf("Submission '%s' is missing from the target.", id));
+        }
+    }
+
+    for _, id := range targetSummary.SubmissionIDs {
+        _, found := slices.BinarySearch(sourceSummary.SubmissionIDs, id);
+        if (!found) {
+            errs = errors.Join(errs, fmt.Errorf("Submission '%s' is in the target, but not the source.", id));
+        }
+    }
+
+    if (errs != nil) {
+        return nil, errs;
+    }
+
+    return sourceSummary, nil;
+}
+
+// Count the records for the given courses in a backend.
+// Missing courses are skipped.
+func GetMigrationSummary(backend Backend, courseIDs []string) (*MigrationSummary, error) {
+    summary := &MigrationSummary{
+        SubmissionIDs: make([]string, 0),
+    };
+
+    serverUsers, err := backend.GetServerUsers();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    summary.ServerUsers = len(serverUsers);
+
+    auditRecords, err := backend.GetAuditRecords(model.AuditQuery{});
+    if (err != nil) {
+        return nil, err;
+    }
+
+    summary.AuditRecords = len(auditRecords);
+
+    for _, courseID := range courseIDs {
+        course, err := backend.GetCourse(courseID);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        if (course == nil) {
+            continue;
+        }
+
+        summary.Courses++;
+        summary.Assignments += len(course.Assignments);
+
+        users, err := backend.GetUsers(course);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        summary.Users += len(users);
+
+        for email, _ := range users {
+            tokens, err := backend.GetAPITokens(course, email);
+            if (err != nil) {
+                return nil, err;
+            }
+
+            summary.APITokens += len(tokens);
+        }
+
+        for _, assignment := range course.Assignments {
+            emails, err := backend.GetSubmissionUsers(assignment);
+            if (err != nil) {
+                return nil, err;
+            }
+
+            for _, email := range emails {
+                history, err := backend.GetSubmissionHistory(assignment, email);
+                if (err != nil) {
+                    return nil, err;
+                }
+
+                summary.Submissions += len(history);
+                for _, item := range history {
+                    summary.SubmissionIDs = append(summary.SubmissionIDs, item.ID);
+                }
+            }
+        }
+
+        completions, err := backend.GetTaskCompletions(courseID);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        summary.TaskCompletions += len(completions);
+    }
+
+    slices.Sort(summary.SubmissionIDs);
+
+    return summary, nil;
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+    "path/filepath"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+// Migrate the current backend into a fresh SQLite database.
+func (this *DBTests) DBTestMigrateBackend(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    tempDir, err := util.MkDirTemp("autograder-test-db-migrate-");
+    if (err != nil) {
+        test.Fatalf("Failed to create temp dir: '%v'.", err);
+    }
+
+    oldPath := config.DB_SQLITE_PATH.Get();
+    defer config.DB_SQLITE_PATH.Set(oldPath);
+    config.DB_SQLITE_PATH.Set(filepath.Join(tempDir, "target.db"));
+
+    target, err := OpenBackend(DB_TYPE_SQLITE);
+    if (err != nil) {
+        test.Fatalf("Failed to open target: '%v'.", err);
+    }
+    defer target.Close();
+
+    courseID := MustGetTestCourse().GetID();
+
+    instance := time.Date(2023, 10, 15, 12, 30, 0, 0, time.UTC);
+    err = LogTaskCompletion(courseID, "test-task", instance);
+    if (err != nil) {
+        test.Fatalf("Failed to log task completion: '%v'.", err);
+    }
+
+    token, _, err := model.NewAPIToken("grader@test.com", "test", time.Hour);
+    if (err != nil) {
+        test.Fatalf("Failed to create API token: '%v'.", err);
+    }
+
+    err = SaveAPIToken(MustGetTestCourse(), token);
+    if (err != nil) {
+        test.Fatalf("Failed to save API token: '%v'.", err);
+    }
+
+    _, err = MigrateServerUsers(false);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate server users: '%v'.", err);
+    }
+
+    err = AppendAuditRecord(model.NewAuditRecord(model.AUDIT_SOURCE_CMD, courseID, "cmd:test", "cmd/test", "", nil));
+    if (err != nil) {
+        test.Fatalf("Failed to append audit record: '%v'.", err);
+    }
+
+    // Submissions from users who have left the course should still be migrated.
+    _, err = RemoveUser(MustGetTestCourse(), "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to remove user: '%v'.", err);
+    }
+
+    courseIDs, err := MigrateBackend(backend, target);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate: '%v'.", err);
+    }
+
+    if (!slices.Contains(courseIDs, courseID)) {
+        test.Fatalf("Test course was not migrated: '%v'.", courseIDs);
+    }
+
+    summary, err := VerifyMigration(backend, target);
+    if (err != nil) {
+        test.Fatalf("Migration does not verify: '%v'.", err);
+    }
+
+    if ((summary.Courses != len(courseIDs)) || (summary.Submissions != 3) || (summary.TaskCompletions == 0) || (summary.APITokens != 1) || (summary.ServerUsers == 0) || (summary.AuditRecords != 1)) {
+        test.Fatalf("Unexpected migration summary: '%+v'.", summary);
+    }
+
+    targetInstance, err := target.GetLastTaskCompletion(courseID, "test-task");
+    if (err != nil) {
+        test.Fatalf("Failed to get target task completion: '%v'.", err);
+    }
+
+    if (!instance.Equal(targetInstance)) {
+        test.Fatalf("Task completion time does not match. Expected: '%v', Actual: '%v'.", instance, targetInstance);
+    }
+
+    // The audit log is append-only, so migrating again should not duplicate it.
+    _, err = MigrateBackend(backend, target);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate again: '%v'.", err);
+    }
+
+    records, err := target.GetAuditRecords(model.AuditQuery{});
+    if (err != nil) {
+        test.Fatalf("Failed to get target audit records: '%v'.", err);
+    }
+
+    if (len(records) != 1) {
+        test.Fatalf("Unexpected number of target audit records. Expected: 1, Actual: %d.", len(records));
+    }
+
+    // Removing a submission from the target should fail verification.
+    _, err = target.RemoveSubmission(MustGetTestAssignment(), "student@test.com", "");
+    if (err != nil) {
+        test.Fatalf("Failed to remove submission: '%v'.", err);
+    }
+
+    _, err = VerifyMigration(backend, target);
+    if (err == nil) {
+        test.Fatalf("Migration verified with a missing submission.");
+    }
+}
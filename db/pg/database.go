@@ -1,33 +1,46 @@
+// A database backend that uses Postgres.
+// All data (including submission files) is stored in the database,
+// so multiple autograder instances can share the same database.
+// The operations are shared with the other SQL backends (see the sqldb package),
+// and the schema is managed through the migrations in schema.go.
 package pg
 
 import (
-    "context"
+    "database/sql"
     "fmt"
 
-    "github.com/jackc/pgx/v5/pgxpool"
+    _ "github.com/jackc/pgx/v5/stdlib"
+    "github.com/rs/zerolog/log"
 
     "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db/sqldb"
 )
 
-type backend struct {
-    pool *pgxpool.Pool
-}
+var dialect sqldb.Dialect = sqldb.Dialect{
+    Name: "pg",
+    NumberedPlaceholders: true,
+    Migrations: migrations,
+    MigrationLock: `LOCK TABLE schema_version IN EXCLUSIVE MODE`,
+};
 
-func Open() (*backend, error) {
+func Open() (*sqldb.Backend, error) {
     uri := config.DB_PG_URI.Get();
     if (uri == "") {
         return nil, fmt.Errorf("Postgres connection URI is empty.");
     }
 
-    pool, err := pgxpool.New(context.Background(), uri);
-	if (err != nil) {
-        return nil, fmt.Errorf("Failed to open connection pool to Postgres database at '%s': %w.", uri, err);
-	}
+    db, err := sql.Open("pgx", uri);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to open connection pool to Postgres database: '%w'.", err);
+    }
 
-    return &backend{pool}, nil;
-}
+    err = db.Ping();
+    if (err != nil) {
+        db.Close();
+        return nil, fmt.Errorf("Failed to connect to Postgres database: '%w'.", err);
+    }
+
+    log.Debug().Msg("Opened Postgres database.");
 
-func (this *backend) Close() error {
-    this.pool.Close()
-    return nil;
+    return sqldb.NewBackend(db, &dialect), nil;
 }
@@ -0,0 +1,108 @@
+package pg
+
+// Schema migrations (see sqldb.Dialect.Migrations).
+var migrations []string = []string{
+    // 1: Initial schema.
+    `
+    CREATE TABLE courses (
+        id TEXT PRIMARY KEY,
+        data JSONB NOT NULL
+    );
+
+    CREATE TABLE assignments (
+        course_id TEXT NOT NULL,
+        id TEXT NOT NULL,
+        data JSONB NOT NULL,
+        PRIMARY KEY (course_id, id)
+    );
+
+    CREATE TABLE users (
+        course_id TEXT NOT NULL,
+        email TEXT NOT NULL,
+        name TEXT NOT NULL DEFAULT '',
+        role TEXT NOT NULL,
+        pass TEXT NOT NULL DEFAULT '',
+        salt TEXT NOT NULL DEFAULT '',
+        lms_id TEXT NOT NULL DEFAULT '',
+        PRIMARY KEY (course_id, email)
+    );
+
+    CREATE TABLE submissions (
+        course_id TEXT NOT NULL,
+        assignment_id TEXT NOT NULL,
+        user_email TEXT NOT NULL,
+        short_id TEXT NOT NULL,
+        info JSONB NOT NULL,
+        stdout TEXT NOT NULL DEFAULT '',
+        stderr TEXT NOT NULL DEFAULT '',
+        PRIMARY KEY (course_id, assignment_id, user_email, short_id)
+    );
+
+    -- Each file is individually gzipped (see util.GzipDirectoryToBytes()).
+    CREATE TABLE submission_files (
+        course_id TEXT NOT NULL,
+        assignment_id TEXT NOT NULL,
+        user_email TEXT NOT NULL,
+        short_id TEXT NOT NULL,
+        is_input BOOLEAN NOT NULL,
+        path TEXT NOT NULL,
+        contents BYTEA NOT NULL,
+        PRIMARY KEY (course_id, assignment_id, user_email, short_id, is_input, path),
+        FOREIGN KEY (course_id, assignment_id, user_email, short_id)
+            REFERENCES submissions (course_id, assignment_id, user_email, short_id)
+            ON DELETE CASCADE
+    );
+
+    CREATE TABLE task_completions (
+        course_id TEXT NOT NULL,
+        task_id TEXT NOT NULL,
+        completed_at TIMESTAMPTZ NOT NULL,
+        PRIMARY KEY (course_id, task_id)
+    );
+    `,
+
+    // 2: API tokens.
+    `
+    CREATE TABLE api_tokens (
+        course_id TEXT NOT NULL,
+        user_email TEXT NOT NULL,
+        id TEXT NOT NULL,
+        name TEXT NOT NULL DEFAULT '',
+        hash TEXT NOT NULL,
+        creation_time TEXT NOT NULL,
+        expiration_time TEXT NOT NULL,
+        PRIMARY KEY (course_id, user_email, id)
+    );
+    `,
+
+    // 3: Server users.
+    `
+    CREATE TABLE server_users (
+        email TEXT PRIMARY KEY,
+        name TEXT NOT NULL DEFAULT '',
+        pass TEXT NOT NULL DEFAULT '',
+        salt TEXT NOT NULL DEFAULT ''
+    );
+    `,
+
+    // 4: Audit log.
+    `
+    -- Records are only ever inserted, the id gives the order they were added in.
+    -- unix_time is the record's timestamp in seconds (used for filtering).
+    CREATE TABLE audit_log (
+        id BIGSERIAL PRIMARY KEY,
+        record_time TEXT NOT NULL,
+        unix_time BIGINT NOT NULL,
+        source TEXT NOT NULL,
+        course_id TEXT NOT NULL DEFAULT '',
+        actor TEXT NOT NULL DEFAULT '',
+        action TEXT NOT NULL,
+        target TEXT NOT NULL DEFAULT '',
+        parameters JSONB NOT NULL,
+        success BOOLEAN NOT NULL,
+        locator TEXT NOT NULL DEFAULT ''
+    );
+
+    CREATE INDEX audit_log_course_time ON audit_log (course_id, unix_time);
+    `,
+};
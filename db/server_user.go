@@ -0,0 +1,174 @@
+package db
+
+import (
+    "fmt"
+    "slices"
+
+    "github.com/eriq-augustine/autograder/model"
+)
+
+// The result of creating server users from existing course users.
+type ServerUserMigrationResult struct {
+    // Emails of the server users that were (or would be, on a dry run) created.
+    Added []string `json:"added"`
+
+    // Emails of course users that already had a server user.
+    Existing []string `json:"existing"`
+}
+
+func GetServerUsers() (map[string]*model.ServerUser, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetServerUsers();
+}
+
+// Returns nil if no matching user exists.
+func GetServerUser(email string) (*model.ServerUser, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetServerUser(email);
+}
+
+func SaveServerUsers(users map[string]*model.ServerUser) error {
+    if (backend == nil) {
+        return fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.SaveServerUsers(users);
+}
+
+// Convenience function for SaveServerUsers() with a single user.
+func SaveServerUser(user *model.ServerUser) error {
+    return SaveServerUsers(map[string]*model.ServerUser{user.Email: user});
+}
+
+// Remove a server user (the user's course enrollments are not touched).
+// Returns a boolean indicating if the user exists.
+func RemoveServerUser(email string) (bool, error) {
+    if (backend == nil) {
+        return false, fmt.Errorf("Database has not been opened.");
+    }
+
+    user, err := GetServerUser(email);
+    if (err != nil) {
+        return false, err;
+    }
+
+    if (user == nil) {
+        return false, nil;
+    }
+
+    return true, backend.RemoveServerUser(email);
+}
+
+// Get all the courses a user is enrolled in (has a course user in), ordered by course ID.
+func GetEnrollments(email string) ([]*model.Enrollment, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    courses, err := GetCourses();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get courses: '%w'.", err);
+    }
+
+    enrollments := make([]*model.Enrollment, 0);
+    for _, courseID := range getSortedCourseIDs(courses) {
+        course := courses[courseID];
+
+        user, err := GetUser(course, email);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get user '%s' in course '%s': '%w'.", email, courseID, err);
+        }
+
+        if (user == nil) {
+            continue;
+        }
+
+        enrollments = append(enrollments, &model.Enrollment{
+            CourseID: course.GetID(),
+            CourseName: course.GetDisplayName(),
+            Role: user.Role,
+        });
+    }
+
+    return enrollments, nil;
+}
+
+// Create a server user for every course user that does not already have one.
+// New server users do not have a password (course passwords may have been chosen by a course admin),
+// users set their own server password with a password reset.
+// Existing server users are never modified.
+func MigrateServerUsers(dryRun bool) (*ServerUserMigrationResult, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    existingUsers, err := GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get server users: '%w'.", err);
+    }
+
+    courses, err := GetCourses();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get courses: '%w'.", err);
+    }
+
+    result := &ServerUserMigrationResult{
+        Added: make([]string, 0),
+        Existing: make([]string, 0),
+    };
+
+    newUsers := make(map[string]*model.ServerUser);
+
+    for _, courseID := range getSortedCourseIDs(courses) {
+        courseUsers, err := GetUsers(courses[courseID]);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get users for course '%s': '%w'.", courseID, err);
+        }
+
+        for _, courseUser := range courseUsers {
+            if (existingUsers[courseUser.Email] != nil) {
+                if (!slices.Contains(result.Existing, courseUser.Email)) {
+                    result.Existing = append(result.Existing, courseUser.Email);
+                }
+
+                continue;
+            }
+
+            if (newUsers[courseUser.Email] == nil) {
+                newUsers[courseUser.Email] = model.NewServerUser(courseUser.Email, courseUser.Name);
+                result.Added = append(result.Added, courseUser.Email);
+            }
+        }
+    }
+
+    slices.Sort(result.Added);
+    slices.Sort(result.Existing);
+
+    if (dryRun) {
+        return result, nil;
+    }
+
+    err = SaveServerUsers(newUsers);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to save server users: '%w'.", err);
+    }
+
+    return result, nil;
+}
+
+func getSortedCourseIDs(courses map[string]*model.Course) []string {
+    courseIDs := make([]string, 0, len(courses));
+    for courseID := range courses {
+        courseIDs = append(courseIDs, courseID);
+    }
+
+    slices.Sort(courseIDs);
+
+    return courseIDs;
+}
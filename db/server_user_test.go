@@ -0,0 +1,190 @@
+package db
+
+import (
+    "slices"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func (this *DBTests) DBTestServerUsers(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    email := "server@test.com";
+
+    user := model.NewServerUser(email, "Server User");
+    err := user.SetPassword(util.Sha256HexFromString("server"));
+    if (err != nil) {
+        test.Fatalf("Failed to set password: '%v'.", err);
+    }
+
+    err = SaveServerUser(user);
+    if (err != nil) {
+        test.Fatalf("Failed to save server user: '%v'.", err);
+    }
+
+    dbUser, err := GetServerUser(email);
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    if ((dbUser == nil) || (*dbUser != *user)) {
+        test.Fatalf("Unexpected server user. Expected: '%+v', Actual: '%+v'.", user, dbUser);
+    }
+
+    if (!dbUser.CheckPassword(util.Sha256HexFromString("server"))) {
+        test.Fatalf("Password does not match after round trip.");
+    }
+
+    users, err := GetServerUsers();
+    if (err != nil) {
+        test.Fatalf("Failed to get server users: '%v'.", err);
+    }
+
+    if ((len(users) != 1) || (users[email] == nil)) {
+        test.Fatalf("Unexpected server users: '%+v'.", users);
+    }
+
+    // Clearing a course should not touch server users.
+    err = ClearCourse(MustGetTestCourse());
+    if (err != nil) {
+        test.Fatalf("Failed to clear course: '%v'.", err);
+    }
+
+    exists, err := RemoveServerUser(email);
+    if (err != nil) {
+        test.Fatalf("Failed to remove server user: '%v'.", err);
+    }
+
+    if (!exists) {
+        test.Fatalf("Server user was removed along with a course.");
+    }
+
+    dbUser, err = GetServerUser(email);
+    if (err != nil) {
+        test.Fatalf("Failed to get removed server user: '%v'.", err);
+    }
+
+    if (dbUser != nil) {
+        test.Fatalf("Server user still exists after removal: '%+v'.", dbUser);
+    }
+
+    exists, err = RemoveServerUser(email);
+    if (err != nil) {
+        test.Fatalf("Failed to remove missing server user: '%v'.", err);
+    }
+
+    if (exists) {
+        test.Fatalf("Missing server user was reported as existing.");
+    }
+}
+
+func (this *DBTests) DBTestMigrateServerUsers(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    // A dry run should not write anything.
+    result, err := MigrateServerUsers(true);
+    if (err != nil) {
+        test.Fatalf("Failed to dry run migration: '%v'.", err);
+    }
+
+    users, err := GetServerUsers();
+    if (err != nil) {
+        test.Fatalf("Failed to get server users: '%v'.", err);
+    }
+
+    if (len(users) != 0) {
+        test.Fatalf("Dry run created server users: '%+v'.", users);
+    }
+
+    result, err = MigrateServerUsers(false);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate server users: '%v'.", err);
+    }
+
+    expectedAdded := []string{"admin@test.com", "grader@test.com", "no-lms-id@test.com", "other@test.com", "owner@test.com", "student@test.com"};
+    if (!slices.Equal(expectedAdded, result.Added) || (len(result.Existing) != 0)) {
+        test.Fatalf("Unexpected migration result: '%+v'.", result);
+    }
+
+    // Course passwords are not copied, the user has to set their own server password.
+    serverStudent, err := GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server student: '%v'.", err);
+    }
+
+    if ((serverStudent == nil) || serverStudent.HasPassword() || serverStudent.CheckPassword(util.Sha256HexFromString("student"))) {
+        test.Fatalf("Migrated server student has a password: '%+v'.", serverStudent);
+    }
+
+    // A second migration should find everyone.
+    result, err = MigrateServerUsers(false);
+    if (err != nil) {
+        test.Fatalf("Failed to re-migrate server users: '%v'.", err);
+    }
+
+    if ((len(result.Added) != 0) || !slices.Equal(expectedAdded, result.Existing)) {
+        test.Fatalf("Unexpected second migration result: '%+v'.", result);
+    }
+}
+
+func (this *DBTests) DBTestGetEnrollments(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    enrollments, err := GetEnrollments("no-lms-id@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get enrollments: '%v'.", err);
+    }
+
+    courseIDs := make([]string, 0, len(enrollments));
+    for _, enrollment := range enrollments {
+        if (enrollment.Role != model.RoleStudent) {
+            test.Fatalf("Unexpected role in enrollment: '%+v'.", enrollment);
+        }
+
+        courseIDs = append(courseIDs, enrollment.CourseID);
+    }
+
+    expected := []string{"course-languages", "course-with-lms", "course-without-source"};
+    if (!slices.Equal(expected, courseIDs)) {
+        test.Fatalf("Unexpected enrollments. Expected: '%v', Actual: '%v'.", expected, courseIDs);
+    }
+
+    enrollments, err = GetEnrollments("zzz@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get enrollments for unknown user: '%v'.", err);
+    }
+
+    if (len(enrollments) != 0) {
+        test.Fatalf("Unknown user has enrollments: '%+v'.", enrollments);
+    }
+}
+
+// Course admins choose the passwords of the users they add, so adding a course user must not create a server user.
+func (this *DBTests) DBTestSyncUsersSkipsServerUsers(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    course := MustGetTestCourse();
+
+    newUser := model.NewUser("new@test.com", "New User", model.RoleStudent);
+    newUser.Pass = util.Sha256HexFromString("new");
+
+    _, err := SyncUser(course, newUser, false, false, false);
+    if (err != nil) {
+        test.Fatalf("Failed to sync user: '%v'.", err);
+    }
+
+    serverUser, err := GetServerUser("new@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    if (serverUser != nil) {
+        test.Fatalf("New course user got a server user: '%+v'.", serverUser);
+    }
+}
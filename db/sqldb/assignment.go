@@ -0,0 +1,29 @@
+package sqldb
+
+import (
+    "fmt"
+
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func (this *Backend) SaveAssignment(assignment *model.Assignment) error {
+    return saveAssignment(this.db, assignment);
+}
+
+func saveAssignment(db querier, assignment *model.Assignment) error {
+    data, err := util.ToJSON(assignment);
+    if (err != nil) {
+        return fmt.Errorf("Failed to serialize assignment '%s': '%w'.", assignment.FullID(), err);
+    }
+
+    _, err = db.Exec(
+        `INSERT INTO assignments (course_id, id, data) VALUES (?, ?, ?)
+        ON CONFLICT (course_id, id) DO UPDATE SET data = excluded.data`,
+        assignment.GetCourse().GetID(), assignment.GetID(), data);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save assignment '%s': '%w'.", assignment.FullID(), err);
+    }
+
+    return nil;
+}
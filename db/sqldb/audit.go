@@ -0,0 +1,110 @@
+package sqldb
+
+import (
+    "fmt"
+    "slices"
+    "strings"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const AUDIT_COLUMNS = "record_time, source, course_id, actor, action, target, parameters, success, locator";
+
+func (this *Backend) AppendAuditRecord(record *model.AuditRecord) error {
+    parameters, err := util.ToJSON(record.Parameters);
+    if (err != nil) {
+        return fmt.Errorf("Failed to serialize audit record parameters: '%w'.", err);
+    }
+
+    _, err = this.db.Exec(
+        `INSERT INTO audit_log (unix_time, ` + AUDIT_COLUMNS + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+        record.Time().Unix(), string(record.Timestamp), record.Source, record.CourseID, record.Actor,
+        record.Action, record.Target, parameters, record.Success, record.Locator);
+    if (err != nil) {
+        return fmt.Errorf("Failed to append audit record: '%w'.", err);
+    }
+
+    return nil;
+}
+
+func (this *Backend) GetAuditRecords(query model.AuditQuery) ([]*model.AuditRecord, error) {
+    conditions := make([]string, 0);
+    args := make([]any, 0);
+
+    if (query.CourseID != "") {
+        conditions = append(conditions, "course_id = ?");
+        args = append(args, query.CourseID);
+    }
+
+    if (query.Actor != "") {
+        conditions = append(conditions, "actor = ?");
+        args = append(args, query.Actor);
+    }
+
+    if (query.Action != "") {
+        conditions = append(conditions, "action = ?");
+        args = append(args, query.Action);
+    }
+
+    if (!query.After.IsZero()) {
+        conditions = append(conditions, "unix_time >= ?");
+        args = append(args, query.After.Truncate(time.Second).Unix());
+    }
+
+    if (!query.Before.IsZero()) {
+        conditions = append(conditions, "unix_time <= ?");
+        args = append(args, query.Before.Unix());
+    }
+
+    statement := `SELECT ` + AUDIT_COLUMNS + ` FROM audit_log`;
+    if (len(conditions) > 0) {
+        statement += ` WHERE ` + strings.Join(conditions, " AND ");
+    }
+
+    // Fetch newest first so the limit keeps the most recent records.
+    statement += ` ORDER BY id DESC`;
+    if (query.Limit > 0) {
+        statement += ` LIMIT ?`;
+        args = append(args, query.Limit);
+    }
+
+    rows, err := this.db.Query(statement, args...);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get audit records: '%w'.", err);
+    }
+    defer rows.Close();
+
+    records := make([]*model.AuditRecord, 0);
+    for rows.Next() {
+        var record model.AuditRecord;
+        var timestamp string;
+        var parameters string;
+
+        err = rows.Scan(&timestamp, &record.Source, &record.CourseID, &record.Actor, &record.Action,
+                &record.Target, &parameters, &record.Success, &record.Locator);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read audit record: '%w'.", err);
+        }
+
+        record.Timestamp = common.Timestamp(timestamp);
+
+        err = util.JSONFromString(parameters, &record.Parameters);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to parse audit record parameters: '%w'.", err);
+        }
+
+        records = append(records, &record);
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read audit records: '%w'.", err);
+    }
+
+    slices.Reverse(records);
+
+    return records, nil;
+}
@@ -0,0 +1,301 @@
+package sqldb
+
+import (
+    "database/sql"
+    "fmt"
+    "path/filepath"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func (this *Backend) ClearCourse(course *model.Course) error {
+    return this.withTx(func(tx querier) error {
+        for _, table := range DATA_TABLES {
+            column := "course_id";
+            if (table == "courses") {
+                column = "id";
+            }
+
+            _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", table, column), course.GetID());
+            if (err != nil) {
+                return fmt.Errorf("Failed to clear table '%s' for course '%s': '%w'.", table, course.GetID(), err);
+            }
+        }
+
+        return nil;
+    });
+}
+
+func (this *Backend) LoadCourse(path string) (*model.Course, error) {
+    course, users, submissions, err := model.FullLoadCourseFromPath(path);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    taskLog, err := model.LoadStaticTaskLog(path);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to load static task log for course config '%s': '%w'.", path, err);
+    }
+
+    err = this.withTx(func(tx querier) error {
+        err := saveCourse(tx, course);
+        if (err != nil) {
+            return err;
+        }
+
+        err = saveUsers(tx, course, users);
+        if (err != nil) {
+            return err;
+        }
+
+        err = saveSubmissions(tx, submissions);
+        if (err != nil) {
+            return err;
+        }
+
+        // Keep the later completion of each task, so a stale dump will not move completions back.
+        for taskID, instance := range taskLog {
+            lastInstance, err := getLastTaskCompletion(tx, course.GetID(), taskID);
+            if (err != nil) {
+                return err;
+            }
+
+            if (!instance.After(lastInstance)) {
+                continue;
+            }
+
+            err = logTaskCompletion(tx, course.GetID(), taskID, instance);
+            if (err != nil) {
+                return err;
+            }
+        }
+
+        return nil;
+    });
+
+    if (err != nil) {
+        return nil, err;
+    }
+
+    log.Debug().Str("database", this.dialect.Name).Str("path", path).Str("id", course.GetID()).
+            Int("num-assignments", len(course.Assignments)).Msg("Loaded course.");
+
+    return course, nil;
+}
+
+func (this *Backend) SaveCourse(course *model.Course) error {
+    return this.withTx(func(tx querier) error {
+        return saveCourse(tx, course);
+    });
+}
+
+// Save a course and replace all of its assignments.
+func saveCourse(db querier, course *model.Course) error {
+    data, err := util.ToJSON(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to serialize course '%s': '%w'.", course.GetID(), err);
+    }
+
+    _, err = db.Exec(
+        `INSERT INTO courses (id, data) VALUES (?, ?)
+        ON CONFLICT (id) DO UPDATE SET data = excluded.data`,
+        course.GetID(), data);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save course '%s': '%w'.", course.GetID(), err);
+    }
+
+    _, err = db.Exec(`DELETE FROM assignments WHERE course_id = ?`, course.GetID());
+    if (err != nil) {
+        return fmt.Errorf("Failed to clear old assignments for course '%s': '%w'.", course.GetID(), err);
+    }
+
+    for _, assignment := range course.Assignments {
+        err = saveAssignment(db, assignment);
+        if (err != nil) {
+            return err;
+        }
+    }
+
+    return nil;
+}
+
+func (this *Backend) DumpCourse(course *model.Course, targetDir string) error {
+    err := util.ToJSONFileIndent(course, filepath.Join(targetDir, model.COURSE_CONFIG_FILENAME));
+    if (err != nil) {
+        return fmt.Errorf("Failed to dump course config: '%w'.", err);
+    }
+
+    for _, assignment := range course.Assignments {
+        path := filepath.Join(targetDir, "assignments", assignment.GetID(), model.ASSIGNMENT_CONFIG_FILENAME);
+
+        err = util.MkDir(filepath.Dir(path));
+        if (err != nil) {
+            return fmt.Errorf("Failed to make assignment dump dir: '%w'.", err);
+        }
+
+        err = util.ToJSONFileIndent(assignment, path);
+        if (err != nil) {
+            return fmt.Errorf("Failed to dump assignment '%s': '%w'.", assignment.FullID(), err);
+        }
+    }
+
+    users, err := this.GetUsers(course);
+    if (err != nil) {
+        return err;
+    }
+
+    err = util.ToJSONFileIndent(users, filepath.Join(targetDir, model.USERS_FILENAME));
+    if (err != nil) {
+        return fmt.Errorf("Failed to dump users: '%w'.", err);
+    }
+
+    submissions, err := getCourseSubmissions(this.db, course.GetID());
+    if (err != nil) {
+        return err;
+    }
+
+    for _, submission := range submissions {
+        info := submission.Info;
+        baseDir := filepath.Join(targetDir, model.SUBMISSIONS_DIRNAME, info.AssignmentID, info.User, info.ShortID);
+
+        err = dumpSubmission(submission, baseDir);
+        if (err != nil) {
+            return err;
+        }
+    }
+
+    taskLog, err := this.GetTaskCompletions(course.GetID());
+    if (err != nil) {
+        return err;
+    }
+
+    err = util.ToJSONFileIndent(taskLog, filepath.Join(targetDir, model.TASKS_FILENAME));
+    if (err != nil) {
+        return fmt.Errorf("Failed to dump task log: '%w'.", err);
+    }
+
+    return nil;
+}
+
+func (this *Backend) GetCourse(courseID string) (*model.Course, error) {
+    var data string;
+    err := this.db.QueryRow(`SELECT data FROM courses WHERE id = ?`, courseID).Scan(&data);
+    if (err == sql.ErrNoRows) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get course '%s': '%w'.", courseID, err);
+    }
+
+    return this.loadCourse(data);
+}
+
+func (this *Backend) GetCourses() (map[string]*model.Course, error) {
+    datas, err := queryStrings(this.db, `SELECT data FROM courses`);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get courses: '%w'.", err);
+    }
+
+    courses := make(map[string]*model.Course, len(datas));
+    for _, data := range datas {
+        course, err := this.loadCourse(data);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        courses[course.GetID()] = course;
+    }
+
+    return courses, nil;
+}
+
+// Build a full course (including assignments) from the course's JSON.
+func (this *Backend) loadCourse(data string) (*model.Course, error) {
+    course, err := model.ReadCourseJSON(data);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    datas, err := queryStrings(this.db, `SELECT data FROM assignments WHERE course_id = ?`, course.GetID());
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get assignments for course '%s': '%w'.", course.GetID(), err);
+    }
+
+    for _, data := range datas {
+        _, err = model.ReadAssignmentJSON(course, data);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to load assignment for course '%s': '%w'.", course.GetID(), err);
+        }
+    }
+
+    return course, nil;
+}
+
+// Run a query that selects a single string column and collect all the results.
+// The result set will be closed before returning.
+func queryStrings(db querier, query string, args ...any) ([]string, error) {
+    rows, err := db.Query(query, args...);
+    if (err != nil) {
+        return nil, err;
+    }
+    defer rows.Close();
+
+    results := make([]string, 0);
+    for rows.Next() {
+        var value string;
+        err = rows.Scan(&value);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        results = append(results, value);
+    }
+
+    return results, rows.Err();
+}
+
+// Write a submission in the standard layout (see model.LoadGradingResult()).
+func dumpSubmission(submission *model.GradingResult, baseDir string) error {
+    err := util.MkDir(baseDir);
+    if (err != nil) {
+        return fmt.Errorf("Failed to make submission dir '%s': '%w'.", baseDir, err);
+    }
+
+    err = util.ToJSONFileIndent(submission.Info, filepath.Join(baseDir, model.SUBMISSION_RESULT_FILENAME));
+    if (err != nil) {
+        return fmt.Errorf("Failed to write submission result for '%s': '%w'.", submission.Info.ID, err);
+    }
+
+    for dirname, files := range map[string]map[string][]byte{
+            common.GRADING_INPUT_DIRNAME: submission.InputFilesGZip,
+            common.GRADING_OUTPUT_DIRNAME: submission.OutputFilesGZip} {
+        dir := filepath.Join(baseDir, dirname);
+
+        err = util.MkDir(dir);
+        if (err != nil) {
+            return fmt.Errorf("Failed to make submission dir '%s': '%w'.", dir, err);
+        }
+
+        err = util.GzipBytesToDirectory(dir, files);
+        if (err != nil) {
+            return fmt.Errorf("Failed to write submission files for '%s': '%w'.", submission.Info.ID, err);
+        }
+    }
+
+    err = util.WriteFile(submission.Stdout, filepath.Join(baseDir, common.SUBMISSION_STDOUT_FILENAME));
+    if (err != nil) {
+        return fmt.Errorf("Failed to write submission stdout file: '%w'.", err);
+    }
+
+    err = util.WriteFile(submission.Stderr, filepath.Join(baseDir, common.SUBMISSION_STDERR_FILENAME));
+    if (err != nil) {
+        return fmt.Errorf("Failed to write submission stderr file: '%w'.", err);
+    }
+
+    return nil;
+}
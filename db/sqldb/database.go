@@ -0,0 +1,199 @@
+// The operations shared by the SQL database backends (SQLite and Postgres).
+// Each SQL backend opens its own connection (through database/sql) and supplies a Dialect,
+// and everything else (queries, scanning, transactions, and migrations) is handled here.
+// Queries are written with '?' placeholders, and rewritten for the dialect when they are run.
+package sqldb
+
+import (
+    "database/sql"
+    "fmt"
+    "slices"
+    "strings"
+
+    "github.com/rs/zerolog/log"
+)
+
+// All the tables that hold course data (not schema information).
+// Submission files are listed before submissions so foreign keys are not violated.
+var DATA_TABLES []string = []string{"courses", "assignments", "users", "submission_files", "submissions", "task_completions", "api_tokens"};
+
+// Tables that hold data that is not tied to a course.
+// These are cleared with the rest of the database, but not when clearing a single course.
+var SERVER_TABLES []string = []string{"server_users", "audit_log"};
+
+// What differs between SQL databases.
+type Dialect struct {
+    // Used in logging.
+    Name string
+
+    // Use numbered placeholders ($1, $2, ...) instead of '?'.
+    NumberedPlaceholders bool
+
+    // Schema migrations.
+    // Each migration is applied in order (inside a transaction) exactly once,
+    // and the schema version is the number of applied migrations.
+    // Existing migrations should never be modified, only new migrations added.
+    Migrations []string
+
+    // An optional statement run at the start of the migration transaction
+    // to make sure that concurrent servers do not try to migrate at the same time.
+    MigrationLock string
+}
+
+type Backend struct {
+    conn *sql.DB
+    // The connection, with placeholders rewritten for the dialect.
+    db querier
+    dialect *Dialect
+}
+
+// The common operations shared by the database and transactions.
+type querier interface {
+    Exec(query string, args ...any) (sql.Result, error);
+    Query(query string, args ...any) (*sql.Rows, error);
+    QueryRow(query string, args ...any) *sql.Row;
+}
+
+// Rewrites the placeholders of each query before passing it on.
+type dialectQuerier struct {
+    base querier
+    dialect *Dialect
+}
+
+func NewBackend(conn *sql.DB, dialect *Dialect) *Backend {
+    return &Backend{
+        conn: conn,
+        db: &dialectQuerier{conn, dialect},
+        dialect: dialect,
+    };
+}
+
+func (this *Backend) Close() error {
+    return this.conn.Close();
+}
+
+func (this *Backend) EnsureTables() error {
+    return this.migrate();
+}
+
+func (this *Backend) Clear() error {
+    return this.withTx(func(tx querier) error {
+        for _, table := range append(slices.Clone(DATA_TABLES), SERVER_TABLES...) {
+            _, err := tx.Exec("DELETE FROM " + table);
+            if (err != nil) {
+                return fmt.Errorf("Failed to clear table '%s': '%w'.", table, err);
+            }
+        }
+
+        return nil;
+    });
+}
+
+// Run a function inside a transaction.
+// The transaction will be committed if the function returns nil, and rolled back otherwise.
+// Since some backends only have one connection, the function must only use the transaction (and not the backend's db).
+func (this *Backend) withTx(operation func(tx querier) error) error {
+    tx, err := this.conn.Begin();
+    if (err != nil) {
+        return fmt.Errorf("Failed to begin transaction: '%w'.", err);
+    }
+    // Rolling back a committed transaction is a no-op.
+    defer tx.Rollback();
+
+    err = operation(&dialectQuerier{tx, this.dialect});
+    if (err != nil) {
+        return err;
+    }
+
+    err = tx.Commit();
+    if (err != nil) {
+        return fmt.Errorf("Failed to commit transaction: '%w'.", err);
+    }
+
+    return nil;
+}
+
+// Bring the schema up to the most recent version.
+func (this *Backend) migrate() error {
+    _, err := this.db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)`);
+    if (err != nil) {
+        return fmt.Errorf("Failed to create schema version table: '%w'.", err);
+    }
+
+    migrations := this.dialect.Migrations;
+
+    return this.withTx(func(tx querier) error {
+        if (this.dialect.MigrationLock != "") {
+            _, err := tx.Exec(this.dialect.MigrationLock);
+            if (err != nil) {
+                return fmt.Errorf("Failed to lock schema version table: '%w'.", err);
+            }
+        }
+
+        var version int;
+        err := tx.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version);
+        if (err != nil) {
+            return fmt.Errorf("Failed to get schema version: '%w'.", err);
+        }
+
+        if (version > len(migrations)) {
+            return fmt.Errorf("Database schema version (%d) is newer than this autograder supports (%d).", version, len(migrations));
+        }
+
+        for i := version; i < len(migrations); i++ {
+            _, err = tx.Exec(migrations[i]);
+            if (err != nil) {
+                return fmt.Errorf("Failed to apply schema migration %d: '%w'.", i + 1, err);
+            }
+
+            log.Info().Str("database", this.dialect.Name).Int("version", i + 1).Msg("Applied schema migration.");
+        }
+
+        _, err = tx.Exec(`DELETE FROM schema_version`);
+        if (err != nil) {
+            return fmt.Errorf("Failed to clear schema version: '%w'.", err);
+        }
+
+        _, err = tx.Exec(`INSERT INTO schema_version (version) VALUES (?)`, len(migrations));
+        if (err != nil) {
+            return fmt.Errorf("Failed to set schema version: '%w'.", err);
+        }
+
+        return nil;
+    });
+}
+
+func (this *dialectQuerier) Exec(query string, args ...any) (sql.Result, error) {
+    return this.base.Exec(this.dialect.bind(query), args...);
+}
+
+func (this *dialectQuerier) Query(query string, args ...any) (*sql.Rows, error) {
+    return this.base.Query(this.dialect.bind(query), args...);
+}
+
+func (this *dialectQuerier) QueryRow(query string, args ...any) *sql.Row {
+    return this.base.QueryRow(this.dialect.bind(query), args...);
+}
+
+// Rewrite the '?' placeholders in a query for this dialect.
+// Queries must not use '?' for anything else.
+func (this *Dialect) bind(query string) string {
+    if (!this.NumberedPlaceholders) {
+        return query;
+    }
+
+    var builder strings.Builder;
+    count := 0;
+
+    for _, char := range query {
+        if (char != '?') {
+            builder.WriteRune(char);
+            continue;
+        }
+
+        count++;
+        builder.WriteString(fmt.Sprintf("$%d", count));
+    }
+
+    return builder.String();
+}
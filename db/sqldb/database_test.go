@@ -0,0 +1,24 @@
+package sqldb
+
+import (
+    "testing"
+)
+
+func TestDialectBind(test *testing.T) {
+    testCases := []struct{numbered bool; query string; expected string}{
+        {false, `SELECT 1`, `SELECT 1`},
+        {false, `SELECT a FROM b WHERE c = ? AND d = ?`, `SELECT a FROM b WHERE c = ? AND d = ?`},
+        {true, `SELECT 1`, `SELECT 1`},
+        {true, `SELECT a FROM b WHERE c = ? AND d = ?`, `SELECT a FROM b WHERE c = $1 AND d = $2`},
+        {true, `INSERT INTO a (b, c) VALUES (?, ?) LIMIT ?`, `INSERT INTO a (b, c) VALUES ($1, $2) LIMIT $3`},
+    };
+
+    for i, testCase := range testCases {
+        dialect := Dialect{NumberedPlaceholders: testCase.numbered};
+
+        actual := dialect.bind(testCase.query);
+        if (testCase.expected != actual) {
+            test.Errorf("Case %d: Unexpected query. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual);
+        }
+    }
+}
@@ -0,0 +1,89 @@
+package sqldb
+
+import (
+    "database/sql"
+    "fmt"
+
+    "github.com/eriq-augustine/autograder/model"
+)
+
+const SERVER_USER_COLUMNS = "email, name, pass, salt";
+
+func (this *Backend) GetServerUsers() (map[string]*model.ServerUser, error) {
+    rows, err := this.db.Query(`SELECT ` + SERVER_USER_COLUMNS + ` FROM server_users`);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get server users: '%w'.", err);
+    }
+    defer rows.Close();
+
+    users := make(map[string]*model.ServerUser);
+    for rows.Next() {
+        user, err := scanServerUser(rows);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read server users: '%w'.", err);
+        }
+
+        users[user.Email] = user;
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read server users: '%w'.", err);
+    }
+
+    return users, nil;
+}
+
+func (this *Backend) GetServerUser(email string) (*model.ServerUser, error) {
+    row := this.db.QueryRow(`SELECT ` + SERVER_USER_COLUMNS + ` FROM server_users WHERE email = ?`, email);
+
+    user, err := scanServerUser(row);
+    if (err == sql.ErrNoRows) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get server user '%s': '%w'.", email, err);
+    }
+
+    return user, nil;
+}
+
+func (this *Backend) SaveServerUsers(users map[string]*model.ServerUser) error {
+    return this.withTx(func(tx querier) error {
+        for _, user := range users {
+            _, err := tx.Exec(
+                `INSERT INTO server_users (` + SERVER_USER_COLUMNS + `) VALUES (?, ?, ?, ?)
+                ON CONFLICT (email) DO UPDATE SET
+                    name = excluded.name,
+                    pass = excluded.pass,
+                    salt = excluded.salt`,
+                user.Email, user.Name, user.Pass, user.Salt);
+            if (err != nil) {
+                return fmt.Errorf("Failed to save server user '%s': '%w'.", user.Email, err);
+            }
+        }
+
+        return nil;
+    });
+}
+
+func (this *Backend) RemoveServerUser(email string) error {
+    _, err := this.db.Exec(`DELETE FROM server_users WHERE email = ?`, email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to remove server user '%s': '%w'.", email, err);
+    }
+
+    return nil;
+}
+
+func scanServerUser(row scanner) (*model.ServerUser, error) {
+    var user model.ServerUser;
+
+    err := row.Scan(&user.Email, &user.Name, &user.Pass, &user.Salt);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return &user, nil;
+}
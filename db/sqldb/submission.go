@@ -0,0 +1,463 @@
+package sqldb
+
+import (
+    "database/sql"
+    "fmt"
+    "time"
+
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func (this *Backend) SaveSubmissions(course *model.Course, submissions []*model.GradingResult) error {
+    return this.withTx(func(tx querier) error {
+        return saveSubmissions(tx, submissions);
+    });
+}
+
+func saveSubmissions(db querier, submissions []*model.GradingResult) error {
+    for _, submission := range submissions {
+        info := submission.Info;
+
+        data, err := util.ToJSON(info);
+        if (err != nil) {
+            return fmt.Errorf("Failed to serialize submission result '%s': '%w'.", info.ID, err);
+        }
+
+        _, err = db.Exec(
+            `INSERT INTO submissions (course_id, assignment_id, user_email, short_id, info, stdout, stderr)
+            VALUES (?, ?, ?, ?, ?, ?, ?)
+            ON CONFLICT (course_id, assignment_id, user_email, short_id) DO UPDATE SET
+                info = excluded.info,
+                stdout = excluded.stdout,
+                stderr = excluded.stderr`,
+            info.CourseID, info.AssignmentID, info.User, info.ShortID, data, submission.Stdout, submission.Stderr);
+        if (err != nil) {
+            return fmt.Errorf("Failed to save submission '%s': '%w'.", info.ID, err);
+        }
+
+        // Replace any existing files.
+        _, err = db.Exec(
+            `DELETE FROM submission_files
+            WHERE course_id = ? AND assignment_id = ? AND user_email = ? AND short_id = ?`,
+            info.CourseID, info.AssignmentID, info.User, info.ShortID);
+        if (err != nil) {
+            return fmt.Errorf("Failed to clear old files for submission '%s': '%w'.", info.ID, err);
+        }
+
+        for isInput, files := range map[bool]map[string][]byte{true: submission.InputFilesGZip, false: submission.OutputFilesGZip} {
+            for path, contents := range files {
+                _, err = db.Exec(
+                    `INSERT INTO submission_files (course_id, assignment_id, user_email, short_id, is_input, path, contents)
+                    VALUES (?, ?, ?, ?, ?, ?, ?)`,
+                    info.CourseID, info.AssignmentID, info.User, info.ShortID, isInput, path, contents);
+                if (err != nil) {
+                    return fmt.Errorf("Failed to save file '%s' for submission '%s': '%w'.", path, info.ID, err);
+                }
+            }
+        }
+    }
+
+    return nil;
+}
+
+func (this *Backend) GetNextSubmissionID(assignment *model.Assignment, email string) (string, error) {
+    submissionID := time.Now().Unix();
+
+    for ; ; {
+        var exists bool;
+        err := this.db.QueryRow(
+            `SELECT EXISTS(
+                SELECT 1 FROM submissions
+                WHERE course_id = ? AND assignment_id = ? AND user_email = ? AND short_id = ?
+            )`,
+            assignment.GetCourse().GetID(), assignment.GetID(), email, fmt.Sprintf("%d", submissionID)).Scan(&exists);
+        if (err != nil) {
+            return "", fmt.Errorf("Failed to check for existing submission id: '%w'.", err);
+        }
+
+        if (!exists) {
+            break;
+        }
+
+        // This ID has been used.
+        submissionID++;
+    }
+
+    return fmt.Sprintf("%d", submissionID), nil;
+}
+
+func (this *Backend) GetSubmissionResult(assignment *model.Assignment, email string, shortSubmissionID string) (*model.GradingInfo, error) {
+    var err error;
+
+    if (shortSubmissionID == "") {
+        shortSubmissionID, err = this.getMostRecentSubmissionID(assignment, email);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get most recent submission id: '%w'.", err);
+        }
+    }
+
+    if (shortSubmissionID == "") {
+        return nil, nil;
+    }
+
+    var data string;
+    err = this.db.QueryRow(
+        `SELECT info FROM submissions
+        WHERE course_id = ? AND assignment_id = ? AND user_email = ? AND short_id = ?`,
+        assignment.GetCourse().GetID(), assignment.GetID(), email, shortSubmissionID).Scan(&data);
+    if (err == sql.ErrNoRows) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get submission '%s': '%w'.", shortSubmissionID, err);
+    }
+
+    return parseGradingInfo(data);
+}
+
+func (this *Backend) GetSubmissionUsers(assignment *model.Assignment) ([]string, error) {
+    emails, err := queryStrings(this.db,
+        `SELECT DISTINCT user_email FROM submissions
+        WHERE course_id = ? AND assignment_id = ?
+        ORDER BY user_email`,
+        assignment.GetCourse().GetID(), assignment.GetID());
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get submission users: '%w'.", err);
+    }
+
+    return emails, nil;
+}
+
+func (this *Backend) GetSubmissionHistory(assignment *model.Assignment, email string) ([]*model.SubmissionHistoryItem, error) {
+    datas, err := queryStrings(this.db,
+        `SELECT info FROM submissions
+        WHERE course_id = ? AND assignment_id = ? AND user_email = ?
+        ORDER BY short_id`,
+        assignment.GetCourse().GetID(), assignment.GetID(), email);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get submission history: '%w'.", err);
+    }
+
+    history := make([]*model.SubmissionHistoryItem, 0, len(datas));
+    for _, data := range datas {
+        gradingInfo, err := parseGradingInfo(data);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        history = append(history, gradingInfo.ToHistoryItem());
+    }
+
+    return history, nil;
+}
+
+func (this *Backend) GetRecentSubmissions(assignment *model.Assignment, filterRole model.UserRole) (map[string]*model.GradingInfo, error) {
+    rows, err := this.db.Query(
+        `SELECT
+            u.email,
+            (
+                SELECT s.info FROM submissions s
+                WHERE s.course_id = u.course_id AND s.assignment_id = ? AND s.user_email = u.email
+                ORDER BY s.short_id DESC
+                LIMIT 1
+            )
+        FROM users u
+        WHERE u.course_id = ? AND (CAST(? AS TEXT) = '' OR u.role = ?)`,
+        assignment.GetID(), assignment.GetCourse().GetID(), roleFilterString(filterRole), roleFilterString(filterRole));
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get recent submissions: '%w'.", err);
+    }
+    defer rows.Close();
+
+    gradingInfos := make(map[string]*model.GradingInfo);
+
+    for rows.Next() {
+        var email string;
+        var data sql.NullString;
+
+        err = rows.Scan(&email, &data);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read recent submission: '%w'.", err);
+        }
+
+        if (!data.Valid) {
+            gradingInfos[email] = nil;
+            continue;
+        }
+
+        gradingInfo, err := parseGradingInfo(data.String);
+        if (err != nil) {
+            return nil, fmt.Errorf("Unable to deserialize grading info for '%s': '%w'.", email, err);
+        }
+
+        gradingInfos[email] = gradingInfo;
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read recent submissions: '%w'.", err);
+    }
+
+    return gradingInfos, nil;
+}
+
+func (this *Backend) GetScoringInfos(assignment *model.Assignment, filterRole model.UserRole) (map[string]*model.ScoringInfo, error) {
+    scoringInfos := make(map[string]*model.ScoringInfo);
+
+    submissionResults, err := this.GetRecentSubmissions(assignment, filterRole);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    for email, submissionResult := range submissionResults {
+        if (submissionResult == nil) {
+            scoringInfos[email] = nil;
+        } else {
+            scoringInfos[email] = submissionResult.ToScoringInfo();
+        }
+    }
+
+    return scoringInfos, nil;
+}
+
+func (this *Backend) GetRecentSubmissionSurvey(assignment *model.Assignment, filterRole model.UserRole) (map[string]*model.SubmissionHistoryItem, error) {
+    results := make(map[string]*model.SubmissionHistoryItem);
+
+    submissionResults, err := this.GetRecentSubmissions(assignment, filterRole);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    for email, submissionResult := range submissionResults {
+        if (submissionResult == nil) {
+            results[email] = nil;
+        } else {
+            results[email] = submissionResult.ToHistoryItem();
+        }
+    }
+
+    return results, nil;
+}
+
+func (this *Backend) GetSubmissionContents(assignment *model.Assignment, email string, shortSubmissionID string) (*model.GradingResult, error) {
+    var err error;
+
+    if (shortSubmissionID == "") {
+        shortSubmissionID, err = this.getMostRecentSubmissionID(assignment, email);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get most recent submission id: '%w'.", err);
+        }
+    }
+
+    if (shortSubmissionID == "") {
+        return nil, nil;
+    }
+
+    row := this.db.QueryRow(
+        `SELECT info, stdout, stderr FROM submissions
+        WHERE course_id = ? AND assignment_id = ? AND user_email = ? AND short_id = ?`,
+        assignment.GetCourse().GetID(), assignment.GetID(), email, shortSubmissionID);
+
+    result, err := scanGradingResult(row);
+    if (err == sql.ErrNoRows) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get submission '%s': '%w'.", shortSubmissionID, err);
+    }
+
+    err = loadSubmissionFiles(this.db, result);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return result, nil;
+}
+
+func (this *Backend) GetRecentSubmissionContents(assignment *model.Assignment, filterRole model.UserRole) (map[string]*model.GradingResult, error) {
+    results := make(map[string]*model.GradingResult);
+
+    users, err := this.GetUsers(assignment.Course);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    for email, user := range users {
+        if ((filterRole != model.RoleUnknown) && (filterRole != user.Role)) {
+            continue;
+        }
+
+        result, err := this.GetSubmissionContents(assignment, email, "");
+        if (err != nil) {
+            return nil, err;
+        }
+
+        results[email] = result;
+    }
+
+    return results, nil;
+}
+
+func (this *Backend) RemoveSubmission(assignment *model.Assignment, email string, shortSubmissionID string) (bool, error) {
+    var err error;
+
+    if (shortSubmissionID == "") {
+        shortSubmissionID, err = this.getMostRecentSubmissionID(assignment, email);
+        if (err != nil) {
+            return false, fmt.Errorf("Failed to get most recent submission id: `%w`.", err);
+        }
+    }
+
+    if (shortSubmissionID == "") {
+        return false, nil;
+    }
+
+    // Submission files will cascade.
+    result, err := this.db.Exec(
+        `DELETE FROM submissions
+        WHERE course_id = ? AND assignment_id = ? AND user_email = ? AND short_id = ?`,
+        assignment.GetCourse().GetID(), assignment.GetID(), email, shortSubmissionID);
+    if (err != nil) {
+        return false, fmt.Errorf("Failed to remove submission '%s': '%w'", shortSubmissionID, err);
+    }
+
+    count, err := result.RowsAffected();
+    if (err != nil) {
+        return false, fmt.Errorf("Failed to check removal of submission '%s': '%w'", shortSubmissionID, err);
+    }
+
+    return (count > 0), nil;
+}
+
+// Get the short id of the most recent submission (or empty string if there are no submissions).
+func (this *Backend) getMostRecentSubmissionID(assignment *model.Assignment, email string) (string, error) {
+    var shortSubmissionID string;
+    err := this.db.QueryRow(
+        `SELECT short_id FROM submissions
+        WHERE course_id = ? AND assignment_id = ? AND user_email = ?
+        ORDER BY short_id DESC
+        LIMIT 1`,
+        assignment.GetCourse().GetID(), assignment.GetID(), email).Scan(&shortSubmissionID);
+    if (err == sql.ErrNoRows) {
+        return "", nil;
+    }
+
+    if (err != nil) {
+        return "", err;
+    }
+
+    return shortSubmissionID, nil;
+}
+
+// Get all the full submissions for a course.
+func getCourseSubmissions(db querier, courseID string) ([]*model.GradingResult, error) {
+    rows, err := db.Query(`SELECT info, stdout, stderr FROM submissions WHERE course_id = ?`, courseID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get submissions for course '%s': '%w'.", courseID, err);
+    }
+
+    results := make([]*model.GradingResult, 0);
+    for rows.Next() {
+        result, err := scanGradingResult(rows);
+        if (err != nil) {
+            rows.Close();
+            return nil, fmt.Errorf("Failed to read submissions for course '%s': '%w'.", courseID, err);
+        }
+
+        results = append(results, result);
+    }
+
+    // The result set must be closed before the files can be queried.
+    rows.Close();
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read submissions for course '%s': '%w'.", courseID, err);
+    }
+
+    for _, result := range results {
+        err = loadSubmissionFiles(db, result);
+        if (err != nil) {
+            return nil, err;
+        }
+    }
+
+    return results, nil;
+}
+
+// Fill in the input and output files for a result that already has its info.
+func loadSubmissionFiles(db querier, result *model.GradingResult) error {
+    info := result.Info;
+
+    rows, err := db.Query(
+        `SELECT is_input, path, contents FROM submission_files
+        WHERE course_id = ? AND assignment_id = ? AND user_email = ? AND short_id = ?`,
+        info.CourseID, info.AssignmentID, info.User, info.ShortID);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get files for submission '%s': '%w'.", info.ID, err);
+    }
+    defer rows.Close();
+
+    result.InputFilesGZip = make(map[string][]byte);
+    result.OutputFilesGZip = make(map[string][]byte);
+
+    for rows.Next() {
+        var isInput bool;
+        var path string;
+        var contents []byte;
+
+        err = rows.Scan(&isInput, &path, &contents);
+        if (err != nil) {
+            return fmt.Errorf("Failed to read file for submission '%s': '%w'.", info.ID, err);
+        }
+
+        if (isInput) {
+            result.InputFilesGZip[path] = contents;
+        } else {
+            result.OutputFilesGZip[path] = contents;
+        }
+    }
+
+    return rows.Err();
+}
+
+func parseGradingInfo(data string) (*model.GradingInfo, error) {
+    var gradingInfo model.GradingInfo;
+    err := util.JSONFromString(data, &gradingInfo);
+    if (err != nil) {
+        return nil, fmt.Errorf("Unable to deserialize grading info: '%w'.", err);
+    }
+
+    return &gradingInfo, nil;
+}
+
+// Scan the info and text output of a result (files are not loaded).
+func scanGradingResult(row scanner) (*model.GradingResult, error) {
+    var data string;
+    var result model.GradingResult;
+
+    err := row.Scan(&data, &result.Stdout, &result.Stderr);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    result.Info, err = parseGradingInfo(data);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return &result, nil;
+}
+
+// Get a string to compare user roles against.
+// An empty string means no filtering.
+func roleFilterString(filterRole model.UserRole) string {
+    if (filterRole == model.RoleUnknown) {
+        return "";
+    }
+
+    return filterRole.String();
+}
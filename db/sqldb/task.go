@@ -0,0 +1,77 @@
+package sqldb
+
+import (
+    "database/sql"
+    "fmt"
+    "time"
+)
+
+func (this *Backend) LogTaskCompletion(courseID string, taskID string, instance time.Time) error {
+    return logTaskCompletion(this.db, courseID, taskID, instance);
+}
+
+func logTaskCompletion(db querier, courseID string, taskID string, instance time.Time) error {
+    _, err := db.Exec(
+        `INSERT INTO task_completions (course_id, task_id, completed_at) VALUES (?, ?, ?)
+        ON CONFLICT (course_id, task_id) DO UPDATE SET completed_at = excluded.completed_at`,
+        courseID, taskID, instance.Format(time.RFC3339Nano));
+    if (err != nil) {
+        return fmt.Errorf("Failed to log completion of task '%s': '%w'.", taskID, err);
+    }
+
+    return nil;
+}
+
+func (this *Backend) GetLastTaskCompletion(courseID string, taskID string) (time.Time, error) {
+    return getLastTaskCompletion(this.db, courseID, taskID);
+}
+
+func getLastTaskCompletion(db querier, courseID string, taskID string) (time.Time, error) {
+    var text string;
+    err := db.QueryRow(
+        `SELECT completed_at FROM task_completions WHERE course_id = ? AND task_id = ?`,
+        courseID, taskID).Scan(&text);
+    if (err == sql.ErrNoRows) {
+        return time.Time{}, nil;
+    }
+
+    if (err != nil) {
+        return time.Time{}, fmt.Errorf("Failed to get last completion of task '%s': '%w'.", taskID, err);
+    }
+
+    instance, err := time.Parse(time.RFC3339Nano, text);
+    if (err != nil) {
+        return time.Time{}, fmt.Errorf("Failed to parse last completion of task '%s': '%w'.", taskID, err);
+    }
+
+    return instance, nil;
+}
+
+func (this *Backend) GetTaskCompletions(courseID string) (map[string]time.Time, error) {
+    rows, err := this.db.Query(`SELECT task_id, completed_at FROM task_completions WHERE course_id = ?`, courseID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get task log for course '%s': '%w'.", courseID, err);
+    }
+    defer rows.Close();
+
+    log := make(map[string]time.Time);
+
+    for rows.Next() {
+        var taskID string;
+        var text string;
+
+        err = rows.Scan(&taskID, &text);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read task log for course '%s': '%w'.", courseID, err);
+        }
+
+        instance, err := time.Parse(time.RFC3339Nano, text);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to parse task log for course '%s': '%w'.", courseID, err);
+        }
+
+        log[taskID] = instance;
+    }
+
+    return log, rows.Err();
+}
@@ -0,0 +1,95 @@
+package sqldb
+
+import (
+    "database/sql"
+    "fmt"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+const API_TOKEN_COLUMNS = "user_email, id, name, hash, creation_time, expiration_time";
+
+func (this *Backend) GetAPITokens(course *model.Course, email string) (map[string]*model.APIToken, error) {
+    rows, err := this.db.Query(`SELECT ` + API_TOKEN_COLUMNS + ` FROM api_tokens WHERE course_id = ? AND user_email = ?`,
+        course.GetID(), email);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get API tokens for '%s': '%w'.", email, err);
+    }
+    defer rows.Close();
+
+    tokens := make(map[string]*model.APIToken);
+    for rows.Next() {
+        token, err := scanAPIToken(rows);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read API tokens for '%s': '%w'.", email, err);
+        }
+
+        tokens[token.ID] = token;
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read API tokens for '%s': '%w'.", email, err);
+    }
+
+    return tokens, nil;
+}
+
+func (this *Backend) GetAPIToken(course *model.Course, email string, tokenID string) (*model.APIToken, error) {
+    row := this.db.QueryRow(`SELECT ` + API_TOKEN_COLUMNS + ` FROM api_tokens WHERE course_id = ? AND user_email = ? AND id = ?`,
+        course.GetID(), email, tokenID);
+
+    token, err := scanAPIToken(row);
+    if (err == sql.ErrNoRows) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get API token '%s': '%w'.", tokenID, err);
+    }
+
+    return token, nil;
+}
+
+func (this *Backend) SaveAPIToken(course *model.Course, token *model.APIToken) error {
+    _, err := this.db.Exec(
+        `INSERT INTO api_tokens (course_id, ` + API_TOKEN_COLUMNS + `) VALUES (?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (course_id, user_email, id) DO UPDATE SET
+            name = excluded.name,
+            hash = excluded.hash,
+            creation_time = excluded.creation_time,
+            expiration_time = excluded.expiration_time`,
+        course.GetID(), token.User, token.ID, token.Name, token.Hash, string(token.CreationTime), string(token.ExpirationTime));
+    if (err != nil) {
+        return fmt.Errorf("Failed to save API token '%s': '%w'.", token.ID, err);
+    }
+
+    return nil;
+}
+
+func (this *Backend) RemoveAPIToken(course *model.Course, email string, tokenID string) error {
+    _, err := this.db.Exec(`DELETE FROM api_tokens WHERE course_id = ? AND user_email = ? AND id = ?`,
+        course.GetID(), email, tokenID);
+    if (err != nil) {
+        return fmt.Errorf("Failed to remove API token '%s': '%w'.", tokenID, err);
+    }
+
+    return nil;
+}
+
+func scanAPIToken(row scanner) (*model.APIToken, error) {
+    var token model.APIToken;
+    var creationTime string;
+    var expirationTime string;
+
+    err := row.Scan(&token.User, &token.ID, &token.Name, &token.Hash, &creationTime, &expirationTime);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    token.CreationTime = common.Timestamp(creationTime);
+    token.ExpirationTime = common.Timestamp(expirationTime);
+
+    return &token, nil;
+}
@@ -0,0 +1,111 @@
+package sqldb
+
+import (
+    "database/sql"
+    "fmt"
+
+    "github.com/eriq-augustine/autograder/model"
+)
+
+const USER_COLUMNS = "email, name, role, pass, salt, lms_id";
+
+func (this *Backend) GetUsers(course *model.Course) (map[string]*model.User, error) {
+    rows, err := this.db.Query(`SELECT ` + USER_COLUMNS + ` FROM users WHERE course_id = ?`, course.GetID());
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get users for course '%s': '%w'.", course.GetID(), err);
+    }
+    defer rows.Close();
+
+    users := make(map[string]*model.User);
+    for rows.Next() {
+        user, err := scanUser(rows);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read users for course '%s': '%w'.", course.GetID(), err);
+        }
+
+        users[user.Email] = user;
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read users for course '%s': '%w'.", course.GetID(), err);
+    }
+
+    return users, nil;
+}
+
+func (this *Backend) GetUser(course *model.Course, email string) (*model.User, error) {
+    row := this.db.QueryRow(`SELECT ` + USER_COLUMNS + ` FROM users WHERE course_id = ? AND email = ?`,
+        course.GetID(), email);
+
+    user, err := scanUser(row);
+    if (err == sql.ErrNoRows) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get user '%s': '%w'.", email, err);
+    }
+
+    return user, nil;
+}
+
+func (this *Backend) SaveUsers(course *model.Course, users map[string]*model.User) error {
+    return this.withTx(func(tx querier) error {
+        return saveUsers(tx, course, users);
+    });
+}
+
+func saveUsers(db querier, course *model.Course, users map[string]*model.User) error {
+    for _, user := range users {
+        _, err := db.Exec(
+            `INSERT INTO users (course_id, ` + USER_COLUMNS + `) VALUES (?, ?, ?, ?, ?, ?, ?)
+            ON CONFLICT (course_id, email) DO UPDATE SET
+                name = excluded.name,
+                role = excluded.role,
+                pass = excluded.pass,
+                salt = excluded.salt,
+                lms_id = excluded.lms_id`,
+            course.GetID(), user.Email, user.Name, user.Role.String(), user.Pass, user.Salt, user.LMSID);
+        if (err != nil) {
+            return fmt.Errorf("Failed to save user '%s': '%w'.", user.Email, err);
+        }
+    }
+
+    return nil;
+}
+
+func (this *Backend) RemoveUser(course *model.Course, email string) error {
+    return this.withTx(func(tx querier) error {
+        _, err := tx.Exec(`DELETE FROM users WHERE course_id = ? AND email = ?`, course.GetID(), email);
+        if (err != nil) {
+            return fmt.Errorf("Failed to remove user '%s': '%w'.", email, err);
+        }
+
+        _, err = tx.Exec(`DELETE FROM api_tokens WHERE course_id = ? AND user_email = ?`, course.GetID(), email);
+        if (err != nil) {
+            return fmt.Errorf("Failed to remove API tokens for '%s': '%w'.", email, err);
+        }
+
+        return nil;
+    });
+}
+
+// Either a *sql.Row or *sql.Rows.
+type scanner interface {
+    Scan(dest ...any) error;
+}
+
+func scanUser(row scanner) (*model.User, error) {
+    var user model.User;
+    var role string;
+
+    err := row.Scan(&user.Email, &user.Name, &role, &user.Pass, &user.Salt, &user.LMSID);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    user.Role = model.GetRole(role);
+
+    return &user, nil;
+}
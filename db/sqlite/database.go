@@ -0,0 +1,68 @@
+// A database backend that uses a single SQLite file.
+// All data (including submission files) is stored in the database file,
+// and all writes are transactional.
+// Meant for single-host deployments.
+// The operations are shared with the other SQL backends (see the sqldb package),
+// and the schema is managed through the migrations in schema.go.
+package sqlite
+
+import (
+    "database/sql"
+    "fmt"
+    "path/filepath"
+
+    "github.com/rs/zerolog/log"
+    _ "modernc.org/sqlite"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db/sqldb"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const DB_FILENAME = "sqlite-database.db";
+
+// Connection options.
+// Foreign keys are not enforced by SQLite by default.
+const DB_PRAGMAS = "_pragma=foreign_keys(1)&_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)";
+
+var dialect sqldb.Dialect = sqldb.Dialect{
+    Name: "sqlite",
+    NumberedPlaceholders: false,
+    Migrations: migrations,
+    // SQLite transactions already lock the whole database.
+    MigrationLock: "",
+};
+
+func Open() (*sqldb.Backend, error) {
+    path := config.DB_SQLITE_PATH.Get();
+    if (path == "") {
+        path = filepath.Join(config.GetDatabaseDir(), DB_FILENAME);
+    }
+
+    path = util.ShouldAbs(path);
+
+    err := util.MkDir(filepath.Dir(path));
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to make db dir '%s': '%w'.", filepath.Dir(path), err);
+    }
+
+    db, err := sql.Open("sqlite", "file:" + path + "?" + DB_PRAGMAS);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to open SQLite database '%s': '%w'.", path, err);
+    }
+
+    // SQLite only allows a single writer,
+    // so use a single connection and let database/sql handle the queueing.
+    // This means that an operation must finish with any result set before issuing another query.
+    db.SetMaxOpenConns(1);
+
+    err = db.Ping();
+    if (err != nil) {
+        db.Close();
+        return nil, fmt.Errorf("Failed to connect to SQLite database '%s': '%w'.", path, err);
+    }
+
+    log.Debug().Str("path", path).Msg("Opened SQLite database.");
+
+    return sqldb.NewBackend(db, &dialect), nil;
+}
@@ -31,6 +31,14 @@ func GetNextSubmissionID(assignment *model.Assignment, email string) (string, er
     return backend.GetNextSubmissionID(assignment, email);
 }
 
+func GetSubmissionUsers(assignment *model.Assignment) ([]string, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetSubmissionUsers(assignment);
+}
+
 func GetSubmissionHistory(assignment *model.Assignment, email string) ([]*model.SubmissionHistoryItem, error) {
     if (backend == nil) {
         return nil, fmt.Errorf("Database has not been opened.");
@@ -59,3 +59,55 @@ func (this *DBTests) DBTestRemoveSubmission(test *testing.T) {
         }
     }
 }
+
+// Saving a submission with an existing ID replaces the old submission (including its files).
+func (this *DBTests) DBTestResaveSubmission(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    assignment := MustGetTestAssignment();
+    email := "student@test.com";
+    shortID := "1697406272";
+
+    submission, err := GetSubmissionContents(assignment, email, shortID);
+    if (err != nil) {
+        test.Fatalf("Failed to get submission: '%v'.", err);
+    }
+
+    if (submission == nil) {
+        test.Fatalf("Could not find submission.");
+    }
+
+    // Replace all the output files with a single (new) file.
+    var contents []byte = nil;
+    for _, data := range submission.InputFilesGZip {
+        contents = data;
+        break;
+    }
+
+    submission.Info.Score = 0;
+    submission.OutputFilesGZip = map[string][]byte{"new.txt": contents};
+
+    err = SaveSubmission(assignment, submission);
+    if (err != nil) {
+        test.Fatalf("Failed to resave submission: '%v'.", err);
+    }
+
+    history, err := GetSubmissionHistory(assignment, email);
+    if (err != nil) {
+        test.Fatalf("Failed to get submission history: '%v'.", err);
+    }
+
+    if (len(history) != 3) {
+        test.Fatalf("Unexpected history length after resave. Expected: 3, Actual: %d.", len(history));
+    }
+
+    newSubmission, err := GetSubmissionContents(assignment, email, shortID);
+    if (err != nil) {
+        test.Fatalf("Failed to get resaved submission: '%v'.", err);
+    }
+
+    if ((newSubmission.Info.Score != 0) || (len(newSubmission.OutputFilesGZip) != 1) || (newSubmission.OutputFilesGZip["new.txt"] == nil)) {
+        test.Fatalf("Unexpected resaved submission: '%+v'.", newSubmission.Info);
+    }
+}
@@ -20,3 +20,11 @@ func GetLastTaskCompletion(courseID string, taskID string) (time.Time, error) {
 
     return backend.GetLastTaskCompletion(courseID, taskID);
 }
+
+func GetTaskCompletions(courseID string) (map[string]time.Time, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetTaskCompletions(courseID);
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+    "fmt"
+
+    "github.com/eriq-augustine/autograder/model"
+)
+
+func GetAPITokens(course *model.Course, email string) (map[string]*model.APIToken, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetAPITokens(course, email);
+}
+
+// Returns nil if no matching token exists.
+func GetAPIToken(course *model.Course, email string, tokenID string) (*model.APIToken, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetAPIToken(course, email, tokenID);
+}
+
+func SaveAPIToken(course *model.Course, token *model.APIToken) error {
+    if (backend == nil) {
+        return fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.SaveAPIToken(course, token);
+}
+
+// Remove (revoke) an API token.
+// Returns a boolean indicating if the token exists.
+// If true, then the token exists and was removed.
+// If false (and the error is nil), then the token did not exist.
+func RemoveAPIToken(course *model.Course, email string, tokenID string) (bool, error) {
+    if (backend == nil) {
+        return false, fmt.Errorf("Database has not been opened.");
+    }
+
+    token, err := GetAPIToken(course, email, tokenID);
+    if (err != nil) {
+        return false, err;
+    }
+
+    if (token == nil) {
+        return false, nil;
+    }
+
+    return true, backend.RemoveAPIToken(course, email, tokenID);
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/model"
+)
+
+func (this *DBTests) DBTestAPITokens(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    course := MustGetTestCourse();
+    email := "student@test.com";
+
+    token, cleartext, err := model.NewAPIToken(email, "test", time.Hour);
+    if (err != nil) {
+        test.Fatalf("Failed to create token: '%v'.", err);
+    }
+
+    err = SaveAPIToken(course, token);
+    if (err != nil) {
+        test.Fatalf("Failed to save token: '%v'.", err);
+    }
+
+    tokens, err := GetAPITokens(course, email);
+    if (err != nil) {
+        test.Fatalf("Failed to get tokens: '%v'.", err);
+    }
+
+    if ((len(tokens) != 1) || (tokens[token.ID] == nil) || (*tokens[token.ID] != *token)) {
+        test.Fatalf("Unexpected tokens. Expected: '%+v', Actual: '%+v'.", token, tokens);
+    }
+
+    dbToken, err := GetAPIToken(course, email, token.ID);
+    if (err != nil) {
+        test.Fatalf("Failed to get token: '%v'.", err);
+    }
+
+    if ((dbToken == nil) || !dbToken.Check(cleartext)) {
+        test.Fatalf("Token does not match its cleartext: '%+v'.", dbToken);
+    }
+
+    // Tokens belong to a single user.
+    dbToken, err = GetAPIToken(course, "grader@test.com", token.ID);
+    if (err != nil) {
+        test.Fatalf("Failed to get other user's token: '%v'.", err);
+    }
+
+    if (dbToken != nil) {
+        test.Fatalf("Found a token for the wrong user: '%+v'.", dbToken);
+    }
+
+    exists, err := RemoveAPIToken(course, email, token.ID);
+    if (err != nil) {
+        test.Fatalf("Failed to remove token: '%v'.", err);
+    }
+
+    if (!exists) {
+        test.Fatalf("Removed token did not exist.");
+    }
+
+    exists, err = RemoveAPIToken(course, email, token.ID);
+    if (err != nil) {
+        test.Fatalf("Failed to remove missing token: '%v'.", err);
+    }
+
+    if (exists) {
+        test.Fatalf("Token exists after being removed.");
+    }
+
+    // Removing a user removes their tokens.
+    err = SaveAPIToken(course, token);
+    if (err != nil) {
+        test.Fatalf("Failed to re-save token: '%v'.", err);
+    }
+
+    _, err = RemoveUser(course, email);
+    if (err != nil) {
+        test.Fatalf("Failed to remove user: '%v'.", err);
+    }
+
+    tokens, err = GetAPITokens(course, email);
+    if (err != nil) {
+        test.Fatalf("Failed to get tokens after removing user: '%v'.", err);
+    }
+
+    if (len(tokens) != 0) {
+        test.Fatalf("User still has tokens after being removed: '%+v'.", tokens);
+    }
+}
@@ -69,11 +69,11 @@ func BuildImageWithOptions(imageInfo *ImageInfo, options *BuildOptions) error {
         return fmt.Errorf("Failed to create tar build context for image '%s': '%w'.", imageInfo.Name, err);
     }
 
-    return buildImage(buildOptions, tar);
+    return buildImage(buildOptions, tar, imageInfo.APIHost());
 }
 
-func buildImage(buildOptions types.ImageBuildOptions, tar io.ReadCloser) error {
-	ctx, docker, err := getDockerClient();
+func buildImage(buildOptions types.ImageBuildOptions, tar io.ReadCloser, host string) error {
+	ctx, docker, err := getDockerClient(host);
     if (err != nil) {
         return err;
     }
@@ -5,11 +5,13 @@ import (
     "fmt"
     "path/filepath"
     "sync"
+    "time"
 
     "github.com/rs/zerolog/log"
 
     "github.com/eriq-augustine/autograder/common"
     "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/metrics"
     "github.com/eriq-augustine/autograder/util"
 )
 
@@ -29,7 +31,7 @@ func BuildImageFromSourceQuick(imageSource ImageSource) error {
 }
 
 func BuildImageFromSource(imageSource ImageSource, force bool, quick bool, options *BuildOptions) error {
-    if (config.DOCKER_DISABLE.Get()) {
+    if (config.DOCKER_DISABLE.Get() || !imageSource.GetImageInfo().UsesContainer()) {
         return nil;
     }
 
@@ -51,7 +53,9 @@ func BuildImageFromSource(imageSource ImageSource, force bool, quick bool, optio
         return nil;
     }
 
+    startTime := time.Now();
     buildErr := BuildImageWithOptions(imageSource.GetImageInfo(), options);
+    metrics.ObserveDockerImageBuild(imageSource.GetImageInfo().Name, buildErr, time.Since(startTime));
 
     // Always try to store the result of cache building.
     _, _, cacheErr := util.CachePut(imageSource.GetCachePath(), CACHE_KEY_BUILD_SUCCESS, (buildErr == nil));
@@ -59,6 +63,25 @@ func BuildImageFromSource(imageSource ImageSource, force bool, quick bool, optio
     return errors.Join(buildErr, cacheErr);
 }
 
+// Check if the most recent build of an image succeeded.
+// Images that do not use a container are always considered built.
+func IsImageBuilt(imageSource ImageSource) (bool, error) {
+    if (!imageSource.GetImageInfo().UsesContainer()) {
+        return true, nil;
+    }
+
+    lastBuildSuccess, exists, err := util.CacheFetch(imageSource.GetCachePath(), CACHE_KEY_BUILD_SUCCESS);
+    if (err != nil) {
+        return false, fmt.Errorf("Failed to fetch the last build status from cache for image source '%s': '%w'.", imageSource.FullID(), err);
+    }
+
+    if (!exists) {
+        return false, nil;
+    }
+
+    return lastBuildSuccess.(bool), nil;
+}
+
 func NeedImageRebuild(imageSource ImageSource, quick bool) (bool, error) {
     // Check if the last build failed.
     lastBuildSuccess, exists, err := util.CacheFetch(imageSource.GetCachePath(), CACHE_KEY_BUILD_SUCCESS);
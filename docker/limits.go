@@ -0,0 +1,188 @@
+package docker
+
+import (
+    "fmt"
+    "sync"
+
+    "github.com/eriq-augustine/autograder/config"
+)
+
+// The reasons a grading container can be killed.
+const (
+    LIMIT_REASON_TIMEOUT = "timeout"
+    LIMIT_REASON_OOM = "oom"
+    LIMIT_REASON_OUTPUT = "output"
+)
+
+// Resource limits placed on a grading container.
+// A zero value means there is no limit (beyond what docker imposes).
+// A zero timeout or output limit falls back to the server's defaults when grading (see WithDefaults()).
+type ResourceLimits struct {
+    TimeoutSecs int `json:"timeout-secs,omitempty"`
+    MemoryMB int `json:"memory-mb,omitempty"`
+    CPUs float64 `json:"cpus,omitempty"`
+    PIDs int `json:"pids,omitempty"`
+    // The max size of stdout and stderr (combined).
+    MaxOutputKB int `json:"max-output-kb,omitempty"`
+}
+
+// Returned when a grading process was killed for going over one of its limits.
+type LimitError struct {
+    Reason string
+    Message string
+}
+
+func (this *LimitError) Error() string {
+    return this.Message;
+}
+
+func NewTimeoutError(limits *ResourceLimits) *LimitError {
+    return &LimitError{
+        Reason: LIMIT_REASON_TIMEOUT,
+        Message: fmt.Sprintf("Grading timed out after %d seconds.", limits.TimeoutSecs),
+    };
+}
+
+func NewOOMError(limits *ResourceLimits) *LimitError {
+    return &LimitError{
+        Reason: LIMIT_REASON_OOM,
+        Message: fmt.Sprintf("Grading ran out of memory (limit: %d MB).", limits.MemoryMB),
+    };
+}
+
+func NewOutputError(limits *ResourceLimits) *LimitError {
+    return &LimitError{
+        Reason: LIMIT_REASON_OUTPUT,
+        Message: fmt.Sprintf("Grading produced too much output (limit: %d KB).", limits.MaxOutputKB),
+    };
+}
+
+// A note added to stderr when a grader finished normally, but its output went over the limit (and was cut off).
+func NewOutputTruncatedNotice(limits *ResourceLimits) string {
+    return fmt.Sprintf("\n[autograder] Output was truncated (limit: %d KB).\n", limits.MaxOutputKB);
+}
+
+func (this *ResourceLimits) MaxOutputBytes() int {
+    return this.MaxOutputKB * 1024;
+}
+
+// Get a copy of these limits with any unset timeout or output limit filled in from the config.
+// Nil limits are treated as empty.
+func (this *ResourceLimits) WithDefaults() *ResourceLimits {
+    limits := ResourceLimits{};
+    if (this != nil) {
+        limits = *this;
+    }
+
+    if (limits.TimeoutSecs == 0) {
+        limits.TimeoutSecs = max(0, config.DOCKER_LIMITS_TIMEOUT_SECS.Get());
+    }
+
+    if (limits.MaxOutputKB == 0) {
+        limits.MaxOutputKB = max(0, config.DOCKER_LIMITS_MAX_OUTPUT_KB.Get());
+    }
+
+    return &limits;
+}
+
+func (this *ResourceLimits) Validate() error {
+    if (this.TimeoutSecs < 0) {
+        return fmt.Errorf("Timeout cannot be negative, found: %d.", this.TimeoutSecs);
+    }
+
+    if (this.MemoryMB < 0) {
+        return fmt.Errorf("Memory limit cannot be negative, found: %d.", this.MemoryMB);
+    }
+
+    if (this.CPUs < 0) {
+        return fmt.Errorf("CPU limit cannot be negative, found: %f.", this.CPUs);
+    }
+
+    if (this.PIDs < 0) {
+        return fmt.Errorf("PID limit cannot be negative, found: %d.", this.PIDs);
+    }
+
+    if (this.MaxOutputKB < 0) {
+        return fmt.Errorf("Output limit cannot be negative, found: %d.", this.MaxOutputKB);
+    }
+
+    return nil;
+}
+
+// Collects output up to a limit (no limit if the max is not positive).
+// Once the limit is passed, further writes will fail and the Full() channel will be closed.
+// Multiple writers (e.g. stdout and stderr) can share the same limit.
+type CappedOutput struct {
+    lock sync.Mutex
+    maxBytes int
+    size int
+    exceeded bool
+    full chan struct{}
+}
+
+type CappedWriter struct {
+    output *CappedOutput
+    buffer []byte
+}
+
+func NewCappedOutput(maxBytes int) *CappedOutput {
+    return &CappedOutput{
+        maxBytes: maxBytes,
+        full: make(chan struct{}),
+    };
+}
+
+func (this *CappedOutput) NewWriter() *CappedWriter {
+    return &CappedWriter{
+        output: this,
+        buffer: make([]byte, 0),
+    };
+}
+
+// Closed once the limit has been passed.
+func (this *CappedOutput) Full() <-chan struct{} {
+    return this.full;
+}
+
+// Has any output been cut off?
+// Output can go over the limit just before a process exits on its own,
+// so this should always be checked once all the output has been collected.
+func (this *CappedOutput) Truncated() bool {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    return this.exceeded;
+}
+
+func (this *CappedWriter) Write(data []byte) (int, error) {
+    output := this.output;
+
+    output.lock.Lock();
+    defer output.lock.Unlock();
+
+    length := len(data);
+    if ((output.maxBytes > 0) && ((output.size + length) > output.maxBytes)) {
+        data = data[0:(output.maxBytes - output.size)];
+    }
+
+    this.buffer = append(this.buffer, data...);
+    output.size += len(data);
+
+    if (len(data) < length) {
+        if (!output.exceeded) {
+            output.exceeded = true;
+            close(output.full);
+        }
+
+        return len(data), fmt.Errorf("Output limit reached.");
+    }
+
+    return length, nil;
+}
+
+func (this *CappedWriter) String() string {
+    this.output.lock.Lock();
+    defer this.output.lock.Unlock();
+
+    return string(this.buffer);
+}
@@ -0,0 +1,135 @@
+package docker
+
+import (
+    "testing"
+
+    "github.com/eriq-augustine/autograder/config"
+)
+
+func TestResourceLimitsValidate(test *testing.T) {
+    testCases := []struct{limits ResourceLimits; valid bool; expected ResourceLimits}{
+        {ResourceLimits{}, true, ResourceLimits{}},
+        {
+            ResourceLimits{TimeoutSecs: 10, MemoryMB: 256, CPUs: 0.5, PIDs: 64, MaxOutputKB: 4},
+            true,
+            ResourceLimits{TimeoutSecs: 10, MemoryMB: 256, CPUs: 0.5, PIDs: 64, MaxOutputKB: 4},
+        },
+        {ResourceLimits{TimeoutSecs: -1}, false, ResourceLimits{}},
+        {ResourceLimits{MemoryMB: -1}, false, ResourceLimits{}},
+        {ResourceLimits{CPUs: -0.5}, false, ResourceLimits{}},
+        {ResourceLimits{PIDs: -1}, false, ResourceLimits{}},
+        {ResourceLimits{MaxOutputKB: -1}, false, ResourceLimits{}},
+    };
+
+    for i, testCase := range testCases {
+        err := testCase.limits.Validate();
+        if (testCase.valid != (err == nil)) {
+            test.Errorf("Case %d: Unexpected validation result. Expected valid: %v, Error: '%v'.", i, testCase.valid, err);
+            continue;
+        }
+
+        if (testCase.valid && (testCase.limits != testCase.expected)) {
+            test.Errorf("Case %d: Unexpected limits. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, testCase.limits);
+        }
+    }
+}
+
+func TestResourceLimitsWithDefaults(test *testing.T) {
+    oldTimeout := config.DOCKER_LIMITS_TIMEOUT_SECS.Get();
+    oldMaxOutput := config.DOCKER_LIMITS_MAX_OUTPUT_KB.Get();
+    defer config.DOCKER_LIMITS_TIMEOUT_SECS.Set(oldTimeout);
+    defer config.DOCKER_LIMITS_MAX_OUTPUT_KB.Set(oldMaxOutput);
+
+    testCases := []struct{limits *ResourceLimits; defaultTimeout int; defaultMaxOutput int; expected ResourceLimits}{
+        {nil, 0, 0, ResourceLimits{}},
+        {&ResourceLimits{}, 0, 0, ResourceLimits{}},
+        {nil, 60, 512, ResourceLimits{TimeoutSecs: 60, MaxOutputKB: 512}},
+        {&ResourceLimits{MemoryMB: 256}, 60, 512, ResourceLimits{TimeoutSecs: 60, MemoryMB: 256, MaxOutputKB: 512}},
+        {&ResourceLimits{TimeoutSecs: 10, MaxOutputKB: 4}, 60, 512, ResourceLimits{TimeoutSecs: 10, MaxOutputKB: 4}},
+    };
+
+    for i, testCase := range testCases {
+        config.DOCKER_LIMITS_TIMEOUT_SECS.Set(testCase.defaultTimeout);
+        config.DOCKER_LIMITS_MAX_OUTPUT_KB.Set(testCase.defaultMaxOutput);
+
+        var original ResourceLimits;
+        if (testCase.limits != nil) {
+            original = *testCase.limits;
+        }
+
+        actual := testCase.limits.WithDefaults();
+        if (*actual != testCase.expected) {
+            test.Errorf("Case %d: Unexpected limits. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, *actual);
+            continue;
+        }
+
+        if ((testCase.limits != nil) && (*testCase.limits != original)) {
+            test.Errorf("Case %d: Original limits were modified. Expected: '%+v', Actual: '%+v'.", i, original, *testCase.limits);
+        }
+    }
+}
+
+func TestCappedOutputUnlimited(test *testing.T) {
+    output := NewCappedOutput(0);
+    stdout := output.NewWriter();
+
+    _, err := stdout.Write([]byte("1234567890"));
+    if (err != nil) {
+        test.Fatalf("Failed to write with no limit: '%v'.", err);
+    }
+
+    select {
+        case <-output.Full():
+            test.Fatalf("Output with no limit was marked full.");
+        default:
+    }
+
+    if (stdout.String() != "1234567890") {
+        test.Fatalf("Unexpected output. Stdout: '%s'.", stdout.String());
+    }
+}
+
+func TestCappedOutput(test *testing.T) {
+    output := NewCappedOutput(10);
+    stdout := output.NewWriter();
+    stderr := output.NewWriter();
+
+    _, err := stdout.Write([]byte("12345"));
+    if (err != nil) {
+        test.Fatalf("Failed to write under the limit: '%v'.", err);
+    }
+
+    _, err = stderr.Write([]byte("abcde"));
+    if (err != nil) {
+        test.Fatalf("Failed to write up to the limit: '%v'.", err);
+    }
+
+    select {
+        case <-output.Full():
+            test.Fatalf("Output was marked full without going over the limit.");
+        default:
+    }
+
+    if (output.Truncated()) {
+        test.Fatalf("Output was marked truncated without going over the limit.");
+    }
+
+    count, err := stdout.Write([]byte("678"));
+    if ((err == nil) || (count != 0)) {
+        test.Fatalf("Write over the limit did not fail. Count: %d, Error: '%v'.", count, err);
+    }
+
+    select {
+        case <-output.Full():
+        default:
+            test.Fatalf("Output was not marked full after going over the limit.");
+    }
+
+    if (!output.Truncated()) {
+        test.Fatalf("Output was not marked truncated after going over the limit.");
+    }
+
+    if ((stdout.String() != "12345") || (stderr.String() != "abcde")) {
+        test.Fatalf("Unexpected output. Stdout: '%s', Stderr: '%s'.", stdout.String(), stderr.String());
+    }
+}
@@ -2,6 +2,9 @@ package docker
 
 import (
     "fmt"
+    "slices"
+    "strings"
+    "sync"
 
     "github.com/eriq-augustine/autograder/common"
 )
@@ -10,6 +13,29 @@ const (
     DEFAULT_IMAGE = "eriqaugustine/autograder.base"
 )
 
+// Grading runtimes that build and run images through the docker API.
+const (
+    RUNTIME_DOCKER = "docker"
+    RUNTIME_PODMAN = "podman"
+)
+
+// Grading runtimes that do not use the docker API.
+const (
+    RUNTIME_LOCAL = "local"
+    RUNTIME_MOCK = "mock"
+)
+
+// The names of all the grading runtimes that an image can use.
+// The runtimes themselves live in the grader package (which registers any additional names here),
+// but the names are kept here so images can be validated without the grader.
+var runtimeNamesLock sync.RWMutex;
+var runtimeNames map[string]bool = map[string]bool{
+    RUNTIME_DOCKER: true,
+    RUNTIME_PODMAN: true,
+    RUNTIME_LOCAL: true,
+    RUNTIME_MOCK: true,
+};
+
 type ImageInfo struct {
     Image string `json:"image,omitempty"`
     PreStaticDockerCommands []string `json:"pre-static-docker-commands,omitempty"`
@@ -17,6 +43,11 @@ type ImageInfo struct {
 
     Invocation []string `json:"invocation,omitempty"`
 
+    Limits *ResourceLimits `json:"limits,omitempty"`
+
+    // The name of the grading runtime used to run this image (defaults to RUNTIME_DOCKER).
+    Runtime string `json:"runtime,omitempty"`
+
     StaticFiles []*common.FileSpec `json:"static-files,omitempty"`
 
     PreStaticFileOperations []common.FileOperation `json:"pre-static-files-ops,omitempty"`
@@ -44,6 +75,47 @@ func (this *ImageInfo) GetGradingConfig() *GradingConfig {
     };
 }
 
+func RegisterRuntimeName(name string) {
+    runtimeNamesLock.Lock();
+    defer runtimeNamesLock.Unlock();
+
+    runtimeNames[name] = true;
+}
+
+func IsRuntimeName(name string) bool {
+    runtimeNamesLock.RLock();
+    defer runtimeNamesLock.RUnlock();
+
+    return runtimeNames[name];
+}
+
+func GetRuntimeNames() []string {
+    runtimeNamesLock.RLock();
+    defer runtimeNamesLock.RUnlock();
+
+    names := make([]string, 0, len(runtimeNames));
+    for name, _ := range runtimeNames {
+        names = append(names, name);
+    }
+
+    slices.Sort(names);
+    return names;
+}
+
+// Does this image get built and run through the docker API (docker or podman)?
+func (this *ImageInfo) UsesContainer() bool {
+    return ((this.Runtime == "") || (this.Runtime == RUNTIME_DOCKER) || (this.Runtime == RUNTIME_PODMAN));
+}
+
+// The docker API host to use for this image (an empty string means the default docker host).
+func (this *ImageInfo) APIHost() string {
+    if (this.Runtime == RUNTIME_PODMAN) {
+        return PodmanHost();
+    }
+
+    return "";
+}
+
 func (this *ImageInfo) Validate() error {
     if (this.Name == "") {
         return fmt.Errorf("Missing name.");
@@ -65,6 +137,19 @@ func (this *ImageInfo) Validate() error {
         this.Image = DEFAULT_IMAGE;
     }
 
+    if ((this.Runtime != "") && !IsRuntimeName(this.Runtime)) {
+        return fmt.Errorf("Unknown grading runtime '%s', known runtimes: ['%s'].", this.Runtime, strings.Join(GetRuntimeNames(), "', '"));
+    }
+
+    if (this.Limits == nil) {
+        this.Limits = &ResourceLimits{};
+    }
+
+    err := this.Limits.Validate();
+    if (err != nil) {
+        return fmt.Errorf("Failed to validate limits: '%w'.", err);
+    }
+
     if (this.PreStaticDockerCommands == nil) {
         this.PreStaticDockerCommands = make([]string, 0);
     }
@@ -78,7 +163,7 @@ func (this *ImageInfo) Validate() error {
     }
 
     for _, staticFile := range this.StaticFiles {
-        err := staticFile.Validate();
+        err = staticFile.Validate();
         if (err != nil) {
             return fmt.Errorf("Failed to validate static file spec: '%w'.", err);
         }
@@ -92,7 +177,7 @@ func (this *ImageInfo) Validate() error {
         this.PreStaticFileOperations = make([]common.FileOperation, 0);
     }
 
-    err := common.ValidateFileOperations(this.PreStaticFileOperations);
+    err = common.ValidateFileOperations(this.PreStaticFileOperations);
     if (err != nil) {
         return fmt.Errorf("Failed to validate pre-static file operations: '%w'.", err);
     }
@@ -34,3 +34,32 @@ func TestImageInfoStruct(test *testing.T) {
         util.MustToJSON(testCase);
     }
 }
+
+func TestImageInfoValidateRuntime(test *testing.T) {
+    RegisterRuntimeName("test-runtime");
+
+    testCases := []struct{runtime string; valid bool}{
+        {"", true},
+        {RUNTIME_DOCKER, true},
+        {RUNTIME_PODMAN, true},
+        {RUNTIME_LOCAL, true},
+        {RUNTIME_MOCK, true},
+        {"test-runtime", true},
+        {"ZZZ", false},
+        {"Docker", false},
+    };
+
+    for i, testCase := range testCases {
+        imageInfo := &ImageInfo{
+            Image: "foo",
+            Runtime: testCase.runtime,
+            Name: "foo",
+            BaseDir: "bar",
+        };
+
+        err := imageInfo.Validate();
+        if (testCase.valid != (err == nil)) {
+            test.Errorf("Case %d: Unexpected validation result for runtime '%s'. Expected valid: %v, Error: '%v'.", i, testCase.runtime, testCase.valid, err);
+        }
+    }
+}
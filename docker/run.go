@@ -2,8 +2,9 @@ package docker
 
 import (
     "fmt"
+    "io"
     "regexp"
-    "strings"
+    "time"
 
     "github.com/docker/docker/api/types"
     "github.com/docker/docker/api/types/container"
@@ -14,18 +15,43 @@ import (
     "github.com/eriq-augustine/autograder/util"
 )
 
-func RunContainer(imageName string, inputDir string, outputDir string, gradingID string) (string, string, error) {
-    ctx, docker, err := getDockerClient();
+// Run a grading container for an image.
+// The container will be killed if it goes over any of the image's limits,
+// in which case a *LimitError will be returned (along with any output that was collected).
+// If a stream is given, stdout will also be written to it as it is produced.
+func RunContainer(imageInfo *ImageInfo, inputDir string, outputDir string, gradingID string, stream io.Writer) (string, string, error) {
+    ctx, docker, err := getDockerClient(imageInfo.APIHost());
     if (err != nil) {
         return "", "", err;
     }
     defer docker.Close()
 
+    imageName := imageInfo.Name;
+
+    limits := imageInfo.Limits.WithDefaults();
+
     inputDir = util.ShouldAbs(inputDir);
     outputDir = util.ShouldAbs(outputDir);
 
     name := cleanContainerName(fmt.Sprintf("%s-%s", gradingID, util.UUID()));
 
+    resources := container.Resources{};
+
+    if (limits.MemoryMB > 0) {
+        resources.Memory = int64(limits.MemoryMB) * 1024 * 1024;
+        // Do not allow swap.
+        resources.MemorySwap = resources.Memory;
+    }
+
+    if (limits.CPUs > 0) {
+        resources.NanoCPUs = int64(limits.CPUs * 1e9);
+    }
+
+    if (limits.PIDs > 0) {
+        pids := int64(limits.PIDs);
+        resources.PidsLimit = &pids;
+    }
+
     containerInstance, err := docker.ContainerCreate(
         ctx,
         &container.Config{
@@ -34,7 +60,9 @@ func RunContainer(imageName string, inputDir string, outputDir string, gradingID
             NetworkDisabled: true,
         },
         &container.HostConfig{
-            AutoRemove: true,
+            // The container is removed manually so it can be inspected after it stops.
+            AutoRemove: false,
+            Resources: resources,
             Mounts: []mount.Mount{
                 mount.Mount{
                     Type: "bind",
@@ -58,6 +86,13 @@ func RunContainer(imageName string, inputDir string, outputDir string, gradingID
         return "", "", fmt.Errorf("Failed to create container '%s': '%w'.", name, err);
     }
 
+    defer func() {
+        err := docker.ContainerRemove(ctx, containerInstance.ID, types.ContainerRemoveOptions{Force: true});
+        if (err != nil) {
+            log.Warn().Err(err).Str("container-name", name).Str("container-id", containerInstance.ID).Msg("Failed to remove container.");
+        }
+    }();
+
     err = docker.ContainerStart(ctx, containerInstance.ID, types.ContainerStartOptions{});
     if (err != nil) {
         return "", "", fmt.Errorf("Failed to start container '%s' (%s): '%w'.", name, containerInstance.ID, err);
@@ -74,7 +109,40 @@ func RunContainer(imageName string, inputDir string, outputDir string, gradingID
         log.Warn().Err(err).Str("container-name", name).Str("container-id", containerInstance.ID).Msg("Failed to get output from container (but run did not throw an error).");
         out = nil;
     }
-    defer out.Close()
+
+    // Read the output while the container runs so it can be killed if it writes too much.
+    output := NewCappedOutput(limits.MaxOutputBytes());
+    outBuffer := output.NewWriter();
+    errBuffer := output.NewWriter();
+    copyDone := make(chan struct{});
+
+    if (out != nil) {
+        defer out.Close();
+
+        go func() {
+            defer close(copyDone);
+
+            var outWriter io.Writer = outBuffer;
+            if (stream != nil) {
+                outWriter = io.MultiWriter(outBuffer, stream);
+            }
+
+            stdcopy.StdCopy(outWriter, errBuffer, out);
+        }();
+    } else {
+        close(copyDone);
+    }
+
+    // A nil channel never fires, so there is no timeout.
+    var timeoutChan <-chan time.Time = nil;
+    if (limits.TimeoutSecs > 0) {
+        timeout := time.NewTimer(time.Duration(limits.TimeoutSecs) * time.Second);
+        defer timeout.Stop();
+
+        timeoutChan = timeout.C;
+    }
+
+    var limitErr *LimitError = nil;
 
     statusChan, errorChan := docker.ContainerWait(ctx, containerInstance.ID, container.WaitConditionNotRunning);
     select {
@@ -84,22 +152,43 @@ func RunContainer(imageName string, inputDir string, outputDir string, gradingID
             }
         case <-statusChan:
             // Waiting is complete.
+        case <-timeoutChan:
+            limitErr = NewTimeoutError(limits);
+        case <-output.Full():
+            limitErr = NewOutputError(limits);
+    }
+
+    if (limitErr != nil) {
+        log.Debug().Str("container-name", name).Str("container-id", containerInstance.ID).Str("reason", limitErr.Reason).Msg("Killing container.");
+
+        err = docker.ContainerKill(ctx, containerInstance.ID, "KILL");
+        if (err != nil) {
+            log.Warn().Err(err).Str("container-name", name).Str("container-id", containerInstance.ID).Msg("Failed to kill container.");
+        }
+    } else {
+        info, err := docker.ContainerInspect(ctx, containerInstance.ID);
+        if (err != nil) {
+            log.Warn().Err(err).Str("container-name", name).Str("container-id", containerInstance.ID).Msg("Failed to inspect container.");
+        } else if ((info.State != nil) && (info.State.OOMKilled)) {
+            limitErr = NewOOMError(limits);
+        }
     }
 
-    stdout := "";
-    stderr := "";
+    // The log stream ends once the container stops.
+    <-copyDone;
 
-    // Read the output after the container is done.
-    if (out != nil) {
-        outBuffer := new(strings.Builder);
-        errBuffer := new(strings.Builder);
+    stdout := outBuffer.String();
+    stderr := errBuffer.String();
 
-        stdcopy.StdCopy(outBuffer, errBuffer, out);
+    if ((limitErr == nil) && output.Truncated()) {
+        log.Warn().Str("container-name", name).Str("container-id", containerInstance.ID).Int("max-output-kb", limits.MaxOutputKB).Msg("Container output was truncated.");
+        stderr += NewOutputTruncatedNotice(limits);
+    }
 
-        stdout = outBuffer.String();
-        stderr = errBuffer.String();
+    log.Debug().Str("container-name", name).Str("container-id", containerInstance.ID).Str("stdout", stdout).Str("stderr", stderr).Msg("Container output.");
 
-        log.Debug().Str("container-name", name).Str("container-id", containerInstance.ID).Str("stdout", stdout).Str("stderr", stderr).Msg("Container output.");
+    if (limitErr != nil) {
+        return stdout, stderr, limitErr;
     }
 
     return stdout, stderr, nil;
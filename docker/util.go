@@ -3,12 +3,16 @@ package docker
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/docker/docker/client"
+
+	"github.com/eriq-augustine/autograder/config"
 )
 
 func CanAccessDocker() bool {
-    _, docker, err := getDockerClient();
+    _, docker, err := getDockerClient("");
     if (docker != nil) {
         defer docker.Close();
     }
@@ -16,9 +20,32 @@ func CanAccessDocker() bool {
     return (err == nil);
 }
 
-func getDockerClient() (context.Context, *client.Client, error) {
+// Get the API host for podman's docker-compatible socket.
+func PodmanHost() string {
+    host := config.PODMAN_HOST.Get();
+    if (host != "") {
+        return host;
+    }
+
+    runtimeDir := os.Getenv("XDG_RUNTIME_DIR");
+    if (runtimeDir == "") {
+        runtimeDir = fmt.Sprintf("/run/user/%d", os.Getuid());
+    }
+
+    return "unix://" + filepath.Join(runtimeDir, "podman", "podman.sock");
+}
+
+// Get a client for the docker API.
+// An empty host will use the standard docker environment (e.g. DOCKER_HOST).
+func getDockerClient(host string) (context.Context, *client.Client, error) {
 	ctx := context.Background()
-	docker, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+
+	options := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()};
+	if (host != "") {
+		options = append(options, client.WithHost(host));
+	}
+
+	docker, err := client.NewClientWithOpts(options...)
 	if err != nil {
 		return ctx, nil, fmt.Errorf("Cannot create Docker client: '%w'.", err);
 	}
@@ -2,11 +2,8 @@ package grader
 
 import (
     "fmt"
-    "os"
     "path/filepath"
 
-    "github.com/rs/zerolog/log"
-
     "github.com/eriq-augustine/autograder/common"
     "github.com/eriq-augustine/autograder/docker"
     "github.com/eriq-augustine/autograder/model"
@@ -15,49 +12,35 @@ import (
 
 // Grade using a docker container.
 // Directory information:
-//  - input -- A temp dir that will be mounted at DOCKER_INPUT_DIR (read-only).
-//  - output -- Passed in directory that will be mounted at DOCKER_OUTPUT_DIR.
+//  - input -- Will be mounted at DOCKER_INPUT_DIR (read-only).
+//  - output -- Will be mounted at DOCKER_OUTPUT_DIR.
 //  - work -- Should already be created inside the docker image, will only exist within the container.
-func runDockerGrader(assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (
-        *model.GradingInfo, map[string][]byte, string, string, error) {
-    tempDir, inputDir, outputDir, _, err := common.PrepTempGradingDir("docker");
-    if (err != nil) {
-        return nil, nil, "", "", err;
-    }
+type dockerRuntime struct {}
 
-    if (!options.LeaveTempDir) {
-        defer os.RemoveAll(tempDir);
-    } else {
-        log.Info().Str("path", tempDir).Msg("Leaving behind temp grading dir.");
-    }
-
-    // Copy over submission files to the temp input dir.
-    err = util.CopyDirent(submissionPath, inputDir, true);
+func (this *dockerRuntime) Prepare(assignment *model.Assignment) error {
+    // Ensure the assignment docker image is built.
+    err := docker.BuildImageFromSourceQuick(assignment);
     if (err != nil) {
-        return nil, nil, "", "", fmt.Errorf("Failed to copy over submission/input contents: '%w'.", err);
+        return fmt.Errorf("Failed to build assignment assignment '%s' docker image: '%w'.", assignment.FullID(), err);
     }
 
-    stdout, stderr, err := docker.RunContainer(assignment.ImageName(), inputDir, outputDir, fullSubmissionID);
-    if (err != nil) {
-        return nil, nil, stdout, stderr, err;
-    }
+    return nil;
+}
 
-    resultPath := filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME);
-    if (!util.PathExists(resultPath)) {
-        return nil, nil, stdout, stderr,
-                fmt.Errorf("Cannot find output file ('%s') after the grading container (%s) was run.", resultPath, assignment.ImageName());
-    }
+func (this *dockerRuntime) Run(assignment *model.Assignment, submissionPath string, gradingDir string, options GradeOptions, fullSubmissionID string) (
+        string, string, error) {
+    inputDir := filepath.Join(gradingDir, common.GRADING_INPUT_DIRNAME);
+    outputDir := filepath.Join(gradingDir, common.GRADING_OUTPUT_DIRNAME);
 
-    var gradingInfo model.GradingInfo;
-    err = util.JSONFromFile(resultPath, &gradingInfo);
+    // Copy over submission files to the input dir.
+    err := util.CopyDirent(submissionPath, inputDir, true);
     if (err != nil) {
-        return nil, nil, stdout, stderr, err;
+        return "", "", fmt.Errorf("Failed to copy over submission/input contents: '%w'.", err);
     }
 
-    fileContents, err := util.GzipDirectoryToBytes(outputDir);
-    if (err != nil) {
-        return nil, nil, stdout, stderr, fmt.Errorf("Failed to copy grading output '%s': '%w'.", outputDir, err);
-    }
+    return docker.RunContainer(assignment.GetImageInfo(), inputDir, outputDir, fullSubmissionID, options.Output);
+}
 
-    return &gradingInfo, fileContents, stdout, stderr, nil;
+func (this *dockerRuntime) Collect(assignment *model.Assignment, gradingDir string) (*model.GradingInfo, map[string][]byte, error) {
+    return collectGradingOutput(gradingDir);
 }
@@ -1,8 +1,12 @@
 package grader
 
 import (
+    "errors"
     "fmt"
-    "sync"
+    "io"
+    "os"
+
+    "github.com/rs/zerolog/log"
 
     "github.com/eriq-augustine/autograder/common"
     "github.com/eriq-augustine/autograder/config"
@@ -12,11 +16,11 @@ import (
     "github.com/eriq-augustine/autograder/util"
 )
 
-var submissionLocks sync.Map;
-
 type GradeOptions struct {
     NoDocker bool
     LeaveTempDir bool
+    // If set, the grader's stdout will also be written here as it is produced.
+    Output io.Writer
 }
 
 func GetDefaultGradeOptions() GradeOptions {
@@ -33,8 +37,21 @@ func GradeDefault(assignment *model.Assignment, submissionPath string, user stri
 }
 
 // Grade with custom options.
+// The submission will go through the grading queue, and this will block until grading is complete.
 func Grade(assignment *model.Assignment, submissionPath string, user string, message string, options GradeOptions) (
         *model.GradingResult, RejectReason, error) {
+    job := newGradingJob(assignment, submissionPath, user, message, options);
+    jobQueue.add(job);
+    job.wait();
+
+    return job.Result, job.Reject, job.Error;
+}
+
+// Actually grade a submission.
+// Callers should go through the grading queue,
+// which makes sure that there is never more than one active grading for each course/assignment/user.
+func grade(assignment *model.Assignment, submissionPath string, user string, message string, options GradeOptions) (
+        *model.GradingResult, RejectReason, error) {
     reject, err := checkForRejection(assignment, submissionPath, user, message);
     if (err != nil) {
         return nil, nil, fmt.Errorf("Failed to check for rejection: '%w'.", err);
@@ -44,18 +61,33 @@ func Grade(assignment *model.Assignment, submissionPath string, user string, mes
         return nil, reject, nil;
     }
 
-    gradingKey := fmt.Sprintf("%s::%s::%s", assignment.GetCourse().GetID(), assignment.GetID(), user);
+    gradingResult, err := gradeSubmission(assignment, submissionPath, user, message, "", options);
+    if (err != nil) {
+        return gradingResult, nil, err;
+    }
 
-    // Get the existing mutex, or store (and fetch) a new one.
-    val, _ := submissionLocks.LoadOrStore(gradingKey, &sync.Mutex{});
-    lock := val.(*sync.Mutex)
+    if (!config.NO_STORE.Get()) {
+        err = db.SaveSubmission(assignment, gradingResult);
+        if (err != nil) {
+            return gradingResult, nil, fmt.Errorf("Failed to save grading result: '%w'.", err);
+        }
+    }
 
-    lock.Lock();
-    defer lock.Unlock()
+    return gradingResult, nil, nil;
+}
 
-    submissionID, inputFileContents, err := prepForGrading(assignment, submissionPath, user);
+// Run the grader on a submission without checking for rejection or storing the result.
+// If no submission ID is given, then the next submission ID for the user will be used.
+func gradeSubmission(assignment *model.Assignment, submissionPath string, user string, message string, submissionID string, options GradeOptions) (
+        *model.GradingResult, error) {
+    runtimeName, runtime, err := getAssignmentRuntime(assignment, options);
     if (err != nil) {
-        return nil, nil, fmt.Errorf("Failed to prep for grading: '%w'.", err);
+        return nil, err;
+    }
+
+    submissionID, inputFileContents, err := prepForGrading(runtime, assignment, submissionPath, user, submissionID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to prep for grading: '%w'.", err);
     }
 
     var gradingResult model.GradingResult;
@@ -70,11 +102,7 @@ func Grade(assignment *model.Assignment, submissionPath string, user string, mes
 
     startTimestamp := common.NowTimestamp();
 
-    if (options.NoDocker) {
-        gradingInfo, outputFileContents, stdout, stderr, err = runNoDockerGrader(assignment, submissionPath, options, fullSubmissionID);
-    } else {
-        gradingInfo, outputFileContents, stdout, stderr, err = runDockerGrader(assignment, submissionPath, options, fullSubmissionID);
-    }
+    gradingInfo, outputFileContents, stdout, stderr, err = runGrader(runtimeName, runtime, assignment, submissionPath, options, fullSubmissionID);
 
     endTimestamp := common.NowTimestamp();
 
@@ -83,7 +111,12 @@ func Grade(assignment *model.Assignment, submissionPath string, user string, mes
     gradingResult.Stderr = stderr;
 
     if (err != nil) {
-        return &gradingResult, nil, err;
+        var limitErr *docker.LimitError;
+        if (errors.As(err, &limitErr)) {
+            gradingResult.FailureReason = limitErr.Reason;
+        }
+
+        return &gradingResult, err;
     }
 
     // Set all the autograder fields in the grading info.
@@ -107,26 +140,48 @@ func Grade(assignment *model.Assignment, submissionPath string, user string, mes
     gradingResult.Info = gradingInfo;
     gradingResult.OutputFilesGZip = outputFileContents;
 
-    if (!config.NO_STORE.Get()) {
-        err = db.SaveSubmission(assignment, &gradingResult);
-        if (err != nil) {
-            return &gradingResult, nil, fmt.Errorf("Failed to save grading result: '%w'.", err);
-        }
+    return &gradingResult, nil;
+}
+
+// Run the grader in a fresh grading dir, and collect its output.
+func runGrader(runtimeName string, runtime GradingRuntime, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (
+        *model.GradingInfo, map[string][]byte, string, string, error) {
+    tempDir, _, _, _, err := common.PrepTempGradingDir(runtimeName);
+    if (err != nil) {
+        return nil, nil, "", "", err;
     }
 
-    return &gradingResult, nil, nil;
-}
+    if (!options.LeaveTempDir) {
+        defer os.RemoveAll(tempDir);
+    } else {
+        log.Info().Str("path", tempDir).Msg("Leaving behind temp grading dir.");
+    }
 
-func prepForGrading(assignment *model.Assignment, submissionPath string, user string) (string, map[string][]byte, error) {
-    // Ensure the assignment docker image is built.
-    err := docker.BuildImageFromSourceQuick(assignment);
+    stdout, stderr, err := runtime.Run(assignment, submissionPath, tempDir, options, fullSubmissionID);
     if (err != nil) {
-        return "", nil, fmt.Errorf("Failed to build assignment assignment '%s' docker image: '%w'.", assignment.FullID(), err);
+        return nil, nil, stdout, stderr, err;
     }
 
-    submissionID, err := db.GetNextSubmissionID(assignment, user);
+    gradingInfo, fileContents, err := runtime.Collect(assignment, tempDir);
     if (err != nil) {
-        return "", nil, fmt.Errorf("Unable to get next submission id for assignment '%s', user '%s': '%w'.", assignment.FullID(), user, err);
+        return nil, nil, stdout, stderr, fmt.Errorf("Failed to collect output from '%s' grader: '%w'.", runtimeName, err);
+    }
+
+    return gradingInfo, fileContents, stdout, stderr, nil;
+}
+
+func prepForGrading(runtime GradingRuntime, assignment *model.Assignment, submissionPath string, user string, submissionID string) (
+        string, map[string][]byte, error) {
+    err := runtime.Prepare(assignment);
+    if (err != nil) {
+        return "", nil, err;
+    }
+
+    if (submissionID == "") {
+        submissionID, err = db.GetNextSubmissionID(assignment, user);
+        if (err != nil) {
+            return "", nil, fmt.Errorf("Unable to get next submission id for assignment '%s', user '%s': '%w'.", assignment.FullID(), user, err);
+        }
     }
 
     fileContents, err := util.GzipDirectoryToBytes(submissionPath);
@@ -0,0 +1,51 @@
+package grader
+
+import (
+    "path/filepath"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+// A runtime that does not run a grader, and instead just writes out a canned result (for testing).
+// If no info is set, then the result will have the assignment's name and no questions.
+type MockRuntime struct {
+    Info *model.GradingInfo
+    Stdout string
+    Stderr string
+    // If set, all runs will fail with this error.
+    Error error
+}
+
+func (this *MockRuntime) Prepare(assignment *model.Assignment) error {
+    return nil;
+}
+
+func (this *MockRuntime) Run(assignment *model.Assignment, submissionPath string, gradingDir string, options GradeOptions, fullSubmissionID string) (
+        string, string, error) {
+    if (options.Output != nil) {
+        options.Output.Write([]byte(this.Stdout));
+    }
+
+    if (this.Error != nil) {
+        return this.Stdout, this.Stderr, this.Error;
+    }
+
+    info := this.Info;
+    if (info == nil) {
+        info = &model.GradingInfo{
+            Name: assignment.GetName(),
+            Questions: make([]*model.GradedQuestion, 0),
+        };
+    }
+
+    resultPath := filepath.Join(gradingDir, common.GRADING_OUTPUT_DIRNAME, common.GRADER_OUTPUT_RESULT_FILENAME);
+    err := util.ToJSONFileIndent(info, resultPath);
+
+    return this.Stdout, this.Stderr, err;
+}
+
+func (this *MockRuntime) Collect(assignment *model.Assignment, gradingDir string) (*model.GradingInfo, map[string][]byte, error) {
+    return collectGradingOutput(gradingDir);
+}
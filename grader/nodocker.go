@@ -1,98 +1,153 @@
 package grader
 
 import (
-    "bytes"
+    "errors"
     "fmt"
-    "os"
+    "io"
     "os/exec"
     "path/filepath"
     "strings"
+    "time"
 
     "github.com/rs/zerolog/log"
 
     "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/docker"
     "github.com/eriq-augustine/autograder/model"
-    "github.com/eriq-augustine/autograder/util"
 )
 
 const PYTHON_AUTOGRADER_INVOCATION = "python3 -m autograder.cli.grading.grade-dir --grader <grader> --dir <basedir> --outpath <outpath>"
 const PYTHON_GRADER_FILENAME = "grader.py"
 const PYTHON_DOCKER_IMAGE_BASENAME = "autograder.python";
 
-func runNoDockerGrader(assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (
-        *model.GradingInfo, map[string][]byte, string, string, error) {
+// How long to wait for output to finish after the grader exits.
+const NO_DOCKER_WAIT_DELAY = 5 * time.Second;
+
+// Grade by running the assignment's invocation directly on this machine.
+// Only the time and output limits are enforced.
+type localRuntime struct {}
+
+func (this *localRuntime) Prepare(assignment *model.Assignment) error {
+    return nil;
+}
+
+func (this *localRuntime) Run(assignment *model.Assignment, submissionPath string, gradingDir string, options GradeOptions, fullSubmissionID string) (
+        string, string, error) {
     imageInfo := assignment.GetImageInfo();
     if (imageInfo == nil) {
-        return nil, nil, "", "", fmt.Errorf("No image information associated with assignment: '%s'.", assignment.FullID());
-    }
-
-    tempDir, inputDir, outputDir, workDir, err := common.PrepTempGradingDir("nodocker");
-    if (err != nil) {
-        return nil, nil, "", "", err;
+        return "", "", fmt.Errorf("No image information associated with assignment: '%s'.", assignment.FullID());
     }
 
-    if (!options.LeaveTempDir) {
-        defer os.RemoveAll(tempDir);
-    } else {
-        log.Info().Str("path", tempDir).Msg("Leaving behind temp grading dir.");
-    }
+    inputDir := filepath.Join(gradingDir, common.GRADING_INPUT_DIRNAME);
+    outputDir := filepath.Join(gradingDir, common.GRADING_OUTPUT_DIRNAME);
+    workDir := filepath.Join(gradingDir, common.GRADING_WORK_DIRNAME);
 
-    cmd, err := getAssignmentInvocation(assignment, tempDir, inputDir, outputDir, workDir);
+    cmd, err := getAssignmentInvocation(assignment, gradingDir, inputDir, outputDir, workDir);
     if (err != nil) {
-        return nil, nil, "", "", err;
+        return "", "", err;
     }
 
     // Copy over the static files (and do any file ops).
-    err = common.CopyFileSpecs(imageInfo.BaseDir, workDir, tempDir,
+    err = common.CopyFileSpecs(imageInfo.BaseDir, workDir, gradingDir,
             imageInfo.StaticFiles, false, imageInfo.PreStaticFileOperations, imageInfo.PostStaticFileOperations);
     if (err != nil) {
-        return nil, nil, "", "", fmt.Errorf("Failed to copy static assignment files: '%w'.", err);
+        return "", "", fmt.Errorf("Failed to copy static assignment files: '%w'.", err);
     }
 
     // Copy over the submission files (and do any file ops).
-    err = common.CopyFileSpecs(submissionPath, inputDir, tempDir,
+    err = common.CopyFileSpecs(submissionPath, inputDir, gradingDir,
             []*common.FileSpec{common.GetPathFileSpec(".")}, true, []common.FileOperation{}, imageInfo.PostSubmissionFileOperations);
     if (err != nil) {
-        return nil, nil, "", "", fmt.Errorf("Failed to copy submission ssignment files: '%w'.", err);
+        return "", "", fmt.Errorf("Failed to copy submission ssignment files: '%w'.", err);
     }
 
-    stdout, stderr, err := runCMD(cmd);
+    stdout, stderr, err := runCMD(cmd, imageInfo.Limits.WithDefaults(), options.Output);
     if (err != nil) {
-        return nil, nil, stdout, stderr,
-                fmt.Errorf("Failed to run non-docker grader for assignment '%s': '%w'.", assignment.FullID(), err);
+        var limitErr *docker.LimitError;
+        if (errors.As(err, &limitErr)) {
+            return stdout, stderr, err;
+        }
+
+        return stdout, stderr, fmt.Errorf("Failed to run non-docker grader for assignment '%s': '%w'.", assignment.FullID(), err);
     }
 
-    resultPath := filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME);
-    if (!util.PathExists(resultPath)) {
-        return nil, nil, stdout, stderr, fmt.Errorf("Cannot find output file ('%s') after non-docker grading.", resultPath);
+    return stdout, stderr, nil;
+}
+
+func (this *localRuntime) Collect(assignment *model.Assignment, gradingDir string) (*model.GradingInfo, map[string][]byte, error) {
+    return collectGradingOutput(gradingDir);
+}
+
+// Run a grading command, killing it (and its process group) if it goes over the time or output limits.
+// A *docker.LimitError is returned when a limit is hit.
+// If a stream is given, stdout will also be written to it as it is produced.
+func runCMD(cmd *exec.Cmd, limits *docker.ResourceLimits, stream io.Writer) (string, string, error) {
+    output := docker.NewCappedOutput(limits.MaxOutputBytes());
+    outBuffer := output.NewWriter();
+    errBuffer := output.NewWriter();
+
+    cmd.Stdout = outBuffer;
+    if (stream != nil) {
+        cmd.Stdout = io.MultiWriter(outBuffer, stream);
     }
 
-    var gradingInfo model.GradingInfo;
-    err = util.JSONFromFile(resultPath, &gradingInfo);
+    cmd.Stderr = errBuffer;
+
+    // Don't wait forever on output pipes held open by stray children.
+    cmd.WaitDelay = NO_DOCKER_WAIT_DELAY;
+
+    setProcessGroup(cmd);
+
+    err := cmd.Start();
     if (err != nil) {
-        return nil, nil, stdout, stderr, err;
+        return "", "", err;
     }
 
-    fileContents, err := util.GzipDirectoryToBytes(outputDir);
-    if (err != nil) {
-        return nil, nil, stdout, stderr, fmt.Errorf("Failed to copy grading output '%s': '%w'.", outputDir, err);
+    done := make(chan error, 1);
+    go func() {
+        done <- cmd.Wait();
+    }();
+
+    // A nil channel never fires, so there is no timeout.
+    var timeoutChan <-chan time.Time = nil;
+    if (limits.TimeoutSecs > 0) {
+        timeout := time.NewTimer(time.Duration(limits.TimeoutSecs) * time.Second);
+        defer timeout.Stop();
+
+        timeoutChan = timeout.C;
     }
 
-    return &gradingInfo, fileContents, stdout, stderr, nil;
-}
+    var limitErr *docker.LimitError = nil;
 
-func runCMD(cmd *exec.Cmd) (string, string, error) {
-    var outBuffer bytes.Buffer;
-    var errBuffer bytes.Buffer;
+    select {
+        case err = <-done:
+        case <-timeoutChan:
+            limitErr = docker.NewTimeoutError(limits);
+        case <-output.Full():
+            limitErr = docker.NewOutputError(limits);
+    }
+
+    // Always kill the group, so nothing started by the grader is left running.
+    killErr := killProcessGroup(cmd);
+    if (killErr != nil) {
+        log.Warn().Err(killErr).Int("pid", cmd.Process.Pid).Msg("Failed to kill grader process group.");
+    }
 
-    cmd.Stdout = &outBuffer;
-    cmd.Stderr = &errBuffer;
+    if (limitErr != nil) {
+        log.Debug().Int("pid", cmd.Process.Pid).Str("reason", limitErr.Reason).Msg("Killed grader process group.");
 
-    err := cmd.Run();
+        <-done;
+        err = limitErr;
+    }
 
     stdout := outBuffer.String();
     stderr := errBuffer.String();
 
+    if ((limitErr == nil) && output.Truncated()) {
+        log.Warn().Int("pid", cmd.Process.Pid).Int("max-output-kb", limits.MaxOutputKB).Msg("Grader output was truncated.");
+        stderr += docker.NewOutputTruncatedNotice(limits);
+    }
+
     return stdout, stderr, err;
 }
 
@@ -0,0 +1,88 @@
+package grader
+
+import (
+    "errors"
+    "os/exec"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/docker"
+)
+
+func TestRunCMDLimits(test *testing.T) {
+    testCases := []struct{command string; reason string; stdout string}{
+        {"echo 'abc'", "", "abc\n"},
+        // The background sleep is in the same process group and must be killed as well.
+        {"sleep 30 & sleep 30", docker.LIMIT_REASON_TIMEOUT, ""},
+        {"yes", docker.LIMIT_REASON_OUTPUT, ""},
+    };
+
+    for i, testCase := range testCases {
+        limits := &docker.ResourceLimits{TimeoutSecs: 1, MaxOutputKB: 1};
+
+        startTime := time.Now();
+        stdout, _, err := runCMD(exec.Command("sh", "-c", testCase.command), limits, nil);
+        duration := time.Since(startTime);
+
+        if (duration > (5 * time.Second)) {
+            test.Errorf("Case %d: Command took too long to stop: %s.", i, duration);
+        }
+
+        if (testCase.reason == "") {
+            if (err != nil) {
+                test.Errorf("Case %d: Failed to run command: '%v'.", i, err);
+            } else if (stdout != testCase.stdout) {
+                test.Errorf("Case %d: Unexpected stdout. Expected: '%s', Actual: '%s'.", i, testCase.stdout, stdout);
+            }
+
+            continue;
+        }
+
+        var limitErr *docker.LimitError;
+        if (!errors.As(err, &limitErr)) {
+            test.Errorf("Case %d: Did not get a limit error, got: '%v'.", i, err);
+            continue;
+        }
+
+        if (limitErr.Reason != testCase.reason) {
+            test.Errorf("Case %d: Unexpected reason. Expected: '%s', Actual: '%s'.", i, testCase.reason, limitErr.Reason);
+        }
+
+        if (len(stdout) > limits.MaxOutputBytes()) {
+            test.Errorf("Case %d: Output is over the limit: %d bytes.", i, len(stdout));
+        }
+    }
+}
+
+// A grader that goes over the output limit right before exiting may finish before it can be killed,
+// but the truncation must still be reported.
+func TestRunCMDTruncatedOutput(test *testing.T) {
+    limits := &docker.ResourceLimits{TimeoutSecs: 5, MaxOutputKB: 1};
+    notice := docker.NewOutputTruncatedNotice(limits);
+
+    for i := 0; i < 10; i++ {
+        stdout, stderr, err := runCMD(exec.Command("sh", "-c", "head -c 4096 /dev/zero"), limits, nil);
+
+        if (len(stdout) > limits.MaxOutputBytes()) {
+            test.Fatalf("Run %d: Output is over the limit: %d bytes.", i, len(stdout));
+        }
+
+        if (err == nil) {
+            if (!strings.HasSuffix(stderr, notice)) {
+                test.Fatalf("Run %d: Truncated output was not noted. Stderr: '%s'.", i, stderr);
+            }
+
+            continue;
+        }
+
+        var limitErr *docker.LimitError;
+        if (!errors.As(err, &limitErr) || (limitErr.Reason != docker.LIMIT_REASON_OUTPUT)) {
+            test.Fatalf("Run %d: Unexpected error: '%v'.", i, err);
+        }
+
+        if (strings.Contains(stderr, notice)) {
+            test.Fatalf("Run %d: Killed grader also got a truncation notice. Stderr: '%s'.", i, stderr);
+        }
+    }
+}
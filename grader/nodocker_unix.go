@@ -0,0 +1,28 @@
+//go:build !windows
+
+package grader
+
+import (
+    "os/exec"
+    "syscall"
+)
+
+// Run the command in its own process group so that it (and anything it starts) can be killed together.
+func setProcessGroup(cmd *exec.Cmd) {
+    cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true};
+}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+    if (cmd.Process == nil) {
+        return nil;
+    }
+
+    // A negative PID signals the entire process group.
+    err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL);
+    if (err == syscall.ESRCH) {
+        // Everything in the group has already exited.
+        return nil;
+    }
+
+    return err;
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package grader
+
+import (
+    "os/exec"
+)
+
+// Process groups are not available, only the process itself will be killed.
+func setProcessGroup(cmd *exec.Cmd) {
+}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+    if (cmd.Process == nil) {
+        return nil;
+    }
+
+    return cmd.Process.Kill();
+}
@@ -0,0 +1,603 @@
+package grader
+
+// All grading goes through a single queue.
+// Jobs are graded by a pool of workers (see config.GRADING_WORKERS),
+// so that is the most gradings (and containers) that will run at once on a server.
+// Each course can also be capped (see config.GRADING_COURSE_MAX).
+// Jobs for the same course/assignment/user are graded in the order they were submitted and never at the same time
+// (a job will wait for any earlier job with the same key to finish).
+// Between different users, the scheduler round-robins
+// so that one user with many queued submissions cannot starve the others.
+// When the server shuts down, the queue is drained (see DrainQueue()):
+// no new jobs are accepted, but all queued and running jobs are finished.
+
+import (
+    "fmt"
+    "runtime/debug"
+    "slices"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/metrics"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+type JobStatus string;
+
+const (
+    JOB_STATUS_QUEUED JobStatus = "queued"
+    JOB_STATUS_RUNNING JobStatus = "running"
+    JOB_STATUS_DONE JobStatus = "done"
+    JOB_STATUS_FAILED JobStatus = "failed"
+)
+
+// How long a finished (async) job will be kept around for status checks.
+const JOB_RETENTION = 1 * time.Hour;
+
+// How often finished jobs are checked for pruning.
+const JOB_PRUNE_INTERVAL = 5 * time.Minute;
+
+type GradingJob struct {
+    ID string `json:"id"`
+    Status JobStatus `json:"status"`
+
+    CourseID string `json:"course-id"`
+    AssignmentID string `json:"assignment-id"`
+    User string `json:"user"`
+    Message string `json:"message"`
+
+    QueueTime common.Timestamp `json:"queue-time"`
+    StartTime common.Timestamp `json:"start-time"`
+    EndTime common.Timestamp `json:"end-time"`
+
+    // Only set once the job is done or failed.
+    // Async and regrade jobs only keep the summary of their result (see summarizeResult()).
+    Result *model.GradingResult `json:"-"`
+    Reject RejectReason `json:"-"`
+    Error error `json:"-"`
+
+    assignment *model.Assignment
+    submissionPath string
+    options GradeOptions
+    // The job has its own copy of the submission that should be removed after grading.
+    ownsSubmission bool
+    // The grader's stdout as it is being written.
+    output *OutputStream
+    // Set if this job is regrading a stored submission.
+    regrade *regradeTarget
+    // Async jobs are kept around (see JOB_RETENTION) after they finish for status checks.
+    // Other jobs are forgotten as soon as they finish (their callers already hold the job).
+    async bool
+
+    finishTime time.Time
+    done chan struct{}
+}
+
+type gradingQueue struct {
+    lock sync.Mutex
+    available *sync.Cond
+
+    pending []*GradingJob
+    // {key: job}.
+    running map[string]*GradingJob
+    jobs map[string]*GradingJob
+    numWorkers int
+
+    // Incremented every time a job is started.
+    startCount uint64
+    // The value of startCount the last time each user had a job started: {email: count}.
+    lastStarted map[string]uint64
+
+    // New jobs are immediately failed once the queue is closed.
+    closed bool
+
+    // Whether the background pruning of finished jobs has been started.
+    pruning bool
+
+    run func(job *GradingJob) (*model.GradingResult, RejectReason, error)
+}
+
+var jobQueue *gradingQueue = newGradingQueue(runGradingJob);
+
+// Grade a submission in the background.
+// The submission will be copied, so the caller is free to remove it once this returns.
+// Returns the ID of the grading job (see GetGradingJob()).
+func GradeAsync(assignment *model.Assignment, submissionPath string, user string, message string, options GradeOptions) (string, error) {
+    tempDir, err := util.MkDirTemp("autograder-grading-job-");
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to make temp dir for submission: '%w'.", err);
+    }
+
+    err = util.CopyDirContents(submissionPath, tempDir);
+    if (err != nil) {
+        util.RemoveDirent(tempDir);
+        return "", fmt.Errorf("Failed to copy submission '%s': '%w'.", submissionPath, err);
+    }
+
+    job := newGradingJob(assignment, tempDir, user, message, options);
+    job.ownsSubmission = true;
+    job.async = true;
+
+    jobQueue.add(job);
+
+    return job.ID, nil;
+}
+
+// Grade in the background with default options pulled from config.
+func GradeAsyncDefault(assignment *model.Assignment, submissionPath string, user string, message string) (string, error) {
+    return GradeAsync(assignment, submissionPath, user, message, GetDefaultGradeOptions());
+}
+
+// Get a snapshot of a grading job.
+// Returns nil if the job does not exist (or finished long enough ago that it was forgotten).
+func GetGradingJob(id string) *GradingJob {
+    return jobQueue.get(id);
+}
+
+// Get the output stream for a grading job's stdout.
+// Returns nil if the job does not exist.
+func GetGradingJobOutput(id string) *OutputStream {
+    return jobQueue.getOutput(id);
+}
+
+type QueueStats struct {
+    MaxRunning int `json:"max-running"`
+    Queued int `json:"queued"`
+    Running int `json:"running"`
+
+    CourseMaxRunning int `json:"course-max-running"`
+    CourseQueued int `json:"course-queued"`
+    CourseRunning int `json:"course-running"`
+
+    // The running and then queued jobs for the course (in queue order).
+    CourseJobs []*GradingJob `json:"course-jobs"`
+}
+
+// Stop accepting new grading jobs and wait for all queued and running jobs to finish.
+// Jobs submitted after this is called will fail.
+// Returns false if the timeout was reached before all the jobs finished.
+func DrainQueue(timeout time.Duration) bool {
+    return jobQueue.drain(timeout);
+}
+
+// Get the current state of the grading queue.
+// Server-wide counts are always included, jobs are only included for the given course.
+func GetQueueStats(courseID string) *QueueStats {
+    return jobQueue.stats(courseID);
+}
+
+func newGradingJob(assignment *model.Assignment, submissionPath string, user string, message string, options GradeOptions) *GradingJob {
+    output := newOutputStream();
+    options.Output = output;
+
+    return &GradingJob{
+        ID: util.UUID(),
+        Status: JOB_STATUS_QUEUED,
+        CourseID: assignment.GetCourse().GetID(),
+        AssignmentID: assignment.GetID(),
+        User: user,
+        Message: message,
+        QueueTime: common.NowTimestamp(),
+        assignment: assignment,
+        submissionPath: submissionPath,
+        options: options,
+        output: output,
+        done: make(chan struct{}),
+    };
+}
+
+// Jobs with the same key cannot run at the same time.
+func (this *GradingJob) key() string {
+    return fmt.Sprintf("%s::%s::%s", this.CourseID, this.AssignmentID, this.User);
+}
+
+// Block until the job is finished.
+// The result fields are safe to read once this returns.
+func (this *GradingJob) wait() {
+    <-this.done;
+}
+
+// Check if the job is finished without blocking.
+// The result fields are safe to read if this returns true.
+func (this *GradingJob) isDone() bool {
+    select {
+        case <-this.done:
+            return true;
+        default:
+            return false;
+    }
+}
+
+func runGradingJob(job *GradingJob) (*model.GradingResult, RejectReason, error) {
+    if (job.ownsSubmission) {
+        defer util.RemoveDirent(job.submissionPath);
+    }
+
+    startTime := time.Now();
+
+    var result *model.GradingResult;
+    var reject RejectReason;
+    var err error;
+
+    if (job.regrade != nil) {
+        result, err = regrade(job);
+    } else {
+        result, reject, err = grade(job.assignment, job.submissionPath, job.User, job.Message, job.options);
+    }
+
+    metrics.ObserveGrading(job.CourseID, job.AssignmentID, getRejectReasonType(reject), err, time.Since(startTime));
+
+    return result, reject, err;
+}
+
+func newGradingQueue(run func(job *GradingJob) (*model.GradingResult, RejectReason, error)) *gradingQueue {
+    queue := &gradingQueue{
+        pending: make([]*GradingJob, 0),
+        running: make(map[string]*GradingJob),
+        jobs: make(map[string]*GradingJob),
+        lastStarted: make(map[string]uint64),
+        run: run,
+    };
+
+    queue.available = sync.NewCond(&queue.lock);
+
+    return queue;
+}
+
+func (this *gradingQueue) add(job *GradingJob) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    if (this.closed) {
+        this.reject(job);
+        return;
+    }
+
+    this.startWorkers();
+
+    this.pending = append(this.pending, job);
+    this.jobs[job.ID] = job;
+
+    log.Info().Str("job-id", job.ID).Str("key", job.key()).
+            Int("queue-length", len(this.pending)).Int("running", len(this.running)).Msg("Queued grading job.");
+
+    this.available.Broadcast();
+}
+
+// Fail a job without running it.
+// The caller should hold the lock.
+func (this *gradingQueue) reject(job *GradingJob) {
+    if (job.ownsSubmission) {
+        util.RemoveDirent(job.submissionPath);
+    }
+
+    job.Status = JOB_STATUS_FAILED;
+    job.Error = fmt.Errorf("The grading queue is closed (the server is shutting down).");
+    job.EndTime = common.NowTimestamp();
+    job.finishTime = time.Now();
+
+    if (job.async) {
+        this.jobs[job.ID] = job;
+    }
+
+    job.output.Close();
+    close(job.done);
+
+    log.Warn().Str("job-id", job.ID).Str("key", job.key()).Msg("Rejected grading job, queue is closed.");
+}
+
+func (this *gradingQueue) drain(timeout time.Duration) bool {
+    this.lock.Lock();
+    this.closed = true;
+    this.lock.Unlock();
+
+    log.Info().Msg("Draining grading queue.");
+
+    done := make(chan struct{});
+    go func() {
+        this.lock.Lock();
+        defer this.lock.Unlock();
+
+        for ((len(this.pending) + len(this.running)) > 0) {
+            this.available.Wait();
+        }
+
+        close(done);
+    }();
+
+    select {
+        case <-done:
+            log.Info().Msg("Grading queue drained.");
+            return true;
+        case <-time.After(timeout):
+            return false;
+    }
+}
+
+func (this *gradingQueue) get(id string) *GradingJob {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    job, ok := this.jobs[id];
+    if (!ok) {
+        return nil;
+    }
+
+    snapshot := *job;
+    return &snapshot;
+}
+
+func (this *gradingQueue) getOutput(id string) *OutputStream {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    job, ok := this.jobs[id];
+    if (!ok) {
+        return nil;
+    }
+
+    return job.output;
+}
+
+func (this *gradingQueue) stats(courseID string) *QueueStats {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    stats := &QueueStats{
+        MaxRunning: max(1, config.GRADING_WORKERS.Get()),
+        Queued: len(this.pending),
+        Running: len(this.running),
+        CourseMaxRunning: config.GRADING_COURSE_MAX.Get(),
+        CourseJobs: make([]*GradingJob, 0),
+    };
+
+    running := make([]*GradingJob, 0);
+    for _, job := range this.running {
+        if (job.CourseID == courseID) {
+            running = append(running, job);
+        }
+    }
+
+    slices.SortFunc(running, func(a *GradingJob, b *GradingJob) int {
+        return strings.Compare(string(a.StartTime), string(b.StartTime));
+    });
+
+    for _, job := range running {
+        snapshot := *job;
+        stats.CourseJobs = append(stats.CourseJobs, &snapshot);
+        stats.CourseRunning++;
+    }
+
+    for _, job := range this.pending {
+        if (job.CourseID == courseID) {
+            snapshot := *job;
+            stats.CourseJobs = append(stats.CourseJobs, &snapshot);
+            stats.CourseQueued++;
+        }
+    }
+
+    return stats;
+}
+
+// Start workers until there are as many as the config asks for
+// (and start pruning finished jobs if that has not been started yet).
+// The caller should hold the lock.
+func (this *gradingQueue) startWorkers() {
+    maxWorkers := max(1, config.GRADING_WORKERS.Get());
+
+    for (this.numWorkers < maxWorkers) {
+        go this.work();
+        this.numWorkers++;
+    }
+
+    if (!this.pruning) {
+        go this.pruneLoop();
+        this.pruning = true;
+    }
+}
+
+func (this *gradingQueue) pruneLoop() {
+    ticker := time.NewTicker(JOB_PRUNE_INTERVAL);
+    defer ticker.Stop();
+
+    for range ticker.C {
+        this.lock.Lock();
+        this.pruneJobs();
+        this.lock.Unlock();
+    }
+}
+
+// Forget about jobs that finished a while ago.
+// The caller should hold the lock.
+func (this *gradingQueue) pruneJobs() {
+    now := time.Now();
+
+    for id, job := range this.jobs {
+        if (!job.finishTime.IsZero() && (now.Sub(job.finishTime) > JOB_RETENTION)) {
+            delete(this.jobs, id);
+        }
+    }
+}
+
+func (this *gradingQueue) work() {
+    for {
+        job := this.next();
+        this.runJob(job);
+    }
+}
+
+// Run a job and finish it.
+// A panic while grading fails the job (instead of taking down the server or leaving the job's key running forever).
+func (this *gradingQueue) runJob(job *GradingJob) {
+    var result *model.GradingResult;
+    var reject RejectReason;
+    var err error;
+
+    defer func() {
+        value := recover();
+        if (value != nil) {
+            log.Error().Str("job-id", job.ID).Str("key", job.key()).Any("panic", value).
+                    Str("stack", string(debug.Stack())).Msg("Grading job panicked.");
+
+            result = nil;
+            reject = nil;
+            err = fmt.Errorf("Grading panicked: '%v'.", value);
+        }
+
+        this.finish(job, result, reject, err);
+    }();
+
+    result, reject, err = this.run(job);
+}
+
+// Block until there is a job that can be run, and then claim it.
+func (this *gradingQueue) next() *GradingJob {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    for {
+        index := this.nextIndex();
+        if (index >= 0) {
+            job := this.pending[index];
+            this.pending = slices.Delete(this.pending, index, index + 1);
+
+            this.running[job.key()] = job;
+            job.Status = JOB_STATUS_RUNNING;
+            job.StartTime = common.NowTimestamp();
+
+            this.startCount++;
+            this.lastStarted[job.User] = this.startCount;
+
+            log.Debug().Str("job-id", job.ID).
+                    Int("queue-length", len(this.pending)).Int("running", len(this.running)).Msg("Started grading job.");
+
+            return job;
+        }
+
+        this.available.Wait();
+    }
+}
+
+// Get the index of the next pending job to run (or -1 if nothing can run).
+// A job can run if no job with the same key is running and its course is under its limit.
+// Each user's jobs run in submission order,
+// and the user that least recently had a job started goes first.
+// The caller should hold the lock.
+func (this *gradingQueue) nextIndex() int {
+    if (len(this.pending) == 0) {
+        return -1;
+    }
+
+    courseMax := config.GRADING_COURSE_MAX.Get();
+
+    courseCounts := make(map[string]int);
+    for _, job := range this.running {
+        courseCounts[job.CourseID]++;
+    }
+
+    bestIndex := -1;
+    var bestStarted uint64 = 0;
+    seenUsers := make(map[string]bool);
+
+    for i, job := range this.pending {
+        if (seenUsers[job.User]) {
+            continue;
+        }
+
+        if (this.running[job.key()] != nil) {
+            continue;
+        }
+
+        if ((courseMax > 0) && (courseCounts[job.CourseID] >= courseMax)) {
+            continue;
+        }
+
+        // This is the user's first runnable job.
+        seenUsers[job.User] = true;
+
+        lastStarted := this.lastStarted[job.User];
+        if ((bestIndex < 0) || (lastStarted < bestStarted)) {
+            bestIndex = i;
+            bestStarted = lastStarted;
+        }
+    }
+
+    return bestIndex;
+}
+
+func (this *gradingQueue) finish(job *GradingJob, result *model.GradingResult, reject RejectReason, err error) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    job.Result = result;
+    job.Reject = reject;
+    job.Error = err;
+    job.EndTime = common.NowTimestamp();
+    job.finishTime = time.Now();
+
+    if (err != nil) {
+        job.Status = JOB_STATUS_FAILED;
+    } else {
+        job.Status = JOB_STATUS_DONE;
+    }
+
+    delete(this.running, job.key());
+
+    // Users without any more jobs start fresh in the round robin.
+    if (!this.hasUserJobs(job.User)) {
+        delete(this.lastStarted, job.User);
+    }
+
+    // Async and regrade jobs can be held onto for a while after they finish, so only keep a summary of their result.
+    if (job.async || (job.regrade != nil)) {
+        job.Result = summarizeResult(result);
+    }
+
+    if (!job.async) {
+        delete(this.jobs, job.ID);
+    }
+
+    job.output.Close();
+    close(job.done);
+
+    log.Info().Str("job-id", job.ID).Str("status", string(job.Status)).
+            Int("queue-length", len(this.pending)).Int("running", len(this.running)).Msg("Finished grading job.");
+
+    this.available.Broadcast();
+}
+
+// Check if a user has any queued or running jobs.
+// The caller should hold the lock.
+func (this *gradingQueue) hasUserJobs(user string) bool {
+    for _, job := range this.running {
+        if (job.User == user) {
+            return true;
+        }
+    }
+
+    for _, job := range this.pending {
+        if (job.User == user) {
+            return true;
+        }
+    }
+
+    return false;
+}
+
+// Only keep the parts of a result that status checks use (dropping the submission's files and output).
+func summarizeResult(result *model.GradingResult) *model.GradingResult {
+    if (result == nil) {
+        return nil;
+    }
+
+    return &model.GradingResult{
+        Info: result.Info,
+        FailureReason: result.FailureReason,
+    };
+}
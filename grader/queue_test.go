@@ -0,0 +1,387 @@
+package grader
+
+import (
+    "fmt"
+    "slices"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+// Jobs for the same user should run one at a time in submission order,
+// while jobs for other users run alongside them.
+func TestGradingQueueOrdering(test *testing.T) {
+    oldWorkers := config.GRADING_WORKERS.Get();
+    config.GRADING_WORKERS.Set(4);
+    defer config.GRADING_WORKERS.Set(oldWorkers);
+
+    assignment := db.MustGetTestAssignment();
+
+    var lock sync.Mutex;
+    active := make(map[string]int);
+    order := make(map[string][]string);
+    maxActive := 0;
+    overlaps := 0;
+
+    run := func(job *GradingJob) (*model.GradingResult, RejectReason, error) {
+        lock.Lock();
+        active[job.User]++;
+        if (active[job.User] > 1) {
+            overlaps++;
+        }
+
+        order[job.User] = append(order[job.User], job.Message);
+
+        total := 0;
+        for _, count := range active {
+            total += count;
+        }
+        maxActive = max(maxActive, total);
+        lock.Unlock();
+
+        time.Sleep(20 * time.Millisecond);
+
+        lock.Lock();
+        active[job.User]--;
+        lock.Unlock();
+
+        if (job.Message == "fail") {
+            return nil, nil, fmt.Errorf("Failed on purpose.");
+        }
+
+        return nil, nil, nil;
+    };
+
+    queue := newGradingQueue(run);
+
+    users := []string{"a@test.com", "b@test.com", "c@test.com"};
+    messages := []string{"0", "1", "2", "fail"};
+
+    jobs := make([]*GradingJob, 0);
+    for _, message := range messages {
+        for _, user := range users {
+            job := newGradingJob(assignment, "", user, message, GradeOptions{});
+            // Only async jobs are kept for status checks.
+            job.async = true;
+            queue.add(job);
+            jobs = append(jobs, job);
+        }
+    }
+
+    for _, job := range jobs {
+        job.wait();
+    }
+
+    if (overlaps != 0) {
+        test.Fatalf("Jobs for the same user ran at the same time %d times.", overlaps);
+    }
+
+    if (maxActive < 2) {
+        test.Fatalf("Jobs for different users never ran at the same time.");
+    }
+
+    for _, user := range users {
+        if (!slices.Equal(messages, order[user])) {
+            test.Fatalf("Jobs for user '%s' ran out of order. Expected: '%v', Actual: '%v'.", user, messages, order[user]);
+        }
+    }
+
+    for _, job := range jobs {
+        snapshot := queue.get(job.ID);
+        if (snapshot == nil) {
+            test.Fatalf("Could not find job '%s'.", job.ID);
+        }
+
+        expected := JOB_STATUS_DONE;
+        if (job.Message == "fail") {
+            expected = JOB_STATUS_FAILED;
+        }
+
+        if (snapshot.Status != expected) {
+            test.Fatalf("Unexpected status for job '%s'. Expected: '%s', Actual: '%s'.", job.ID, expected, snapshot.Status);
+        }
+    }
+
+    if (queue.get("ZZZ") != nil) {
+        test.Fatalf("Found a job that does not exist.");
+    }
+}
+
+// With a single worker, users should take turns.
+func TestGradingQueueRoundRobin(test *testing.T) {
+    oldWorkers := config.GRADING_WORKERS.Get();
+    config.GRADING_WORKERS.Set(1);
+    defer config.GRADING_WORKERS.Set(oldWorkers);
+
+    assignment := db.MustGetTestAssignment();
+
+    release := make(chan struct{});
+    var lock sync.Mutex;
+    order := make([]string, 0);
+
+    run := func(job *GradingJob) (*model.GradingResult, RejectReason, error) {
+        // Hold the only worker until all the jobs are queued.
+        if (job.User == "blocker@test.com") {
+            <-release;
+            return nil, nil, nil;
+        }
+
+        lock.Lock();
+        defer lock.Unlock();
+
+        order = append(order, job.Message);
+        return nil, nil, nil;
+    };
+
+    queue := newGradingQueue(run);
+
+    jobs := []*GradingJob{newGradingJob(assignment, "", "blocker@test.com", "", GradeOptions{})};
+    queue.add(jobs[0]);
+
+    // Wait for the blocker to start.
+    for (queue.get(jobs[0].ID).Status != JOB_STATUS_RUNNING) {
+        time.Sleep(time.Millisecond);
+    }
+
+    for i := 0; i < 5; i++ {
+        job := newGradingJob(assignment, "", "a@test.com", fmt.Sprintf("a%d", i), GradeOptions{});
+        queue.add(job);
+        jobs = append(jobs, job);
+    }
+
+    for i := 0; i < 2; i++ {
+        job := newGradingJob(assignment, "", "b@test.com", fmt.Sprintf("b%d", i), GradeOptions{});
+        queue.add(job);
+        jobs = append(jobs, job);
+    }
+
+    stats := queue.stats(assignment.GetCourse().GetID());
+    if ((stats.Queued != 7) || (stats.Running != 1) || (len(stats.CourseJobs) != 8)) {
+        test.Fatalf("Unexpected queue stats: '%+v'.", stats);
+    }
+
+    close(release);
+
+    for _, job := range jobs {
+        job.wait();
+    }
+
+    expected := []string{"a0", "b0", "a1", "b1", "a2", "a3", "a4"};
+    if (!slices.Equal(expected, order)) {
+        test.Fatalf("Unexpected grading order. Expected: '%v', Actual: '%v'.", expected, order);
+    }
+}
+
+// No more than the course max should run at once, even with free workers.
+func TestGradingQueueCourseMax(test *testing.T) {
+    oldWorkers := config.GRADING_WORKERS.Get();
+    config.GRADING_WORKERS.Set(4);
+    defer config.GRADING_WORKERS.Set(oldWorkers);
+
+    oldCourseMax := config.GRADING_COURSE_MAX.Get();
+    config.GRADING_COURSE_MAX.Set(1);
+    defer config.GRADING_COURSE_MAX.Set(oldCourseMax);
+
+    assignment := db.MustGetTestAssignment();
+
+    var lock sync.Mutex;
+    active := make(map[string]int);
+    maxActive := make(map[string]int);
+
+    run := func(job *GradingJob) (*model.GradingResult, RejectReason, error) {
+        lock.Lock();
+        active[job.CourseID]++;
+        maxActive[job.CourseID] = max(maxActive[job.CourseID], active[job.CourseID]);
+        lock.Unlock();
+
+        time.Sleep(10 * time.Millisecond);
+
+        lock.Lock();
+        active[job.CourseID]--;
+        lock.Unlock();
+
+        return nil, nil, nil;
+    };
+
+    queue := newGradingQueue(run);
+
+    jobs := make([]*GradingJob, 0);
+    for _, courseID := range []string{"course-a", "course-b"} {
+        for i := 0; i < 4; i++ {
+            job := newGradingJob(assignment, "", fmt.Sprintf("%d@test.com", i), "", GradeOptions{});
+            job.CourseID = courseID;
+
+            queue.add(job);
+            jobs = append(jobs, job);
+        }
+    }
+
+    for _, job := range jobs {
+        job.wait();
+    }
+
+    for courseID, count := range maxActive {
+        if (count != 1) {
+            test.Fatalf("Course '%s' had %d concurrent gradings, expected 1.", courseID, count);
+        }
+    }
+}
+
+// Draining should finish all queued/running jobs and fail any new ones.
+func TestGradingQueueDrain(test *testing.T) {
+    oldWorkers := config.GRADING_WORKERS.Get();
+    config.GRADING_WORKERS.Set(1);
+    defer config.GRADING_WORKERS.Set(oldWorkers);
+
+    assignment := db.MustGetTestAssignment();
+
+    run := func(job *GradingJob) (*model.GradingResult, RejectReason, error) {
+        time.Sleep(20 * time.Millisecond);
+        return nil, nil, nil;
+    };
+
+    queue := newGradingQueue(run);
+
+    jobs := make([]*GradingJob, 0);
+    for i := 0; i < 3; i++ {
+        job := newGradingJob(assignment, "", fmt.Sprintf("%d@test.com", i), "", GradeOptions{});
+        queue.add(job);
+        jobs = append(jobs, job);
+    }
+
+    if (!queue.drain(5 * time.Second)) {
+        test.Fatalf("Queue did not drain in time.");
+    }
+
+    for i, job := range jobs {
+        if (job.Status != JOB_STATUS_DONE) {
+            test.Fatalf("Job %d was not finished during the drain: '%s'.", i, job.Status);
+        }
+    }
+
+    job := newGradingJob(assignment, "", "late@test.com", "", GradeOptions{});
+    queue.add(job);
+    job.wait();
+
+    if ((job.Status != JOB_STATUS_FAILED) || (job.Error == nil)) {
+        test.Fatalf("Job added to a closed queue did not fail: '%s' ('%v').", job.Status, job.Error);
+    }
+
+    // A slow job should time out the drain.
+    slowQueue := newGradingQueue(func(job *GradingJob) (*model.GradingResult, RejectReason, error) {
+        time.Sleep(500 * time.Millisecond);
+        return nil, nil, nil;
+    });
+
+    slowQueue.add(newGradingJob(assignment, "", "slow@test.com", "", GradeOptions{}));
+
+    if (slowQueue.drain(10 * time.Millisecond)) {
+        test.Fatalf("Slow queue drained before the timeout.");
+    }
+}
+
+// A panic while grading should fail the job, and not block later jobs with the same key.
+func TestGradingQueuePanic(test *testing.T) {
+    assignment := db.MustGetTestAssignment();
+
+    queue := newGradingQueue(func(job *GradingJob) (*model.GradingResult, RejectReason, error) {
+        if (job.Message == "panic") {
+            panic("Panic on purpose.");
+        }
+
+        return nil, nil, nil;
+    });
+
+    panicJob := newGradingJob(assignment, "", "a@test.com", "panic", GradeOptions{});
+    queue.add(panicJob);
+
+    nextJob := newGradingJob(assignment, "", "a@test.com", "next", GradeOptions{});
+    queue.add(nextJob);
+
+    panicJob.wait();
+    nextJob.wait();
+
+    if ((panicJob.Status != JOB_STATUS_FAILED) || (panicJob.Error == nil)) {
+        test.Fatalf("Panicking job did not fail: '%s' ('%v').", panicJob.Status, panicJob.Error);
+    }
+
+    if (nextJob.Status != JOB_STATUS_DONE) {
+        test.Fatalf("Job after a panic did not finish: '%s' ('%v').", nextJob.Status, nextJob.Error);
+    }
+}
+
+// Only async jobs are kept once they finish, and they only keep a summary of their result.
+func TestGradingQueueRetention(test *testing.T) {
+    assignment := db.MustGetTestAssignment();
+
+    queue := newGradingQueue(func(job *GradingJob) (*model.GradingResult, RejectReason, error) {
+        result := &model.GradingResult{
+            Info: &model.GradingInfo{ID: job.ID},
+            Stdout: "output",
+        };
+
+        return result, nil, nil;
+    });
+
+    syncJob := newGradingJob(assignment, "", "a@test.com", "", GradeOptions{});
+    queue.add(syncJob);
+    syncJob.wait();
+
+    if ((syncJob.Result == nil) || (syncJob.Result.Stdout != "output")) {
+        test.Fatalf("Synchronous job does not have its full result: '%+v'.", syncJob.Result);
+    }
+
+    if (queue.get(syncJob.ID) != nil) {
+        test.Fatalf("Synchronous job was kept after it finished.");
+    }
+
+    asyncJob := newGradingJob(assignment, "", "a@test.com", "", GradeOptions{});
+    asyncJob.async = true;
+    queue.add(asyncJob);
+    asyncJob.wait();
+
+    snapshot := queue.get(asyncJob.ID);
+    if ((snapshot == nil) || (snapshot.Result == nil) || (snapshot.Result.Info.ID != asyncJob.ID)) {
+        test.Fatalf("Async job was not kept after it finished: '%+v'.", snapshot);
+    }
+
+    if (snapshot.Result.Stdout != "") {
+        test.Fatalf("Async job kept its full result.");
+    }
+
+    // Jobs that finished long ago are pruned.
+    queue.lock.Lock();
+    queue.jobs[asyncJob.ID].finishTime = time.Now().Add(-2 * JOB_RETENTION);
+    queue.pruneJobs();
+    queue.lock.Unlock();
+
+    if (queue.get(asyncJob.ID) != nil) {
+        test.Fatalf("Old async job was not pruned.");
+    }
+}
+
+// Users without any queued or running jobs should not be tracked by the round robin.
+func TestGradingQueueForgetsIdleUsers(test *testing.T) {
+    assignment := db.MustGetTestAssignment();
+
+    queue := newGradingQueue(func(job *GradingJob) (*model.GradingResult, RejectReason, error) {
+        return nil, nil, nil;
+    });
+
+    for i := 0; i < 3; i++ {
+        job := newGradingJob(assignment, "", fmt.Sprintf("%d@test.com", i), "", GradeOptions{});
+        queue.add(job);
+        job.wait();
+    }
+
+    queue.lock.Lock();
+    defer queue.lock.Unlock();
+
+    if (len(queue.lastStarted) != 0) {
+        test.Fatalf("Idle users are still tracked: '%v'.", queue.lastStarted);
+    }
+}
@@ -0,0 +1,394 @@
+package grader
+
+// Regrading reruns an assignment's grader over stored submissions (e.g. after a grader bug has been fixed).
+// Regrades go through the grading queue like any other submission,
+// but are never rejected.
+
+import (
+    "fmt"
+    "slices"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+type RegradeOptions struct {
+    GradeOptions
+
+    // Only regrade submissions from these users (all users when empty).
+    Users []string
+    // Regrade all of each user's submissions instead of just their most recent one.
+    AllSubmissions bool
+    // Grade and compare, but do not store the new results.
+    DryRun bool
+    // Replace the old submissions instead of storing the new results as new submissions.
+    Overwrite bool
+}
+
+type RegradeResult struct {
+    User string `json:"user"`
+    OldSubmissionID string `json:"old-submission-id"`
+    // Empty on a dry run or error.
+    NewSubmissionID string `json:"new-submission-id,omitempty"`
+
+    // Whether the new result differs from the old one (see model.GradingInfo.Equals()).
+    Changed bool `json:"changed"`
+    OldScore float64 `json:"old-score"`
+    NewScore float64 `json:"new-score"`
+    Questions []*QuestionDiff `json:"questions"`
+
+    Error string `json:"error,omitempty"`
+}
+
+type QuestionDiff struct {
+    Name string `json:"name"`
+    OldScore float64 `json:"old-score"`
+    NewScore float64 `json:"new-score"`
+    OldMaxPoints float64 `json:"old-max-points"`
+    NewMaxPoints float64 `json:"new-max-points"`
+}
+
+// Information about the submission a regrade job is replacing.
+type regradeTarget struct {
+    old *model.SubmissionHistoryItem
+    options RegradeOptions
+
+    // Only set once the job has started (and loaded the old submission).
+    oldInfo *model.GradingInfo
+}
+
+// The state of a regrade that was started with StartRegrade().
+type RegradeStatus struct {
+    ID string `json:"id"`
+    CourseID string `json:"course-id"`
+    AssignmentID string `json:"assignment-id"`
+    StartTime common.Timestamp `json:"start-time"`
+
+    // The grading job for each submission being regraded.
+    JobIDs []string `json:"job-ids"`
+    Done bool `json:"done"`
+    // Results for the jobs that have finished (in the same order as the job IDs).
+    Results []*RegradeResult `json:"results"`
+}
+
+type regradeRun struct {
+    id string
+    assignment *model.Assignment
+    startTime time.Time
+    jobs []*GradingJob
+}
+
+// Regrades are kept around (like async grading jobs) for status checks until JOB_RETENTION after they finish.
+var regrades map[string]*regradeRun = make(map[string]*regradeRun);
+var regradesLock sync.Mutex;
+
+// Queue up regrades for an assignment's stored submissions and return right away.
+// The submissions are only loaded when their regrade job runs,
+// so errors for specific submissions are reported in their result.
+// Use GetRegradeStatus() to check on the regrade.
+func StartRegrade(assignment *model.Assignment, options RegradeOptions) (*RegradeStatus, error) {
+    run, err := startRegrade(assignment, options);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    regradesLock.Lock();
+    defer regradesLock.Unlock();
+
+    return run.status(), nil;
+}
+
+func startRegrade(assignment *model.Assignment, options RegradeOptions) (*regradeRun, error) {
+    users := options.Users;
+    if (len(users) == 0) {
+        courseUsers, err := db.GetUsers(assignment.GetCourse());
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get users for course '%s': '%w'.", assignment.GetCourse().GetID(), err);
+        }
+
+        users = make([]string, 0, len(courseUsers));
+        for email, _ := range courseUsers {
+            users = append(users, email);
+        }
+    }
+
+    users = slices.Clone(users);
+    slices.Sort(users);
+
+    run := &regradeRun{
+        id: util.UUID(),
+        assignment: assignment,
+        startTime: time.Now(),
+        jobs: make([]*GradingJob, 0),
+    };
+
+    // Find all the submissions before queueing any, so nothing is regraded if the submissions cannot be listed.
+    for _, user := range users {
+        submissions, err := getRegradeSubmissions(assignment, user, options.AllSubmissions);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        for _, submission := range submissions {
+            run.jobs = append(run.jobs, newRegradeJob(assignment, user, submission, options));
+        }
+    }
+
+    regradesLock.Lock();
+    pruneRegrades();
+    regrades[run.id] = run;
+    regradesLock.Unlock();
+
+    for _, job := range run.jobs {
+        jobQueue.add(job);
+    }
+
+    log.Info().Str("assignment", assignment.FullID()).Str("regrade-id", run.id).Int("submissions", len(run.jobs)).
+            Bool("dry-run", options.DryRun).Bool("overwrite", options.Overwrite).Msg("Started regrade.");
+
+    return run, nil;
+}
+
+// Get the current state of a regrade.
+// Returns nil if the regrade does not exist (or finished long enough ago that it was forgotten).
+func GetRegradeStatus(id string) *RegradeStatus {
+    regradesLock.Lock();
+    defer regradesLock.Unlock();
+
+    pruneRegrades();
+
+    run, ok := regrades[id];
+    if (!ok) {
+        return nil;
+    }
+
+    return run.status();
+}
+
+// Regrade an assignment's stored submissions and block until all the regrades are done.
+// See StartRegrade().
+func Regrade(assignment *model.Assignment, options RegradeOptions) ([]*RegradeResult, error) {
+    run, err := startRegrade(assignment, options);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    results := make([]*RegradeResult, 0, len(run.jobs));
+    for _, job := range run.jobs {
+        job.wait();
+        results = append(results, newRegradeResult(job));
+    }
+
+    log.Info().Str("assignment", assignment.FullID()).Str("regrade-id", run.id).Int("submissions", len(results)).
+            Bool("dry-run", options.DryRun).Bool("overwrite", options.Overwrite).Msg("Finished regrade.");
+
+    return results, nil;
+}
+
+func (this *regradeRun) status() *RegradeStatus {
+    status := &RegradeStatus{
+        ID: this.id,
+        CourseID: this.assignment.GetCourse().GetID(),
+        AssignmentID: this.assignment.GetID(),
+        StartTime: common.TimestampFromTime(this.startTime),
+        JobIDs: make([]string, 0, len(this.jobs)),
+        Done: true,
+        Results: make([]*RegradeResult, 0),
+    };
+
+    for _, job := range this.jobs {
+        status.JobIDs = append(status.JobIDs, job.ID);
+
+        if (!job.isDone()) {
+            status.Done = false;
+            continue;
+        }
+
+        status.Results = append(status.Results, newRegradeResult(job));
+    }
+
+    return status;
+}
+
+// Get the time the last job of a regrade finished, or false if the regrade is not done.
+func (this *regradeRun) finishTime() (time.Time, bool) {
+    finishTime := this.startTime;
+
+    for _, job := range this.jobs {
+        if (!job.isDone()) {
+            return time.Time{}, false;
+        }
+
+        if (job.finishTime.After(finishTime)) {
+            finishTime = job.finishTime;
+        }
+    }
+
+    return finishTime, true;
+}
+
+// Forget about regrades that finished a while ago.
+// The caller should hold the regrades lock.
+func pruneRegrades() {
+    now := time.Now();
+
+    for id, run := range regrades {
+        finishTime, done := run.finishTime();
+        if (done && (now.Sub(finishTime) > JOB_RETENTION)) {
+            delete(regrades, id);
+        }
+    }
+}
+
+// Get the submissions to regrade for a user (in the order they were submitted).
+func getRegradeSubmissions(assignment *model.Assignment, user string, allSubmissions bool) ([]*model.SubmissionHistoryItem, error) {
+    history, err := db.GetSubmissionHistory(assignment, user);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get submission history for '%s': '%w'.", user, err);
+    }
+
+    slices.SortStableFunc(history, func(a *model.SubmissionHistoryItem, b *model.SubmissionHistoryItem) int {
+        return strings.Compare(string(a.GradingStartTime), string(b.GradingStartTime));
+    });
+
+    if (!allSubmissions && (len(history) > 0)) {
+        history = history[(len(history) - 1):];
+    }
+
+    return history, nil;
+}
+
+// The submission's files are not loaded until the job runs (see regrade()).
+func newRegradeJob(assignment *model.Assignment, user string, submission *model.SubmissionHistoryItem, options RegradeOptions) *GradingJob {
+    job := newGradingJob(assignment, "", user, submission.Message, options.GradeOptions);
+    job.regrade = &regradeTarget{
+        old: submission,
+        options: options,
+    };
+
+    return job;
+}
+
+// Regrade a submission (the job should have a regrade target).
+func regrade(job *GradingJob) (*model.GradingResult, error) {
+    target := job.regrade;
+    options := target.options;
+
+    submission, err := db.GetSubmissionContents(job.assignment, job.User, target.old.ShortID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get submission '%s' for '%s': '%w'.", target.old.ShortID, job.User, err);
+    }
+
+    if (submission == nil) {
+        return nil, fmt.Errorf("Could not find submission '%s' for '%s'.", target.old.ShortID, job.User);
+    }
+
+    oldInfo := submission.Info;
+    target.oldInfo = oldInfo;
+
+    tempDir, err := util.MkDirTemp("autograder-regrade-");
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to make temp dir for regrade: '%w'.", err);
+    }
+    defer util.RemoveDirent(tempDir);
+
+    err = util.GzipBytesToDirectory(tempDir, submission.InputFilesGZip);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to write input files for submission '%s': '%w'.", target.old.ShortID, err);
+    }
+
+    // A new submission needs a new ID, but an overwrite or dry run can just reuse the old one.
+    submissionID := oldInfo.ShortID;
+    if (!options.Overwrite && !options.DryRun) {
+        submissionID = "";
+    }
+
+    result, err := gradeSubmission(job.assignment, tempDir, job.User, job.Message, submissionID, job.options);
+    if (err != nil) {
+        return result, err;
+    }
+
+    if (options.DryRun || config.NO_STORE.Get()) {
+        return result, nil;
+    }
+
+    if (options.Overwrite) {
+        // Keep the original submission time so things like late policies still see the original time.
+        result.Info.GradingStartTime = oldInfo.GradingStartTime;
+    }
+
+    // An overwrite has the same ID as the old submission, so saving replaces the old submission in place.
+    err = db.SaveSubmission(job.assignment, result);
+    if (err != nil) {
+        return result, fmt.Errorf("Failed to save regrade result: '%w'.", err);
+    }
+
+    return result, nil;
+}
+
+func newRegradeResult(job *GradingJob) *RegradeResult {
+    result := &RegradeResult{
+        User: job.User,
+        OldSubmissionID: job.regrade.old.ID,
+        OldScore: job.regrade.old.Score,
+        Questions: make([]*QuestionDiff, 0),
+    };
+
+    if (job.Error != nil) {
+        result.Error = job.Error.Error();
+        return result;
+    }
+
+    oldInfo := job.regrade.oldInfo;
+
+    newInfo := job.Result.Info;
+
+    if (!job.regrade.options.DryRun && !config.NO_STORE.Get()) {
+        result.NewSubmissionID = newInfo.ID;
+    }
+
+    result.Changed = !oldInfo.Equals(*newInfo, false);
+    result.NewScore = newInfo.Score;
+    result.Questions = diffQuestions(oldInfo, newInfo);
+
+    return result;
+}
+
+// Pair up questions by name (in the order they first appear).
+func diffQuestions(oldInfo *model.GradingInfo, newInfo *model.GradingInfo) []*QuestionDiff {
+    diffs := make([]*QuestionDiff, 0);
+    diffsByName := make(map[string]*QuestionDiff);
+
+    getDiff := func(name string) *QuestionDiff {
+        diff, ok := diffsByName[name];
+        if (!ok) {
+            diff = &QuestionDiff{Name: name};
+            diffsByName[name] = diff;
+            diffs = append(diffs, diff);
+        }
+
+        return diff;
+    };
+
+    for _, question := range oldInfo.Questions {
+        diff := getDiff(question.Name);
+        diff.OldScore = question.Score;
+        diff.OldMaxPoints = question.MaxPoints;
+    }
+
+    for _, question := range newInfo.Questions {
+        diff := getDiff(question.Name);
+        diff.NewScore = question.Score;
+        diff.NewMaxPoints = question.MaxPoints;
+    }
+
+    return diffs;
+}
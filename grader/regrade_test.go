@@ -0,0 +1,160 @@
+package grader
+
+import (
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+const REGRADE_TEST_USER = "student@test.com";
+const REGRADE_TEST_RECENT_ID = "course101::hw0::student@test.com::1697406272";
+
+func TestRegrade(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    assignment, cleanup := prepRegradeTest();
+    defer cleanup();
+
+    testCases := []struct{options RegradeOptions; numResults int; numHistory int; changed bool}{
+        {RegradeOptions{Users: []string{REGRADE_TEST_USER}, DryRun: true}, 1, 3, true},
+        {RegradeOptions{Users: []string{REGRADE_TEST_USER}, DryRun: true, AllSubmissions: true}, 3, 3, true},
+        {RegradeOptions{Users: []string{"ZZZ"}, DryRun: true}, 0, 3, true},
+        {RegradeOptions{Users: []string{REGRADE_TEST_USER}, Overwrite: true}, 1, 3, true},
+        // The most recent submission already has the new result.
+        {RegradeOptions{Users: []string{REGRADE_TEST_USER}}, 1, 4, false},
+    };
+
+    for i, testCase := range testCases {
+        results, err := Regrade(assignment, testCase.options);
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to regrade: '%v'.", i, err);
+        }
+
+        if (len(results) != testCase.numResults) {
+            test.Fatalf("Case %d: Unexpected number of results. Expected: %d, Actual: %d.", i, testCase.numResults, len(results));
+        }
+
+        history, err := db.GetSubmissionHistory(assignment, REGRADE_TEST_USER);
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to get submission history: '%v'.", i, err);
+        }
+
+        if (len(history) != testCase.numHistory) {
+            test.Fatalf("Case %d: Unexpected history length. Expected: %d, Actual: %d.", i, testCase.numHistory, len(history));
+        }
+
+        if (testCase.numResults == 0) {
+            continue;
+        }
+
+        // The most recent submission is always last.
+        result := results[len(results) - 1];
+
+        if (result.Error != "") {
+            test.Fatalf("Case %d: Regrade had an error: '%s'.", i, result.Error);
+        }
+
+        if ((result.Changed != testCase.changed) || (result.NewScore != 1)) {
+            test.Fatalf("Case %d: Unexpected regrade result: '%+v'.", i, result);
+        }
+
+        // The recent submission has not been overwritten yet.
+        if ((i < 3) && ((result.OldSubmissionID != REGRADE_TEST_RECENT_ID) || (result.OldScore != 2) || (result.Questions[0].OldScore != 1))) {
+            test.Fatalf("Case %d: Unexpected old submission: '%+v'.", i, result);
+        }
+
+        if (testCase.options.DryRun != (result.NewSubmissionID == "")) {
+            test.Fatalf("Case %d: Unexpected new submission ID: '%s'.", i, result.NewSubmissionID);
+        }
+
+        if ((len(result.Questions) != 3) || (result.Questions[0].NewScore != 0)) {
+            test.Fatalf("Case %d: Unexpected question diff: '%+v'.", i, result.Questions[0]);
+        }
+
+        if (testCase.options.Overwrite && (result.NewSubmissionID != REGRADE_TEST_RECENT_ID)) {
+            test.Fatalf("Case %d: Overwrite got a new submission ID: '%s'.", i, result.NewSubmissionID);
+        }
+    }
+
+    // The overwritten submission should have the new score, but its old submission time.
+    info, err := db.GetSubmissionResult(assignment, REGRADE_TEST_USER, REGRADE_TEST_RECENT_ID);
+    if (err != nil) {
+        test.Fatalf("Failed to get overwritten submission: '%v'.", err);
+    }
+
+    if ((info.Score != 1) || (info.GradingStartTime != "2023-10-15T21:44:33Z")) {
+        test.Fatalf("Unexpected overwritten submission: '%s'.", info);
+    }
+}
+
+func TestStartRegrade(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    assignment, cleanup := prepRegradeTest();
+    defer cleanup();
+
+    options := RegradeOptions{Users: []string{REGRADE_TEST_USER}, DryRun: true, AllSubmissions: true};
+    status, err := StartRegrade(assignment, options);
+    if (err != nil) {
+        test.Fatalf("Failed to start regrade: '%v'.", err);
+    }
+
+    if (len(status.JobIDs) != 3) {
+        test.Fatalf("Unexpected number of jobs. Expected: %d, Actual: %d.", 3, len(status.JobIDs));
+    }
+
+    deadline := time.Now().Add(10 * time.Second);
+    for ((status != nil) && !status.Done) {
+        if (time.Now().After(deadline)) {
+            test.Fatalf("Regrade did not finish in time: '%+v'.", status);
+        }
+
+        time.Sleep(10 * time.Millisecond);
+        status = GetRegradeStatus(status.ID);
+    }
+
+    if (status == nil) {
+        test.Fatalf("Could not find regrade.");
+    }
+
+    if (len(status.Results) != 3) {
+        test.Fatalf("Unexpected number of results. Expected: %d, Actual: %d.", 3, len(status.Results));
+    }
+
+    for i, result := range status.Results {
+        if ((result.Error != "") || !result.Changed) {
+            test.Errorf("Result %d: Unexpected result: '%+v'.", i, result);
+        }
+    }
+
+    if (GetRegradeStatus("ZZZ") != nil) {
+        test.Fatalf("Found a regrade that does not exist.");
+    }
+}
+
+// Get the test assignment with a grader where Q1 now fails.
+func prepRegradeTest() (*model.Assignment, func()) {
+    assignment := db.MustGetTestAssignment();
+
+    oldRuntime := assignment.ImageInfo.Runtime;
+    assignment.ImageInfo.Runtime = "test-regrade";
+
+    RegisterRuntime("test-regrade", &MockRuntime{
+        Info: &model.GradingInfo{
+            Name: "HW0",
+            Questions: []*model.GradedQuestion{
+                &model.GradedQuestion{Name: "Q1", MaxPoints: 1, Score: 0},
+                &model.GradedQuestion{Name: "Q2", MaxPoints: 1, Score: 1},
+                &model.GradedQuestion{Name: "Style", MaxPoints: 0, Score: 0, Message: "Style is clean!"},
+            },
+        },
+    });
+
+    return assignment, func() {
+        assignment.ImageInfo.Runtime = oldRuntime;
+    };
+}
@@ -2,6 +2,7 @@ package grader
 
 import (
     "fmt"
+    "reflect"
     "time"
 
     "github.com/eriq-augustine/autograder/common"
@@ -35,6 +36,20 @@ func (this *RejectWindowMax) String() string {
             this.Max, this.WindowDuration.ShortString(), nextTime.Format(time.DateTime));
 }
 
+// Get the name of a rejection's type (e.g. "RejectMaxAttempts"), or an empty string if there is no rejection.
+func getRejectReasonType(reject RejectReason) string {
+    if (reject == nil) {
+        return "";
+    }
+
+    reasonType := reflect.TypeOf(reject);
+    if (reasonType.Kind() == reflect.Pointer) {
+        reasonType = reasonType.Elem();
+    }
+
+    return reasonType.Name();
+}
+
 func checkForRejection(assignment *model.Assignment, submissionPath string, user string, message string) (RejectReason, error) {
     return checkSubmissionLimit(assignment, user);
 }
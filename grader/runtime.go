@@ -0,0 +1,128 @@
+package grader
+
+// Runtimes are responsible for actually running an assignment's grader on a submission.
+// Runtimes are registered by name, and an assignment picks its runtime with the "runtime" field
+// (defaulting to docker.RUNTIME_DOCKER).
+// When docker is disabled (see GradeOptions.NoDocker), assignments that would use a container runtime are graded locally instead.
+
+import (
+    "fmt"
+    "path/filepath"
+    "slices"
+    "sync"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/docker"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    RUNTIME_LOCAL = docker.RUNTIME_LOCAL
+    RUNTIME_MOCK = docker.RUNTIME_MOCK
+)
+
+type GradingRuntime interface {
+    // Get ready to grade an assignment (e.g. build an image).
+    // This is called before every grading, so it should be quick when nothing has changed.
+    Prepare(assignment *model.Assignment) error;
+
+    // Run the grader on a submission.
+    // The grading dir will already have the standard input, output, and work dirs (see common.CreateStandardGradingDirs()).
+    // The grader's output (including common.GRADER_OUTPUT_RESULT_FILENAME) should be left in the output dir.
+    // Returns the grader's stdout and stderr.
+    Run(assignment *model.Assignment, submissionPath string, gradingDir string, options GradeOptions, fullSubmissionID string) (string, string, error);
+
+    // Collect the grading information and output files after a successful run.
+    Collect(assignment *model.Assignment, gradingDir string) (*model.GradingInfo, map[string][]byte, error);
+}
+
+var runtimesLock sync.RWMutex;
+var runtimes map[string]GradingRuntime = map[string]GradingRuntime{
+    docker.RUNTIME_DOCKER: &dockerRuntime{},
+    // Podman uses the same docker API, just through a different socket (see docker.ImageInfo.APIHost()).
+    docker.RUNTIME_PODMAN: &dockerRuntime{},
+    RUNTIME_LOCAL: &localRuntime{},
+    RUNTIME_MOCK: &MockRuntime{},
+};
+
+// Register a runtime, replacing any existing runtime with the same name.
+// Runtimes should be registered before any courses that use them are loaded,
+// since images are validated against the known runtime names.
+func RegisterRuntime(name string, runtime GradingRuntime) {
+    runtimesLock.Lock();
+    defer runtimesLock.Unlock();
+
+    runtimes[name] = runtime;
+    docker.RegisterRuntimeName(name);
+}
+
+// Get a runtime by name, or nil if no runtime has that name.
+func GetRuntime(name string) GradingRuntime {
+    runtimesLock.RLock();
+    defer runtimesLock.RUnlock();
+
+    return runtimes[name];
+}
+
+func GetRuntimeNames() []string {
+    runtimesLock.RLock();
+    defer runtimesLock.RUnlock();
+
+    names := make([]string, 0, len(runtimes));
+    for name, _ := range runtimes {
+        names = append(names, name);
+    }
+
+    slices.Sort(names);
+    return names;
+}
+
+// Get the name of the runtime that will be used to grade an assignment.
+func getRuntimeName(assignment *model.Assignment, options GradeOptions) string {
+    imageInfo := assignment.GetImageInfo();
+
+    if (options.NoDocker && imageInfo.UsesContainer()) {
+        return RUNTIME_LOCAL;
+    }
+
+    if (imageInfo.Runtime == "") {
+        return docker.RUNTIME_DOCKER;
+    }
+
+    return imageInfo.Runtime;
+}
+
+func getAssignmentRuntime(assignment *model.Assignment, options GradeOptions) (string, GradingRuntime, error) {
+    name := getRuntimeName(assignment, options);
+
+    runtime := GetRuntime(name);
+    if (runtime == nil) {
+        return "", nil, fmt.Errorf("Unknown grading runtime '%s' for assignment '%s'.", name, assignment.FullID());
+    }
+
+    return name, runtime, nil;
+}
+
+// The standard way to collect grader output: read the result file and gzip the output dir.
+func collectGradingOutput(gradingDir string) (*model.GradingInfo, map[string][]byte, error) {
+    outputDir := filepath.Join(gradingDir, common.GRADING_OUTPUT_DIRNAME);
+
+    resultPath := filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME);
+    if (!util.PathExists(resultPath)) {
+        return nil, nil, fmt.Errorf("Cannot find output file ('%s') after grading.", resultPath);
+    }
+
+    var gradingInfo model.GradingInfo;
+    err := util.JSONFromFile(resultPath, &gradingInfo);
+    if (err != nil) {
+        return nil, nil, err;
+    }
+
+    fileContents, err := util.GzipDirectoryToBytes(outputDir);
+    if (err != nil) {
+        return nil, nil, fmt.Errorf("Failed to copy grading output '%s': '%w'.", outputDir, err);
+    }
+
+    return &gradingInfo, fileContents, nil;
+}
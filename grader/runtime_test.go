@@ -0,0 +1,98 @@
+package grader
+
+import (
+    "fmt"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/docker"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+func TestGetRuntimeName(test *testing.T) {
+    assignment := db.MustGetTestAssignment();
+
+    oldRuntime := assignment.ImageInfo.Runtime;
+    defer func() {
+        assignment.ImageInfo.Runtime = oldRuntime;
+    }();
+
+    testCases := []struct{runtime string; noDocker bool; expected string}{
+        {"", false, docker.RUNTIME_DOCKER},
+        {"", true, RUNTIME_LOCAL},
+        {docker.RUNTIME_DOCKER, true, RUNTIME_LOCAL},
+        {docker.RUNTIME_PODMAN, false, docker.RUNTIME_PODMAN},
+        {docker.RUNTIME_PODMAN, true, RUNTIME_LOCAL},
+        {RUNTIME_MOCK, false, RUNTIME_MOCK},
+        {RUNTIME_MOCK, true, RUNTIME_MOCK},
+        {"ZZZ", false, "ZZZ"},
+    };
+
+    for i, testCase := range testCases {
+        assignment.ImageInfo.Runtime = testCase.runtime;
+
+        actual := getRuntimeName(assignment, GradeOptions{NoDocker: testCase.noDocker});
+        if (testCase.expected != actual) {
+            test.Errorf("Case %d: Unexpected runtime. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual);
+        }
+    }
+
+    assignment.ImageInfo.Runtime = "ZZZ";
+    _, _, err := getAssignmentRuntime(assignment, GradeOptions{});
+    if (err == nil) {
+        test.Fatalf("Did not get an error for an unknown runtime.");
+    }
+}
+
+func TestGradeMockRuntime(test *testing.T) {
+    oldNoStore := config.NO_STORE.Get();
+    config.NO_STORE.Set(true);
+    defer config.NO_STORE.Set(oldNoStore);
+
+    assignment := db.MustGetTestAssignment();
+
+    oldRuntime := assignment.ImageInfo.Runtime;
+    assignment.ImageInfo.Runtime = "test-mock";
+    defer func() {
+        assignment.ImageInfo.Runtime = oldRuntime;
+    }();
+
+    runtime := &MockRuntime{
+        Info: &model.GradingInfo{
+            Name: "mock",
+            Questions: []*model.GradedQuestion{
+                &model.GradedQuestion{Name: "Q1", MaxPoints: 2, Score: 1},
+                &model.GradedQuestion{Name: "Q2", MaxPoints: 3, Score: 3},
+            },
+        },
+        Stdout: "out",
+    };
+    RegisterRuntime("test-mock", runtime);
+
+    submissionPath := test.TempDir();
+
+    result, reject, err := grade(assignment, submissionPath, BASE_TEST_USER, TEST_MESSAGE, GradeOptions{});
+    if (err != nil) {
+        test.Fatalf("Failed to grade with the mock runtime: '%v'.", err);
+    }
+
+    if (reject != nil) {
+        test.Fatalf("Submission was rejected: '%s'.", reject.String());
+    }
+
+    if ((result.Info.Score != 4) || (result.Info.MaxPoints != 5) || (result.Stdout != "out")) {
+        test.Fatalf("Unexpected grading result: '%s' (stdout: '%s').", result.Info, result.Stdout);
+    }
+
+    runtime.Error = fmt.Errorf("Failed on purpose.");
+
+    result, _, err = grade(assignment, submissionPath, BASE_TEST_USER, TEST_MESSAGE, GradeOptions{});
+    if (err == nil) {
+        test.Fatalf("Did not get an error from a failing runtime.");
+    }
+
+    if ((result == nil) || (result.Stdout != "out")) {
+        test.Fatalf("Output was not kept from a failing runtime: '%v'.", result);
+    }
+}
@@ -0,0 +1,91 @@
+package grader
+
+import (
+    "strings"
+    "sync"
+)
+
+// Collects the lines of a grader's stdout as they are written,
+// so that they can be followed while grading is still running.
+type OutputStream struct {
+    lock sync.Mutex
+    lines []string
+    partial strings.Builder
+    closed bool
+    // Closed (and replaced) every time the stream changes.
+    changed chan struct{}
+}
+
+func newOutputStream() *OutputStream {
+    return &OutputStream{
+        lines: make([]string, 0),
+        changed: make(chan struct{}),
+    };
+}
+
+func (this *OutputStream) Write(data []byte) (int, error) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    if (this.closed) {
+        return len(data), nil;
+    }
+
+    this.partial.Write(data);
+
+    text := this.partial.String();
+    index := strings.LastIndex(text, "\n");
+    if (index < 0) {
+        return len(data), nil;
+    }
+
+    this.lines = append(this.lines, strings.Split(text[0:index], "\n")...);
+
+    this.partial.Reset();
+    this.partial.WriteString(text[(index + 1):]);
+
+    this.notify();
+
+    return len(data), nil;
+}
+
+// Mark the stream as done, any partial line will be added as a final line.
+func (this *OutputStream) Close() error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    if (this.closed) {
+        return nil;
+    }
+
+    if (this.partial.Len() > 0) {
+        this.lines = append(this.lines, this.partial.String());
+        this.partial.Reset();
+    }
+
+    this.closed = true;
+    this.notify();
+
+    return nil;
+}
+
+// Get all the lines starting at the given index,
+// whether the stream is closed,
+// and a channel that will be closed the next time the stream changes.
+func (this *OutputStream) Lines(start int) ([]string, bool, <-chan struct{}) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    lines := make([]string, 0);
+    if (start < len(this.lines)) {
+        lines = append(lines, this.lines[start:]...);
+    }
+
+    return lines, this.closed, this.changed;
+}
+
+// The caller should hold the lock.
+func (this *OutputStream) notify() {
+    close(this.changed);
+    this.changed = make(chan struct{});
+}
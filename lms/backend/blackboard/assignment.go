@@ -0,0 +1,40 @@
+package blackboard
+
+import (
+    "fmt"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+)
+
+// Assignments are gradebook columns.
+func (this *BlackboardBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+    endpoint := fmt.Sprintf(
+        "/learn/api/public/v2/courses/%s/gradebook/columns/%s",
+        this.CourseID, assignmentID);
+
+    var column Column;
+    err := this.get(endpoint, &column);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignment: '%w'.", err);
+    }
+
+    return column.ToLMSType(this.CourseID), nil;
+}
+
+func (this *BlackboardBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+    endpoint := fmt.Sprintf(
+        "/learn/api/public/v2/courses/%s/gradebook/columns?limit=%d",
+        this.CourseID, PAGE_SIZE);
+
+    columns, err := fetchAll[Column](this, endpoint);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignments: '%w'.", err);
+    }
+
+    assignments := make([]*lmstypes.Assignment, 0, len(columns));
+    for _, column := range columns {
+        assignments = append(assignments, column.ToLMSType(this.CourseID));
+    }
+
+    return assignments, nil;
+}
@@ -0,0 +1,59 @@
+package blackboard
+
+import (
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+var testDueDate time.Time = time.Date(2023, time.October, 4, 0, 0, 0, 0, time.UTC);
+
+var testAssignments []*lmstypes.Assignment = []*lmstypes.Assignment{
+    &lmstypes.Assignment{
+        ID: TEST_ASSIGNMENT_ID,
+        Name: "Homework 0",
+        LMSCourseID: TEST_COURSE_ID,
+        DueDate: &testDueDate,
+        MaxPoints: 100.0,
+    },
+    // A manually graded column without a due date.
+    &lmstypes.Assignment{
+        ID: "_98766_1",
+        Name: "Participation",
+        LMSCourseID: TEST_COURSE_ID,
+        DueDate: nil,
+        MaxPoints: 10.0,
+    },
+};
+
+func TestBlackboardFetchAssignmentBase(test *testing.T) {
+    assignment, err := testBackend.FetchAssignment(TEST_ASSIGNMENT_ID);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(testAssignments[0], assignment)) {
+        test.Fatalf("Assignment not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(testAssignments[0]), util.MustToJSONIndent(assignment));
+    }
+
+    _, err = testBackend.FetchAssignment("ZZZ");
+    if (err == nil) {
+        test.Fatalf("Did not get an error when fetching a missing assignment.");
+    }
+}
+
+func TestBlackboardFetchAssignmentsBase(test *testing.T) {
+    assignments, err := testBackend.FetchAssignments();
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignments: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(testAssignments, assignments)) {
+        test.Fatalf("Assignments not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(testAssignments), util.MustToJSONIndent(assignments));
+    }
+}
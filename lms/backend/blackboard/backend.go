@@ -0,0 +1,47 @@
+package blackboard
+
+// A backend for Blackboard Learn's REST API.
+// Requests are authenticated with OAuth 2 client credentials (an application key and secret)
+// for an application that has been registered with the Blackboard instance.
+
+import (
+    "fmt"
+    "strings"
+)
+
+type BlackboardBackend struct {
+    // Either a primary ID (e.g. "_123_1") or an external ID (e.g. "courseId:CS101").
+    CourseID string
+    AppKey string
+    AppSecret string
+    BaseURL string
+}
+
+func NewBackend(blackboardCourseID string, appKey string, appSecret string, baseURL string) (*BlackboardBackend, error) {
+    if (blackboardCourseID == "") {
+        return nil, fmt.Errorf("Blackboard course ID (course-id) cannot be empty.");
+    }
+
+    if (appKey == "") {
+        return nil, fmt.Errorf("Blackboard application key (app-key) cannot be empty.");
+    }
+
+    if (appSecret == "") {
+        return nil, fmt.Errorf("Blackboard application secret (app-secret) cannot be empty.");
+    }
+
+    if (baseURL == "") {
+        return nil, fmt.Errorf("Blackboard base URL (base-url) cannot be empty.");
+    }
+
+    baseURL = strings.TrimSuffix(baseURL, "/");
+
+    backend := BlackboardBackend{
+        CourseID: blackboardCourseID,
+        AppKey: appKey,
+        AppSecret: appSecret,
+        BaseURL: baseURL,
+    };
+
+    return &backend, nil;
+}
@@ -0,0 +1,38 @@
+package blackboard
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+)
+
+func (this *BlackboardBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+    for i, comment := range comments {
+        if (i != 0) {
+            time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
+        }
+
+        err := this.UpdateComment(assignmentID, comment);
+        if (err != nil) {
+            return fmt.Errorf("Failed on comment %d: '%w'.", i, err);
+        }
+    }
+
+    return nil;
+}
+
+// The comment's author is the ID of the graded user (see Grade.ToLMSType()).
+// Only the feedback is changed, the score is left as-is.
+func (this *BlackboardBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+    payload := map[string]any{
+        "feedback": comment.Text,
+    };
+
+    err := this.updateGrade(assignmentID, comment.Author, payload);
+    if (err != nil) {
+        return fmt.Errorf("Failed to update comment: '%w'.", err);
+    }
+
+    return nil;
+}
@@ -0,0 +1,159 @@
+package blackboard
+
+import (
+    "encoding/base64"
+    "fmt"
+    "sync"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    PAGE_SIZE int = 100;
+    UPLOAD_SLEEP_TIME_SEC = int64(0.5 * float64(time.Second));
+
+    TOKEN_ENDPOINT = "/learn/api/public/v1/oauth2/token";
+    // Tokens are refreshed when they are this close to expiring.
+    TOKEN_EXPIRY_BUFFER = 60 * time.Second;
+
+    // How far back user change cursors are set.
+    CURSOR_OVERLAP = 5 * time.Minute;
+)
+
+type accessToken struct {
+    Token string
+    Expires time.Time
+}
+
+// Access tokens are shared by all backends using the same application on the same server.
+// {"<base url>::<app key>": *accessToken}.
+var tokens map[string]*accessToken = make(map[string]*accessToken);
+var tokensLock sync.Mutex;
+
+// A page of results from a list endpoint.
+type resultsPage[T any] struct {
+    Results []*T `json:"results"`
+    Paging *struct {
+        // A path (and query) relative to the base URL, empty on the last page.
+        NextPage string `json:"nextPage"`
+    } `json:"paging"`
+}
+
+type tokenResponse struct {
+    AccessToken string `json:"access_token"`
+    TokenType string `json:"token_type"`
+    ExpiresIn int64 `json:"expires_in"`
+}
+
+// Get a valid access token, fetching a new one if necessary.
+func (this *BlackboardBackend) getToken() (string, error) {
+    tokensLock.Lock();
+    defer tokensLock.Unlock();
+
+    key := this.BaseURL + "::" + this.AppKey;
+
+    token, ok := tokens[key];
+    if (ok && time.Now().Add(TOKEN_EXPIRY_BUFFER).Before(token.Expires)) {
+        return token.Token, nil;
+    }
+
+    credentials := base64.StdEncoding.EncodeToString([]byte(this.AppKey + ":" + this.AppSecret));
+    headers := map[string][]string{
+        "Authorization": []string{"Basic " + credentials},
+    };
+
+    form := map[string]string{
+        "grant_type": "client_credentials",
+    };
+
+    body, _, err := common.PostWithHeaders(this.BaseURL + TOKEN_ENDPOINT, form, headers);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to fetch Blackboard access token: '%w'.", err);
+    }
+
+    var response tokenResponse;
+    err = util.JSONFromString(body, &response);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to unmarshal Blackboard access token: '%w'.", err);
+    }
+
+    if (response.AccessToken == "") {
+        return "", fmt.Errorf("Blackboard did not return an access token.");
+    }
+
+    tokens[key] = &accessToken{
+        Token: response.AccessToken,
+        Expires: time.Now().Add(time.Duration(response.ExpiresIn) * time.Second),
+    };
+
+    return response.AccessToken, nil;
+}
+
+func (this *BlackboardBackend) standardHeaders() (map[string][]string, error) {
+    token, err := this.getToken();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return map[string][]string{
+        "Authorization": []string{"Bearer " + token},
+        "Accept": []string{"application/json"},
+    }, nil;
+}
+
+func (this *BlackboardBackend) get(endpoint string, result any) error {
+    headers, err := this.standardHeaders();
+    if (err != nil) {
+        return err;
+    }
+
+    body, _, err := common.GetWithHeaders(this.BaseURL + endpoint, headers);
+    if (err != nil) {
+        return err;
+    }
+
+    err = util.JSONFromString(body, result);
+    if (err != nil) {
+        return fmt.Errorf("Failed to unmarshal response from '%s': '%w'.", endpoint, err);
+    }
+
+    return nil;
+}
+
+func (this *BlackboardBackend) patch(endpoint string, payload any) error {
+    headers, err := this.standardHeaders();
+    if (err != nil) {
+        return err;
+    }
+
+    _, _, err = common.SendJSONWithHeaders("PATCH", this.BaseURL + endpoint, payload, headers);
+    return err;
+}
+
+// Fetch all the results of a list endpoint (following pages).
+func fetchAll[T any](this *BlackboardBackend, endpoint string) ([]*T, error) {
+    results := make([]*T, 0);
+
+    for (endpoint != "") {
+        var page resultsPage[T];
+        err := this.get(endpoint, &page);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        for _, result := range page.Results {
+            if (result != nil) {
+                results = append(results, result);
+            }
+        }
+
+        endpoint = "";
+        if (page.Paging != nil) {
+            endpoint = page.Paging.NextPage;
+        }
+    }
+
+    return results, nil;
+}
@@ -0,0 +1,77 @@
+package blackboard
+
+import (
+    "embed"
+    "io/fs"
+    "net/http"
+    "os"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/lms/backend/testhttp"
+)
+
+const (
+    TEST_COURSE_ID = "_12345_1"
+    TEST_ASSIGNMENT_ID = "_98765_1"
+    TEST_APP_KEY = "KEY"
+    TEST_APP_SECRET = "SECRET"
+    TEST_TOKEN = "TOKEN123"
+)
+
+//go:embed testdata/http
+var httpDataDir embed.FS;
+
+var testServer *testhttp.Server;
+var testBackend *BlackboardBackend;
+
+func TestMain(suite *testing.M) {
+    // Run inside a func so defers will run before os.Exit().
+    code := func() int {
+        db.PrepForTestingMain();
+        defer db.CleanupTestingMain();
+
+        dataDir, err := fs.Sub(httpDataDir, "testdata/http");
+        if (err != nil) {
+            panic(err);
+        }
+
+        testServer, err = testhttp.Start(dataDir, testhttp.Options{CheckRequest: checkRequest});
+        if (err != nil) {
+            panic(err);
+        }
+        defer testServer.Close();
+
+        testBackend, err = NewBackend(TEST_COURSE_ID, TEST_APP_KEY, TEST_APP_SECRET, testServer.URL());
+        if (err != nil) {
+            panic(err);
+        }
+
+        return suite.Run();
+    }();
+
+    os.Exit(code);
+}
+
+// The token endpoint uses basic auth with the application's credentials,
+// everything else uses the bearer token.
+func checkRequest(response http.ResponseWriter, request *http.Request) bool {
+    authorized := false;
+    if (request.URL.Path == TOKEN_ENDPOINT) {
+        key, secret, ok := request.BasicAuth();
+        authorized = (ok && (key == TEST_APP_KEY) && (secret == TEST_APP_SECRET));
+    } else {
+        authorized = (request.Header.Get("Authorization") == ("Bearer " + TEST_TOKEN));
+    }
+
+    if (!authorized) {
+        response.WriteHeader(http.StatusUnauthorized);
+        response.Write([]byte(`{"status":401,"message":"Bearer token is invalid"}`));
+    }
+
+    return authorized;
+}
+
+func getPatchedBody(path string) map[string]any {
+    return testServer.GetJSONBody("PATCH", path);
+}
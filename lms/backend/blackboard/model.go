@@ -0,0 +1,113 @@
+package blackboard
+
+import (
+    "strings"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+type Membership struct {
+    UserID string `json:"userId"`
+    CourseRoleID string `json:"courseRoleId"`
+    User *User `json:"user"`
+}
+
+type User struct {
+    ID string `json:"id"`
+    UserName string `json:"userName"`
+    Name struct {
+        Given string `json:"given"`
+        Family string `json:"family"`
+    } `json:"name"`
+    Contact struct {
+        Email string `json:"email"`
+    } `json:"contact"`
+}
+
+// A gradebook column.
+type Column struct {
+    ID string `json:"id"`
+    Name string `json:"name"`
+    Score struct {
+        Possible float64 `json:"possible"`
+    } `json:"score"`
+    Grading struct {
+        Due *time.Time `json:"due"`
+    } `json:"grading"`
+}
+
+type Grade struct {
+    UserID string `json:"userId"`
+    Score *float64 `json:"score"`
+    // Feedback to the student.
+    Feedback string `json:"feedback"`
+}
+
+// Blackboard course role to autograder role.
+var roleMapping map[string]model.UserRole = map[string]model.UserRole{
+    "Guest": model.RoleOther,
+    "Student": model.RoleStudent,
+    "Grader": model.RoleGrader,
+    "TeachingAssistant": model.RoleGrader,
+    "CourseBuilder": model.RoleAdmin,
+    "Instructor": model.RoleOwner,
+};
+
+func (this *Membership) GetRole() model.UserRole {
+    role, ok := roleMapping[this.CourseRoleID];
+    if (!ok) {
+        return model.RoleOther;
+    }
+
+    return role;
+}
+
+func (this *Membership) ToLMSType() *lmstypes.User {
+    user := &lmstypes.User{
+        ID: this.UserID,
+        Role: this.GetRole(),
+    };
+
+    if (this.User != nil) {
+        user.Name = strings.TrimSpace(this.User.Name.Given + " " + this.User.Name.Family);
+        user.Email = this.User.Contact.Email;
+    }
+
+    return user;
+}
+
+func (this *Column) ToLMSType(courseID string) *lmstypes.Assignment {
+    return &lmstypes.Assignment{
+        ID: this.ID,
+        Name: this.Name,
+        LMSCourseID: courseID,
+        DueDate: this.Grading.Due,
+        MaxPoints: this.Score.Possible,
+    };
+}
+
+// Blackboard only has a single feedback field per grade,
+// so the comment's ID and author are both the ID of the graded user
+// (which is what UpdateComment() needs to find the grade again).
+func (this *Grade) ToLMSType() *lmstypes.SubmissionScore {
+    score := &lmstypes.SubmissionScore{
+        UserID: this.UserID,
+        Comments: make([]*lmstypes.SubmissionComment, 0, 1),
+    };
+
+    if (this.Score != nil) {
+        score.Score = *this.Score;
+    }
+
+    if (this.Feedback != "") {
+        score.Comments = append(score.Comments, &lmstypes.SubmissionComment{
+            ID: this.UserID,
+            Author: this.UserID,
+            Text: this.Feedback,
+        });
+    }
+
+    return score;
+}
@@ -0,0 +1,76 @@
+package blackboard
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+)
+
+func (this *BlackboardBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+    endpoint := fmt.Sprintf(
+        "/learn/api/public/v2/courses/%s/gradebook/columns/%s/users/%s",
+        this.CourseID, assignmentID, userID);
+
+    var grade Grade;
+    err := this.get(endpoint, &grade);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch score: '%w'.", err);
+    }
+
+    return grade.ToLMSType(), nil;
+}
+
+func (this *BlackboardBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+    endpoint := fmt.Sprintf(
+        "/learn/api/public/v2/courses/%s/gradebook/columns/%s/users?limit=%d",
+        this.CourseID, assignmentID, PAGE_SIZE);
+
+    grades, err := fetchAll[Grade](this, endpoint);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch scores: '%w'.", err);
+    }
+
+    scores := make([]*lmstypes.SubmissionScore, 0, len(grades));
+    for _, grade := range grades {
+        scores = append(scores, grade.ToLMSType());
+    }
+
+    return scores, nil;
+}
+
+// Blackboard does not have a bulk grade update, so each score is sent individually.
+func (this *BlackboardBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+    for i, score := range scores {
+        if (i != 0) {
+            time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
+        }
+
+        if (len(score.Comments) > 1) {
+            return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments));
+        }
+
+        payload := map[string]any{
+            "score": score.Score,
+        };
+
+        for _, comment := range score.Comments {
+            payload["feedback"] = comment.Text;
+        }
+
+        err := this.updateGrade(assignmentID, score.UserID, payload);
+        if (err != nil) {
+            return fmt.Errorf("Failed to upload score for user '%s': '%w'.", score.UserID, err);
+        }
+    }
+
+    return nil;
+}
+
+func (this *BlackboardBackend) updateGrade(assignmentID string, userID string, payload map[string]any) error {
+    endpoint := fmt.Sprintf(
+        "/learn/api/public/v2/courses/%s/gradebook/columns/%s/users/%s",
+        this.CourseID, assignmentID, userID);
+
+    return this.patch(endpoint, payload);
+}
@@ -0,0 +1,109 @@
+package blackboard
+
+import (
+    "fmt"
+    "reflect"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const TEST_COMMENT = "{\n\"id\": \"course101::hw0::student@test.com::1696364768\",\n\"raw-score\": 100,\n\"score\": 100,\n\"lock\": false,\n\"__autograder__v01__\": 0\n}";
+
+var testStudentScore lmstypes.SubmissionScore = lmstypes.SubmissionScore{
+    UserID: "_40_1",
+    Score: 100.0,
+    Comments: []*lmstypes.SubmissionComment{
+        &lmstypes.SubmissionComment{
+            ID: "_40_1",
+            Author: "_40_1",
+            Text: TEST_COMMENT,
+        },
+    },
+};
+
+var testGraderScore lmstypes.SubmissionScore = lmstypes.SubmissionScore{
+    UserID: "_30_1",
+    Score: 80.5,
+    Comments: []*lmstypes.SubmissionComment{},
+};
+
+func TestBlackboardFetchAssignmentScoreBase(test *testing.T) {
+    score, err := testBackend.FetchAssignmentScore(TEST_ASSIGNMENT_ID, "_40_1");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment score: '%v'.", err);
+    }
+
+    expectedJSON := util.MustToJSONIndent(testStudentScore);
+    actualJSON := util.MustToJSONIndent(score);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Score not as expected. Expected: '%s', Actual: '%s'.", expectedJSON, actualJSON);
+    }
+}
+
+// Scores are split over two pages.
+func TestBlackboardFetchAssignmentScoresBase(test *testing.T) {
+    scores, err := testBackend.FetchAssignmentScores(TEST_ASSIGNMENT_ID);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment scores: '%v'.", err);
+    }
+
+    expectedJSON := util.MustToJSONIndent([]*lmstypes.SubmissionScore{&testStudentScore, &testGraderScore});
+    actualJSON := util.MustToJSONIndent(scores);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Scores not as expected. Expected: '%s', Actual: '%s'.", expectedJSON, actualJSON);
+    }
+}
+
+func TestBlackboardUpdateAssignmentScoresBase(test *testing.T) {
+    scores := []*lmstypes.SubmissionScore{
+        &testStudentScore,
+        &testGraderScore,
+    };
+
+    err := testBackend.UpdateAssignmentScores(TEST_ASSIGNMENT_ID, scores);
+    if (err != nil) {
+        test.Fatalf("Failed to update assignment scores: '%v'.", err);
+    }
+
+    // Scores without a comment leave the existing feedback alone.
+    expected := map[string]map[string]any{
+        "_40_1": map[string]any{"score": 100.0, "feedback": TEST_COMMENT},
+        "_30_1": map[string]any{"score": 80.5},
+    };
+
+    for userID, expectedBody := range expected {
+        body := getPatchedBody(gradePath(userID));
+        if (!reflect.DeepEqual(expectedBody, body)) {
+            test.Errorf("Unexpected body for user '%s'. Expected: '%v', Actual: '%v'.", userID, expectedBody, body);
+        }
+    }
+}
+
+func TestBlackboardUpdateCommentBase(test *testing.T) {
+    comment := &lmstypes.SubmissionComment{
+        ID: "_40_1",
+        Author: "_40_1",
+        Text: "New Comment",
+    };
+
+    err := testBackend.UpdateComments(TEST_ASSIGNMENT_ID, []*lmstypes.SubmissionComment{comment});
+    if (err != nil) {
+        test.Fatalf("Failed to update comment: '%v'.", err);
+    }
+
+    // Only the feedback is sent, the score is left alone.
+    expected := map[string]any{"feedback": "New Comment"};
+
+    body := getPatchedBody(gradePath("_40_1"));
+    if (!reflect.DeepEqual(expected, body)) {
+        test.Fatalf("Unexpected body. Expected: '%v', Actual: '%v'.", expected, body);
+    }
+}
+
+func gradePath(userID string) string {
+    return fmt.Sprintf("/learn/api/public/v2/courses/%s/gradebook/columns/%s/users/%s", TEST_COURSE_ID, TEST_ASSIGNMENT_ID, userID);
+}
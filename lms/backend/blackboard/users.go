@@ -0,0 +1,104 @@
+package blackboard
+
+import (
+    "fmt"
+    "net/url"
+    "strings"
+    "time"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+)
+
+func (this *BlackboardBackend) FetchUsers() ([]*lmstypes.User, error) {
+    memberships, err := this.fetchMemberships();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    users := make([]*lmstypes.User, 0, len(memberships));
+    for _, membership := range memberships {
+        users = append(users, membership.ToLMSType());
+    }
+
+    return users, nil;
+}
+
+// Blackboard cannot search course memberships by email, so all users are fetched and filtered.
+func (this *BlackboardBackend) FetchUser(email string) (*lmstypes.User, error) {
+    users, err := this.FetchUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err);
+    }
+
+    matches := make([]*lmstypes.User, 0, 1);
+    for _, user := range users {
+        if (strings.EqualFold(user.Email, email)) {
+            matches = append(matches, user);
+        }
+    }
+
+    if (len(matches) != 1) {
+        log.Warn().Str("email", email).Int("num-results", len(matches)).Msg("Did not find exactly one matching user in blackboard.");
+        return nil, nil;
+    }
+
+    return matches[0], nil;
+}
+
+// Blackboard can filter memberships by when they were modified, so cursors are times.
+func (this *BlackboardBackend) FetchUsersWithCursor() ([]*lmstypes.User, string, error) {
+    cursor := newCursor();
+
+    users, err := this.FetchUsers();
+    if (err != nil) {
+        return nil, "", err;
+    }
+
+    return users, cursor, nil;
+}
+
+// Deleted memberships are not reported by Blackboard, so removals will only be seen by full syncs.
+func (this *BlackboardBackend) FetchUsersChangedSince(cursor string) ([]*lmstypes.User, string, error) {
+    since, err := time.Parse(time.RFC3339, cursor);
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Failed to parse cursor '%s': '%w'.", cursor, err);
+    }
+
+    newCursor := newCursor();
+
+    endpoint := fmt.Sprintf(
+        "/learn/api/public/v1/courses/%s/users?expand=user&limit=%d&modified=%s&modifiedCompare=greaterOrEqual",
+        this.CourseID, PAGE_SIZE, url.QueryEscape(since.UTC().Format(time.RFC3339)));
+
+    memberships, err := fetchAll[Membership](this, endpoint);
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Failed to fetch user changes: '%w'.", err);
+    }
+
+    users := make([]*lmstypes.User, 0, len(memberships));
+    for _, membership := range memberships {
+        users = append(users, membership.ToLMSType());
+    }
+
+    return users, newCursor, nil;
+}
+
+func (this *BlackboardBackend) fetchMemberships() ([]*Membership, error) {
+    endpoint := fmt.Sprintf(
+        "/learn/api/public/v1/courses/%s/users?expand=user&limit=%d",
+        this.CourseID, PAGE_SIZE);
+
+    memberships, err := fetchAll[Membership](this, endpoint);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch users: '%w'.", err);
+    }
+
+    return memberships, nil;
+}
+
+// The cursor overlaps with the previous fetch in case the clocks of the autograder and Blackboard do not agree.
+func newCursor() string {
+    return time.Now().Add(-CURSOR_OVERLAP).UTC().Format(time.RFC3339);
+}
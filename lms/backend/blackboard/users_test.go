@@ -0,0 +1,108 @@
+package blackboard
+
+import (
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+// Users are split over two pages.
+var testUsers []*lmstypes.User = []*lmstypes.User{
+    &lmstypes.User{ID: "_10_1", Name: "owner user", Email: "owner@test.com", Role: model.RoleOwner},
+    &lmstypes.User{ID: "_20_1", Name: "admin user", Email: "admin@test.com", Role: model.RoleAdmin},
+    &lmstypes.User{ID: "_30_1", Name: "grader user", Email: "grader@test.com", Role: model.RoleGrader},
+    &lmstypes.User{ID: "_40_1", Name: "student user", Email: "student@test.com", Role: model.RoleStudent},
+    &lmstypes.User{ID: "_50_1", Name: "other user", Email: "other@test.com", Role: model.RoleOther},
+};
+
+func TestBlackboardUserGetBase(test *testing.T) {
+    for i, expected := range testUsers {
+        user, err := testBackend.FetchUser(expected.Email);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to fetch user: '%v'.", i, err);
+            continue;
+        }
+
+        if ((user == nil) || (*expected != *user)) {
+            test.Errorf("Case %d: User not as expected. Expected: '%+v', Actual: '%+v'.", i, expected, user);
+            continue;
+        }
+    }
+}
+
+func TestBlackboardUserGetMissing(test *testing.T) {
+    user, err := testBackend.FetchUser("ZZZ@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch user: '%v'.", err);
+    }
+
+    if (user != nil) {
+        test.Fatalf("Found a user that should not exist: '%+v'.", user);
+    }
+}
+
+func TestBlackboardUsersGetBase(test *testing.T) {
+    users, err := testBackend.FetchUsers();
+    if (err != nil) {
+        test.Fatalf("Failed to fetch users: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(testUsers, users)) {
+        test.Fatalf("Users not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(testUsers), util.MustToJSONIndent(users));
+    }
+}
+
+func TestBlackboardErrors(test *testing.T) {
+    badCourseBackend, err := NewBackend("_99999_1", TEST_APP_KEY, TEST_APP_SECRET, testServer.URL());
+    if (err != nil) {
+        test.Fatalf("Failed to create backend: '%v'.", err);
+    }
+
+    // Use a different key so a cached token is not reused.
+    badCredentialsBackend, err := NewBackend(TEST_COURSE_ID, "ZZZ", TEST_APP_SECRET, testServer.URL());
+    if (err != nil) {
+        test.Fatalf("Failed to create backend: '%v'.", err);
+    }
+
+    for i, backend := range []*BlackboardBackend{badCourseBackend, badCredentialsBackend} {
+        _, err = backend.FetchUsers();
+        if (err == nil) {
+            test.Errorf("Case %d: Did not get an error.", i);
+        }
+    }
+}
+
+func TestBlackboardUsersChangedSinceBase(test *testing.T) {
+    expected := []*lmstypes.User{
+        &lmstypes.User{ID: "_40_1", Name: "student user", Email: "student@test.com", Role: model.RoleGrader},
+    };
+
+    users, cursor, err := testBackend.FetchUsersChangedSince("2023-10-01T00:00:00Z");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch user changes: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected, users)) {
+        test.Fatalf("Users not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(expected), util.MustToJSONIndent(users));
+    }
+
+    cursorTime, err := time.Parse(time.RFC3339, cursor);
+    if (err != nil) {
+        test.Fatalf("New cursor is not a time: '%v'.", err);
+    }
+
+    if (cursorTime.After(time.Now())) {
+        test.Fatalf("New cursor is in the future: '%s'.", cursor);
+    }
+
+    _, _, err = testBackend.FetchUsersChangedSince("ZZZ");
+    if (err == nil) {
+        test.Fatalf("Did not get an error on a bad cursor.");
+    }
+}
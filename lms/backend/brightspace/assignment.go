@@ -0,0 +1,39 @@
+package brightspace
+
+import (
+    "fmt"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+)
+
+// Assignments are grade objects.
+func (this *BrightspaceBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+    endpoint := fmt.Sprintf("/d2l/api/le/%s/%s/grades/%s", LE_VERSION, this.CourseID, assignmentID);
+
+    var gradeObject GradeObject;
+    err := this.get(endpoint, &gradeObject);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignment: '%w'.", err);
+    }
+
+    return gradeObject.ToLMSType(this.CourseID), nil;
+}
+
+func (this *BrightspaceBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+    endpoint := fmt.Sprintf("/d2l/api/le/%s/%s/grades/", LE_VERSION, this.CourseID);
+
+    var gradeObjects []*GradeObject;
+    err := this.get(endpoint, &gradeObjects);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignments: '%w'.", err);
+    }
+
+    assignments := make([]*lmstypes.Assignment, 0, len(gradeObjects));
+    for _, gradeObject := range gradeObjects {
+        if (gradeObject != nil) {
+            assignments = append(assignments, gradeObject.ToLMSType(this.CourseID));
+        }
+    }
+
+    return assignments, nil;
+}
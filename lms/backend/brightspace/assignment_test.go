@@ -0,0 +1,56 @@
+package brightspace
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+// Grade objects do not have due dates.
+var testAssignments []*lmstypes.Assignment = []*lmstypes.Assignment{
+    &lmstypes.Assignment{
+        ID: TEST_ASSIGNMENT_ID,
+        Name: "Homework 0",
+        LMSCourseID: TEST_COURSE_ID,
+        DueDate: nil,
+        MaxPoints: 100.0,
+    },
+    &lmstypes.Assignment{
+        ID: "98766",
+        Name: "Participation",
+        LMSCourseID: TEST_COURSE_ID,
+        DueDate: nil,
+        MaxPoints: 10.0,
+    },
+};
+
+func TestBrightspaceFetchAssignmentBase(test *testing.T) {
+    assignment, err := testBackend.FetchAssignment(TEST_ASSIGNMENT_ID);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(testAssignments[0], assignment)) {
+        test.Fatalf("Assignment not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(testAssignments[0]), util.MustToJSONIndent(assignment));
+    }
+
+    _, err = testBackend.FetchAssignment("ZZZ");
+    if (err == nil) {
+        test.Fatalf("Did not get an error when fetching a missing assignment.");
+    }
+}
+
+func TestBrightspaceFetchAssignmentsBase(test *testing.T) {
+    assignments, err := testBackend.FetchAssignments();
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignments: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(testAssignments, assignments)) {
+        test.Fatalf("Assignments not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(testAssignments), util.MustToJSONIndent(assignments));
+    }
+}
@@ -0,0 +1,52 @@
+package brightspace
+
+// A backend for D2L Brightspace's Valence API.
+// Requests are signed with ID-key authentication:
+// an application ID/key (registered with the Brightspace instance)
+// and a user ID/key (from a service account that has authorized the application).
+
+import (
+    "fmt"
+    "strings"
+)
+
+type BrightspaceBackend struct {
+    // The org unit ID of the course offering.
+    CourseID string
+    AppID string
+    AppKey string
+    UserID string
+    UserKey string
+    BaseURL string
+}
+
+func NewBackend(brightspaceCourseID string, appID string, appKey string, userID string, userKey string, baseURL string) (*BrightspaceBackend, error) {
+    if (brightspaceCourseID == "") {
+        return nil, fmt.Errorf("Brightspace course ID (course-id) cannot be empty.");
+    }
+
+    if ((appID == "") || (appKey == "")) {
+        return nil, fmt.Errorf("Brightspace application ID (app-key) and key (app-secret) cannot be empty.");
+    }
+
+    if ((userID == "") || (userKey == "")) {
+        return nil, fmt.Errorf("Brightspace user ID (user-id) and key (user-key) cannot be empty.");
+    }
+
+    if (baseURL == "") {
+        return nil, fmt.Errorf("Brightspace base URL (base-url) cannot be empty.");
+    }
+
+    baseURL = strings.TrimSuffix(baseURL, "/");
+
+    backend := BrightspaceBackend{
+        CourseID: brightspaceCourseID,
+        AppID: appID,
+        AppKey: appKey,
+        UserID: userID,
+        UserKey: userKey,
+        BaseURL: baseURL,
+    };
+
+    return &backend, nil;
+}
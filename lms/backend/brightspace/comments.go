@@ -0,0 +1,54 @@
+package brightspace
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+)
+
+func (this *BrightspaceBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+    for i, comment := range comments {
+        if (i != 0) {
+            time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
+        }
+
+        err := this.UpdateComment(assignmentID, comment);
+        if (err != nil) {
+            return fmt.Errorf("Failed on comment %d: '%w'.", i, err);
+        }
+    }
+
+    return nil;
+}
+
+// The comment's author is the ID of the graded user (see GradeValue.ToLMSType()).
+// Brightspace sets the grade and comments together,
+// so the current grade value is fetched to keep the score and private comments.
+func (this *BrightspaceBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+    current, err := this.fetchGradeValue(assignmentID, comment.Author);
+    if (err != nil) {
+        return fmt.Errorf("Failed to fetch the current grade for comment: '%w'.", err);
+    }
+
+    value := IncomingGradeValue{
+        Comments: textInput(comment.Text),
+        PrivateComments: textInput(""),
+        GradeObjectType: GRADE_OBJECT_TYPE_NUMERIC,
+    };
+
+    if (current.PointsNumerator != nil) {
+        value.PointsNumerator = *current.PointsNumerator;
+    }
+
+    if (current.PrivateComments != nil) {
+        value.PrivateComments = textInput(current.PrivateComments.Text);
+    }
+
+    err = this.updateGradeValue(assignmentID, comment.Author, &value);
+    if (err != nil) {
+        return fmt.Errorf("Failed to update comment: '%w'.", err);
+    }
+
+    return nil;
+}
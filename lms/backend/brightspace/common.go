@@ -0,0 +1,185 @@
+package brightspace
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/base64"
+    "fmt"
+    "net/url"
+    "strings"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    // API versions for the Learning Platform (users, enrollments) and Learning Environment (grades).
+    LP_VERSION = "1.31";
+    LE_VERSION = "1.61";
+
+    UPLOAD_SLEEP_TIME_SEC = int64(0.5 * float64(time.Second));
+)
+
+// A page of results that uses a bookmark to continue (e.g. enrollments).
+type pagedResultSet[T any] struct {
+    PagingInfo struct {
+        Bookmark string `json:"Bookmark"`
+        HasMoreItems bool `json:"HasMoreItems"`
+    } `json:"PagingInfo"`
+    Items []*T `json:"Items"`
+}
+
+// A page of results that links to the next page (e.g. grade values).
+type objectListPage[T any] struct {
+    // An absolute URL, null on the last page.
+    Next *string `json:"Next"`
+    Objects []*T `json:"Objects"`
+}
+
+// Sign a request using ID-key authentication.
+// The signature covers the method, the (lowercase) path, and a timestamp,
+// and is signed once with the application key and once with the user key.
+func (this *BrightspaceBackend) authParams(method string, path string) url.Values {
+    timestamp := fmt.Sprintf("%d", time.Now().Unix());
+    base := strings.ToUpper(method) + "&" + strings.ToLower(path) + "&" + timestamp;
+
+    params := url.Values{};
+    params.Set("x_a", this.AppID);
+    params.Set("x_b", this.UserID);
+    params.Set("x_c", signature(this.AppKey, base));
+    params.Set("x_d", signature(this.UserKey, base));
+    params.Set("x_t", timestamp);
+
+    return params;
+}
+
+func signature(key string, base string) string {
+    mac := hmac.New(sha256.New, []byte(key));
+    mac.Write([]byte(base));
+    return base64.RawURLEncoding.EncodeToString(mac.Sum(nil));
+}
+
+// Get a full, signed URL for an endpoint (a path with an optional query).
+func (this *BrightspaceBackend) signedURL(method string, endpoint string) (string, error) {
+    uri, err := url.Parse(this.BaseURL + endpoint);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to parse URL for endpoint '%s': '%w'.", endpoint, err);
+    }
+
+    query := uri.Query();
+    for key, values := range this.authParams(method, uri.Path) {
+        query[key] = values;
+    }
+
+    uri.RawQuery = query.Encode();
+
+    return uri.String(), nil;
+}
+
+func (this *BrightspaceBackend) get(endpoint string, result any) error {
+    uri, err := this.signedURL("GET", endpoint);
+    if (err != nil) {
+        return err;
+    }
+
+    body, _, err := common.GetWithHeaders(uri, nil);
+    if (err != nil) {
+        return err;
+    }
+
+    err = util.JSONFromString(body, result);
+    if (err != nil) {
+        return fmt.Errorf("Failed to unmarshal response from '%s': '%w'.", endpoint, err);
+    }
+
+    return nil;
+}
+
+func (this *BrightspaceBackend) put(endpoint string, payload any) error {
+    uri, err := this.signedURL("PUT", endpoint);
+    if (err != nil) {
+        return err;
+    }
+
+    _, _, err = common.SendJSONWithHeaders("PUT", uri, payload, nil);
+    return err;
+}
+
+// Fetch all the items of a bookmark-paged endpoint.
+func fetchAllPaged[T any](this *BrightspaceBackend, endpoint string) ([]*T, error) {
+    results := make([]*T, 0);
+    bookmark := "";
+
+    for {
+        pageEndpoint := endpoint;
+        if (bookmark != "") {
+            pageEndpoint += "?bookmark=" + url.QueryEscape(bookmark);
+        }
+
+        var page pagedResultSet[T];
+        err := this.get(pageEndpoint, &page);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        for _, item := range page.Items {
+            if (item != nil) {
+                results = append(results, item);
+            }
+        }
+
+        if (!page.PagingInfo.HasMoreItems || (page.PagingInfo.Bookmark == "")) {
+            break;
+        }
+
+        bookmark = page.PagingInfo.Bookmark;
+    }
+
+    return results, nil;
+}
+
+// Fetch all the objects of an object list endpoint.
+func fetchAllObjects[T any](this *BrightspaceBackend, endpoint string) ([]*T, error) {
+    results := make([]*T, 0);
+
+    for (endpoint != "") {
+        var page objectListPage[T];
+        err := this.get(endpoint, &page);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        for _, object := range page.Objects {
+            if (object != nil) {
+                results = append(results, object);
+            }
+        }
+
+        endpoint = "";
+        if ((page.Next != nil) && (*page.Next != "")) {
+            endpoint, err = relativeEndpoint(*page.Next);
+            if (err != nil) {
+                return nil, err;
+            }
+        }
+    }
+
+    return results, nil;
+}
+
+// Next links are absolute (and may use a different host than the configured one),
+// so only keep the path and query so the request can be sent to our base URL and re-signed.
+func relativeEndpoint(next string) (string, error) {
+    uri, err := url.Parse(next);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to parse next page URL '%s': '%w'.", next, err);
+    }
+
+    endpoint := uri.Path;
+    if (uri.RawQuery != "") {
+        endpoint += "?" + uri.RawQuery;
+    }
+
+    return endpoint, nil;
+}
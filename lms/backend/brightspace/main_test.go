@@ -0,0 +1,82 @@
+package brightspace
+
+import (
+    "embed"
+    "io/fs"
+    "net/http"
+    "os"
+    "strings"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/lms/backend/testhttp"
+)
+
+const (
+    TEST_COURSE_ID = "12345"
+    TEST_ASSIGNMENT_ID = "98765"
+    TEST_APP_ID = "APP-ID"
+    TEST_APP_KEY = "APP-KEY"
+    TEST_USER_ID = "USER-ID"
+    TEST_USER_KEY = "USER-KEY"
+)
+
+//go:embed testdata/http
+var httpDataDir embed.FS;
+
+var testServer *testhttp.Server;
+var testBackend *BrightspaceBackend;
+
+func TestMain(suite *testing.M) {
+    // Run inside a func so defers will run before os.Exit().
+    code := func() int {
+        db.PrepForTestingMain();
+        defer db.CleanupTestingMain();
+
+        dataDir, err := fs.Sub(httpDataDir, "testdata/http");
+        if (err != nil) {
+            panic(err);
+        }
+
+        options := testhttp.Options{
+            // Authentication parameters are not part of the key (the signatures change with time).
+            IgnoredParams: []string{"x_a", "x_b", "x_c", "x_d", "x_t"},
+            CheckRequest: checkRequest,
+        };
+
+        testServer, err = testhttp.Start(dataDir, options);
+        if (err != nil) {
+            panic(err);
+        }
+        defer testServer.Close();
+
+        testBackend, err = NewBackend(TEST_COURSE_ID, TEST_APP_ID, TEST_APP_KEY, TEST_USER_ID, TEST_USER_KEY, testServer.URL());
+        if (err != nil) {
+            panic(err);
+        }
+
+        return suite.Run();
+    }();
+
+    os.Exit(code);
+}
+
+// Check the ID-key signatures (see BrightspaceBackend.authParams()).
+func checkRequest(response http.ResponseWriter, request *http.Request) bool {
+    query := request.URL.Query();
+    base := request.Method + "&" + strings.ToLower(request.URL.Path) + "&" + query.Get("x_t");
+
+    authorized := ((query.Get("x_a") == TEST_APP_ID) && (query.Get("x_b") == TEST_USER_ID) &&
+            (query.Get("x_c") == signature(TEST_APP_KEY, base)) && (query.Get("x_d") == signature(TEST_USER_KEY, base)));
+
+    if (!authorized) {
+        response.WriteHeader(http.StatusForbidden);
+        response.Write([]byte(`Invalid token`));
+    }
+
+    return authorized;
+}
+
+func getPutBody(path string) map[string]any {
+    return testServer.GetJSONBody("PUT", path);
+}
@@ -0,0 +1,138 @@
+package brightspace
+
+import (
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+const GRADE_OBJECT_TYPE_NUMERIC = 1;
+
+// A user enrolled in an org unit.
+type OrgUnitUser struct {
+    User struct {
+        Identifier string `json:"Identifier"`
+        DisplayName string `json:"DisplayName"`
+        EmailAddress string `json:"EmailAddress"`
+    } `json:"User"`
+    Role struct {
+        ID int64 `json:"Id"`
+        Code string `json:"Code"`
+        Name string `json:"Name"`
+    } `json:"Role"`
+}
+
+type GradeObject struct {
+    ID int64 `json:"Id"`
+    Name string `json:"Name"`
+    MaxPoints float64 `json:"MaxPoints"`
+}
+
+type RichText struct {
+    Text string `json:"Text"`
+    HTML string `json:"Html"`
+}
+
+type GradeValue struct {
+    PointsNumerator *float64 `json:"PointsNumerator"`
+    Comments *RichText `json:"Comments"`
+    PrivateComments *RichText `json:"PrivateComments"`
+    LastModified *time.Time `json:"LastModified"`
+}
+
+type UserGradeValue struct {
+    User struct {
+        Identifier string `json:"Identifier"`
+    } `json:"User"`
+    GradeValue *GradeValue `json:"GradeValue"`
+}
+
+// Rich text sent to Brightspace.
+type RichTextInput struct {
+    Content string `json:"Content"`
+    Type string `json:"Type"`
+}
+
+// The body for setting a numeric grade.
+type IncomingGradeValue struct {
+    Comments RichTextInput `json:"Comments"`
+    PrivateComments RichTextInput `json:"PrivateComments"`
+    GradeObjectType int `json:"GradeObjectType"`
+    PointsNumerator float64 `json:"PointsNumerator"`
+}
+
+// Brightspace roles are defined per-instance, so they are matched on their (lowercase) name.
+var roleMapping map[string]model.UserRole = map[string]model.UserRole{
+    "student": model.RoleStudent,
+    "learner": model.RoleStudent,
+    "teaching assistant": model.RoleGrader,
+    "ta": model.RoleGrader,
+    "administrator": model.RoleAdmin,
+    "instructor": model.RoleOwner,
+};
+
+func (this *OrgUnitUser) GetRole() model.UserRole {
+    role, ok := roleMapping[strings.ToLower(strings.TrimSpace(this.Role.Name))];
+    if (!ok) {
+        return model.RoleOther;
+    }
+
+    return role;
+}
+
+func (this *OrgUnitUser) ToLMSType() *lmstypes.User {
+    return &lmstypes.User{
+        ID: this.User.Identifier,
+        Name: this.User.DisplayName,
+        Email: this.User.EmailAddress,
+        Role: this.GetRole(),
+    };
+}
+
+// Grade objects do not have due dates.
+func (this *GradeObject) ToLMSType(courseID string) *lmstypes.Assignment {
+    return &lmstypes.Assignment{
+        ID: fmt.Sprintf("%d", this.ID),
+        Name: this.Name,
+        LMSCourseID: courseID,
+        MaxPoints: this.MaxPoints,
+    };
+}
+
+// Brightspace only has a single (public) comment per grade,
+// so the comment's ID and author are both the ID of the graded user
+// (which is what UpdateComment() needs to find the grade again).
+func (this *GradeValue) ToLMSType(userID string) *lmstypes.SubmissionScore {
+    score := &lmstypes.SubmissionScore{
+        UserID: userID,
+        Comments: make([]*lmstypes.SubmissionComment, 0, 1),
+    };
+
+    if (this.PointsNumerator != nil) {
+        score.Score = *this.PointsNumerator;
+    }
+
+    if (this.LastModified != nil) {
+        score.Time = *this.LastModified;
+    }
+
+    if ((this.Comments != nil) && (this.Comments.Text != "")) {
+        score.Comments = append(score.Comments, &lmstypes.SubmissionComment{
+            ID: userID,
+            Author: userID,
+            Text: this.Comments.Text,
+        });
+    }
+
+    return score;
+}
+
+func textInput(text string) RichTextInput {
+    return RichTextInput{
+        Content: text,
+        Type: "Text",
+    };
+}
@@ -0,0 +1,87 @@
+package brightspace
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+)
+
+func (this *BrightspaceBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+    gradeValue, err := this.fetchGradeValue(assignmentID, userID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch score: '%w'.", err);
+    }
+
+    return gradeValue.ToLMSType(userID), nil;
+}
+
+// Users without a grade value are skipped.
+func (this *BrightspaceBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+    endpoint := fmt.Sprintf("/d2l/api/le/%s/%s/grades/%s/values/", LE_VERSION, this.CourseID, assignmentID);
+
+    values, err := fetchAllObjects[UserGradeValue](this, endpoint);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch scores: '%w'.", err);
+    }
+
+    scores := make([]*lmstypes.SubmissionScore, 0, len(values));
+    for _, value := range values {
+        if (value.GradeValue == nil) {
+            continue;
+        }
+
+        scores = append(scores, value.GradeValue.ToLMSType(value.User.Identifier));
+    }
+
+    return scores, nil;
+}
+
+// Brightspace does not have a bulk grade update, so each score is sent individually.
+// A score's comment replaces the existing public comment (a score without a comment clears it).
+func (this *BrightspaceBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+    for i, score := range scores {
+        if (i != 0) {
+            time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
+        }
+
+        if (len(score.Comments) > 1) {
+            return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments));
+        }
+
+        value := IncomingGradeValue{
+            Comments: textInput(""),
+            PrivateComments: textInput(""),
+            GradeObjectType: GRADE_OBJECT_TYPE_NUMERIC,
+            PointsNumerator: score.Score,
+        };
+
+        for _, comment := range score.Comments {
+            value.Comments = textInput(comment.Text);
+        }
+
+        err := this.updateGradeValue(assignmentID, score.UserID, &value);
+        if (err != nil) {
+            return fmt.Errorf("Failed to upload score for user '%s': '%w'.", score.UserID, err);
+        }
+    }
+
+    return nil;
+}
+
+func (this *BrightspaceBackend) fetchGradeValue(assignmentID string, userID string) (*GradeValue, error) {
+    endpoint := fmt.Sprintf("/d2l/api/le/%s/%s/grades/%s/values/%s", LE_VERSION, this.CourseID, assignmentID, userID);
+
+    var gradeValue GradeValue;
+    err := this.get(endpoint, &gradeValue);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return &gradeValue, nil;
+}
+
+func (this *BrightspaceBackend) updateGradeValue(assignmentID string, userID string, value *IncomingGradeValue) error {
+    endpoint := fmt.Sprintf("/d2l/api/le/%s/%s/grades/%s/values/%s", LE_VERSION, this.CourseID, assignmentID, userID);
+    return this.put(endpoint, value);
+}
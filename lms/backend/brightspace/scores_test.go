@@ -0,0 +1,124 @@
+package brightspace
+
+import (
+    "fmt"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const TEST_COMMENT = "{\n\"id\": \"course101::hw0::student@test.com::1696364768\",\n\"raw-score\": 100,\n\"score\": 100,\n\"lock\": false,\n\"__autograder__v01__\": 0\n}";
+
+var testGradedTime time.Time = time.Date(2023, time.October, 3, 20, 26, 8, 0, time.UTC);
+
+var testStudentScore lmstypes.SubmissionScore = lmstypes.SubmissionScore{
+    UserID: "40",
+    Score: 100.0,
+    Time: testGradedTime,
+    Comments: []*lmstypes.SubmissionComment{
+        &lmstypes.SubmissionComment{
+            ID: "40",
+            Author: "40",
+            Text: TEST_COMMENT,
+        },
+    },
+};
+
+var testGraderScore lmstypes.SubmissionScore = lmstypes.SubmissionScore{
+    UserID: "30",
+    Score: 80.5,
+    Time: testGradedTime,
+    Comments: []*lmstypes.SubmissionComment{},
+};
+
+func TestBrightspaceFetchAssignmentScoreBase(test *testing.T) {
+    score, err := testBackend.FetchAssignmentScore(TEST_ASSIGNMENT_ID, "40");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment score: '%v'.", err);
+    }
+
+    expectedJSON := util.MustToJSONIndent(testStudentScore);
+    actualJSON := util.MustToJSONIndent(score);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Score not as expected. Expected: '%s', Actual: '%s'.", expectedJSON, actualJSON);
+    }
+}
+
+// Scores are split over two pages (the next link is on another host),
+// and users without a grade value are skipped.
+func TestBrightspaceFetchAssignmentScoresBase(test *testing.T) {
+    scores, err := testBackend.FetchAssignmentScores(TEST_ASSIGNMENT_ID);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment scores: '%v'.", err);
+    }
+
+    expectedJSON := util.MustToJSONIndent([]*lmstypes.SubmissionScore{&testStudentScore, &testGraderScore});
+    actualJSON := util.MustToJSONIndent(scores);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Scores not as expected. Expected: '%s', Actual: '%s'.", expectedJSON, actualJSON);
+    }
+}
+
+func TestBrightspaceUpdateAssignmentScoresBase(test *testing.T) {
+    scores := []*lmstypes.SubmissionScore{
+        &testStudentScore,
+        &testGraderScore,
+    };
+
+    err := testBackend.UpdateAssignmentScores(TEST_ASSIGNMENT_ID, scores);
+    if (err != nil) {
+        test.Fatalf("Failed to update assignment scores: '%v'.", err);
+    }
+
+    expected := map[string]map[string]any{
+        "40": gradeBody(100.0, TEST_COMMENT, ""),
+        "30": gradeBody(80.5, "", ""),
+    };
+
+    for userID, expectedBody := range expected {
+        body := getPutBody(gradePath(userID));
+        if (!reflect.DeepEqual(expectedBody, body)) {
+            test.Errorf("Unexpected body for user '%s'. Expected: '%v', Actual: '%v'.", userID, expectedBody, body);
+        }
+    }
+}
+
+func TestBrightspaceUpdateCommentBase(test *testing.T) {
+    comment := &lmstypes.SubmissionComment{
+        ID: "40",
+        Author: "40",
+        Text: "New Comment",
+    };
+
+    err := testBackend.UpdateComments(TEST_ASSIGNMENT_ID, []*lmstypes.SubmissionComment{comment});
+    if (err != nil) {
+        test.Fatalf("Failed to update comment: '%v'.", err);
+    }
+
+    // The existing score and private comments are kept.
+    expected := gradeBody(100.0, "New Comment", "Private Note");
+
+    body := getPutBody(gradePath("40"));
+    if (!reflect.DeepEqual(expected, body)) {
+        test.Fatalf("Unexpected body. Expected: '%v', Actual: '%v'.", expected, body);
+    }
+}
+
+func gradePath(userID string) string {
+    return fmt.Sprintf("/d2l/api/le/%s/%s/grades/%s/values/%s", LE_VERSION, TEST_COURSE_ID, TEST_ASSIGNMENT_ID, userID);
+}
+
+// A grade value as it is decoded from JSON.
+func gradeBody(points float64, comment string, privateComment string) map[string]any {
+    return map[string]any{
+        "Comments": map[string]any{"Content": comment, "Type": "Text"},
+        "PrivateComments": map[string]any{"Content": privateComment, "Type": "Text"},
+        "GradeObjectType": 1.0,
+        "PointsNumerator": points,
+    };
+}
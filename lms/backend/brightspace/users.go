@@ -0,0 +1,48 @@
+package brightspace
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+)
+
+func (this *BrightspaceBackend) FetchUsers() ([]*lmstypes.User, error) {
+    endpoint := fmt.Sprintf("/d2l/api/lp/%s/enrollments/orgUnits/%s/users/", LP_VERSION, this.CourseID);
+
+    orgUnitUsers, err := fetchAllPaged[OrgUnitUser](this, endpoint);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch users: '%w'.", err);
+    }
+
+    users := make([]*lmstypes.User, 0, len(orgUnitUsers));
+    for _, orgUnitUser := range orgUnitUsers {
+        users = append(users, orgUnitUser.ToLMSType());
+    }
+
+    return users, nil;
+}
+
+// Enrollments cannot be searched by email, so all users are fetched and filtered.
+func (this *BrightspaceBackend) FetchUser(email string) (*lmstypes.User, error) {
+    users, err := this.FetchUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err);
+    }
+
+    matches := make([]*lmstypes.User, 0, 1);
+    for _, user := range users {
+        if (strings.EqualFold(user.Email, email)) {
+            matches = append(matches, user);
+        }
+    }
+
+    if (len(matches) != 1) {
+        log.Warn().Str("email", email).Int("num-results", len(matches)).Msg("Did not find exactly one matching user in brightspace.");
+        return nil, nil;
+    }
+
+    return matches[0], nil;
+}
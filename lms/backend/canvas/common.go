@@ -13,8 +13,14 @@ const (
     POST_PAGE_SIZE int = 75;
     HEADER_LINK string = "Link";
     UPLOAD_SLEEP_TIME_SEC = int64(0.5 * float64(time.Second));
+
+    // How far back a new cursor is set (to cover clock differences between the autograder and Canvas).
+    CURSOR_OVERLAP = 5 * time.Minute;
 )
 
+// All enrollment states (Canvas only returns deleted and inactive enrollments when they are asked for).
+var ALL_ENROLLMENT_STATES []string = []string{"active", "invited", "creation_pending", "deleted", "rejected", "completed", "inactive"};
+
 // Lock for each API token being used.
 // Note that it is possible to have multiple backends with the same token.
 // {string: *sync.Mutex}.
@@ -4,17 +4,12 @@ import (
     "embed"
     "fmt"
     "io/fs"
-    "net/http"
-    "net/http/httptest"
-    "net/url"
     "os"
-    "strings"
     "testing"
     "time"
 
-    "github.com/eriq-augustine/autograder/common"
     "github.com/eriq-augustine/autograder/db"
-    "github.com/eriq-augustine/autograder/util"
+    "github.com/eriq-augustine/autograder/lms/backend/testhttp"
 )
 
 const (
@@ -23,135 +18,38 @@ const (
     TEST_TOKEN = "ABC123"
 )
 
-var server *httptest.Server;
-var serverURL string;
-
 //go:embed testdata/http
 var httpDataDir embed.FS;
 
+var testServer *testhttp.Server;
 var testBackend *CanvasBackend;
 
 func TestMain(suite *testing.M) {
-    var err error;
-
     // Run inside a func so defers will run before os.Exit().
     code := func() int {
         db.PrepForTestingMain();
         defer db.CleanupTestingMain();
 
-        err = startTestServer();
+        dataDir, err := fs.Sub(httpDataDir, "testdata/http");
         if (err != nil) {
             panic(err);
         }
-        defer stopTestServer();
 
-        testBackend, err = NewBackend(TEST_COURSE_ID, TEST_TOKEN, serverURL);
+        testServer, err = testhttp.Start(dataDir, testhttp.Options{});
         if (err != nil) {
             panic(err);
         }
+        defer testServer.Close();
 
-        return suite.Run();
-    }();
-
-    os.Exit(code);
-}
-
-func startTestServer() error {
-    if (server != nil) {
-        return fmt.Errorf("Test server already started.");
-    }
-
-    requests, err := loadRequests();
-    if (err != nil) {
-        return err;
-    }
-
-    server = httptest.NewServer(makeHandler(requests));
-    serverURL = server.URL;
-
-    return nil;
-}
-
-func makeHandler(requests map[string]*common.SavedHTTPRequest) http.Handler {
-    return &testCanvasHandler{requests};
-}
-
-type testCanvasHandler struct {
-    requests map[string]*common.SavedHTTPRequest
-}
-
-func (this *testCanvasHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
-    key := fmt.Sprintf("%s::%s?%s", request.Method, request.URL.Path, request.URL.RawQuery);
-    savedRequest := this.requests[key];
-    if (savedRequest == nil) {
-        fmt.Printf("ERROR 404: '%s'.\n", key);
-        http.NotFound(response, request);
-        return;
-    }
-
-    for key, value := range savedRequest.ResponseHeaders {
-        response.Header()[key] = value;
-    }
-
-    response.WriteHeader(savedRequest.ResponseCode);
-    _, err := response.Write([]byte(savedRequest.ResponseBody));
-    if (err != nil) {
-        panic(err);
-    }
-}
-
-func loadRequests() (map[string]*common.SavedHTTPRequest, error) {
-    requests := make(map[string]*common.SavedHTTPRequest);
-
-    err := fs.WalkDir(httpDataDir, ".", func(path string, info fs.DirEntry, err error) error {
-        if (err != nil) {
-            return err;
-        }
-
-        if (info.IsDir()) {
-            return nil;
-        }
-
-        if (!strings.HasSuffix(info.Name(), ".json")) {
-            return nil;
-        }
-
-        data, err := httpDataDir.ReadFile(path);
+        testBackend, err = NewBackend(TEST_COURSE_ID, TEST_TOKEN, testServer.URL());
         if (err != nil) {
-            return fmt.Errorf("Failed to read embedded test file '%s': '%w'.", path, err);
-        }
-
-        var request common.SavedHTTPRequest;
-        err = util.JSONFromString(string(data), &request);
-        if (err != nil) {
-            return fmt.Errorf("Failed to JSON parse test file '%s': '%w'.", path, err);
-        }
-
-        uri, err := url.Parse(request.URL);
-        if (err != nil) {
-            return fmt.Errorf("Failed to parse test URL '%s': '%w'.", request.URL, err);
+            panic(err);
         }
 
-        key := fmt.Sprintf("%s::%s?%s", request.Method, uri.Path, uri.RawQuery);
-        requests[key] = &request;
-
-        return nil;
-    });
-
-    if (err != nil) {
-        return nil, fmt.Errorf("Failed to walk embeded test dir: '%w'.", err);
-    }
-
-    return requests, nil;
-}
-
-func stopTestServer() {
-    if (server != nil) {
-        server.Close();
+        return suite.Run();
+    }();
 
-        server = nil;
-        serverURL = "";
-    }
+    os.Exit(code);
 }
 
 func mustParseTime(text string) *time.Time {
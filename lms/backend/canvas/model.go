@@ -41,8 +41,18 @@ type Enrollment struct {
     Type string `json:"type"`
     EnrollmentState string `json:"enrollment_state"`
     Role string `json:"role"`
+    UpdatedAt *time.Time `json:"updated_at"`
+
+    // Only included when fetching enrollments directly.
+    User *User `json:"user"`
 }
 
+// Enrollment states where the user is still in the course.
+var activeEnrollmentStates map[string]bool = map[string]bool{
+    "active": true,
+    "invited": true,
+};
+
 // Canvas enrollment to autograder role.
 // Canvas has default enrollment "types" and then "roles" which may be the same
 // as the type or custom.
@@ -65,6 +75,10 @@ var roleToEnrollmentMapping map[model.UserRole]string = map[model.UserRole]strin
     model.RoleOwner: "TeacherEnrollment",
 };
 
+func (this *Enrollment) IsActive() bool {
+    return activeEnrollmentStates[this.EnrollmentState];
+}
+
 func (this *Enrollment) GetRole() model.UserRole {
     typeRole := enrollmentToRoleMapping[this.Type];
     roleRole := enrollmentToRoleMapping[this.Role];
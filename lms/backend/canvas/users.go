@@ -3,6 +3,8 @@ package canvas
 import (
     "fmt"
     neturl "net/url"
+    "strings"
+    "time"
 
     "github.com/rs/zerolog/log"
 
@@ -91,3 +93,155 @@ func (this *CanvasBackend) FetchUser(email string) (*lmstypes.User, error) {
 
     return pageUsers[0].ToLMSType(), nil;
 }
+
+// Cursors are times (see FetchUsersChangedSince()).
+func (this *CanvasBackend) FetchUsersWithCursor() ([]*lmstypes.User, string, error) {
+    cursor := newCursor();
+
+    users, err := this.FetchUsers();
+    if (err != nil) {
+        return nil, "", err;
+    }
+
+    return users, cursor, nil;
+}
+
+// Canvas cannot filter enrollments by when they were updated,
+// so all of the course's enrollments (in every state) are fetched and filtered by their update time.
+// This is a single listing of light-weight enrollments, and (unlike the users listing)
+// it includes removed enrollments, so removals are seen without a full sync.
+// A user is returned if any of their enrollments changed,
+// and is marked as removed if they no longer have any active enrollments.
+func (this *CanvasBackend) FetchUsersChangedSince(cursor string) ([]*lmstypes.User, string, error) {
+    return this.fetchUsersChangedSince(cursor, false);
+}
+
+func (this *CanvasBackend) fetchUsersChangedSince(cursor string, rewriteLinks bool) ([]*lmstypes.User, string, error) {
+    since, err := time.Parse(time.RFC3339, cursor);
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Failed to parse cursor '%s': '%w'.", cursor, err);
+    }
+
+    newCursor := newCursor();
+
+    enrollments, err := this.fetchEnrollments(rewriteLinks);
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Failed to fetch user changes: '%w'.", err);
+    }
+
+    // Group the enrollments by user (keeping the order users were first seen in).
+    userIDs := make([]string, 0);
+    userEnrollments := make(map[string][]*Enrollment);
+
+    for _, enrollment := range enrollments {
+        if (enrollment.User == nil) {
+            log.Warn().Str("enrollment-id", enrollment.ID).Msg("Canvas enrollment does not have a user.");
+            continue;
+        }
+
+        _, ok := userEnrollments[enrollment.User.ID];
+        if (!ok) {
+            userIDs = append(userIDs, enrollment.User.ID);
+        }
+
+        userEnrollments[enrollment.User.ID] = append(userEnrollments[enrollment.User.ID], enrollment);
+    }
+
+    users := make([]*lmstypes.User, 0);
+
+    for _, userID := range userIDs {
+        changed := false;
+        activeEnrollments := make([]Enrollment, 0);
+
+        for _, enrollment := range userEnrollments[userID] {
+            // Enrollments without a time are always considered changed.
+            if ((enrollment.UpdatedAt == nil) || !enrollment.UpdatedAt.Before(since)) {
+                changed = true;
+            }
+
+            if (enrollment.IsActive()) {
+                activeEnrollments = append(activeEnrollments, *enrollment);
+            }
+        }
+
+        if (!changed) {
+            continue;
+        }
+
+        removed := (len(activeEnrollments) == 0);
+
+        // The user's role comes from the enrollments they still have (or their old enrollments if they were removed).
+        canvasUser := *userEnrollments[userID][0].User;
+        canvasUser.Enrollments = activeEnrollments;
+        if (removed) {
+            canvasUser.Enrollments = make([]Enrollment, 0, len(userEnrollments[userID]));
+            for _, enrollment := range userEnrollments[userID] {
+                canvasUser.Enrollments = append(canvasUser.Enrollments, *enrollment);
+            }
+        }
+
+        user := canvasUser.ToLMSType();
+        user.Removed = removed;
+
+        users = append(users, user);
+    }
+
+    return users, newCursor, nil;
+}
+
+func (this *CanvasBackend) fetchEnrollments(rewriteLinks bool) ([]*Enrollment, error) {
+    this.getAPILock();
+    defer this.releaseAPILock();
+
+    states := make([]string, 0, len(ALL_ENROLLMENT_STATES));
+    for _, state := range ALL_ENROLLMENT_STATES {
+        states = append(states, "state[]=" + state);
+    }
+
+    apiEndpoint := fmt.Sprintf(
+        "/api/v1/courses/%s/enrollments?%s&per_page=%d",
+        this.CourseID, strings.Join(states, "&"), PAGE_SIZE);
+    url := this.BaseURL + apiEndpoint;
+
+    headers := this.standardHeaders();
+
+    enrollments := make([]*Enrollment, 0);
+
+    for (url != "") {
+        var err error;
+
+        if (rewriteLinks) {
+            url, err = this.rewriteLink(url);
+            if (err != nil) {
+                return nil, err;
+            }
+        }
+
+        body, responseHeaders, err := common.GetWithHeaders(url, headers);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to fetch enrollments: '%w'.", err);
+        }
+
+        var pageEnrollments []*Enrollment;
+        err = util.JSONFromString(body, &pageEnrollments);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to unmarshal enrollments page: '%w'.", err);
+        }
+
+        for _, enrollment := range pageEnrollments {
+            if (enrollment == nil) {
+                continue;
+            }
+
+            enrollments = append(enrollments, enrollment);
+        }
+
+        url = fetchNextCanvasLink(responseHeaders);
+    }
+
+    return enrollments, nil;
+}
+
+func newCursor() string {
+    return time.Now().Add(-CURSOR_OVERLAP).UTC().Format(time.RFC3339);
+}
@@ -86,3 +86,59 @@ func TestCanvasUsersGetBase(test *testing.T) {
                 util.MustToJSONIndent(expected), util.MustToJSONIndent(users));
     }
 }
+
+func TestCanvasUsersChangedSinceBase(test *testing.T) {
+    expected := []*lmstypes.User{
+        &lmstypes.User{
+            ID: "00040",
+            Name: "student",
+            Email: "student@test.com",
+            Role: model.RoleStudent,
+        },
+        // Only the (inactive) student enrollment changed, the role comes from the remaining teacher enrollment.
+        &lmstypes.User{
+            ID: "00010",
+            Name: "owner",
+            Email: "owner@test.com",
+            Role: model.RoleOwner,
+        },
+        &lmstypes.User{
+            ID: "00050",
+            Name: "removed",
+            Email: "removed@test.com",
+            Role: model.RoleStudent,
+            Removed: true,
+        },
+    };
+
+    users, cursor, err := testBackend.fetchUsersChangedSince("2023-09-01T00:00:00Z", true);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch user changes: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected, users)) {
+        test.Fatalf("Users not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(expected), util.MustToJSONIndent(users));
+    }
+
+    if (cursor == "") {
+        test.Fatalf("Got an empty cursor.");
+    }
+
+    // Nothing has changed since the new cursor.
+    users, _, err = testBackend.fetchUsersChangedSince(cursor, true);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch user changes with new cursor: '%v'.", err);
+    }
+
+    if (len(users) != 0) {
+        test.Fatalf("Got changed users after the new cursor: '%s'.", util.MustToJSONIndent(users));
+    }
+}
+
+func TestCanvasUsersChangedSinceBadCursor(test *testing.T) {
+    _, _, err := testBackend.FetchUsersChangedSince("not a time");
+    if (err == nil) {
+        test.Fatalf("Did not get an error on a bad cursor.");
+    }
+}
@@ -0,0 +1,48 @@
+package lti
+
+import (
+    "fmt"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+)
+
+var lineItemScopes []string = []string{ltitool.SCOPE_LINE_ITEM_READONLY};
+
+func (this *LTIBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+    lineItem, err := this.fetchLineItem(assignmentID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignment: '%w'.", err);
+    }
+
+    return lineItemToLMSType(lineItem, this.CourseID), nil;
+}
+
+func (this *LTIBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+    if (this.LineItemsURL == "") {
+        return nil, fmt.Errorf("No LTI line items URL is known, launch the autograder from the course or set one in the course's config.");
+    }
+
+    lineItems, err := fetchAll[ltitool.LineItem](this, this.LineItemsURL, ltitool.MEDIA_TYPE_LINE_ITEM_CONTAINER, lineItemScopes);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignments: '%w'.", err);
+    }
+
+    assignments := make([]*lmstypes.Assignment, 0, len(lineItems));
+    for _, lineItem := range lineItems {
+        assignments = append(assignments, lineItemToLMSType(lineItem, this.CourseID));
+    }
+
+    return assignments, nil;
+}
+
+// The ID of a line item is its URL.
+func (this *LTIBackend) fetchLineItem(lineItemID string) (*ltitool.LineItem, error) {
+    var lineItem ltitool.LineItem;
+    _, err := this.get(lineItemID, ltitool.MEDIA_TYPE_LINE_ITEM, lineItemScopes, &lineItem);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return &lineItem, nil;
+}
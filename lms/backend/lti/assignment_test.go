@@ -0,0 +1,65 @@
+package lti
+
+import (
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+var testDueDate time.Time = time.Date(2023, time.October, 4, 0, 0, 0, 0, time.UTC);
+
+func getTestAssignments() []*lmstypes.Assignment {
+    return []*lmstypes.Assignment{
+        &lmstypes.Assignment{
+            ID: testPlatform.LineItemsURL() + "/1",
+            Name: "Homework 0",
+            LMSCourseID: ltitool.MOCK_CONTEXT_ID,
+            DueDate: &testDueDate,
+            MaxPoints: 100.0,
+        },
+        &lmstypes.Assignment{
+            ID: testPlatform.LineItemsURL() + "/2",
+            Name: "Participation",
+            LMSCourseID: ltitool.MOCK_CONTEXT_ID,
+            DueDate: nil,
+            MaxPoints: 10.0,
+        },
+    };
+}
+
+func TestLTIFetchAssignmentBase(test *testing.T) {
+    expected := getTestAssignments()[0];
+
+    assignment, err := testBackend.FetchAssignment(expected.ID);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected, assignment)) {
+        test.Fatalf("Assignment not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(expected), util.MustToJSONIndent(assignment));
+    }
+
+    _, err = testBackend.FetchAssignment(testPlatform.LineItemsURL() + "/ZZZ");
+    if (err == nil) {
+        test.Fatalf("Did not get an error when fetching a missing assignment.");
+    }
+}
+
+func TestLTIFetchAssignmentsBase(test *testing.T) {
+    expected := getTestAssignments();
+
+    assignments, err := testBackend.FetchAssignments();
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignments: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected, assignments)) {
+        test.Fatalf("Assignments not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(expected), util.MustToJSONIndent(assignments));
+    }
+}
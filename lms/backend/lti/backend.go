@@ -0,0 +1,44 @@
+package lti
+
+// A backend that uses the services of an LTI 1.3 platform:
+// Assignment and Grade Services (AGS) for assignments and scores,
+// and Names and Role Provisioning Services (NRPS) for users.
+// Assignments are line items, and are identified by the line item's ID (a URL).
+// Users are identified by their LTI user ID.
+
+import (
+    "fmt"
+
+    "github.com/eriq-augustine/autograder/model"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+)
+
+type LTIBackend struct {
+    // The course's ID in the LMS (only used for display).
+    CourseID string
+    Platform *model.LTIPlatform
+    LineItemsURL string
+    MembershipsURL string
+}
+
+func NewBackend(lmsCourseID string, platform *model.LTIPlatform, endpoints *ltitool.ServiceEndpoints) (*LTIBackend, error) {
+    if (platform == nil) {
+        return nil, fmt.Errorf("LTI backend requires LTI platform information (lti).");
+    }
+
+    if (platform.TokenURL == "") {
+        return nil, fmt.Errorf("LTI platform token URL (token-url) cannot be empty.");
+    }
+
+    backend := LTIBackend{
+        CourseID: lmsCourseID,
+        Platform: platform,
+    };
+
+    if (endpoints != nil) {
+        backend.LineItemsURL = endpoints.LineItemsURL;
+        backend.MembershipsURL = endpoints.MembershipsURL;
+    }
+
+    return &backend, nil;
+}
@@ -0,0 +1,53 @@
+package lti
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+)
+
+func (this *LTIBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+    for i, comment := range comments {
+        if (i != 0) {
+            time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
+        }
+
+        err := this.UpdateComment(assignmentID, comment);
+        if (err != nil) {
+            return fmt.Errorf("Failed on comment %d: '%w'.", i, err);
+        }
+    }
+
+    return nil;
+}
+
+// The comment's author is the ID of the graded user (see resultToLMSType()).
+// Scores and comments are sent together, so the current result is fetched to keep the existing score.
+func (this *LTIBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+    result, err := this.fetchResult(assignmentID, comment.Author);
+    if (err != nil) {
+        return fmt.Errorf("Failed to fetch the current score for comment: '%w'.", err);
+    }
+
+    score := ltitool.Score{
+        UserID: comment.Author,
+        Comment: comment.Text,
+        ActivityProgress: ltitool.ACTIVITY_PROGRESS_COMPLETED,
+        GradingProgress: ltitool.GRADING_PROGRESS_NOT_READY,
+    };
+
+    if ((result != nil) && (result.ResultScore != nil)) {
+        score.ScoreGiven = result.ResultScore;
+        score.ScoreMaximum = result.ResultMaximum;
+        score.GradingProgress = ltitool.GRADING_PROGRESS_FULLY_GRADED;
+    }
+
+    err = this.postScore(assignmentID, &score);
+    if (err != nil) {
+        return fmt.Errorf("Failed to update comment: '%w'.", err);
+    }
+
+    return nil;
+}
@@ -0,0 +1,124 @@
+package lti
+
+import (
+    "fmt"
+    "net/url"
+    "regexp"
+    "strings"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const UPLOAD_SLEEP_TIME_SEC = int64(0.5 * float64(time.Second));
+
+const (
+    LINK_REL_NEXT = "next";
+    // NRPS: the memberships that changed since this response.
+    LINK_REL_DIFFERENCES = "differences";
+)
+
+// Matches a link in a Link header: <url>; rel="next".
+var linkRegex *regexp.Regexp = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?([^";,]+)"?`);
+
+func (this *LTIBackend) standardHeaders(scopes []string, accept string) (map[string][]string, error) {
+    token, err := ltitool.GetAccessToken(this.Platform, scopes);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return map[string][]string{
+        "Authorization": []string{"Bearer " + token},
+        "Accept": []string{accept},
+    }, nil;
+}
+
+// Get a service resource.
+// Returns the response's links ({rel: url}), e.g. the next page.
+func (this *LTIBackend) get(uri string, accept string, scopes []string, result any) (map[string]string, error) {
+    headers, err := this.standardHeaders(scopes, accept);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    body, responseHeaders, err := common.GetWithHeaders(uri, headers);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    err = util.JSONFromString(body, result);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to unmarshal response from '%s': '%w'.", uri, err);
+    }
+
+    return getLinks(responseHeaders), nil;
+}
+
+func (this *LTIBackend) post(uri string, contentType string, scopes []string, payload any) error {
+    headers, err := this.standardHeaders(scopes, "application/json");
+    if (err != nil) {
+        return err;
+    }
+
+    headers["Content-Type"] = []string{contentType};
+
+    _, _, err = common.SendJSONWithHeaders("POST", uri, payload, headers);
+    return err;
+}
+
+// Fetch all the items of a paged service endpoint that returns a list.
+func fetchAll[T any](this *LTIBackend, uri string, accept string, scopes []string) ([]*T, error) {
+    results := make([]*T, 0);
+
+    for (uri != "") {
+        var page []*T;
+        links, err := this.get(uri, accept, scopes, &page);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        for _, item := range page {
+            if (item != nil) {
+                results = append(results, item);
+            }
+        }
+
+        uri = links[LINK_REL_NEXT];
+    }
+
+    return results, nil;
+}
+
+func getLinks(headers map[string][]string) map[string]string {
+    links := make(map[string]string);
+
+    for _, value := range headers["Link"] {
+        for _, match := range linkRegex.FindAllStringSubmatch(value, -1) {
+            links[match[2]] = match[1];
+        }
+    }
+
+    return links;
+}
+
+// Get the URL of a sub-resource of a service URL (which may already have a query),
+// e.g. the results of a line item.
+func serviceURL(base string, suffix string, query map[string]string) (string, error) {
+    uri, err := url.Parse(base);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to parse service URL '%s': '%w'.", base, err);
+    }
+
+    uri.Path = strings.TrimSuffix(uri.Path, "/") + suffix;
+
+    values := uri.Query();
+    for key, value := range query {
+        values.Set(key, value);
+    }
+
+    uri.RawQuery = values.Encode();
+
+    return uri.String(), nil;
+}
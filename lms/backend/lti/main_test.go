@@ -0,0 +1,45 @@
+package lti
+
+import (
+    "os"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/db"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+)
+
+const TEST_CLIENT_ID = "autograder-test";
+
+var testPlatform *ltitool.MockPlatform;
+var testBackend *LTIBackend;
+
+func TestMain(suite *testing.M) {
+    var err error;
+
+    // Run inside a func so defers will run before os.Exit().
+    code := func() int {
+        // The tool key is stored in the (temp) work dir.
+        db.PrepForTestingMain();
+        defer db.CleanupTestingMain();
+
+        testPlatform, err = ltitool.NewMockPlatform(TEST_CLIENT_ID);
+        if (err != nil) {
+            panic(err);
+        }
+        defer testPlatform.Close();
+
+        endpoints := &ltitool.ServiceEndpoints{
+            LineItemsURL: testPlatform.LineItemsURL(),
+            MembershipsURL: testPlatform.MembershipsURL(),
+        };
+
+        testBackend, err = NewBackend(ltitool.MOCK_CONTEXT_ID, testPlatform.Platform(), endpoints);
+        if (err != nil) {
+            panic(err);
+        }
+
+        return suite.Run();
+    }();
+
+    os.Exit(code);
+}
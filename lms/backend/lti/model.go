@@ -0,0 +1,49 @@
+package lti
+
+import (
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+)
+
+func memberToLMSType(member *ltitool.Member) *lmstypes.User {
+    return &lmstypes.User{
+        ID: member.UserID,
+        Name: member.Name,
+        Email: member.Email,
+        Role: ltitool.GetRole(member.Roles),
+    };
+}
+
+func lineItemToLMSType(lineItem *ltitool.LineItem, courseID string) *lmstypes.Assignment {
+    return &lmstypes.Assignment{
+        ID: lineItem.ID,
+        Name: lineItem.Label,
+        LMSCourseID: courseID,
+        DueDate: lineItem.EndDateTime,
+        MaxPoints: lineItem.ScoreMaximum,
+    };
+}
+
+// A result only has a single comment,
+// so the comment's ID and author are both the ID of the graded user
+// (which is what UpdateComment() needs to find the result again).
+func resultToLMSType(result *ltitool.Result) *lmstypes.SubmissionScore {
+    score := &lmstypes.SubmissionScore{
+        UserID: result.UserID,
+        Comments: make([]*lmstypes.SubmissionComment, 0, 1),
+    };
+
+    if (result.ResultScore != nil) {
+        score.Score = *result.ResultScore;
+    }
+
+    if (result.Comment != "") {
+        score.Comments = append(score.Comments, &lmstypes.SubmissionComment{
+            ID: result.UserID,
+            Author: result.UserID,
+            Text: result.Comment,
+        });
+    }
+
+    return score;
+}
@@ -0,0 +1,122 @@
+package lti
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+)
+
+var resultScopes []string = []string{ltitool.SCOPE_RESULT_READONLY};
+var scoreScopes []string = []string{ltitool.SCOPE_SCORE};
+
+func (this *LTIBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+    result, err := this.fetchResult(assignmentID, userID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch score: '%w'.", err);
+    }
+
+    if (result == nil) {
+        return nil, fmt.Errorf("User '%s' does not have a score for assignment '%s'.", userID, assignmentID);
+    }
+
+    return resultToLMSType(result), nil;
+}
+
+// Results without a score or comment are skipped.
+func (this *LTIBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+    uri, err := serviceURL(assignmentID, "/results", nil);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    results, err := fetchAll[ltitool.Result](this, uri, ltitool.MEDIA_TYPE_RESULT_CONTAINER, resultScopes);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch scores: '%w'.", err);
+    }
+
+    scores := make([]*lmstypes.SubmissionScore, 0, len(results));
+    for _, result := range results {
+        if ((result.ResultScore == nil) && (result.Comment == "")) {
+            continue;
+        }
+
+        scores = append(scores, resultToLMSType(result));
+    }
+
+    return scores, nil;
+}
+
+// AGS does not have a bulk score update, so each score is sent individually.
+// A score's comment replaces the existing comment (a score without a comment clears it).
+func (this *LTIBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+    lineItem, err := this.fetchLineItem(assignmentID);
+    if (err != nil) {
+        return fmt.Errorf("Failed to fetch assignment for scores: '%w'.", err);
+    }
+
+    for i, score := range scores {
+        if (i != 0) {
+            time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
+        }
+
+        if (len(score.Comments) > 1) {
+            return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments));
+        }
+
+        points := score.Score;
+
+        ltiScore := ltitool.Score{
+            UserID: score.UserID,
+            ScoreGiven: &points,
+            ScoreMaximum: lineItem.ScoreMaximum,
+            ActivityProgress: ltitool.ACTIVITY_PROGRESS_COMPLETED,
+            GradingProgress: ltitool.GRADING_PROGRESS_FULLY_GRADED,
+        };
+
+        for _, comment := range score.Comments {
+            ltiScore.Comment = comment.Text;
+        }
+
+        err = this.postScore(assignmentID, &ltiScore);
+        if (err != nil) {
+            return fmt.Errorf("Failed to upload score for user '%s': '%w'.", score.UserID, err);
+        }
+    }
+
+    return nil;
+}
+
+// Returns nil if the user does not have a result.
+func (this *LTIBackend) fetchResult(assignmentID string, userID string) (*ltitool.Result, error) {
+    uri, err := serviceURL(assignmentID, "/results", map[string]string{"user_id": userID});
+    if (err != nil) {
+        return nil, err;
+    }
+
+    results, err := fetchAll[ltitool.Result](this, uri, ltitool.MEDIA_TYPE_RESULT_CONTAINER, resultScopes);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    for _, result := range results {
+        if (result.UserID == userID) {
+            return result, nil;
+        }
+    }
+
+    return nil, nil;
+}
+
+// Platforms reject scores older than ones they already have, so the timestamp is always the current time.
+func (this *LTIBackend) postScore(assignmentID string, score *ltitool.Score) error {
+    uri, err := serviceURL(assignmentID, "/scores", nil);
+    if (err != nil) {
+        return err;
+    }
+
+    score.Timestamp = time.Now();
+
+    return this.post(uri, ltitool.MEDIA_TYPE_SCORE, scoreScopes, score);
+}
@@ -0,0 +1,129 @@
+package lti
+
+import (
+    "testing"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func testAssignmentID() string {
+    return testPlatform.LineItemsURL() + "/1";
+}
+
+func TestLTIFetchAssignmentScoreBase(test *testing.T) {
+    expected := &lmstypes.SubmissionScore{
+        UserID: "grader-id",
+        Score: 80.5,
+        Comments: []*lmstypes.SubmissionComment{},
+    };
+
+    score, err := testBackend.FetchAssignmentScore(testAssignmentID(), "grader-id");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment score: '%v'.", err);
+    }
+
+    expectedJSON := util.MustToJSONIndent(expected);
+    actualJSON := util.MustToJSONIndent(score);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Score not as expected. Expected: '%s', Actual: '%s'.", expectedJSON, actualJSON);
+    }
+
+    _, err = testBackend.FetchAssignmentScore(testAssignmentID(), "other-id");
+    if (err == nil) {
+        test.Fatalf("Did not get an error when fetching a missing score.");
+    }
+}
+
+func TestLTIFetchAssignmentScoresBase(test *testing.T) {
+    scores, err := testBackend.FetchAssignmentScores(testPlatform.LineItemsURL() + "/2");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment scores: '%v'.", err);
+    }
+
+    if (len(scores) != 0) {
+        test.Fatalf("Found scores for an assignment without results: '%s'.", util.MustToJSONIndent(scores));
+    }
+}
+
+// Update scores, and then read them back (which also covers paging results).
+func TestLTIUpdateAssignmentScoresBase(test *testing.T) {
+    expected := []*lmstypes.SubmissionScore{
+        &lmstypes.SubmissionScore{
+            UserID: "owner-id",
+            Score: 10,
+            Comments: []*lmstypes.SubmissionComment{},
+        },
+        &lmstypes.SubmissionScore{
+            UserID: "admin-id",
+            Score: 20,
+            Comments: []*lmstypes.SubmissionComment{
+                &lmstypes.SubmissionComment{ID: "admin-id", Author: "admin-id", Text: "Admin Comment"},
+            },
+        },
+        &lmstypes.SubmissionScore{
+            UserID: "grader-id",
+            Score: 30,
+            Comments: []*lmstypes.SubmissionComment{},
+        },
+        &lmstypes.SubmissionScore{
+            UserID: "student-id",
+            Score: 40,
+            Comments: []*lmstypes.SubmissionComment{
+                &lmstypes.SubmissionComment{ID: "student-id", Author: "student-id", Text: "Student Comment"},
+            },
+        },
+    };
+
+    err := testBackend.UpdateAssignmentScores(testAssignmentID(), expected);
+    if (err != nil) {
+        test.Fatalf("Failed to update assignment scores: '%v'.", err);
+    }
+
+    score := testPlatform.GetScore(testAssignmentID(), "student-id");
+    if ((score == nil) || (score.ScoreMaximum != 100.0) ||
+            (score.ActivityProgress != ltitool.ACTIVITY_PROGRESS_COMPLETED) || (score.GradingProgress != ltitool.GRADING_PROGRESS_FULLY_GRADED)) {
+        test.Fatalf("Unexpected posted score: '%s'.", util.MustToJSONIndent(score));
+    }
+
+    scores, err := testBackend.FetchAssignmentScores(testAssignmentID());
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment scores: '%v'.", err);
+    }
+
+    expectedJSON := util.MustToJSONIndent(expected);
+    actualJSON := util.MustToJSONIndent(scores);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Scores not as expected. Expected: '%s', Actual: '%s'.", expectedJSON, actualJSON);
+    }
+}
+
+func TestLTIUpdateCommentBase(test *testing.T) {
+    comment := &lmstypes.SubmissionComment{
+        ID: "grader-id",
+        Author: "grader-id",
+        Text: "New Comment",
+    };
+
+    err := testBackend.UpdateComments(testAssignmentID(), []*lmstypes.SubmissionComment{comment});
+    if (err != nil) {
+        test.Fatalf("Failed to update comment: '%v'.", err);
+    }
+
+    // The existing score is kept.
+    score, err := testBackend.FetchAssignmentScore(testAssignmentID(), "grader-id");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment score: '%v'.", err);
+    }
+
+    if ((score.Score != 80.5) && (score.Score != 30)) {
+        test.Fatalf("Score was changed by a comment update: '%s'.", util.MustToJSONIndent(score));
+    }
+
+    if ((len(score.Comments) != 1) || (score.Comments[0].Text != "New Comment")) {
+        test.Fatalf("Comment was not updated: '%s'.", util.MustToJSONIndent(score));
+    }
+}
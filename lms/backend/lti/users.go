@@ -0,0 +1,120 @@
+package lti
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+)
+
+// Inactive and deleted members are skipped.
+func (this *LTIBackend) FetchUsers() ([]*lmstypes.User, error) {
+    users, _, err := this.FetchUsersWithCursor();
+    return users, err;
+}
+
+// NRPS platforms may provide a "differences" link with the memberships,
+// which is used as the cursor.
+func (this *LTIBackend) FetchUsersWithCursor() ([]*lmstypes.User, string, error) {
+    if (this.MembershipsURL == "") {
+        return nil, "", fmt.Errorf("No LTI memberships URL is known, launch the autograder from the course or set one in the course's config.");
+    }
+
+    members, differencesURL, err := this.fetchMembers(this.MembershipsURL);
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Failed to fetch users: '%w'.", err);
+    }
+
+    users := make([]*lmstypes.User, 0, len(members));
+    for _, member := range members {
+        if (!isActive(member)) {
+            continue;
+        }
+
+        users = append(users, memberToLMSType(member));
+    }
+
+    return users, differencesURL, nil;
+}
+
+// Inactive and deleted members are marked as removed.
+func (this *LTIBackend) FetchUsersChangedSince(cursor string) ([]*lmstypes.User, string, error) {
+    if (cursor == "") {
+        return nil, "", fmt.Errorf("No LTI differences URL was provided.");
+    }
+
+    members, differencesURL, err := this.fetchMembers(cursor);
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Failed to fetch user changes: '%w'.", err);
+    }
+
+    users := make([]*lmstypes.User, 0, len(members));
+    for _, member := range members {
+        user := memberToLMSType(member);
+        user.Removed = !isActive(member);
+
+        users = append(users, user);
+    }
+
+    return users, differencesURL, nil;
+}
+
+// Memberships cannot be searched by email, so all users are fetched and filtered.
+func (this *LTIBackend) FetchUser(email string) (*lmstypes.User, error) {
+    users, err := this.FetchUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err);
+    }
+
+    matches := make([]*lmstypes.User, 0, 1);
+    for _, user := range users {
+        if (strings.EqualFold(user.Email, email)) {
+            matches = append(matches, user);
+        }
+    }
+
+    if (len(matches) != 1) {
+        log.Warn().Str("email", email).Int("num-results", len(matches)).Msg("Did not find exactly one matching user in LTI platform.");
+        return nil, nil;
+    }
+
+    return matches[0], nil;
+}
+
+// Fetch all pages of members.
+// Returns: (members, differences URL (if any), error).
+func (this *LTIBackend) fetchMembers(uri string) ([]*ltitool.Member, string, error) {
+    scopes := []string{ltitool.SCOPE_MEMBERSHIPS_READONLY};
+
+    members := make([]*ltitool.Member, 0);
+    differencesURL := "";
+
+    for (uri != "") {
+        var container ltitool.MembershipContainer;
+        links, err := this.get(uri, ltitool.MEDIA_TYPE_MEMBERSHIP_CONTAINER, scopes, &container);
+        if (err != nil) {
+            return nil, "", err;
+        }
+
+        for _, member := range container.Members {
+            if (member != nil) {
+                members = append(members, member);
+            }
+        }
+
+        if (links[LINK_REL_DIFFERENCES] != "") {
+            differencesURL = links[LINK_REL_DIFFERENCES];
+        }
+
+        uri = links[LINK_REL_NEXT];
+    }
+
+    return members, differencesURL, nil;
+}
+
+func isActive(member *ltitool.Member) bool {
+    return ((member.Status == "") || (member.Status == ltitool.MEMBER_STATUS_ACTIVE));
+}
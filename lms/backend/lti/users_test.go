@@ -0,0 +1,131 @@
+package lti
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    ltitool "github.com/eriq-augustine/autograder/lti"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+// Members are split over several pages, and inactive members are skipped.
+var testUsers []*lmstypes.User = []*lmstypes.User{
+    &lmstypes.User{ID: "owner-id", Name: "owner", Email: "owner@test.com", Role: model.RoleOwner},
+    &lmstypes.User{ID: "admin-id", Name: "admin", Email: "admin@test.com", Role: model.RoleAdmin},
+    &lmstypes.User{ID: "grader-id", Name: "grader", Email: "grader@test.com", Role: model.RoleGrader},
+    &lmstypes.User{ID: "student-id", Name: "student", Email: "student@test.com", Role: model.RoleStudent},
+    &lmstypes.User{ID: "other-id", Name: "other", Email: "other@test.com", Role: model.RoleOther},
+    &lmstypes.User{ID: "new-id", Name: "new", Email: "new@test.com", Role: model.RoleStudent},
+};
+
+func TestLTIUserGetBase(test *testing.T) {
+    for i, expected := range testUsers {
+        user, err := testBackend.FetchUser(expected.Email);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to fetch user: '%v'.", i, err);
+            continue;
+        }
+
+        if ((user == nil) || (*expected != *user)) {
+            test.Errorf("Case %d: User not as expected. Expected: '%+v', Actual: '%+v'.", i, expected, user);
+            continue;
+        }
+    }
+}
+
+func TestLTIUserGetMissing(test *testing.T) {
+    for _, email := range []string{"ZZZ@test.com", "dropped@test.com"} {
+        user, err := testBackend.FetchUser(email);
+        if (err != nil) {
+            test.Fatalf("Failed to fetch user: '%v'.", err);
+        }
+
+        if (user != nil) {
+            test.Fatalf("Found a user that should not exist: '%+v'.", user);
+        }
+    }
+}
+
+func TestLTIUsersGetBase(test *testing.T) {
+    users, err := testBackend.FetchUsers();
+    if (err != nil) {
+        test.Fatalf("Failed to fetch users: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(testUsers, users)) {
+        test.Fatalf("Users not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(testUsers), util.MustToJSONIndent(users));
+    }
+}
+
+func TestLTIErrors(test *testing.T) {
+    // The platform does not know this client.
+    platform := testPlatform.Platform();
+    platform.ClientID = "ZZZ";
+
+    badClientBackend, err := NewBackend(testBackend.CourseID, platform, nil);
+    if (err != nil) {
+        test.Fatalf("Failed to create backend: '%v'.", err);
+    }
+    badClientBackend.MembershipsURL = testBackend.MembershipsURL;
+
+    // No endpoints have been learned.
+    noEndpointsBackend, err := NewBackend(testBackend.CourseID, testPlatform.Platform(), nil);
+    if (err != nil) {
+        test.Fatalf("Failed to create backend: '%v'.", err);
+    }
+
+    for i, backend := range []*LTIBackend{badClientBackend, noEndpointsBackend} {
+        _, err = backend.FetchUsers();
+        if (err == nil) {
+            test.Errorf("Case %d: Did not get an error.", i);
+        }
+    }
+}
+
+func TestLTIUsersChangedSinceBase(test *testing.T) {
+    _, cursor, err := testBackend.FetchUsersWithCursor();
+    if (err != nil) {
+        test.Fatalf("Failed to fetch users: '%v'.", err);
+    }
+
+    if (cursor == "") {
+        test.Fatalf("Did not get a cursor.");
+    }
+
+    // Leave the platform's active members as they were.
+    defer testPlatform.SetMember(&ltitool.Member{UserID: "other-id", Name: "other", Email: "other@test.com",
+            Status: ltitool.MEMBER_STATUS_ACTIVE, Roles: []string{ltitool.ROLE_MEMBERSHIP_PREFIX + "Mentor"}});
+    defer testPlatform.RemoveMember("late-id");
+
+    testPlatform.RemoveMember("other-id");
+    testPlatform.SetMember(&ltitool.Member{UserID: "late-id", Name: "late", Email: "late@test.com",
+            Status: ltitool.MEMBER_STATUS_ACTIVE, Roles: []string{ltitool.ROLE_MEMBERSHIP_PREFIX + "Learner"}});
+
+    expected := []*lmstypes.User{
+        &lmstypes.User{ID: "other-id", Name: "other", Email: "other@test.com", Role: model.RoleOther, Removed: true},
+        &lmstypes.User{ID: "late-id", Name: "late", Email: "late@test.com", Role: model.RoleStudent},
+    };
+
+    users, cursor, err := testBackend.FetchUsersChangedSince(cursor);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch user changes: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected, users)) {
+        test.Fatalf("Users not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(expected), util.MustToJSONIndent(users));
+    }
+
+    // Nothing has changed since the last fetch.
+    users, _, err = testBackend.FetchUsersChangedSince(cursor);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch user changes: '%v'.", err);
+    }
+
+    if (len(users) != 0) {
+        test.Fatalf("Found changes when there should be none: '%s'.", util.MustToJSONIndent(users));
+    }
+}
@@ -0,0 +1,52 @@
+package moodle
+
+import (
+    "fmt"
+    neturl "net/url"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+)
+
+// Moodle cannot fetch a single assignment, so all the course's assignments are fetched and filtered.
+func (this *MoodleBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+    assignments, err := this.FetchAssignments();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    for _, assignment := range assignments {
+        if (assignment.ID == assignmentID) {
+            return assignment, nil;
+        }
+    }
+
+    return nil, fmt.Errorf("Could not find Moodle assignment '%s' in course '%s'.", assignmentID, this.CourseID);
+}
+
+func (this *MoodleBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+    params := neturl.Values{};
+    params.Set("courseids[0]", this.CourseID);
+
+    var response assignmentsResponse;
+    err := this.call(FUNCTION_GET_ASSIGNMENTS, params, nil, &response);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignments: '%w'.", err);
+    }
+
+    assignments := make([]*lmstypes.Assignment, 0);
+    for _, course := range response.Courses {
+        if (course == nil) {
+            continue;
+        }
+
+        for _, assignment := range course.Assignments {
+            if (assignment == nil) {
+                continue;
+            }
+
+            assignments = append(assignments, assignment.ToLMSType());
+        }
+    }
+
+    return assignments, nil;
+}
@@ -0,0 +1,60 @@
+package moodle
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func (this *MoodleBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+    for i, comment := range comments {
+        if (i != 0) {
+            time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
+        }
+
+        err := this.UpdateComment(assignmentID, comment);
+        if (err != nil) {
+            return fmt.Errorf("Failed on comment %d: '%w'.", i, err);
+        }
+    }
+
+    return nil;
+}
+
+// Moodle feedback comments are saved along with a grade,
+// so the user's current grade is fetched and saved again with the new comment.
+// The comment's author is the ID of the graded user (see GradeItem.ToLMSType()).
+func (this *MoodleBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+    userID := comment.Author;
+
+    gradeItems, err := this.fetchGradeItems(assignmentID, userID);
+    if (err != nil) {
+        return fmt.Errorf("Failed to fetch current score for comment update: '%w'.", err);
+    }
+
+    gradeItem, ok := gradeItems[userID];
+    if (!ok || (gradeItem.Score == nil)) {
+        return fmt.Errorf("User '%s' does not have a score on Moodle assignment '%s', cannot update their comment.", userID, assignmentID);
+    }
+
+    form := map[string]string{
+        "assignmentid": assignmentID,
+        "userid": userID,
+        "grade": util.FloatToStr(*gradeItem.Score),
+        "attemptnumber": "-1",
+        "addattempt": "0",
+        "workflowstate": "",
+        "applytoall": "0",
+    };
+
+    addFeedbackComment(form, "", comment.Text);
+
+    err = this.call(FUNCTION_SAVE_GRADE, nil, form, nil);
+    if (err != nil) {
+        return fmt.Errorf("Failed to update comment: '%w'.", err);
+    }
+
+    return nil;
+}
@@ -0,0 +1,84 @@
+package moodle
+
+import (
+    "fmt"
+    neturl "net/url"
+    "strings"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    REST_ENDPOINT = "/webservice/rest/server.php";
+
+    FUNCTION_GET_ENROLLED_USERS = "core_enrol_get_enrolled_users";
+    FUNCTION_GET_ASSIGNMENTS = "mod_assign_get_assignments";
+    FUNCTION_GET_GRADE_ITEMS = "gradereport_user_get_grade_items";
+    FUNCTION_SAVE_GRADE = "mod_assign_save_grade";
+    FUNCTION_SAVE_GRADES = "mod_assign_save_grades";
+
+    POST_PAGE_SIZE int = 75;
+    UPLOAD_SLEEP_TIME_SEC = int64(0.5 * float64(time.Second));
+
+    // Moodle text formats.
+    FORMAT_PLAIN = "2";
+)
+
+// Moodle reports errors with a 200 and this object as the body.
+type moodleError struct {
+    Exception string `json:"exception"`
+    ErrorCode string `json:"errorcode"`
+    Message string `json:"message"`
+}
+
+// Call a web service function and unmarshal the JSON response into |result| (which may be nil).
+// |queryParams| are placed in the URL (used for reads so requests can be identified),
+// while |formParams| are posted in the body (used for writes, which can be large).
+// The token is always posted in the body so it does not show up in logs.
+func (this *MoodleBackend) call(function string, queryParams neturl.Values, formParams map[string]string, result any) error {
+    query := neturl.Values{};
+    for key, values := range queryParams {
+        query[key] = values;
+    }
+
+    query.Set("wsfunction", function);
+    query.Set("moodlewsrestformat", "json");
+
+    url := fmt.Sprintf("%s%s?%s", this.BaseURL, REST_ENDPOINT, query.Encode());
+
+    form := make(map[string]string, len(formParams) + 1);
+    for key, value := range formParams {
+        form[key] = value;
+    }
+
+    form["wstoken"] = this.APIToken;
+
+    body, _, err := common.PostWithHeaders(url, form, make(map[string][]string));
+    if (err != nil) {
+        return fmt.Errorf("Failed to call Moodle function '%s': '%w'.", function, err);
+    }
+
+    body = strings.TrimSpace(body);
+
+    // Errors are always objects, but not all objects are errors.
+    if (strings.HasPrefix(body, "{")) {
+        var callErr moodleError;
+        err = util.JSONFromString(body, &callErr);
+        if ((err == nil) && (callErr.Exception != "")) {
+            return fmt.Errorf("Moodle function '%s' returned an error (%s): '%s'.", function, callErr.ErrorCode, callErr.Message);
+        }
+    }
+
+    if (result == nil) {
+        return nil;
+    }
+
+    err = util.JSONFromString(body, result);
+    if (err != nil) {
+        return fmt.Errorf("Failed to unmarshal response from Moodle function '%s': '%w'.", function, err);
+    }
+
+    return nil;
+}
@@ -0,0 +1,84 @@
+package moodle
+
+import (
+    "embed"
+    "io/fs"
+    "net/http"
+    "net/url"
+    "os"
+    "sync"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/lms/backend/testhttp"
+)
+
+const (
+    TEST_COURSE_ID = "12345"
+    TEST_ASSIGNMENT_ID = "98765"
+    TEST_TOKEN = "ABC123"
+)
+
+//go:embed testdata/http
+var httpDataDir embed.FS;
+
+var testServer *testhttp.Server;
+var testBackend *MoodleBackend;
+
+// The most recent form posted for each web service function: {function: form}.
+var postedForms map[string]url.Values = make(map[string]url.Values);
+var postedFormsLock sync.Mutex;
+
+func TestMain(suite *testing.M) {
+    // Run inside a func so defers will run before os.Exit().
+    code := func() int {
+        db.PrepForTestingMain();
+        defer db.CleanupTestingMain();
+
+        dataDir, err := fs.Sub(httpDataDir, "testdata/http");
+        if (err != nil) {
+            panic(err);
+        }
+
+        testServer, err = testhttp.Start(dataDir, testhttp.Options{CheckRequest: checkRequest});
+        if (err != nil) {
+            panic(err);
+        }
+        defer testServer.Close();
+
+        testBackend, err = NewBackend(TEST_COURSE_ID, TEST_TOKEN, testServer.URL());
+        if (err != nil) {
+            panic(err);
+        }
+
+        return suite.Run();
+    }();
+
+    os.Exit(code);
+}
+
+// Check the token and record the posted form.
+func checkRequest(response http.ResponseWriter, request *http.Request) bool {
+    err := request.ParseForm();
+    if (err != nil) {
+        panic(err);
+    }
+
+    if (request.PostForm.Get("wstoken") != TEST_TOKEN) {
+        response.Write([]byte(`{"exception":"moodle_exception","errorcode":"invalidtoken","message":"Invalid token - token not found"}`));
+        return false;
+    }
+
+    postedFormsLock.Lock();
+    postedForms[request.URL.Query().Get("wsfunction")] = request.PostForm;
+    postedFormsLock.Unlock();
+
+    return true;
+}
+
+func getPostedForm(function string) url.Values {
+    postedFormsLock.Lock();
+    defer postedFormsLock.Unlock();
+
+    return postedForms[function];
+}
@@ -0,0 +1,155 @@
+package moodle
+
+import (
+    "html"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/model"
+)
+
+type User struct {
+    ID int64 `json:"id"`
+    Name string `json:"fullname"`
+    Email string `json:"email"`
+    Roles []Role `json:"roles"`
+}
+
+type Role struct {
+    ID int64 `json:"roleid"`
+    Name string `json:"name"`
+    ShortName string `json:"shortname"`
+}
+
+type assignmentsResponse struct {
+    Courses []*struct {
+        ID int64 `json:"id"`
+        Assignments []*Assignment `json:"assignments"`
+    } `json:"courses"`
+}
+
+type Assignment struct {
+    ID int64 `json:"id"`
+    Name string `json:"name"`
+    MoodleCourseID int64 `json:"course"`
+    // Unix time, zero if there is no due date.
+    DueDate int64 `json:"duedate"`
+    // Negative values indicate a scale (instead of points).
+    MaxPoints float64 `json:"grade"`
+}
+
+type gradeItemsResponse struct {
+    UserGrades []*UserGrades `json:"usergrades"`
+}
+
+type UserGrades struct {
+    UserID int64 `json:"userid"`
+    GradeItems []*GradeItem `json:"gradeitems"`
+}
+
+type GradeItem struct {
+    ID int64 `json:"id"`
+    ItemModule string `json:"itemmodule"`
+    // For assignments, this is the assignment ID.
+    ItemInstance int64 `json:"iteminstance"`
+    Score *float64 `json:"graderaw"`
+    // Unix time.
+    GradedTime *int64 `json:"gradedategraded"`
+    // Rendered as HTML by Moodle.
+    Feedback string `json:"feedback"`
+}
+
+// Moodle role (short name) to autograder role.
+// Moodle's default roles are used, a user with multiple roles gets the highest one.
+var roleMapping map[string]model.UserRole = map[string]model.UserRole{
+    "guest": model.RoleOther,
+    "user": model.RoleOther,
+    "frontpage": model.RoleOther,
+    "student": model.RoleStudent,
+    // Non-editing teacher.
+    "teacher": model.RoleGrader,
+    "manager": model.RoleAdmin,
+    "coursecreator": model.RoleAdmin,
+    "editingteacher": model.RoleOwner,
+};
+
+// Moodle renders newlines as a break followed by the original newline.
+var htmlBreakRegex *regexp.Regexp = regexp.MustCompile(`(?i)<br\s*/?>(\r?\n)?`);
+var htmlTagRegex *regexp.Regexp = regexp.MustCompile(`<[^>]*>`);
+
+func (this *User) GetRole() model.UserRole {
+    var maxRole model.UserRole = model.RoleOther;
+    for _, role := range this.Roles {
+        maxRole = max(maxRole, roleMapping[role.ShortName]);
+    }
+
+    return maxRole;
+}
+
+func (this *User) ToLMSType() *lmstypes.User {
+    return &lmstypes.User{
+        ID: formatID(this.ID),
+        Name: this.Name,
+        Email: this.Email,
+        Role: this.GetRole(),
+    };
+}
+
+func (this *Assignment) ToLMSType() *lmstypes.Assignment {
+    var dueDate *time.Time = nil;
+    if (this.DueDate > 0) {
+        instance := time.Unix(this.DueDate, 0).UTC();
+        dueDate = &instance;
+    }
+
+    return &lmstypes.Assignment{
+        ID: formatID(this.ID),
+        Name: this.Name,
+        LMSCourseID: formatID(this.MoodleCourseID),
+        DueDate: dueDate,
+        MaxPoints: max(0.0, this.MaxPoints),
+    };
+}
+
+// Moodle only has a single feedback comment per grade,
+// so the comment's ID and author are both the ID of the graded user
+// (which is what UpdateComment() needs to find the grade again).
+func (this *GradeItem) ToLMSType(userID int64) *lmstypes.SubmissionScore {
+    score := &lmstypes.SubmissionScore{
+        UserID: formatID(userID),
+        Comments: make([]*lmstypes.SubmissionComment, 0, 1),
+    };
+
+    if (this.Score != nil) {
+        score.Score = *this.Score;
+    }
+
+    if ((this.GradedTime != nil) && (*this.GradedTime > 0)) {
+        score.Time = time.Unix(*this.GradedTime, 0).UTC();
+    }
+
+    feedback := cleanFeedback(this.Feedback);
+    if (feedback != "") {
+        score.Comments = append(score.Comments, &lmstypes.SubmissionComment{
+            ID: score.UserID,
+            Author: score.UserID,
+            Text: feedback,
+        });
+    }
+
+    return score;
+}
+
+// Convert Moodle's rendered (HTML) feedback back into plain text.
+func cleanFeedback(text string) string {
+    text = htmlBreakRegex.ReplaceAllString(text, "\n");
+    text = htmlTagRegex.ReplaceAllString(text, "");
+    return strings.TrimSpace(html.UnescapeString(text));
+}
+
+func formatID(id int64) string {
+    return strconv.FormatInt(id, 10);
+}
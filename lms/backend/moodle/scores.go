@@ -0,0 +1,148 @@
+package moodle
+
+import (
+    "fmt"
+    neturl "net/url"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func (this *MoodleBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+    gradeItems, err := this.fetchGradeItems(assignmentID, userID);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    gradeItem, ok := gradeItems[userID];
+    if (!ok) {
+        return nil, fmt.Errorf("Could not find a score for user '%s' on Moodle assignment '%s'.", userID, assignmentID);
+    }
+
+    return gradeItem.ToLMSType(gradeItem.userID), nil;
+}
+
+func (this *MoodleBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+    gradeItems, err := this.fetchGradeItems(assignmentID, "");
+    if (err != nil) {
+        return nil, err;
+    }
+
+    scores := make([]*lmstypes.SubmissionScore, 0, len(gradeItems));
+    for _, gradeItem := range gradeItems {
+        scores = append(scores, gradeItem.ToLMSType(gradeItem.userID));
+    }
+
+    return scores, nil;
+}
+
+type userGradeItem struct {
+    *GradeItem
+    userID int64
+}
+
+// Get the grade items (scores and feedback) for an assignment.
+// If |userID| is empty, then items for all users will be fetched.
+// Users that have neither a score nor feedback are skipped.
+// Returns: {userID: item, ...}.
+func (this *MoodleBackend) fetchGradeItems(assignmentID string, userID string) (map[string]*userGradeItem, error) {
+    params := neturl.Values{};
+    params.Set("courseid", this.CourseID);
+    if (userID != "") {
+        params.Set("userid", userID);
+    }
+
+    var response gradeItemsResponse;
+    err := this.call(FUNCTION_GET_GRADE_ITEMS, params, nil, &response);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch scores: '%w'.", err);
+    }
+
+    gradeItems := make(map[string]*userGradeItem);
+    for _, userGrades := range response.UserGrades {
+        if (userGrades == nil) {
+            continue;
+        }
+
+        for _, gradeItem := range userGrades.GradeItems {
+            if ((gradeItem == nil) || (gradeItem.ItemModule != "assign") || (formatID(gradeItem.ItemInstance) != assignmentID)) {
+                continue;
+            }
+
+            if ((gradeItem.Score == nil) && (gradeItem.Feedback == "")) {
+                continue;
+            }
+
+            gradeItems[formatID(userGrades.UserID)] = &userGradeItem{gradeItem, userGrades.UserID};
+        }
+    }
+
+    return gradeItems, nil;
+}
+
+func (this *MoodleBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+    for page := 0; (page * POST_PAGE_SIZE) < len(scores); page++ {
+        startIndex := page * POST_PAGE_SIZE;
+        endIndex := min(len(scores), ((page + 1) * POST_PAGE_SIZE));
+
+        if (page != 0) {
+            time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
+        }
+
+        err := this.updateAssignmentScores(assignmentID, scores[startIndex:endIndex]);
+        if (err != nil) {
+            return fmt.Errorf("Failed on page %d: '%w'.", page, err);
+        }
+    }
+
+    return nil;
+}
+
+func (this *MoodleBackend) updateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+    if (len(scores) > POST_PAGE_SIZE) {
+        return fmt.Errorf("Too many score upload requests at once. Found %d, max %d.", len(scores), POST_PAGE_SIZE);
+    }
+
+    form := make(map[string]string);
+    form["assignmentid"] = assignmentID;
+    form["applytoall"] = "0";
+
+    for i, score := range scores {
+        prefix := fmt.Sprintf("grades[%d]", i);
+
+        form[prefix + "[userid]"] = score.UserID;
+        form[prefix + "[grade]"] = util.FloatToStr(score.Score);
+        // Grade the latest attempt.
+        form[prefix + "[attemptnumber]"] = "-1";
+        form[prefix + "[addattempt]"] = "0";
+        form[prefix + "[workflowstate]"] = "";
+
+        if (len(score.Comments) > 1) {
+            return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments));
+        }
+
+        for _, comment := range score.Comments {
+            addFeedbackComment(form, prefix, comment.Text);
+        }
+    }
+
+    err := this.call(FUNCTION_SAVE_GRADES, nil, form, nil);
+    if (err != nil) {
+        return fmt.Errorf("Failed to upload scores: '%w'.", err);
+    }
+
+    return nil;
+}
+
+// Add a feedback comment to a grade in a form.
+// |prefix| is the grade's key when the form has multiple grades (e.g. "grades[0]"), or empty for a single grade.
+func addFeedbackComment(form map[string]string, prefix string, text string) {
+    key := "plugindata";
+    if (prefix != "") {
+        key = prefix + "[plugindata]";
+    }
+
+    form[key + "[assignfeedbackcomments_editor][text]"] = text;
+    form[key + "[assignfeedbackcomments_editor][format]"] = FORMAT_PLAIN;
+}
@@ -0,0 +1,137 @@
+package moodle
+
+import (
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const TEST_COMMENT = "{\n\"id\": \"course101::hw0::student@test.com::1696364768\",\n\"raw-score\": 100,\n\"score\": 100,\n\"lock\": false,\n\"__autograder__v01__\": 0\n}";
+
+var testGradedTime time.Time = time.Unix(1696364768, 0).UTC();
+
+var testStudentScore lmstypes.SubmissionScore = lmstypes.SubmissionScore{
+    UserID: "40",
+    Score: 100.0,
+    Time: testGradedTime,
+    Comments: []*lmstypes.SubmissionComment{
+        &lmstypes.SubmissionComment{
+            ID: "40",
+            Author: "40",
+            Text: TEST_COMMENT,
+        },
+    },
+};
+
+var testGraderScore lmstypes.SubmissionScore = lmstypes.SubmissionScore{
+    UserID: "30",
+    Score: 80.5,
+    Time: testGradedTime,
+    Comments: []*lmstypes.SubmissionComment{},
+};
+
+func TestMoodleFetchAssignmentScoreBase(test *testing.T) {
+    score, err := testBackend.FetchAssignmentScore(TEST_ASSIGNMENT_ID, "40");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment score: '%v'.", err);
+    }
+
+    // Can't compare directly because of time.Time.
+    // Use JSON instead.
+    expectedJSON := util.MustToJSONIndent(testStudentScore);
+    actualJSON := util.MustToJSONIndent(score);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Score not as expected. Expected: '%s', Actual: '%s'.", expectedJSON, actualJSON);
+    }
+}
+
+func TestMoodleFetchAssignmentScoresBase(test *testing.T) {
+    scores, err := testBackend.FetchAssignmentScores(TEST_ASSIGNMENT_ID);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment scores: '%v'.", err);
+    }
+
+    // Users without a score or feedback are skipped.
+    if (len(scores) != 2) {
+        test.Fatalf("Unexpected number of scores. Expected: 2, Actual: %d.", len(scores));
+    }
+
+    // Scores are not ordered.
+    actual := make(map[string]string);
+    for _, score := range scores {
+        actual[score.UserID] = util.MustToJSONIndent(score);
+    }
+
+    expected := map[string]string{
+        "40": util.MustToJSONIndent(testStudentScore),
+        "30": util.MustToJSONIndent(testGraderScore),
+    };
+
+    for userID, expectedJSON := range expected {
+        if (expectedJSON != actual[userID]) {
+            test.Errorf("Score for user '%s' not as expected. Expected: '%s', Actual: '%s'.", userID, expectedJSON, actual[userID]);
+        }
+    }
+}
+
+func TestMoodleUpdateAssignmentScoresBase(test *testing.T) {
+    scores := []*lmstypes.SubmissionScore{
+        &testStudentScore,
+        &testGraderScore,
+    };
+
+    err := testBackend.UpdateAssignmentScores(TEST_ASSIGNMENT_ID, scores);
+    if (err != nil) {
+        test.Fatalf("Failed to update assignment scores: '%v'.", err);
+    }
+
+    form := getPostedForm(FUNCTION_SAVE_GRADES);
+
+    expected := map[string]string{
+        "assignmentid": TEST_ASSIGNMENT_ID,
+        "grades[0][userid]": "40",
+        "grades[0][grade]": "100",
+        "grades[0][plugindata][assignfeedbackcomments_editor][text]": TEST_COMMENT,
+        "grades[1][userid]": "30",
+        "grades[1][grade]": "80.5",
+        "grades[1][plugindata][assignfeedbackcomments_editor][text]": "",
+    };
+
+    for key, value := range expected {
+        if (form.Get(key) != value) {
+            test.Errorf("Unexpected value for posted '%s'. Expected: '%s', Actual: '%s'.", key, value, form.Get(key));
+        }
+    }
+}
+
+func TestMoodleUpdateCommentBase(test *testing.T) {
+    comment := &lmstypes.SubmissionComment{
+        ID: "40",
+        Author: "40",
+        Text: "New Comment",
+    };
+
+    err := testBackend.UpdateComments(TEST_ASSIGNMENT_ID, []*lmstypes.SubmissionComment{comment});
+    if (err != nil) {
+        test.Fatalf("Failed to update comment: '%v'.", err);
+    }
+
+    form := getPostedForm(FUNCTION_SAVE_GRADE);
+
+    // The existing grade is kept.
+    expected := map[string]string{
+        "assignmentid": TEST_ASSIGNMENT_ID,
+        "userid": "40",
+        "grade": "100",
+        "plugindata[assignfeedbackcomments_editor][text]": "New Comment",
+    };
+
+    for key, value := range expected {
+        if (form.Get(key) != value) {
+            test.Errorf("Unexpected value for posted '%s'. Expected: '%s', Actual: '%s'.", key, value, form.Get(key));
+        }
+    }
+}
@@ -0,0 +1,67 @@
+package moodle
+
+import (
+    "fmt"
+    neturl "net/url"
+    "strings"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+)
+
+func (this *MoodleBackend) FetchUsers() ([]*lmstypes.User, error) {
+    rawUsers, err := this.fetchRawUsers();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    users := make([]*lmstypes.User, 0, len(rawUsers));
+    for _, user := range rawUsers {
+        users = append(users, user.ToLMSType());
+    }
+
+    return users, nil;
+}
+
+// Moodle cannot search enrolled users by email, so all users are fetched and filtered.
+func (this *MoodleBackend) FetchUser(email string) (*lmstypes.User, error) {
+    rawUsers, err := this.fetchRawUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err);
+    }
+
+    matches := make([]*User, 0, 1);
+    for _, user := range rawUsers {
+        if (strings.EqualFold(user.Email, email)) {
+            matches = append(matches, user);
+        }
+    }
+
+    if (len(matches) != 1) {
+        log.Warn().Str("email", email).Int("num-results", len(matches)).Msg("Did not find exactly one matching user in moodle.");
+        return nil, nil;
+    }
+
+    return matches[0].ToLMSType(), nil;
+}
+
+func (this *MoodleBackend) fetchRawUsers() ([]*User, error) {
+    params := neturl.Values{};
+    params.Set("courseid", this.CourseID);
+
+    var rawUsers []*User;
+    err := this.call(FUNCTION_GET_ENROLLED_USERS, params, nil, &rawUsers);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch users: '%w'.", err);
+    }
+
+    users := make([]*User, 0, len(rawUsers));
+    for _, user := range rawUsers {
+        if (user != nil) {
+            users = append(users, user);
+        }
+    }
+
+    return users, nil;
+}
@@ -0,0 +1,76 @@
+package moodle
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+var testUsers []*lmstypes.User = []*lmstypes.User{
+    &lmstypes.User{ID: "10", Name: "owner", Email: "owner@test.com", Role: model.RoleOwner},
+    &lmstypes.User{ID: "20", Name: "admin", Email: "admin@test.com", Role: model.RoleAdmin},
+    // Has both a student and non-editing teacher role.
+    &lmstypes.User{ID: "30", Name: "grader", Email: "grader@test.com", Role: model.RoleGrader},
+    &lmstypes.User{ID: "40", Name: "student", Email: "student@test.com", Role: model.RoleStudent},
+    &lmstypes.User{ID: "50", Name: "other", Email: "other@test.com", Role: model.RoleOther},
+};
+
+func TestMoodleUserGetBase(test *testing.T) {
+    for i, expected := range testUsers {
+        user, err := testBackend.FetchUser(expected.Email);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to fetch user: '%v'.", i, err);
+            continue;
+        }
+
+        if ((user == nil) || (*expected != *user)) {
+            test.Errorf("Case %d: User not as expected. Expected: '%+v', Actual: '%+v'.", i, expected, user);
+            continue;
+        }
+    }
+}
+
+func TestMoodleUserGetMissing(test *testing.T) {
+    user, err := testBackend.FetchUser("ZZZ@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch user: '%v'.", err);
+    }
+
+    if (user != nil) {
+        test.Fatalf("Found a user that should not exist: '%+v'.", user);
+    }
+}
+
+func TestMoodleUsersGetBase(test *testing.T) {
+    users, err := testBackend.FetchUsers();
+    if (err != nil) {
+        test.Fatalf("Failed to fetch users: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(testUsers, users)) {
+        test.Fatalf("Users not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(testUsers), util.MustToJSONIndent(users));
+    }
+}
+
+func TestMoodleErrors(test *testing.T) {
+    badCourseBackend, err := NewBackend("99999", TEST_TOKEN, testServer.URL());
+    if (err != nil) {
+        test.Fatalf("Failed to create backend: '%v'.", err);
+    }
+
+    badTokenBackend, err := NewBackend(TEST_COURSE_ID, "ZZZ", testServer.URL());
+    if (err != nil) {
+        test.Fatalf("Failed to create backend: '%v'.", err);
+    }
+
+    for i, backend := range []*MoodleBackend{badCourseBackend, badTokenBackend} {
+        _, err = backend.FetchUsers();
+        if (err == nil) {
+            test.Errorf("Case %d: Did not get an error.", i);
+        }
+    }
+}
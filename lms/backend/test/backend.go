@@ -11,6 +11,11 @@ import (
 var failUpdateAssignmentScores bool = false;
 var usersModifier FetchUsersModifier = nil;
 
+// The users returned from FetchUsersChangedSince().
+// When nil, changes are not tracked (and no cursors are issued).
+var userChanges []*lmstypes.User = nil;
+var userChangesCount int = 0;
+
 type TestLMSBackend struct {
     CourseID string
 }
@@ -42,6 +47,14 @@ func ClearUsersModifier() {
     usersModifier = nil;
 }
 
+func SetUserChanges(changes []*lmstypes.User) {
+    userChanges = changes;
+}
+
+func ClearUserChanges() {
+    userChanges = nil;
+}
+
 func (this *TestLMSBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
     return nil, nil;
 }
@@ -2,12 +2,15 @@ package test
 
 import (
     "fmt"
+    "strings"
 
     "github.com/eriq-augustine/autograder/db"
     "github.com/eriq-augustine/autograder/lms/lmstypes"
     "github.com/eriq-augustine/autograder/model"
 )
 
+const CURSOR_PREFIX = "test-cursor-";
+
 func (this *TestLMSBackend) FetchUsers() ([]*lmstypes.User, error) {
     localUsers, err := db.GetUsersFromID(this.CourseID);
     if (err != nil) {
@@ -40,6 +43,38 @@ func (this *TestLMSBackend) FetchUser(email string) (*lmstypes.User, error) {
     return UserFromAGUser(user), nil;
 }
 
+func (this *TestLMSBackend) FetchUsersWithCursor() ([]*lmstypes.User, string, error) {
+    users, err := this.FetchUsers();
+    if (err != nil) {
+        return nil, "", err;
+    }
+
+    return users, nextCursor(), nil;
+}
+
+func (this *TestLMSBackend) FetchUsersChangedSince(cursor string) ([]*lmstypes.User, string, error) {
+    if ((userChanges == nil) || !strings.HasPrefix(cursor, CURSOR_PREFIX)) {
+        return nil, "", fmt.Errorf("Test LMS cannot fetch changes since cursor '%s'.", cursor);
+    }
+
+    users := make([]*lmstypes.User, 0, len(userChanges));
+    for _, user := range userChanges {
+        userCopy := *user;
+        users = append(users, &userCopy);
+    }
+
+    return users, nextCursor(), nil;
+}
+
+func nextCursor() string {
+    if (userChanges == nil) {
+        return "";
+    }
+
+    userChangesCount++;
+    return fmt.Sprintf("%s%d", CURSOR_PREFIX, userChangesCount);
+}
+
 func UserFromAGUser(user *model.User) *lmstypes.User {
     return &lmstypes.User{
         ID: "lms-" + user.Email,
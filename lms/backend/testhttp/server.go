@@ -0,0 +1,197 @@
+// A test HTTP server that replays recorded LMS requests (see common.SavedHTTPRequest).
+// Each LMS backend supplies its own recorded requests (usually embedded from testdata/http),
+// and any LMS-specific handling (like checking credentials) as Options.
+package testhttp
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "io/fs"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strings"
+    "sync"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+type Options struct {
+    // Query parameters that are not part of a request's identity (e.g. signatures that change with time).
+    IgnoredParams []string
+
+    // Check a request that matched a recorded request.
+    // If the request should not get the recorded response (e.g. bad credentials),
+    // then write the LMS's error response and return false.
+    // The request body can still be read.
+    // Nil means that all requests get the recorded response.
+    CheckRequest func(response http.ResponseWriter, request *http.Request) bool
+}
+
+type Server struct {
+    server *httptest.Server
+    requests map[string]*common.SavedHTTPRequest
+    options Options
+
+    // The most recent body sent to each path: {"<method>::<path>": body}.
+    bodies map[string][]byte
+    bodiesLock sync.Mutex
+}
+
+// Load all the recorded requests (JSON files) in |dataDir| and start serving them.
+func Start(dataDir fs.FS, options Options) (*Server, error) {
+    server := &Server{
+        options: options,
+        bodies: make(map[string][]byte),
+    };
+
+    requests, err := server.loadRequests(dataDir);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    server.requests = requests;
+    server.server = httptest.NewServer(server);
+
+    return server, nil;
+}
+
+func (this *Server) URL() string {
+    return this.server.URL;
+}
+
+func (this *Server) Close() {
+    this.server.Close();
+}
+
+// Get the most recent body sent to a path (nil if nothing was sent).
+func (this *Server) GetBody(method string, path string) []byte {
+    this.bodiesLock.Lock();
+    defer this.bodiesLock.Unlock();
+
+    return this.bodies[method + "::" + path];
+}
+
+// Get the most recent body sent to a path as a JSON object (nil if nothing was sent).
+func (this *Server) GetJSONBody(method string, path string) map[string]any {
+    data := this.GetBody(method, path);
+    if (data == nil) {
+        return nil;
+    }
+
+    var body map[string]any;
+    err := json.Unmarshal(data, &body);
+    if (err != nil) {
+        panic(fmt.Sprintf("Body sent to '%s::%s' is not a JSON object: '%v'.", method, path, err));
+    }
+
+    return body;
+}
+
+func (this *Server) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+    key, err := this.requestKey(request.Method, request.URL);
+    if (err != nil) {
+        panic(err);
+    }
+
+    savedRequest := this.requests[key];
+    if (savedRequest == nil) {
+        fmt.Printf("ERROR 404: '%s'.\n", key);
+        http.NotFound(response, request);
+        return;
+    }
+
+    body, err := io.ReadAll(request.Body);
+    if (err != nil) {
+        panic(err);
+    }
+
+    // Let the body be read again when checking the request.
+    request.Body = io.NopCloser(bytes.NewReader(body));
+
+    if ((this.options.CheckRequest != nil) && !this.options.CheckRequest(response, request)) {
+        return;
+    }
+
+    if (len(body) > 0) {
+        this.bodiesLock.Lock();
+        this.bodies[request.Method + "::" + request.URL.Path] = body;
+        this.bodiesLock.Unlock();
+    }
+
+    for key, value := range savedRequest.ResponseHeaders {
+        response.Header()[key] = value;
+    }
+
+    response.WriteHeader(savedRequest.ResponseCode);
+    _, err = response.Write([]byte(savedRequest.ResponseBody));
+    if (err != nil) {
+        panic(err);
+    }
+}
+
+// Queries are normalized, since parameter order does not matter.
+func (this *Server) requestKey(method string, uri *url.URL) (string, error) {
+    query, err := url.ParseQuery(uri.RawQuery);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to parse query '%s': '%w'.", uri.RawQuery, err);
+    }
+
+    for _, param := range this.options.IgnoredParams {
+        query.Del(param);
+    }
+
+    return fmt.Sprintf("%s::%s?%s", method, uri.Path, query.Encode()), nil;
+}
+
+func (this *Server) loadRequests(dataDir fs.FS) (map[string]*common.SavedHTTPRequest, error) {
+    requests := make(map[string]*common.SavedHTTPRequest);
+
+    err := fs.WalkDir(dataDir, ".", func(path string, info fs.DirEntry, err error) error {
+        if (err != nil) {
+            return err;
+        }
+
+        if (info.IsDir()) {
+            return nil;
+        }
+
+        if (!strings.HasSuffix(info.Name(), ".json")) {
+            return nil;
+        }
+
+        data, err := fs.ReadFile(dataDir, path);
+        if (err != nil) {
+            return fmt.Errorf("Failed to read test file '%s': '%w'.", path, err);
+        }
+
+        var request common.SavedHTTPRequest;
+        err = util.JSONFromString(string(data), &request);
+        if (err != nil) {
+            return fmt.Errorf("Failed to JSON parse test file '%s': '%w'.", path, err);
+        }
+
+        uri, err := url.Parse(request.URL);
+        if (err != nil) {
+            return fmt.Errorf("Failed to parse test URL '%s': '%w'.", request.URL, err);
+        }
+
+        key, err := this.requestKey(request.Method, uri);
+        if (err != nil) {
+            return err;
+        }
+
+        requests[key] = &request;
+
+        return nil;
+    });
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to walk test dir: '%w'.", err);
+    }
+
+    return requests, nil;
+}
@@ -3,9 +3,14 @@ package lms
 import (
     "fmt"
 
+    "github.com/eriq-augustine/autograder/lms/backend/blackboard"
+    "github.com/eriq-augustine/autograder/lms/backend/brightspace"
     "github.com/eriq-augustine/autograder/lms/backend/canvas"
+    "github.com/eriq-augustine/autograder/lms/backend/lti"
+    "github.com/eriq-augustine/autograder/lms/backend/moodle"
     "github.com/eriq-augustine/autograder/lms/backend/test"
     "github.com/eriq-augustine/autograder/lms/lmstypes"
+    ltitool "github.com/eriq-augustine/autograder/lti"
     "github.com/eriq-augustine/autograder/model"
 )
 
@@ -24,19 +29,86 @@ type lmsBackend interface {
     FetchUser(email string) (*lmstypes.User, error)
 }
 
-func getBackend(course *model.Course) (lmsBackend, error) {
+// Backends that can fetch only the users whose enrollments have changed (optional).
+// A cursor marks a point in the LMS's history, and is opaque outside of the backend.
+type userChangesBackend interface {
+    // Fetch all users, along with a cursor for the LMS's current state.
+    // The cursor will be empty if the LMS cannot (currently) report changes.
+    FetchUsersWithCursor() ([]*lmstypes.User, string, error)
+
+    // Fetch the users whose enrollments changed since the cursor, along with a new cursor.
+    // Users whose enrollments were removed will be marked as such.
+    FetchUsersChangedSince(cursor string) ([]*lmstypes.User, string, error)
+}
+
+// Get the course's backend (wrapped to record metrics).
+func getBackend(course *model.Course) (*metricsBackend, error) {
     adapter := course.GetLMSAdapter();
     if (adapter == nil) {
         return nil, fmt.Errorf("Course '%s' has no LMS information.", course.GetID());
     }
 
+    backend, err := newBackend(course, adapter);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return &metricsBackend{adapter.Type, backend}, nil;
+}
+
+// Get the user changes operations of a course's backend (wrapped to record metrics),
+// or nil if the course's LMS cannot report user changes.
+func getUserChangesBackend(backend *metricsBackend) userChangesBackend {
+    changesBackend, ok := backend.backend.(userChangesBackend);
+    if (!ok) {
+        return nil;
+    }
+
+    return &metricsUserChangesBackend{backend, changesBackend};
+}
+
+func newBackend(course *model.Course, adapter *model.LMSAdapter) (lmsBackend, error) {
     switch (adapter.Type) {
+        case model.LMS_TYPE_BLACKBOARD:
+            backend, err := blackboard.NewBackend(adapter.LMSCourseID, adapter.AppKey, adapter.AppSecret, adapter.BaseURL);
+            if (err != nil) {
+                return nil, err;
+            }
+
+            return backend, nil;
+        case model.LMS_TYPE_BRIGHTSPACE:
+            backend, err := brightspace.NewBackend(adapter.LMSCourseID, adapter.AppKey, adapter.AppSecret, adapter.UserID, adapter.UserKey, adapter.BaseURL);
+            if (err != nil) {
+                return nil, err;
+            }
+
+            return backend, nil;
         case model.LMS_TYPE_CANVAS:
             backend, err := canvas.NewBackend(adapter.LMSCourseID, adapter.APIToken, adapter.BaseURL);
             if (err != nil) {
                 return nil, err;
             }
 
+            return backend, nil;
+        case model.LMS_TYPE_LTI:
+            // Service endpoints are learned from launches, but can be overridden in the config.
+            endpoints, err := ltitool.GetServiceEndpoints(course);
+            if (err != nil) {
+                return nil, err;
+            }
+
+            backend, err := lti.NewBackend(adapter.LMSCourseID, adapter.LTI, endpoints);
+            if (err != nil) {
+                return nil, err;
+            }
+
+            return backend, nil;
+        case model.LMS_TYPE_MOODLE:
+            backend, err := moodle.NewBackend(adapter.LMSCourseID, adapter.APIToken, adapter.BaseURL);
+            if (err != nil) {
+                return nil, err;
+            }
+
             return backend, nil;
         case model.LMS_TYPE_TEST:
             backend, err := test.NewBackend(course.GetID());
@@ -131,3 +203,34 @@ func FetchUser(course *model.Course, email string) (*lmstypes.User, error) {
     return backend.FetchUser(email);
 }
 
+// Fetch all users, along with a cursor that can be passed to FetchUsersChangedSince().
+// The cursor will be empty if the course's LMS cannot report changes.
+func FetchUsersWithCursor(course *model.Course) ([]*lmstypes.User, string, error) {
+    backend, err := getBackend(course);
+    if (err != nil) {
+        return nil, "", err;
+    }
+
+    changesBackend := getUserChangesBackend(backend);
+    if (changesBackend == nil) {
+        users, err := backend.FetchUsers();
+        return users, "", err;
+    }
+
+    return changesBackend.FetchUsersWithCursor();
+}
+
+// Fetch the users whose enrollments changed since a cursor from a previous fetch, along with a new cursor.
+func FetchUsersChangedSince(course *model.Course, cursor string) ([]*lmstypes.User, string, error) {
+    backend, err := getBackend(course);
+    if (err != nil) {
+        return nil, "", err;
+    }
+
+    changesBackend := getUserChangesBackend(backend);
+    if (changesBackend == nil) {
+        return nil, "", fmt.Errorf("LMS type '%s' cannot fetch user changes.", backend.lmsType);
+    }
+
+    return changesBackend.FetchUsersChangedSince(cursor);
+}
@@ -4,6 +4,8 @@ import (
     "errors"
     "fmt"
 
+    "github.com/rs/zerolog/log"
+
     "github.com/eriq-augustine/autograder/common"
     "github.com/eriq-augustine/autograder/db"
     "github.com/eriq-augustine/autograder/lms"
@@ -19,7 +21,7 @@ func SyncLMS(course *model.Course, dryRun bool, sendEmails bool) (*model.LMSSync
         return nil, nil;
     }
 
-    userSync, err := SyncAllLMSUsers(course, dryRun, sendEmails);
+    userSync, err := SyncLMSUsers(course, dryRun, sendEmails);
     if (err != nil) {
         return nil, err;
     }
@@ -37,21 +39,148 @@ func SyncLMS(course *model.Course, dryRun bool, sendEmails bool) (*model.LMSSync
     return result, nil;
 }
 
-// Sync users with the provided LMS.
+// Sync users with the course's LMS, using the cheapest strategy that the course and LMS allow.
+// Courses that do not use incremental user syncs always do a full sync.
+// Otherwise, a full sync is done when the cached LMS state cannot be trusted (see checkUserCache()),
+// only the users that changed are fetched when the LMS can report changes,
+// and all users are fetched but only the changed ones are checked when it cannot.
+func SyncLMSUsers(course *model.Course, dryRun bool, sendEmails bool) (*model.UserSyncResult, error) {
+    if (!usesIncrementalUserSync(course)) {
+        return SyncAllLMSUsers(course, dryRun, sendEmails);
+    }
+
+    localUsers, err := db.GetUsers(course);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch local users: '%w'.", err);
+    }
+
+    cache, err := loadUserCache(course);
+    if (err != nil) {
+        log.Warn().Err(err).Str("course", course.GetID()).Msg("Failed to load LMS user cache.");
+        cache = nil;
+    }
+
+    reason := checkUserCache(course, cache, localUsers);
+    if (reason != "") {
+        log.Debug().Str("course", course.GetID()).Str("reason", reason).Msg("Doing a full LMS user sync.");
+        return SyncAllLMSUsers(course, dryRun, sendEmails);
+    }
+
+    if (cache.Cursor != "") {
+        result, err := syncChangedLMSUsers(course, cache, dryRun, sendEmails);
+        if (err == nil) {
+            return result, nil;
+        }
+
+        // Cursors may expire, so fall back to looking at all users.
+        log.Warn().Err(err).Str("course", course.GetID()).Msg("Failed to sync LMS user changes, checking all LMS users instead.");
+    }
+
+    return syncDiffLMSUsers(course, cache, dryRun, sendEmails);
+}
+
+// Fetch and check all users.
+// Courses that use incremental user syncs will also rebuild their cache (unless this is a dry run).
 func SyncAllLMSUsers(course *model.Course, dryRun bool, sendEmails bool) (*model.UserSyncResult, error) {
-    lmsUsersSlice, err := lms.FetchUsers(course);
+    incremental := usesIncrementalUserSync(course);
+
+    var lmsUsersSlice []*lmstypes.User;
+    var cursor string;
+    var err error;
+
+    if (incremental) {
+        lmsUsersSlice, cursor, err = lms.FetchUsersWithCursor(course);
+    } else {
+        lmsUsersSlice, err = lms.FetchUsers(course);
+    }
+
     if (err != nil) {
         return nil, fmt.Errorf("Failed to fetch LMS users: '%w'.", err);
     }
 
-    lmsUsers := make(map[string]*lmstypes.User, len(lmsUsersSlice));
-    for _, lmsUser := range lmsUsersSlice {
-        if (lmsUser.Email != "") {
-            lmsUsers[lmsUser.Email] = lmsUser;
+    lmsUsers := toEmailMap(lmsUsersSlice);
+
+    result, err := syncLMSUsers(course, dryRun, sendEmails, lmsUsers, nil, model.USER_SYNC_STRATEGY_FULL);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    if (incremental && !dryRun) {
+        cache := &userCache{
+            Cursor: cursor,
+            FullSyncTime: common.NowTimestamp(),
+            Users: lmsUsers,
+        };
+
+        // The next sync will just be a full sync if this fails.
+        err = saveUserCache(course, cache);
+        if (err != nil) {
+            log.Warn().Err(err).Str("course", course.GetID()).Msg("Failed to save LMS user cache.");
         }
     }
 
-    return syncLMSUsers(course, dryRun, sendEmails, lmsUsers, nil);
+    return result, nil;
+}
+
+// Fetch only the users that changed since the cached cursor.
+func syncChangedLMSUsers(course *model.Course, cache *userCache, dryRun bool, sendEmails bool) (*model.UserSyncResult, error) {
+    changes, cursor, err := lms.FetchUsersChangedSince(course, cache.Cursor);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch LMS user changes: '%w'.", err);
+    }
+
+    lmsUsers := make(map[string]*lmstypes.User, len(cache.Users));
+    for email, user := range cache.Users {
+        lmsUsers[email] = user;
+    }
+
+    changedEmails := applyLMSUserChanges(lmsUsers, changes);
+
+    return syncCachedLMSUsers(course, cache, dryRun, sendEmails, lmsUsers, changedEmails, cursor, model.USER_SYNC_STRATEGY_INCREMENTAL);
+}
+
+// Fetch all users, but only check the ones that differ from the cache.
+func syncDiffLMSUsers(course *model.Course, cache *userCache, dryRun bool, sendEmails bool) (*model.UserSyncResult, error) {
+    lmsUsersSlice, cursor, err := lms.FetchUsersWithCursor(course);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch LMS users: '%w'.", err);
+    }
+
+    lmsUsers := toEmailMap(lmsUsersSlice);
+    changedEmails := diffLMSUsers(cache.Users, lmsUsers);
+
+    return syncCachedLMSUsers(course, cache, dryRun, sendEmails, lmsUsers, changedEmails, cursor, model.USER_SYNC_STRATEGY_DIFF);
+}
+
+// Sync the changed users and update the cache (unless this is a dry run).
+func syncCachedLMSUsers(course *model.Course, cache *userCache, dryRun bool, sendEmails bool,
+        lmsUsers map[string]*lmstypes.User, changedEmails []string, cursor string, strategy string) (*model.UserSyncResult, error) {
+    var result *model.UserSyncResult;
+    var err error;
+
+    // An empty list of emails would mean checking all users.
+    if (len(changedEmails) == 0) {
+        result = model.NewUserSyncResultWithStrategy(strategy);
+    } else {
+        result, err = syncLMSUsers(course, dryRun, sendEmails, lmsUsers, changedEmails, strategy);
+        if (err != nil) {
+            return nil, err;
+        }
+    }
+
+    if (dryRun) {
+        return result, nil;
+    }
+
+    cache.Cursor = cursor;
+    cache.Users = lmsUsers;
+
+    err = saveUserCache(course, cache);
+    if (err != nil) {
+        log.Warn().Err(err).Str("course", course.GetID()).Msg("Failed to save LMS user cache.");
+    }
+
+    return result, nil;
 }
 
 func SyncLMSUserEmail(course *model.Course, email string, dryRun bool, sendEmails bool) (*model.UserSyncResult, error) {
@@ -70,14 +199,36 @@ func SyncLMSUserEmails(course *model.Course, emails []string, dryRun bool, sendE
         lmsUsers[lmsUser.Email] = lmsUser;
     }
 
-    return syncLMSUsers(course, dryRun, sendEmails, lmsUsers, emails);
+    return syncLMSUsers(course, dryRun, sendEmails, lmsUsers, emails, model.USER_SYNC_STRATEGY_SELECTED);
+}
+
+// Sync users that were already fetched from the LMS (e.g., from an LTI launch).
+// Only the emails of the provided users will be checked.
+func SyncProvidedLMSUsers(course *model.Course, users []*lmstypes.User, dryRun bool, sendEmails bool) (*model.UserSyncResult, error) {
+    lmsUsers := make(map[string]*lmstypes.User, len(users));
+    emails := make([]string, 0, len(users));
+
+    for _, lmsUser := range users {
+        if (lmsUser.Email == "") {
+            continue;
+        }
+
+        lmsUsers[lmsUser.Email] = lmsUser;
+        emails = append(emails, lmsUser.Email);
+    }
+
+    if (len(emails) == 0) {
+        return model.NewUserSyncResultWithStrategy(model.USER_SYNC_STRATEGY_SELECTED), nil;
+    }
+
+    return syncLMSUsers(course, dryRun, sendEmails, lmsUsers, emails, model.USER_SYNC_STRATEGY_SELECTED);
 }
 
 // Sync users.
 // If |syncEmails| is not empty, then only emails in it will be checked/resolved.
 // Otherwise, all emails from local and LMS users will be checked.
 func syncLMSUsers(course *model.Course, dryRun bool, sendEmails bool, lmsUsers map[string]*lmstypes.User,
-        syncEmails []string) (*model.UserSyncResult, error) {
+        syncEmails []string, strategy string) (*model.UserSyncResult, error) {
     localUsers, err := db.GetUsers(course);
     if (err != nil) {
         return nil, fmt.Errorf("Failed to fetch local users: '%w'.", err);
@@ -87,7 +238,7 @@ func syncLMSUsers(course *model.Course, dryRun bool, sendEmails bool, lmsUsers m
         syncEmails = getAllEmails(localUsers, lmsUsers);
     }
 
-    syncResult := model.NewUserSyncResult();
+    syncResult := model.NewUserSyncResultWithStrategy(strategy);
 
     for _, email := range syncEmails {
         resolveResult, err := resolveUserSync(course, localUsers, lmsUsers, email);
@@ -125,6 +276,18 @@ func syncLMSUsers(course *model.Course, dryRun bool, sendEmails bool, lmsUsers m
     return syncResult, nil;
 }
 
+// Users without an email cannot be matched with local users, and are skipped.
+func toEmailMap(lmsUsers []*lmstypes.User) map[string]*lmstypes.User {
+    users := make(map[string]*lmstypes.User, len(lmsUsers));
+    for _, lmsUser := range lmsUsers {
+        if ((lmsUser.Email != "") && !lmsUser.Removed) {
+            users[lmsUser.Email] = lmsUser;
+        }
+    }
+
+    return users;
+}
+
 func mergeUsers(localUser *model.User, lmsUser *lmstypes.User, mergeAttributes bool) bool {
     changed := false;
 
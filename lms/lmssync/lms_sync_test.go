@@ -23,6 +23,8 @@ type SyncLMSTestCase struct {
 func reset() {
     db.ResetForTesting();
     lmstest.ClearUsersModifier();
+    lmstest.ClearUserChanges();
+    util.RemoveDirent(getUserCachePath(db.MustGetTestCourse()));
 }
 
 func TestCourseSyncLMSUsers(test *testing.T) {
@@ -0,0 +1,188 @@
+package lmssync
+
+// A local cache of a course's LMS users, used by incremental user syncs.
+// The cache is only trusted if the local users (and sync options) have not changed since the cache was written,
+// otherwise a full sync is done (which rebuilds the cache).
+
+import (
+    "fmt"
+    "path/filepath"
+    "slices"
+    "strings"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const USER_CACHE_FILENAME = "lms-users.json";
+
+type userCache struct {
+    // The LMS's change cursor as of the last sync (empty if the LMS cannot report changes).
+    Cursor string `json:"cursor"`
+    // When all LMS users were last fetched and checked.
+    FullSyncTime common.Timestamp `json:"full-sync-time"`
+    // A fingerprint of the local users (and sync options) right after the last sync.
+    LocalFingerprint string `json:"local-fingerprint"`
+    // The LMS users as of the last sync: {email: user}.
+    Users map[string]*lmstypes.User `json:"users"`
+}
+
+func usesIncrementalUserSync(course *model.Course) bool {
+    adapter := course.GetLMSAdapter();
+    return ((adapter != nil) && adapter.IncrementalUserSync);
+}
+
+func getUserCachePath(course *model.Course) string {
+    return filepath.Join(course.GetCacheDir(), USER_CACHE_FILENAME);
+}
+
+// Returns nil (with no error) if there is no cache.
+func loadUserCache(course *model.Course) (*userCache, error) {
+    path := getUserCachePath(course);
+    if (!util.PathExists(path)) {
+        return nil, nil;
+    }
+
+    var cache userCache;
+    err := util.JSONFromFile(path, &cache);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to load LMS user cache '%s': '%w'.", path, err);
+    }
+
+    if (cache.Users == nil) {
+        cache.Users = make(map[string]*lmstypes.User);
+    }
+
+    return &cache, nil;
+}
+
+// Save the cache, fingerprinting the local users as they are now.
+func saveUserCache(course *model.Course, cache *userCache) error {
+    localUsers, err := db.GetUsers(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to fetch local users: '%w'.", err);
+    }
+
+    cache.LocalFingerprint = getLocalFingerprint(course, localUsers);
+
+    err = util.MkDir(course.GetCacheDir());
+    if (err != nil) {
+        return fmt.Errorf("Failed to make cache dir: '%w'.", err);
+    }
+
+    err = util.ToJSONFileIndent(cache, getUserCachePath(course));
+    if (err != nil) {
+        return fmt.Errorf("Failed to save LMS user cache: '%w'.", err);
+    }
+
+    return nil;
+}
+
+// Get the reason that the cache cannot be used for an incremental sync,
+// or an empty string if the cache can be used.
+func checkUserCache(course *model.Course, cache *userCache, localUsers map[string]*model.User) string {
+    if (cache == nil) {
+        return "no cache";
+    }
+
+    if (cache.LocalFingerprint != getLocalFingerprint(course, localUsers)) {
+        return "local users changed";
+    }
+
+    fullSyncHours := config.LMS_SYNC_FULL_HOURS.Get();
+    if (fullSyncHours > 0) {
+        lastFullSync, err := cache.FullSyncTime.Time();
+        if ((err != nil) || (time.Since(lastFullSync) > (time.Duration(fullSyncHours) * time.Hour))) {
+            return "full sync is due";
+        }
+    }
+
+    return "";
+}
+
+// Only the attributes that an LMS sync looks at are used.
+func getLocalFingerprint(course *model.Course, localUsers map[string]*model.User) string {
+    adapter := course.GetLMSAdapter();
+
+    var builder strings.Builder;
+    builder.WriteString(util.MustToJSON([]bool{adapter.SyncUserAttributes, adapter.SyncUserAdds, adapter.SyncUserRemoves}));
+
+    emails := make([]string, 0, len(localUsers));
+    for email, _ := range localUsers {
+        emails = append(emails, email);
+    }
+    slices.Sort(emails);
+
+    for _, email := range emails {
+        user := localUsers[email];
+        builder.WriteString(util.MustToJSON([]any{user.Email, user.Name, user.Role, user.LMSID}));
+    }
+
+    return util.Sha256HexFromString(builder.String());
+}
+
+// Get the emails of users that differ between the old and new LMS users.
+func diffLMSUsers(oldUsers map[string]*lmstypes.User, newUsers map[string]*lmstypes.User) []string {
+    emails := make([]string, 0);
+
+    for email, newUser := range newUsers {
+        oldUser := oldUsers[email];
+        if ((oldUser == nil) || (*oldUser != *newUser)) {
+            emails = append(emails, email);
+        }
+    }
+
+    for email, _ := range oldUsers {
+        _, ok := newUsers[email];
+        if (!ok) {
+            emails = append(emails, email);
+        }
+    }
+
+    slices.Sort(emails);
+    return emails;
+}
+
+// Apply changes from the LMS to the cached users.
+// Returns the emails of all users affected by the changes.
+func applyLMSUserChanges(users map[string]*lmstypes.User, changes []*lmstypes.User) []string {
+    emailSet := make(map[string]bool);
+
+    for _, change := range changes {
+        // Users can change their email, so remove any old email for the same LMS user.
+        if (change.ID != "") {
+            for email, user := range users {
+                if ((user.ID == change.ID) && (email != change.Email)) {
+                    delete(users, email);
+                    emailSet[email] = true;
+                }
+            }
+        }
+
+        if (change.Email == "") {
+            continue;
+        }
+
+        emailSet[change.Email] = true;
+
+        if (change.Removed) {
+            delete(users, change.Email);
+        } else {
+            user := *change;
+            users[change.Email] = &user;
+        }
+    }
+
+    emails := make([]string, 0, len(emailSet));
+    for email, _ := range emailSet {
+        emails = append(emails, email);
+    }
+    slices.Sort(emails);
+
+    return emails;
+}
@@ -0,0 +1,212 @@
+package lmssync
+
+import (
+    "reflect"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/db"
+    lmstest "github.com/eriq-augustine/autograder/lms/backend/test"
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+type userSyncExpectation struct {
+    strategy string
+    add []string
+    mod []string
+    del []string
+    unchanged int
+}
+
+// The test LMS cannot report changes, so incremental syncs diff against the cache.
+func TestSyncLMSUsersDiff(test *testing.T) {
+    defer reset();
+    reset();
+
+    course := getIncrementalTestCourse();
+
+    // No cache, so a full sync (which sets everyone's LMS ID).
+    checkUserSync(test, "initial", course, false, userSyncExpectation{
+        strategy: model.USER_SYNC_STRATEGY_FULL,
+        mod: []string{"admin@test.com", "grader@test.com", "other@test.com", "owner@test.com", "student@test.com"},
+    });
+
+    // Nothing changed.
+    checkUserSync(test, "no changes", course, false, userSyncExpectation{strategy: model.USER_SYNC_STRATEGY_DIFF});
+
+    // Only the changed users are checked.
+    lmstest.SetUsersModifier(func(users []*lmstypes.User) []*lmstypes.User {
+        users = slices.DeleteFunc(users, func(user *lmstypes.User) bool {
+            return (user.Email == "other@test.com");
+        });
+
+        return append(users, &lmstypes.User{ID: "lms-add@test.com", Name: "add", Email: "add@test.com", Role: model.RoleStudent});
+    });
+
+    // Dry runs do not change the cache.
+    checkUserSync(test, "dry run", course, true, userSyncExpectation{
+        strategy: model.USER_SYNC_STRATEGY_DIFF,
+        add: []string{"add@test.com"},
+        del: []string{"other@test.com"},
+    });
+
+    checkUserSync(test, "changes", course, false, userSyncExpectation{
+        strategy: model.USER_SYNC_STRATEGY_DIFF,
+        add: []string{"add@test.com"},
+        del: []string{"other@test.com"},
+    });
+
+    // Changing local users outside of a sync forces a full sync.
+    user := db.MustGetUsers(course)["student@test.com"];
+    user.LMSID = "";
+    err := db.SaveUser(course, user);
+    if (err != nil) {
+        test.Fatalf("Failed to save user: '%v'.", err);
+    }
+
+    // Removed users are still stored locally, so they are removed again.
+    checkUserSync(test, "local change", course, false, userSyncExpectation{
+        strategy: model.USER_SYNC_STRATEGY_FULL,
+        mod: []string{"student@test.com"},
+        del: []string{"other@test.com"},
+        unchanged: 4,
+    });
+}
+
+func TestSyncLMSUsersIncremental(test *testing.T) {
+    defer reset();
+    reset();
+
+    course := getIncrementalTestCourse();
+    lmstest.SetUserChanges([]*lmstypes.User{});
+
+    checkUserSync(test, "initial", course, false, userSyncExpectation{
+        strategy: model.USER_SYNC_STRATEGY_FULL,
+        mod: []string{"admin@test.com", "grader@test.com", "other@test.com", "owner@test.com", "student@test.com"},
+    });
+
+    checkUserSync(test, "no changes", course, false, userSyncExpectation{strategy: model.USER_SYNC_STRATEGY_INCREMENTAL});
+
+    lmstest.SetUserChanges([]*lmstypes.User{
+        &lmstypes.User{ID: "lms-add@test.com", Name: "add", Email: "add@test.com", Role: model.RoleStudent},
+        &lmstypes.User{ID: "lms-grader@test.com", Name: "grader", Email: "grader@test.com", Role: model.RoleAdmin},
+        &lmstypes.User{ID: "lms-other@test.com", Removed: true},
+    });
+
+    checkUserSync(test, "changes", course, false, userSyncExpectation{
+        strategy: model.USER_SYNC_STRATEGY_INCREMENTAL,
+        add: []string{"add@test.com"},
+        mod: []string{"grader@test.com"},
+        del: []string{"other@test.com"},
+    });
+
+    localUsers := db.MustGetUsers(course);
+    if ((localUsers["add@test.com"] == nil) || (localUsers["grader@test.com"].Role != model.RoleAdmin)) {
+        test.Fatalf("Local users were not updated: '%s'.", util.MustToJSONIndent(localUsers));
+    }
+
+    // Failing to fetch changes falls back to a diff.
+    // The test LMS reports local users, so other (who is still stored locally) shows up as a change.
+    lmstest.ClearUserChanges();
+    checkUserSync(test, "bad cursor", course, false, userSyncExpectation{strategy: model.USER_SYNC_STRATEGY_DIFF, unchanged: 1});
+
+    // An old cache forces a full sync.
+    cache, err := loadUserCache(course);
+    if ((err != nil) || (cache == nil)) {
+        test.Fatalf("Failed to load cache: '%v'.", err);
+    }
+
+    cache.FullSyncTime = common.TimestampFromTime(time.Now().Add(-1000 * time.Hour));
+    err = saveUserCache(course, cache);
+    if (err != nil) {
+        test.Fatalf("Failed to save cache: '%v'.", err);
+    }
+
+    checkUserSync(test, "old cache", course, false, userSyncExpectation{strategy: model.USER_SYNC_STRATEGY_FULL, unchanged: 6});
+}
+
+func TestApplyLMSUserChanges(test *testing.T) {
+    users := map[string]*lmstypes.User{
+        "a@test.com": &lmstypes.User{ID: "a", Email: "a@test.com", Role: model.RoleStudent},
+        "b@test.com": &lmstypes.User{ID: "b", Email: "b@test.com", Role: model.RoleStudent},
+        "c@test.com": &lmstypes.User{ID: "c", Email: "c@test.com", Role: model.RoleStudent},
+    };
+
+    changes := []*lmstypes.User{
+        // Changed email.
+        &lmstypes.User{ID: "a", Email: "z@test.com", Role: model.RoleStudent},
+        // Removed without an email.
+        &lmstypes.User{ID: "b", Removed: true},
+        // New.
+        &lmstypes.User{ID: "d", Email: "d@test.com", Role: model.RoleGrader},
+    };
+
+    expectedEmails := []string{"a@test.com", "b@test.com", "d@test.com", "z@test.com"};
+    expectedUsers := map[string]*lmstypes.User{
+        "c@test.com": &lmstypes.User{ID: "c", Email: "c@test.com", Role: model.RoleStudent},
+        "d@test.com": &lmstypes.User{ID: "d", Email: "d@test.com", Role: model.RoleGrader},
+        "z@test.com": &lmstypes.User{ID: "a", Email: "z@test.com", Role: model.RoleStudent},
+    };
+
+    emails := applyLMSUserChanges(users, changes);
+
+    if (!reflect.DeepEqual(expectedEmails, emails)) {
+        test.Fatalf("Unexpected changed emails. Expected: '%v', Actual: '%v'.", expectedEmails, emails);
+    }
+
+    if (!reflect.DeepEqual(expectedUsers, users)) {
+        test.Fatalf("Unexpected users. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expectedUsers), util.MustToJSONIndent(users));
+    }
+}
+
+func getIncrementalTestCourse() *model.Course {
+    course := db.MustGetTestCourse();
+
+    course.GetLMSAdapter().IncrementalUserSync = true;
+    course.GetLMSAdapter().SyncUserAttributes = true;
+    course.GetLMSAdapter().SyncUserAdds = true;
+    course.GetLMSAdapter().SyncUserRemoves = true;
+
+    return course;
+}
+
+func checkUserSync(test *testing.T, label string, course *model.Course, dryRun bool, expected userSyncExpectation) {
+    result, err := SyncLMSUsers(course, dryRun, false);
+    if (err != nil) {
+        test.Fatalf("%s: User sync failed: '%v'.", label, err);
+    }
+
+    if (result.Strategy != expected.strategy) {
+        test.Fatalf("%s: Unexpected strategy. Expected: '%s', Actual: '%s'.", label, expected.strategy, result.Strategy);
+    }
+
+    checks := []struct{name string; expected []string; actual []*model.User}{
+        {"add", expected.add, result.Add},
+        {"mod", expected.mod, result.Mod},
+        {"del", expected.del, result.Del},
+    };
+
+    for _, check := range checks {
+        emails := make([]string, 0, len(check.actual));
+        for _, user := range check.actual {
+            emails = append(emails, user.Email);
+        }
+        slices.Sort(emails);
+
+        if (check.expected == nil) {
+            check.expected = []string{};
+        }
+
+        if (!reflect.DeepEqual(check.expected, emails)) {
+            test.Fatalf("%s: Unexpected %s users. Expected: '%v', Actual: '%v'.", label, check.name, check.expected, emails);
+        }
+    }
+
+    if (len(result.Unchanged) != expected.unchanged) {
+        test.Fatalf("%s: Unexpected number of unchanged users. Expected: %d, Actual: %d.", label, expected.unchanged, len(result.Unchanged));
+    }
+}
@@ -11,6 +11,9 @@ type User struct {
     Name string
     Email string
     Role model.UserRole
+
+    // Only set when fetching changes: the user's enrollment was removed (or deactivated).
+    Removed bool
 }
 
 type SubmissionScore struct {
@@ -0,0 +1,102 @@
+package lms
+
+// Record the latency and errors of every LMS operation.
+
+import (
+    "time"
+
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/metrics"
+)
+
+type metricsBackend struct {
+    lmsType string
+    backend lmsBackend
+}
+
+func (this *metricsBackend) observe(operation string, startTime time.Time, err error) {
+    metrics.ObserveLMSRequest(this.lmsType, operation, err, time.Since(startTime));
+}
+
+func (this *metricsBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+    startTime := time.Now();
+    result, err := this.backend.FetchAssignments();
+    this.observe("fetch-assignments", startTime, err);
+    return result, err;
+}
+
+func (this *metricsBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+    startTime := time.Now();
+    result, err := this.backend.FetchAssignment(assignmentID);
+    this.observe("fetch-assignment", startTime, err);
+    return result, err;
+}
+
+func (this *metricsBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+    startTime := time.Now();
+    err := this.backend.UpdateComments(assignmentID, comments);
+    this.observe("update-comments", startTime, err);
+    return err;
+}
+
+func (this *metricsBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+    startTime := time.Now();
+    err := this.backend.UpdateComment(assignmentID, comment);
+    this.observe("update-comment", startTime, err);
+    return err;
+}
+
+func (this *metricsBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+    startTime := time.Now();
+    result, err := this.backend.FetchAssignmentScores(assignmentID);
+    this.observe("fetch-assignment-scores", startTime, err);
+    return result, err;
+}
+
+func (this *metricsBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+    startTime := time.Now();
+    result, err := this.backend.FetchAssignmentScore(assignmentID, userID);
+    this.observe("fetch-assignment-score", startTime, err);
+    return result, err;
+}
+
+func (this *metricsBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+    startTime := time.Now();
+    err := this.backend.UpdateAssignmentScores(assignmentID, scores);
+    this.observe("update-assignment-scores", startTime, err);
+    return err;
+}
+
+func (this *metricsBackend) FetchUsers() ([]*lmstypes.User, error) {
+    startTime := time.Now();
+    result, err := this.backend.FetchUsers();
+    this.observe("fetch-users", startTime, err);
+    return result, err;
+}
+
+func (this *metricsBackend) FetchUser(email string) (*lmstypes.User, error) {
+    startTime := time.Now();
+    result, err := this.backend.FetchUser(email);
+    this.observe("fetch-user", startTime, err);
+    return result, err;
+}
+
+// The optional user changes operations of a backend (see getUserChangesBackend()).
+type metricsUserChangesBackend struct {
+    *metricsBackend
+    changesBackend userChangesBackend
+}
+
+func (this *metricsUserChangesBackend) FetchUsersWithCursor() ([]*lmstypes.User, string, error) {
+    startTime := time.Now();
+    result, cursor, err := this.changesBackend.FetchUsersWithCursor();
+    this.observe("fetch-users", startTime, err);
+    return result, cursor, err;
+}
+
+func (this *metricsUserChangesBackend) FetchUsersChangedSince(cursor string) ([]*lmstypes.User, string, error) {
+    startTime := time.Now();
+    result, newCursor, err := this.changesBackend.FetchUsersChangedSince(cursor);
+    this.observe("fetch-user-changes", startTime, err);
+    return result, newCursor, err;
+}
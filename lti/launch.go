@@ -0,0 +1,207 @@
+package lti
+
+// Verifying LTI 1.3 launches.
+// A launch is an OIDC ID token (signed by the platform) with extra LTI claims,
+// which the platform posts to the tool after a third-party initiated login.
+// See: https://www.imsglobal.org/spec/lti/v1p3/ and https://www.imsglobal.org/spec/security/v1p0/ .
+
+import (
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/lms/lmstypes"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    LTI_VERSION = "1.3.0";
+    MESSAGE_TYPE_RESOURCE_LINK = "LtiResourceLinkRequest";
+
+    CLAIM_PREFIX = "https://purl.imsglobal.org/spec/lti/claim/";
+    CLAIM_AGS_ENDPOINT = "https://purl.imsglobal.org/spec/lti-ags/claim/endpoint";
+    CLAIM_NRPS = "https://purl.imsglobal.org/spec/lti-nrps/claim/namesroleservice";
+
+    // Allowed clock skew when checking token times.
+    CLOCK_SKEW = 2 * time.Minute;
+)
+
+type LaunchClaims struct {
+    Issuer string `json:"iss"`
+    Subject string `json:"sub"`
+    Audience util.JWTAudience `json:"aud"`
+    AuthorizedParty string `json:"azp,omitempty"`
+    Expiration int64 `json:"exp"`
+    IssuedAt int64 `json:"iat"`
+    Nonce string `json:"nonce"`
+
+    Email string `json:"email,omitempty"`
+    Name string `json:"name,omitempty"`
+    GivenName string `json:"given_name,omitempty"`
+    FamilyName string `json:"family_name,omitempty"`
+
+    MessageType string `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+    Version string `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+    DeploymentID string `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+    TargetLinkURI string `json:"https://purl.imsglobal.org/spec/lti/claim/target_link_uri"`
+    Roles []string `json:"https://purl.imsglobal.org/spec/lti/claim/roles"`
+
+    Context *struct {
+        ID string `json:"id"`
+        Label string `json:"label,omitempty"`
+        Title string `json:"title,omitempty"`
+    } `json:"https://purl.imsglobal.org/spec/lti/claim/context,omitempty"`
+
+    ResourceLink *struct {
+        ID string `json:"id"`
+        Title string `json:"title,omitempty"`
+    } `json:"https://purl.imsglobal.org/spec/lti/claim/resource_link,omitempty"`
+
+    // Assignment and Grade Services.
+    AGS *struct {
+        Scope []string `json:"scope"`
+        LineItems string `json:"lineitems,omitempty"`
+        LineItem string `json:"lineitem,omitempty"`
+    } `json:"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint,omitempty"`
+
+    // Names and Role Provisioning Services.
+    NRPS *struct {
+        ContextMembershipsURL string `json:"context_memberships_url"`
+        ServiceVersions []string `json:"service_versions,omitempty"`
+    } `json:"https://purl.imsglobal.org/spec/lti-nrps/claim/namesroleservice,omitempty"`
+}
+
+// {JWKS URL: keys}.
+var platformKeys map[string]*util.JWKS = make(map[string]*util.JWKS);
+var platformKeysLock sync.Mutex;
+
+// Verify a launch's ID token (signature and claims) from a platform.
+// The launch must come from the given context (the course's LMS course ID),
+// otherwise a user could use their role in one context to launch into another course on the same platform.
+func VerifyLaunch(platform *model.LTIPlatform, contextID string, idToken string, nonce string) (*LaunchClaims, error) {
+    if (contextID == "") {
+        return nil, fmt.Errorf("Cannot verify a launch without a context ID.");
+    }
+
+    keys, err := getPlatformKeys(platform.JWKSURL, false);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    var claims LaunchClaims;
+    err = util.VerifyJWT(idToken, keys, &claims);
+    if (err != nil) {
+        // The platform may have rotated its keys, refresh them once.
+        keys, err = getPlatformKeys(platform.JWKSURL, true);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        err = util.VerifyJWT(idToken, keys, &claims);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to verify launch: '%w'.", err);
+        }
+    }
+
+    if (claims.Issuer != platform.Issuer) {
+        return nil, fmt.Errorf("Launch has the wrong issuer. Expected: '%s', Actual: '%s'.", platform.Issuer, claims.Issuer);
+    }
+
+    if (!claims.Audience.Contains(platform.ClientID)) {
+        return nil, fmt.Errorf("Launch is not for this client: '%v'.", claims.Audience);
+    }
+
+    if (((len(claims.Audience) > 1) || (claims.AuthorizedParty != "")) && (claims.AuthorizedParty != platform.ClientID)) {
+        return nil, fmt.Errorf("Launch has the wrong authorized party: '%s'.", claims.AuthorizedParty);
+    }
+
+    now := time.Now();
+    if (now.Add(-CLOCK_SKEW).After(time.Unix(claims.Expiration, 0))) {
+        return nil, fmt.Errorf("Launch is expired.");
+    }
+
+    if ((claims.IssuedAt != 0) && now.Add(CLOCK_SKEW).Before(time.Unix(claims.IssuedAt, 0))) {
+        return nil, fmt.Errorf("Launch was issued in the future.");
+    }
+
+    if (claims.Nonce != nonce) {
+        return nil, fmt.Errorf("Launch has the wrong nonce.");
+    }
+
+    if (claims.Version != LTI_VERSION) {
+        return nil, fmt.Errorf("Unsupported LTI version: '%s'.", claims.Version);
+    }
+
+    if (claims.MessageType != MESSAGE_TYPE_RESOURCE_LINK) {
+        return nil, fmt.Errorf("Unsupported LTI message type: '%s'.", claims.MessageType);
+    }
+
+    if (claims.DeploymentID == "") {
+        return nil, fmt.Errorf("Launch does not have a deployment ID.");
+    }
+
+    if ((platform.DeploymentID != "") && (claims.DeploymentID != platform.DeploymentID)) {
+        return nil, fmt.Errorf("Launch is from an unknown deployment: '%s'.", claims.DeploymentID);
+    }
+
+    if (claims.Subject == "") {
+        return nil, fmt.Errorf("Launch does not have a user (anonymous launches are not supported).");
+    }
+
+    if ((claims.Context == nil) || (claims.Context.ID != contextID)) {
+        actualContextID := "";
+        if (claims.Context != nil) {
+            actualContextID = claims.Context.ID;
+        }
+
+        return nil, fmt.Errorf("Launch is from the wrong context. Expected: '%s', Actual: '%s'.", contextID, actualContextID);
+    }
+
+    return &claims, nil;
+}
+
+func (this *LaunchClaims) GetName() string {
+    if (this.Name != "") {
+        return this.Name;
+    }
+
+    return strings.TrimSpace(this.GivenName + " " + this.FamilyName);
+}
+
+// Get the launching user as an LMS user.
+// The user's LMS ID is their LTI user ID (which is also what grade and roster services use).
+func (this *LaunchClaims) ToLMSType() *lmstypes.User {
+    return &lmstypes.User{
+        ID: this.Subject,
+        Name: this.GetName(),
+        Email: strings.TrimSpace(this.Email),
+        Role: GetRole(this.Roles),
+    };
+}
+
+func getPlatformKeys(jwksURL string, refresh bool) (*util.JWKS, error) {
+    platformKeysLock.Lock();
+    defer platformKeysLock.Unlock();
+
+    keys, ok := platformKeys[jwksURL];
+    if (ok && !refresh) {
+        return keys, nil;
+    }
+
+    body, err := common.Get(jwksURL);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch LTI platform keys from '%s': '%w'.", jwksURL, err);
+    }
+
+    keys = &util.JWKS{};
+    err = util.JSONFromString(body, keys);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to parse LTI platform keys from '%s': '%w'.", jwksURL, err);
+    }
+
+    platformKeys[jwksURL] = keys;
+    return keys, nil;
+}
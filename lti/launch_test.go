@@ -0,0 +1,135 @@
+package lti
+
+import (
+    "crypto/rand"
+    "crypto/rsa"
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestVerifyLaunch(test *testing.T) {
+    mock, err := NewMockPlatform("client");
+    if (err != nil) {
+        test.Fatalf("Failed to start mock platform: '%v'.", err);
+    }
+    defer mock.Close();
+
+    otherKey, err := rsa.GenerateKey(rand.Reader, 2048);
+    if (err != nil) {
+        test.Fatalf("Failed to generate key: '%v'.", err);
+    }
+
+    now := time.Now();
+
+    testCases := []struct{key string; value any; signingKey *rsa.PrivateKey; valid bool}{
+        {"", nil, nil, true},
+        {"aud", []string{"client"}, nil, true},
+
+        {"iss", "ZZZ", nil, false},
+        {"aud", "ZZZ", nil, false},
+        {"aud", []string{"other", "client"}, nil, false},
+        {"exp", now.Add(-time.Hour).Unix(), nil, false},
+        {"iat", now.Add(time.Hour).Unix(), nil, false},
+        {"nonce", "ZZZ", nil, false},
+        {"sub", "", nil, false},
+        {CLAIM_PREFIX + "version", "1.1", nil, false},
+        {CLAIM_PREFIX + "message_type", "LtiDeepLinkingRequest", nil, false},
+        {CLAIM_PREFIX + "deployment_id", "ZZZ", nil, false},
+        {CLAIM_PREFIX + "deployment_id", "", nil, false},
+        {CLAIM_PREFIX + "context", map[string]any{"id": "ZZZ"}, nil, false},
+        {CLAIM_PREFIX + "context", nil, nil, false},
+        {"", nil, otherKey, false},
+    };
+
+    for i, testCase := range testCases {
+        claims, err := mock.LaunchClaims("nonce", "http://test.com/launch");
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to get claims: '%v'.", i, err);
+        }
+
+        if (testCase.key != "") {
+            claims[testCase.key] = testCase.value;
+        }
+
+        key := mock.key;
+        if (testCase.signingKey != nil) {
+            key = testCase.signingKey;
+        }
+
+        token, err := util.SignJWT(claims, key, MOCK_KEY_ID);
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to sign launch: '%v'.", i, err);
+        }
+
+        launch, err := VerifyLaunch(mock.Platform(), MOCK_CONTEXT_ID, token, "nonce");
+        if (testCase.valid && (err != nil)) {
+            test.Errorf("Case %d: Valid launch failed verification: '%v'.", i, err);
+        } else if (!testCase.valid && (err == nil)) {
+            test.Errorf("Case %d: Invalid launch passed verification: '%+v'.", i, launch);
+        }
+    }
+}
+
+func TestLaunchClaimsToLMSType(test *testing.T) {
+    mock, err := NewMockPlatform("client");
+    if (err != nil) {
+        test.Fatalf("Failed to start mock platform: '%v'.", err);
+    }
+    defer mock.Close();
+
+    mock.SetIdentity("grader-id");
+
+    claims, err := mock.LaunchClaims("nonce", "http://test.com/launch");
+    if (err != nil) {
+        test.Fatalf("Failed to get claims: '%v'.", err);
+    }
+
+    token, err := mock.Sign(claims);
+    if (err != nil) {
+        test.Fatalf("Failed to sign launch: '%v'.", err);
+    }
+
+    launch, err := VerifyLaunch(mock.Platform(), MOCK_CONTEXT_ID, token, "nonce");
+    if (err != nil) {
+        test.Fatalf("Failed to verify launch: '%v'.", err);
+    }
+
+    user := launch.ToLMSType();
+    if ((user.ID != "grader-id") || (user.Email != "grader@test.com") || (user.Name != "grader") || (user.Role != model.RoleGrader)) {
+        test.Fatalf("Unexpected launch user: '%+v'.", user);
+    }
+
+    if ((launch.AGS == nil) || (launch.AGS.LineItems != mock.LineItemsURL())) {
+        test.Fatalf("Unexpected AGS claim: '%+v'.", launch.AGS);
+    }
+
+    if ((launch.NRPS == nil) || (launch.NRPS.ContextMembershipsURL != mock.MembershipsURL())) {
+        test.Fatalf("Unexpected NRPS claim: '%+v'.", launch.NRPS);
+    }
+}
+
+func TestGetRole(test *testing.T) {
+    testCases := []struct{roles []string; expected model.UserRole}{
+        {[]string{}, model.RoleOther},
+        {[]string{ROLE_MEMBERSHIP_PREFIX + "Learner"}, model.RoleStudent},
+        {[]string{"Learner"}, model.RoleStudent},
+        {[]string{ROLE_MEMBERSHIP_PREFIX + "Mentor"}, model.RoleOther},
+        {[]string{ROLE_MEMBERSHIP_PREFIX + "ContentDeveloper"}, model.RoleAdmin},
+        {[]string{ROLE_MEMBERSHIP_PREFIX + "Instructor"}, model.RoleOwner},
+        {[]string{ROLE_MEMBERSHIP_PREFIX + "Learner", ROLE_MEMBERSHIP_PREFIX + "Instructor"}, model.RoleOwner},
+        {[]string{ROLE_MEMBERSHIP_PREFIX + "Instructor", ROLE_TEACHING_ASSISTANT}, model.RoleGrader},
+
+        // Institution roles are ignored.
+        {[]string{"http://purl.imsglobal.org/vocab/lis/v2/institution/person#Instructor"}, model.RoleOther},
+    };
+
+    for i, testCase := range testCases {
+        role := GetRole(testCase.roles);
+        if (role != testCase.expected) {
+            test.Errorf("Case %d: Unexpected role. Expected: '%s', Actual: '%s'.", i, testCase.expected, role);
+        }
+    }
+}
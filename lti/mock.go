@@ -0,0 +1,600 @@
+package lti
+
+// A local LTI 1.3 platform for testing.
+// The platform does not have a login page, the authentication endpoint immediately launches the current identity.
+// Tests can use Authorize() to act as the user's browser (instead of following the platform's auto-submitting form).
+// The platform also has minimal grade (AGS) and roster (NRPS) services,
+// which are paged in small pages so that paging gets exercised.
+
+import (
+    "crypto/rand"
+    "crypto/rsa"
+    "encoding/json"
+    "fmt"
+    "html"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    MOCK_KEY_ID = "mock-platform-key";
+    MOCK_DEPLOYMENT_ID = "mock-deployment";
+    MOCK_CONTEXT_ID = "mock-context";
+    MOCK_PAGE_SIZE = 2;
+)
+
+type MockPlatform struct {
+    ClientID string
+    DeploymentID string
+
+    server *httptest.Server
+    key *rsa.PrivateKey
+
+    lock sync.Mutex
+    // The user ID that the next launch will be for.
+    identity string
+    members []*Member
+    // Every member change increases the version, which is used for NRPS differences.
+    membersVersion int
+    // {user ID: the version the member was last changed in}.
+    memberVersions map[string]int
+    lineItems []*LineItem
+    // {line item ID: {user ID: result}}.
+    results map[string]map[string]*Result
+    // {line item ID: {user ID: score}}.
+    scores map[string]map[string]*Score
+    // {token: scopes}.
+    tokens map[string][]string
+}
+
+var mockDueDate time.Time = time.Date(2023, time.October, 4, 0, 0, 0, 0, time.UTC);
+
+// Start a mock platform.
+// The platform's members are the users in the standard test course (along with "new@test.com", who is not in the course),
+// and the next launch will be for "student@test.com".
+// Callers should Close() the platform when done.
+func NewMockPlatform(clientID string) (*MockPlatform, error) {
+    key, err := rsa.GenerateKey(rand.Reader, 2048);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to generate mock platform key: '%w'.", err);
+    }
+
+    platform := &MockPlatform{
+        ClientID: clientID,
+        DeploymentID: MOCK_DEPLOYMENT_ID,
+        key: key,
+        identity: "student-id",
+        results: make(map[string]map[string]*Result),
+        scores: make(map[string]map[string]*Score),
+        tokens: make(map[string][]string),
+        memberVersions: make(map[string]int),
+    };
+
+    mux := http.NewServeMux();
+    mux.HandleFunc("/auth", platform.handleAuth);
+    mux.HandleFunc("/jwks", platform.handleJWKS);
+    mux.HandleFunc("/token", platform.handleToken);
+    mux.HandleFunc("/lineitems", platform.handleLineItems);
+    mux.HandleFunc("/lineitems/", platform.handleLineItem);
+    mux.HandleFunc("/memberships", platform.handleMemberships);
+
+    platform.server = httptest.NewServer(mux);
+
+    platform.members = []*Member{
+        &Member{UserID: "owner-id", Name: "owner", Email: "owner@test.com", Status: MEMBER_STATUS_ACTIVE, Roles: []string{ROLE_MEMBERSHIP_PREFIX + "Instructor"}},
+        &Member{UserID: "admin-id", Name: "admin", Email: "admin@test.com", Status: MEMBER_STATUS_ACTIVE, Roles: []string{ROLE_MEMBERSHIP_PREFIX + "Administrator"}},
+        &Member{UserID: "grader-id", Name: "grader", Email: "grader@test.com", Status: MEMBER_STATUS_ACTIVE, Roles: []string{ROLE_MEMBERSHIP_PREFIX + "Instructor", ROLE_TEACHING_ASSISTANT}},
+        &Member{UserID: "student-id", Name: "student", Email: "student@test.com", Status: MEMBER_STATUS_ACTIVE, Roles: []string{ROLE_MEMBERSHIP_PREFIX + "Learner"}},
+        &Member{UserID: "other-id", Name: "other", Email: "other@test.com", Status: MEMBER_STATUS_ACTIVE, Roles: []string{ROLE_MEMBERSHIP_PREFIX + "Mentor"}},
+        &Member{UserID: "new-id", Name: "new", Email: "new@test.com", Status: MEMBER_STATUS_ACTIVE, Roles: []string{"Learner"}},
+        &Member{UserID: "dropped-id", Name: "dropped", Email: "dropped@test.com", Status: MEMBER_STATUS_INACTIVE, Roles: []string{"Learner"}},
+    };
+
+    platform.lineItems = []*LineItem{
+        &LineItem{ID: platform.Issuer() + "/lineitems/1", Label: "Homework 0", ScoreMaximum: 100.0, Tag: "hw0", EndDateTime: &mockDueDate},
+        &LineItem{ID: platform.Issuer() + "/lineitems/2", Label: "Participation", ScoreMaximum: 10.0},
+    };
+
+    studentScore := 100.0;
+    graderScore := 80.5;
+    lineItemID := platform.lineItems[0].ID;
+    platform.results[lineItemID] = map[string]*Result{
+        "student-id": &Result{ID: lineItemID + "/results/student-id", ScoreOf: lineItemID, UserID: "student-id",
+                ResultScore: &studentScore, ResultMaximum: 100.0, Comment: "Good job!"},
+        "grader-id": &Result{ID: lineItemID + "/results/grader-id", ScoreOf: lineItemID, UserID: "grader-id",
+                ResultScore: &graderScore, ResultMaximum: 100.0},
+    };
+
+    return platform, nil;
+}
+
+func (this *MockPlatform) Issuer() string {
+    return this.server.URL;
+}
+
+func (this *MockPlatform) Close() {
+    this.server.Close();
+}
+
+// Get the registration information for this platform (as it would appear in a course's config).
+func (this *MockPlatform) Platform() *model.LTIPlatform {
+    return &model.LTIPlatform{
+        Issuer: this.Issuer(),
+        ClientID: this.ClientID,
+        DeploymentID: this.DeploymentID,
+        AuthURL: this.Issuer() + "/auth",
+        JWKSURL: this.Issuer() + "/jwks",
+        TokenURL: this.Issuer() + "/token",
+    };
+}
+
+func (this *MockPlatform) LineItemsURL() string {
+    return this.Issuer() + "/lineitems";
+}
+
+func (this *MockPlatform) MembershipsURL() string {
+    return this.Issuer() + "/memberships";
+}
+
+// Set the user (by LTI user ID) that future launches will be for.
+func (this *MockPlatform) SetIdentity(userID string) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    this.identity = userID;
+}
+
+// Get the parameters that the platform would send to the tool's login initiation endpoint.
+func (this *MockPlatform) LoginParams(targetLinkURI string) map[string]string {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    return map[string]string{
+        "iss": this.Issuer(),
+        "login_hint": this.identity,
+        "target_link_uri": targetLinkURI,
+        "lti_message_hint": "mock-message",
+        "client_id": this.ClientID,
+        "lti_deployment_id": this.DeploymentID,
+    };
+}
+
+// Act as the user's browser visiting the platform's authentication endpoint.
+// Returns: (the URL to post the launch to, the launch form, error).
+func (this *MockPlatform) Authorize(authURL string) (string, url.Values, error) {
+    uri, err := url.Parse(authURL);
+    if (err != nil) {
+        return "", nil, fmt.Errorf("Failed to parse auth URL '%s': '%w'.", authURL, err);
+    }
+
+    return this.authorize(uri.Query());
+}
+
+// Get the claims for a launch of the current identity.
+// Tests can modify these claims and Sign() them to make bad launches.
+func (this *MockPlatform) LaunchClaims(nonce string, targetLinkURI string) (map[string]any, error) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    member := this.getMember(this.identity);
+    if (member == nil) {
+        return nil, fmt.Errorf("Unknown mock identity: '%s'.", this.identity);
+    }
+
+    now := time.Now();
+    claims := map[string]any{
+        "iss": this.Issuer(),
+        "sub": member.UserID,
+        "aud": this.ClientID,
+        "exp": now.Add(time.Hour).Unix(),
+        "iat": now.Unix(),
+        "nonce": nonce,
+        "name": member.Name,
+        "email": member.Email,
+        CLAIM_PREFIX + "message_type": MESSAGE_TYPE_RESOURCE_LINK,
+        CLAIM_PREFIX + "version": LTI_VERSION,
+        CLAIM_PREFIX + "deployment_id": this.DeploymentID,
+        CLAIM_PREFIX + "target_link_uri": targetLinkURI,
+        CLAIM_PREFIX + "roles": member.Roles,
+        CLAIM_PREFIX + "context": map[string]any{"id": MOCK_CONTEXT_ID, "label": "C101", "title": "Course 101"},
+        CLAIM_PREFIX + "resource_link": map[string]any{"id": "mock-resource-link", "title": "Autograder"},
+        CLAIM_AGS_ENDPOINT: map[string]any{
+            "scope": []string{SCOPE_LINE_ITEM, SCOPE_RESULT_READONLY, SCOPE_SCORE},
+            "lineitems": this.LineItemsURL(),
+        },
+        CLAIM_NRPS: map[string]any{
+            "context_memberships_url": this.MembershipsURL(),
+            "service_versions": []string{"2.0"},
+        },
+    };
+
+    return claims, nil;
+}
+
+func (this *MockPlatform) Sign(claims map[string]any) (string, error) {
+    return util.SignJWT(claims, this.key, MOCK_KEY_ID);
+}
+
+// Add or replace (by user ID) a member.
+func (this *MockPlatform) SetMember(member *Member) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    this.membersVersion++;
+    this.memberVersions[member.UserID] = this.membersVersion;
+
+    for i, oldMember := range this.members {
+        if (oldMember.UserID == member.UserID) {
+            this.members[i] = member;
+            return;
+        }
+    }
+
+    this.members = append(this.members, member);
+}
+
+// Mark a member as deleted.
+func (this *MockPlatform) RemoveMember(userID string) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    member := this.getMember(userID);
+    if (member == nil) {
+        return;
+    }
+
+    memberCopy := *member;
+    memberCopy.Status = MEMBER_STATUS_DELETED;
+
+    this.membersVersion++;
+    this.memberVersions[userID] = this.membersVersion;
+
+    for i, oldMember := range this.members {
+        if (oldMember.UserID == userID) {
+            this.members[i] = &memberCopy;
+        }
+    }
+}
+
+// Get the most recent score posted for a user, or nil.
+func (this *MockPlatform) GetScore(lineItemID string, userID string) *Score {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    return this.scores[lineItemID][userID];
+}
+
+func (this *MockPlatform) authorize(query url.Values) (string, url.Values, error) {
+    if ((query.Get("scope") != "openid") || (query.Get("response_type") != "id_token") ||
+            (query.Get("response_mode") != "form_post") || (query.Get("prompt") != "none")) {
+        return "", nil, fmt.Errorf("Bad authentication request: '%s'.", query.Encode());
+    }
+
+    if (query.Get("client_id") != this.ClientID) {
+        return "", nil, fmt.Errorf("Unknown client: '%s'.", query.Get("client_id"));
+    }
+
+    this.lock.Lock();
+    identity := this.identity;
+    this.lock.Unlock();
+
+    if (query.Get("login_hint") != identity) {
+        return "", nil, fmt.Errorf("Login hint does not match the current user.");
+    }
+
+    redirectURL := query.Get("redirect_uri");
+    if ((redirectURL == "") || (query.Get("nonce") == "")) {
+        return "", nil, fmt.Errorf("Missing redirect URI or nonce.");
+    }
+
+    claims, err := this.LaunchClaims(query.Get("nonce"), redirectURL);
+    if (err != nil) {
+        return "", nil, err;
+    }
+
+    idToken, err := this.Sign(claims);
+    if (err != nil) {
+        return "", nil, err;
+    }
+
+    form := url.Values{};
+    form.Set("id_token", idToken);
+    form.Set("state", query.Get("state"));
+
+    return redirectURL, form, nil;
+}
+
+// Respond like a real platform: with a form that posts the launch to the tool.
+func (this *MockPlatform) handleAuth(response http.ResponseWriter, request *http.Request) {
+    redirectURL, form, err := this.authorize(request.URL.Query());
+    if (err != nil) {
+        http.Error(response, err.Error(), http.StatusBadRequest);
+        return;
+    }
+
+    var builder strings.Builder;
+    builder.WriteString(fmt.Sprintf(`<html><body onload="document.forms[0].submit()"><form method="post" action="%s">`, html.EscapeString(redirectURL)));
+    for key, _ := range form {
+        builder.WriteString(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`, html.EscapeString(key), html.EscapeString(form.Get(key))));
+    }
+    builder.WriteString(`</form></body></html>`);
+
+    response.Header().Set("Content-Type", "text/html");
+    response.Write([]byte(builder.String()));
+}
+
+func (this *MockPlatform) handleJWKS(response http.ResponseWriter, request *http.Request) {
+    keys := util.JWKS{Keys: []*util.JWK{util.NewRSAJWK(MOCK_KEY_ID, &this.key.PublicKey)}};
+    writeMockJSON(response, http.StatusOK, "application/json", keys);
+}
+
+// Client assertions are checked against the tool's key set directly (instead of fetching it).
+func (this *MockPlatform) handleToken(response http.ResponseWriter, request *http.Request) {
+    err := request.ParseForm();
+    if ((err != nil) || (request.PostForm.Get("grant_type") != "client_credentials") ||
+            (request.PostForm.Get("client_assertion_type") != CLIENT_ASSERTION_TYPE)) {
+        writeMockJSON(response, http.StatusBadRequest, "application/json", map[string]string{"error": "invalid_request"});
+        return;
+    }
+
+    toolKeys, err := GetToolJWKS();
+    if (err != nil) {
+        writeMockJSON(response, http.StatusInternalServerError, "application/json", map[string]string{"error": "server_error"});
+        return;
+    }
+
+    var claims struct {
+        Issuer string `json:"iss"`
+        Subject string `json:"sub"`
+        Audience util.JWTAudience `json:"aud"`
+        Expiration int64 `json:"exp"`
+    };
+
+    err = util.VerifyJWT(request.PostForm.Get("client_assertion"), toolKeys, &claims);
+    if ((err != nil) || (claims.Issuer != this.ClientID) || (claims.Subject != this.ClientID) ||
+            !claims.Audience.Contains(this.Issuer() + "/token") || time.Now().After(time.Unix(claims.Expiration, 0))) {
+        writeMockJSON(response, http.StatusUnauthorized, "application/json", map[string]string{"error": "invalid_client"});
+        return;
+    }
+
+    token, err := util.RandHex(32);
+    if (err != nil) {
+        writeMockJSON(response, http.StatusInternalServerError, "application/json", map[string]string{"error": "server_error"});
+        return;
+    }
+
+    scopes := strings.Fields(request.PostForm.Get("scope"));
+
+    this.lock.Lock();
+    this.tokens[token] = scopes;
+    this.lock.Unlock();
+
+    writeMockJSON(response, http.StatusOK, "application/json", map[string]any{
+        "access_token": token,
+        "token_type": "Bearer",
+        "expires_in": 3600,
+        "scope": strings.Join(scopes, " "),
+    });
+}
+
+func (this *MockPlatform) handleLineItems(response http.ResponseWriter, request *http.Request) {
+    if (!this.checkAccess(response, request, "GET", SCOPE_LINE_ITEM, SCOPE_LINE_ITEM_READONLY)) {
+        return;
+    }
+
+    this.lock.Lock();
+    lineItems := append([]*LineItem(nil), this.lineItems...);
+    this.lock.Unlock();
+
+    page := writeMockPage(response, request, lineItems);
+    writeMockJSON(response, http.StatusOK, MEDIA_TYPE_LINE_ITEM_CONTAINER, page);
+}
+
+// Handles a single line item, its results, and its scores.
+func (this *MockPlatform) handleLineItem(response http.ResponseWriter, request *http.Request) {
+    parts := strings.Split(strings.TrimPrefix(request.URL.Path, "/lineitems/"), "/");
+    lineItemID := this.Issuer() + "/lineitems/" + parts[0];
+
+    this.lock.Lock();
+    var lineItem *LineItem = nil;
+    for _, item := range this.lineItems {
+        if (item.ID == lineItemID) {
+            lineItem = item;
+        }
+    }
+    this.lock.Unlock();
+
+    if (lineItem == nil) {
+        http.NotFound(response, request);
+        return;
+    }
+
+    if (len(parts) == 1) {
+        if (this.checkAccess(response, request, "GET", SCOPE_LINE_ITEM, SCOPE_LINE_ITEM_READONLY)) {
+            writeMockJSON(response, http.StatusOK, MEDIA_TYPE_LINE_ITEM, lineItem);
+        }
+    } else if (parts[1] == "results") {
+        if (this.checkAccess(response, request, "GET", SCOPE_RESULT_READONLY)) {
+            this.handleResults(response, request, lineItemID);
+        }
+    } else if (parts[1] == "scores") {
+        if (this.checkAccess(response, request, "POST", SCOPE_SCORE)) {
+            this.handleScore(response, request, lineItem);
+        }
+    } else {
+        http.NotFound(response, request);
+    }
+}
+
+func (this *MockPlatform) handleResults(response http.ResponseWriter, request *http.Request, lineItemID string) {
+    userID := request.URL.Query().Get("user_id");
+
+    this.lock.Lock();
+    results := make([]*Result, 0);
+    for _, member := range this.members {
+        result := this.results[lineItemID][member.UserID];
+        if ((result != nil) && ((userID == "") || (userID == member.UserID))) {
+            results = append(results, result);
+        }
+    }
+    this.lock.Unlock();
+
+    page := writeMockPage(response, request, results);
+    writeMockJSON(response, http.StatusOK, MEDIA_TYPE_RESULT_CONTAINER, page);
+}
+
+func (this *MockPlatform) handleScore(response http.ResponseWriter, request *http.Request, lineItem *LineItem) {
+    if (request.Header.Get("Content-Type") != MEDIA_TYPE_SCORE) {
+        http.Error(response, "Bad content type.", http.StatusUnsupportedMediaType);
+        return;
+    }
+
+    var score Score;
+    err := json.NewDecoder(request.Body).Decode(&score);
+    if ((err != nil) || (score.UserID == "") || (score.ActivityProgress == "") || (score.GradingProgress == "")) {
+        http.Error(response, "Bad score.", http.StatusBadRequest);
+        return;
+    }
+
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    if (this.getMember(score.UserID) == nil) {
+        http.Error(response, "Unknown user.", http.StatusBadRequest);
+        return;
+    }
+
+    if (this.scores[lineItem.ID] == nil) {
+        this.scores[lineItem.ID] = make(map[string]*Score);
+    }
+
+    if (this.results[lineItem.ID] == nil) {
+        this.results[lineItem.ID] = make(map[string]*Result);
+    }
+
+    this.scores[lineItem.ID][score.UserID] = &score;
+    this.results[lineItem.ID][score.UserID] = &Result{
+        ID: lineItem.ID + "/results/" + score.UserID,
+        ScoreOf: lineItem.ID,
+        UserID: score.UserID,
+        ResultScore: score.ScoreGiven,
+        ResultMaximum: lineItem.ScoreMaximum,
+        Comment: score.Comment,
+    };
+
+    response.WriteHeader(http.StatusOK);
+}
+
+func (this *MockPlatform) handleMemberships(response http.ResponseWriter, request *http.Request) {
+    if (!this.checkAccess(response, request, "GET", SCOPE_MEMBERSHIPS_READONLY)) {
+        return;
+    }
+
+    // Differences requests only get the members that changed after the given version.
+    since, err := strconv.Atoi(request.URL.Query().Get("since"));
+    if (err != nil) {
+        since = -1;
+    }
+
+    this.lock.Lock();
+
+    members := make([]*Member, 0, len(this.members));
+    for _, member := range this.members {
+        if ((since < 0) || (this.memberVersions[member.UserID] > since)) {
+            members = append(members, member);
+        }
+    }
+
+    differencesURL := fmt.Sprintf("%s?since=%d", this.MembershipsURL(), this.membersVersion);
+
+    this.lock.Unlock();
+
+    response.Header().Add("Link", fmt.Sprintf(`<%s>; rel="differences"`, differencesURL));
+
+    container := MembershipContainer{
+        ID: this.MembershipsURL(),
+        Members: writeMockPage(response, request, members),
+    };
+    container.Context.ID = MOCK_CONTEXT_ID;
+
+    writeMockJSON(response, http.StatusOK, MEDIA_TYPE_MEMBERSHIP_CONTAINER, container);
+}
+
+// Check the method and that the request has a token with one of the given scopes.
+// Writes an error response and returns false on failure.
+func (this *MockPlatform) checkAccess(response http.ResponseWriter, request *http.Request, method string, scopes ...string) bool {
+    if (request.Method != method) {
+        http.Error(response, "Method not allowed.", http.StatusMethodNotAllowed);
+        return false;
+    }
+
+    token := strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer ");
+
+    this.lock.Lock();
+    tokenScopes, ok := this.tokens[token];
+    this.lock.Unlock();
+
+    if (!ok) {
+        http.Error(response, "Unknown token.", http.StatusUnauthorized);
+        return false;
+    }
+
+    for _, scope := range scopes {
+        for _, tokenScope := range tokenScopes {
+            if (scope == tokenScope) {
+                return true;
+            }
+        }
+    }
+
+    http.Error(response, "Token does not have the required scope.", http.StatusForbidden);
+    return false;
+}
+
+// Must be called with the lock held.
+func (this *MockPlatform) getMember(userID string) *Member {
+    for _, member := range this.members {
+        if (member.UserID == userID) {
+            return member;
+        }
+    }
+
+    return nil;
+}
+
+// Get the requested page (the "page" query parameter, starting at 1) of items,
+// and add a link to the next page (if there is one).
+func writeMockPage[T any](response http.ResponseWriter, request *http.Request, items []T) []T {
+    page, err := strconv.Atoi(request.URL.Query().Get("page"));
+    if ((err != nil) || (page < 1)) {
+        page = 1;
+    }
+
+    start := min(len(items), (page - 1) * MOCK_PAGE_SIZE);
+    end := min(len(items), start + MOCK_PAGE_SIZE);
+
+    if (end < len(items)) {
+        next := *request.URL;
+        query := next.Query();
+        query.Set("page", strconv.Itoa(page + 1));
+        next.RawQuery = query.Encode();
+
+        response.Header().Add("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, request.Host, next.RequestURI()));
+    }
+
+    return items[start:end];
+}
+
+func writeMockJSON(response http.ResponseWriter, status int, contentType string, data any) {
+    response.Header().Set("Content-Type", contentType);
+    response.WriteHeader(status);
+    response.Write([]byte(util.MustToJSON(data)));
+}
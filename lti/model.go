@@ -0,0 +1,72 @@
+package lti
+
+import (
+    "time"
+)
+
+// Media types for service requests and responses.
+const (
+    MEDIA_TYPE_LINE_ITEM_CONTAINER = "application/vnd.ims.lis.v2.lineitemcontainer+json";
+    MEDIA_TYPE_LINE_ITEM = "application/vnd.ims.lis.v2.lineitem+json";
+    MEDIA_TYPE_RESULT_CONTAINER = "application/vnd.ims.lis.v2.resultcontainer+json";
+    MEDIA_TYPE_SCORE = "application/vnd.ims.lis.v1.score+json";
+    MEDIA_TYPE_MEMBERSHIP_CONTAINER = "application/vnd.ims.lti-nrps.v2.membershipcontainer+json";
+)
+
+const (
+    ACTIVITY_PROGRESS_COMPLETED = "Completed";
+    GRADING_PROGRESS_FULLY_GRADED = "FullyGraded";
+    GRADING_PROGRESS_NOT_READY = "NotReady";
+
+    MEMBER_STATUS_ACTIVE = "Active";
+    MEMBER_STATUS_INACTIVE = "Inactive";
+    MEMBER_STATUS_DELETED = "Deleted";
+)
+
+// A column in the platform's gradebook (AGS).
+type LineItem struct {
+    ID string `json:"id"`
+    Label string `json:"label"`
+    ScoreMaximum float64 `json:"scoreMaximum"`
+    ResourceLinkID string `json:"resourceLinkId,omitempty"`
+    Tag string `json:"tag,omitempty"`
+    EndDateTime *time.Time `json:"endDateTime,omitempty"`
+}
+
+// The current grade for a user in a line item (AGS).
+type Result struct {
+    ID string `json:"id"`
+    ScoreOf string `json:"scoreOf"`
+    UserID string `json:"userId"`
+    ResultScore *float64 `json:"resultScore,omitempty"`
+    ResultMaximum float64 `json:"resultMaximum,omitempty"`
+    Comment string `json:"comment,omitempty"`
+}
+
+// A grade sent to the platform (AGS).
+type Score struct {
+    UserID string `json:"userId"`
+    ScoreGiven *float64 `json:"scoreGiven,omitempty"`
+    ScoreMaximum float64 `json:"scoreMaximum,omitempty"`
+    Comment string `json:"comment,omitempty"`
+    Timestamp time.Time `json:"timestamp"`
+    ActivityProgress string `json:"activityProgress"`
+    GradingProgress string `json:"gradingProgress"`
+}
+
+// A user in a course (NRPS).
+type Member struct {
+    UserID string `json:"user_id"`
+    Name string `json:"name,omitempty"`
+    Email string `json:"email,omitempty"`
+    Status string `json:"status,omitempty"`
+    Roles []string `json:"roles"`
+}
+
+type MembershipContainer struct {
+    ID string `json:"id"`
+    Context struct {
+        ID string `json:"id"`
+    } `json:"context"`
+    Members []*Member `json:"members"`
+}
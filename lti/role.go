@@ -0,0 +1,46 @@
+package lti
+
+import (
+    "strings"
+
+    "github.com/eriq-augustine/autograder/model"
+)
+
+const (
+    // Context (course) roles.
+    // Platforms may also send the short form (just the part after the '#').
+    ROLE_MEMBERSHIP_PREFIX = "http://purl.imsglobal.org/vocab/lis/v2/membership#";
+    ROLE_TEACHING_ASSISTANT = "http://purl.imsglobal.org/vocab/lis/v2/membership/Instructor#TeachingAssistant";
+)
+
+// LTI context role to autograder role.
+var roleMapping map[string]model.UserRole = map[string]model.UserRole{
+    "Mentor": model.RoleOther,
+    "Learner": model.RoleStudent,
+    "ContentDeveloper": model.RoleAdmin,
+    "Administrator": model.RoleAdmin,
+    "Instructor": model.RoleOwner,
+};
+
+// Get the autograder role for a set of LTI roles.
+// Only context roles are considered (not institution or system roles).
+// Teaching assistants also have the instructor role, so the TA sub-role takes precedence.
+// Otherwise, the highest mapped role is used.
+func GetRole(roles []string) model.UserRole {
+    var role model.UserRole = model.RoleOther;
+
+    for _, ltiRole := range roles {
+        ltiRole = strings.TrimSpace(ltiRole);
+
+        if ((ltiRole == ROLE_TEACHING_ASSISTANT) || (ltiRole == "TeachingAssistant")) {
+            return model.RoleGrader;
+        }
+
+        mappedRole, ok := roleMapping[strings.TrimPrefix(ltiRole, ROLE_MEMBERSHIP_PREFIX)];
+        if (ok && (mappedRole > role)) {
+            role = mappedRole;
+        }
+    }
+
+    return role;
+}
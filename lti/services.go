@@ -0,0 +1,210 @@
+package lti
+
+// Support for the platform's services (Assignment and Grade Services, Names and Role Provisioning Services).
+// The tool gets access tokens using the OAuth 2 client credentials grant,
+// authenticating with a JWT signed by the tool's key (instead of a client secret).
+// Service endpoints are specific to a course (context) and are learned from launches.
+
+import (
+    "fmt"
+    "path/filepath"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    SCOPE_LINE_ITEM = "https://purl.imsglobal.org/spec/lti-ags/scope/lineitem";
+    SCOPE_LINE_ITEM_READONLY = "https://purl.imsglobal.org/spec/lti-ags/scope/lineitem.readonly";
+    SCOPE_RESULT_READONLY = "https://purl.imsglobal.org/spec/lti-ags/scope/result.readonly";
+    SCOPE_SCORE = "https://purl.imsglobal.org/spec/lti-ags/scope/score";
+    SCOPE_MEMBERSHIPS_READONLY = "https://purl.imsglobal.org/spec/lti-nrps/scope/contextmembership.readonly";
+
+    CLIENT_ASSERTION_TYPE = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer";
+    CLIENT_ASSERTION_LIFETIME = 5 * time.Minute;
+
+    // Tokens are refreshed when they are this close to expiring.
+    TOKEN_EXPIRY_BUFFER = 60 * time.Second;
+
+    CACHE_FILENAME = "lti.json";
+    CACHE_KEY_LINE_ITEMS_URL = "lineitems-url";
+    CACHE_KEY_MEMBERSHIPS_URL = "memberships-url";
+)
+
+type accessToken struct {
+    Token string
+    Expires time.Time
+}
+
+type tokenResponse struct {
+    AccessToken string `json:"access_token"`
+    TokenType string `json:"token_type"`
+    ExpiresIn int64 `json:"expires_in"`
+    Scope string `json:"scope"`
+}
+
+// {"<token url>::<client id>::<scopes>": *accessToken}.
+var accessTokens map[string]*accessToken = make(map[string]*accessToken);
+var accessTokensLock sync.Mutex;
+
+// The service endpoints for a course.
+// Empty if a service is not available.
+type ServiceEndpoints struct {
+    LineItemsURL string
+    MembershipsURL string
+}
+
+// Get an access token for the given scopes, fetching a new one if necessary.
+func GetAccessToken(platform *model.LTIPlatform, scopes []string) (string, error) {
+    if (platform.TokenURL == "") {
+        return "", fmt.Errorf("LTI platform '%s' does not have a token URL.", platform.Issuer);
+    }
+
+    scopes = append([]string(nil), scopes...);
+    sort.Strings(scopes);
+    scope := strings.Join(scopes, " ");
+
+    accessTokensLock.Lock();
+    defer accessTokensLock.Unlock();
+
+    cacheKey := platform.TokenURL + "::" + platform.ClientID + "::" + scope;
+
+    token, ok := accessTokens[cacheKey];
+    if (ok && time.Now().Add(TOKEN_EXPIRY_BUFFER).Before(token.Expires)) {
+        return token.Token, nil;
+    }
+
+    assertion, err := newClientAssertion(platform);
+    if (err != nil) {
+        return "", err;
+    }
+
+    form := map[string]string{
+        "grant_type": "client_credentials",
+        "client_assertion_type": CLIENT_ASSERTION_TYPE,
+        "client_assertion": assertion,
+        "scope": scope,
+    };
+
+    headers := map[string][]string{
+        "Accept": []string{"application/json"},
+    };
+
+    body, _, err := common.PostWithHeaders(platform.TokenURL, form, headers);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to fetch LTI access token: '%w'.", err);
+    }
+
+    var response tokenResponse;
+    err = util.JSONFromString(body, &response);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to parse LTI access token: '%w'.", err);
+    }
+
+    if (response.AccessToken == "") {
+        return "", fmt.Errorf("LTI platform did not return an access token.");
+    }
+
+    accessTokens[cacheKey] = &accessToken{
+        Token: response.AccessToken,
+        Expires: time.Now().Add(time.Duration(response.ExpiresIn) * time.Second),
+    };
+
+    return response.AccessToken, nil;
+}
+
+// A JWT that authenticates the tool to the platform's token endpoint.
+func newClientAssertion(platform *model.LTIPlatform) (string, error) {
+    key, keyID, err := GetToolKey();
+    if (err != nil) {
+        return "", err;
+    }
+
+    jti, err := util.RandHex(16);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to generate client assertion ID: '%w'.", err);
+    }
+
+    now := time.Now();
+    claims := map[string]any{
+        "iss": platform.ClientID,
+        "sub": platform.ClientID,
+        "aud": platform.TokenURL,
+        "iat": now.Unix(),
+        "exp": now.Add(CLIENT_ASSERTION_LIFETIME).Unix(),
+        "jti": jti,
+    };
+
+    return util.SignJWT(claims, key, keyID);
+}
+
+// Remember the service endpoints from a launch so they can be used outside of launches.
+func SaveServiceEndpoints(course *model.Course, claims *LaunchClaims) error {
+    cachePath := filepath.Join(course.GetCacheDir(), CACHE_FILENAME);
+
+    err := util.MkDir(course.GetCacheDir());
+    if (err != nil) {
+        return fmt.Errorf("Failed to make LTI cache dir for course '%s': '%w'.", course.GetID(), err);
+    }
+
+    if ((claims.AGS != nil) && (claims.AGS.LineItems != "")) {
+        _, _, err = util.CachePut(cachePath, CACHE_KEY_LINE_ITEMS_URL, claims.AGS.LineItems);
+        if (err != nil) {
+            return fmt.Errorf("Failed to save LTI line items URL for course '%s': '%w'.", course.GetID(), err);
+        }
+    }
+
+    if ((claims.NRPS != nil) && (claims.NRPS.ContextMembershipsURL != "")) {
+        _, _, err = util.CachePut(cachePath, CACHE_KEY_MEMBERSHIPS_URL, claims.NRPS.ContextMembershipsURL);
+        if (err != nil) {
+            return fmt.Errorf("Failed to save LTI memberships URL for course '%s': '%w'.", course.GetID(), err);
+        }
+    }
+
+    return nil;
+}
+
+// Get a course's service endpoints.
+// Endpoints set in the course's config take precedence over ones learned from launches.
+func GetServiceEndpoints(course *model.Course) (*ServiceEndpoints, error) {
+    adapter := course.GetLMSAdapter();
+    if ((adapter == nil) || (adapter.LTI == nil)) {
+        return nil, fmt.Errorf("Course '%s' does not have an LTI platform.", course.GetID());
+    }
+
+    endpoints := &ServiceEndpoints{
+        LineItemsURL: adapter.LTI.LineItemsURL,
+        MembershipsURL: adapter.LTI.MembershipsURL,
+    };
+
+    cachePath := filepath.Join(course.GetCacheDir(), CACHE_FILENAME);
+
+    if (endpoints.LineItemsURL == "") {
+        value, ok, err := util.CacheFetch(cachePath, CACHE_KEY_LINE_ITEMS_URL);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get LTI line items URL for course '%s': '%w'.", course.GetID(), err);
+        }
+
+        if (ok) {
+            endpoints.LineItemsURL, _ = value.(string);
+        }
+    }
+
+    if (endpoints.MembershipsURL == "") {
+        value, ok, err := util.CacheFetch(cachePath, CACHE_KEY_MEMBERSHIPS_URL);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get LTI memberships URL for course '%s': '%w'.", course.GetID(), err);
+        }
+
+        if (ok) {
+            endpoints.MembershipsURL, _ = value.(string);
+        }
+    }
+
+    return endpoints, nil;
+}
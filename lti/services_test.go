@@ -0,0 +1,87 @@
+package lti
+
+import (
+    "path/filepath"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/config"
+)
+
+func TestGetToolKey(test *testing.T) {
+    path := setTestKeyPath(test);
+
+    key, keyID, err := GetToolKey();
+    if (err != nil) {
+        test.Fatalf("Failed to get tool key: '%v'.", err);
+    }
+
+    // Force the key to be loaded from disk.
+    toolKey = nil;
+
+    loadedKey, loadedKeyID, err := GetToolKey();
+    if (err != nil) {
+        test.Fatalf("Failed to load tool key from '%s': '%v'.", path, err);
+    }
+
+    if (!key.Equal(loadedKey) || (keyID != loadedKeyID)) {
+        test.Fatalf("Loaded key does not match the generated key.");
+    }
+
+    keys, err := GetToolJWKS();
+    if (err != nil) {
+        test.Fatalf("Failed to get tool key set: '%v'.", err);
+    }
+
+    if (keys.GetKey(keyID) == nil) {
+        test.Fatalf("Tool key set does not have the tool key '%s'.", keyID);
+    }
+}
+
+func TestGetAccessToken(test *testing.T) {
+    setTestKeyPath(test);
+
+    mock, err := NewMockPlatform("client");
+    if (err != nil) {
+        test.Fatalf("Failed to start mock platform: '%v'.", err);
+    }
+    defer mock.Close();
+
+    scopes := []string{SCOPE_SCORE, SCOPE_LINE_ITEM};
+
+    token, err := GetAccessToken(mock.Platform(), scopes);
+    if (err != nil) {
+        test.Fatalf("Failed to get access token: '%v'.", err);
+    }
+
+    // Tokens are cached (and the order of scopes does not matter).
+    cachedToken, err := GetAccessToken(mock.Platform(), []string{SCOPE_LINE_ITEM, SCOPE_SCORE});
+    if (err != nil) {
+        test.Fatalf("Failed to get cached access token: '%v'.", err);
+    }
+
+    if (token != cachedToken) {
+        test.Fatalf("Access token was not cached.");
+    }
+
+    // The platform will not recognize the client.
+    badPlatform := mock.Platform();
+    badPlatform.ClientID = "ZZZ";
+
+    _, err = GetAccessToken(badPlatform, scopes);
+    if (err == nil) {
+        test.Fatalf("Did not get an error for an unknown client.");
+    }
+}
+
+// Use a fresh tool key for this test.
+func setTestKeyPath(test *testing.T) string {
+    oldPath := config.LTI_KEY_PATH.Get();
+    test.Cleanup(func() {
+        config.LTI_KEY_PATH.Set(oldPath);
+    });
+
+    path := filepath.Join(test.TempDir(), TOOL_KEY_FILENAME);
+    config.LTI_KEY_PATH.Set(path);
+
+    return path;
+}
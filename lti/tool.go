@@ -0,0 +1,139 @@
+package lti
+
+// The autograder acting as an LTI 1.3 tool.
+// The tool has its own RSA key that it signs service requests (client assertions) with.
+// Platforms get the public half of the key from the tool's key set (see GetToolJWKS()).
+
+import (
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/hex"
+    "encoding/pem"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sync"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    TOOL_KEY_FILENAME = "lti-key.pem";
+    TOOL_KEY_BITS = 2048;
+)
+
+var toolKey *rsa.PrivateKey = nil;
+var toolKeyID string = "";
+var toolKeyPath string = "";
+var toolKeyLock sync.Mutex;
+
+// Get the tool's private key and its key ID.
+// The key is loaded from the configured path (or generated if it does not exist),
+// and cached unless the configured path changes.
+func GetToolKey() (*rsa.PrivateKey, string, error) {
+    path := config.LTI_KEY_PATH.Get();
+    if (path == "") {
+        path = filepath.Join(config.GetWorkDir(), TOOL_KEY_FILENAME);
+    }
+
+    toolKeyLock.Lock();
+    defer toolKeyLock.Unlock();
+
+    if ((toolKey != nil) && (toolKeyPath == path)) {
+        return toolKey, toolKeyID, nil;
+    }
+
+    key, err := loadOrCreateKey(path);
+    if (err != nil) {
+        return nil, "", err;
+    }
+
+    // The key ID is derived from the public key, so it only changes when the key does.
+    hash := sha256.Sum256(key.PublicKey.N.Bytes());
+
+    toolKey = key;
+    toolKeyID = hex.EncodeToString(hash[:8]);
+    toolKeyPath = path;
+
+    return toolKey, toolKeyID, nil;
+}
+
+// Get the key set that platforms use to verify the tool's signatures.
+func GetToolJWKS() (*util.JWKS, error) {
+    key, keyID, err := GetToolKey();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return &util.JWKS{Keys: []*util.JWK{util.NewRSAJWK(keyID, &key.PublicKey)}}, nil;
+}
+
+func loadOrCreateKey(path string) (*rsa.PrivateKey, error) {
+    if (util.PathExists(path)) {
+        return loadKey(path);
+    }
+
+    log.Info().Str("path", path).Msg("Generating a new LTI tool key.");
+
+    key, err := rsa.GenerateKey(rand.Reader, TOOL_KEY_BITS);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to generate LTI tool key: '%w'.", err);
+    }
+
+    err = util.MkDir(filepath.Dir(path));
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to make dir for LTI tool key '%s': '%w'.", path, err);
+    }
+
+    block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)};
+
+    // The key is a secret, so only the owner can read it.
+    err = os.WriteFile(path, pem.EncodeToMemory(block), 0600);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to write LTI tool key '%s': '%w'.", path, err);
+    }
+
+    return key, nil;
+}
+
+// Load a PEM-encoded RSA key (PKCS #1 or PKCS #8).
+func loadKey(path string) (*rsa.PrivateKey, error) {
+    data, err := os.ReadFile(path);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read LTI tool key '%s': '%w'.", path, err);
+    }
+
+    block, _ := pem.Decode(data);
+    if (block == nil) {
+        return nil, fmt.Errorf("LTI tool key '%s' is not PEM encoded.", path);
+    }
+
+    switch (block.Type) {
+        case "RSA PRIVATE KEY":
+            key, err := x509.ParsePKCS1PrivateKey(block.Bytes);
+            if (err != nil) {
+                return nil, fmt.Errorf("Failed to parse LTI tool key '%s': '%w'.", path, err);
+            }
+
+            return key, nil;
+        case "PRIVATE KEY":
+            rawKey, err := x509.ParsePKCS8PrivateKey(block.Bytes);
+            if (err != nil) {
+                return nil, fmt.Errorf("Failed to parse LTI tool key '%s': '%w'.", path, err);
+            }
+
+            key, ok := rawKey.(*rsa.PrivateKey);
+            if (!ok) {
+                return nil, fmt.Errorf("LTI tool key '%s' is not an RSA key.", path);
+            }
+
+            return key, nil;
+        default:
+            return nil, fmt.Errorf("LTI tool key '%s' has an unknown PEM type: '%s'.", path, block.Type);
+    }
+}
@@ -0,0 +1,102 @@
+package metrics
+
+// All the metrics the autograder exports.
+
+import (
+    "strconv"
+    "time"
+)
+
+const (
+    OUTCOME_SUCCESS = "success";
+    OUTCOME_ERROR = "error";
+    OUTCOME_REJECTED = "rejected";
+)
+
+var (
+    APIRequests = newCounterVec("autograder_api_requests_total",
+            "Number of HTTP requests handled, by route pattern, method, and status code.",
+            "endpoint", "method", "status");
+    APIRequestDuration = newHistogramVec("autograder_api_request_duration_seconds",
+            "Time to handle HTTP requests, by route pattern and method.",
+            DefaultBuckets, "endpoint", "method");
+
+    Gradings = newCounterVec("autograder_gradings_total",
+            "Number of gradings, by course, assignment, and outcome (success, error, rejected).",
+            "course", "assignment", "outcome");
+    GradingDuration = newHistogramVec("autograder_grading_duration_seconds",
+            "Time to grade submissions (including rejection checks and storing results), by course and assignment.",
+            LongBuckets, "course", "assignment");
+    SubmissionRejections = newCounterVec("autograder_submission_rejections_total",
+            "Number of rejected submissions, by rejection reason type.",
+            "reason");
+
+    DockerImageBuilds = newCounterVec("autograder_docker_image_builds_total",
+            "Number of Docker image builds, by image and outcome (success, error).",
+            "image", "outcome");
+    DockerImageBuildDuration = newHistogramVec("autograder_docker_image_build_duration_seconds",
+            "Time to build Docker images, by image.",
+            LongBuckets, "image");
+
+    TaskRuns = newCounterVec("autograder_task_runs_total",
+            "Number of scheduled task runs, by course and task type.",
+            "course", "task");
+    TaskFailures = newCounterVec("autograder_task_failures_total",
+            "Number of failed scheduled task runs, by course and task type.",
+            "course", "task");
+
+    LMSRequests = newCounterVec("autograder_lms_requests_total",
+            "Number of LMS operations, by LMS type and operation.",
+            "lms", "operation");
+    LMSErrors = newCounterVec("autograder_lms_errors_total",
+            "Number of failed LMS operations, by LMS type and operation.",
+            "lms", "operation");
+    LMSRequestDuration = newHistogramVec("autograder_lms_request_duration_seconds",
+            "Time for LMS operations, by LMS type and operation.",
+            DefaultBuckets, "lms", "operation");
+)
+
+func ObserveAPIRequest(endpoint string, method string, status int, duration time.Duration) {
+    APIRequests.WithLabelValues(endpoint, method, strconv.Itoa(status)).Inc();
+    APIRequestDuration.WithLabelValues(endpoint, method).Observe(duration.Seconds());
+}
+
+// |rejectReason| should be empty if the submission was not rejected.
+func ObserveGrading(courseID string, assignmentID string, rejectReason string, err error, duration time.Duration) {
+    outcome := OUTCOME_SUCCESS;
+    if (err != nil) {
+        outcome = OUTCOME_ERROR;
+    } else if (rejectReason != "") {
+        outcome = OUTCOME_REJECTED;
+        SubmissionRejections.WithLabelValues(rejectReason).Inc();
+    }
+
+    Gradings.WithLabelValues(courseID, assignmentID, outcome).Inc();
+    GradingDuration.WithLabelValues(courseID, assignmentID).Observe(duration.Seconds());
+}
+
+func ObserveDockerImageBuild(image string, err error, duration time.Duration) {
+    outcome := OUTCOME_SUCCESS;
+    if (err != nil) {
+        outcome = OUTCOME_ERROR;
+    }
+
+    DockerImageBuilds.WithLabelValues(image, outcome).Inc();
+    DockerImageBuildDuration.WithLabelValues(image).Observe(duration.Seconds());
+}
+
+func ObserveTaskRun(courseID string, task string, err error) {
+    TaskRuns.WithLabelValues(courseID, task).Inc();
+    if (err != nil) {
+        TaskFailures.WithLabelValues(courseID, task).Inc();
+    }
+}
+
+func ObserveLMSRequest(lmsType string, operation string, err error, duration time.Duration) {
+    LMSRequests.WithLabelValues(lmsType, operation).Inc();
+    if (err != nil) {
+        LMSErrors.WithLabelValues(lmsType, operation).Inc();
+    }
+
+    LMSRequestDuration.WithLabelValues(lmsType, operation).Observe(duration.Seconds());
+}
@@ -0,0 +1,47 @@
+package metrics
+
+// Prometheus metrics (see autograder.go for the metrics themselves).
+// Metrics are kept in their own registry (instead of the Prometheus default registry)
+// so only the autograder's metrics (and the standard Go/process collectors) are exported.
+
+import (
+    "net/http"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/collectors"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Buckets (in seconds) for things that should be fast (e.g. API requests).
+var DefaultBuckets []float64 = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10};
+
+// Buckets (in seconds) for things that can be slow (e.g. gradings and image builds).
+var LongBuckets []float64 = []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800};
+
+var registry *prometheus.Registry = newRegistry();
+
+func newRegistry() *prometheus.Registry {
+    registry := prometheus.NewRegistry();
+
+    registry.MustRegister(collectors.NewGoCollector());
+    registry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}));
+
+    return registry;
+}
+
+func newCounterVec(name string, help string, labelNames ...string) *prometheus.CounterVec {
+    counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labelNames);
+    registry.MustRegister(counter);
+    return counter;
+}
+
+func newHistogramVec(name string, help string, buckets []float64, labelNames ...string) *prometheus.HistogramVec {
+    histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labelNames);
+    registry.MustRegister(histogram);
+    return histogram;
+}
+
+// Serve all metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+    return promhttp.HandlerFor(registry, promhttp.HandlerOpts{});
+}
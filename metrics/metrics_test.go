@@ -0,0 +1,66 @@
+package metrics
+
+import (
+    "errors"
+    "io"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveGrading(test *testing.T) {
+    testCases := []struct{reject string; err error; outcome string}{
+        {"", nil, OUTCOME_SUCCESS},
+        {"", errors.New("failed"), OUTCOME_ERROR},
+        {"RejectMaxAttempts", nil, OUTCOME_REJECTED},
+    };
+
+    for i, testCase := range testCases {
+        before := testutil.ToFloat64(Gradings.WithLabelValues("test-course", "test-assignment", testCase.outcome));
+        beforeRejections := testutil.ToFloat64(SubmissionRejections.WithLabelValues(testCase.reject));
+
+        ObserveGrading("test-course", "test-assignment", testCase.reject, testCase.err, time.Second);
+
+        if (testutil.ToFloat64(Gradings.WithLabelValues("test-course", "test-assignment", testCase.outcome)) != (before + 1)) {
+            test.Errorf("Case %d: Grading outcome '%s' was not counted.", i, testCase.outcome);
+        }
+
+        rejected := (testCase.outcome == OUTCOME_REJECTED);
+        if (rejected && (testutil.ToFloat64(SubmissionRejections.WithLabelValues(testCase.reject)) != (beforeRejections + 1))) {
+            test.Errorf("Case %d: Rejection '%s' was not counted.", i, testCase.reject);
+        }
+    }
+
+    output := getMetricsOutput(test);
+
+    expected := []string{
+        `autograder_gradings_total{assignment="test-assignment",course="test-course",outcome="success"}`,
+        `autograder_grading_duration_seconds_bucket{assignment="test-assignment",course="test-course",le="1"}`,
+        `go_goroutines`,
+    };
+
+    for _, line := range expected {
+        if (!strings.Contains(output, line)) {
+            test.Errorf("Metric '%s' not found in output:\n%s", line, output);
+        }
+    }
+}
+
+func getMetricsOutput(test *testing.T) string {
+    recorder := httptest.NewRecorder();
+    Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil));
+
+    if (recorder.Code != 200) {
+        test.Fatalf("Unexpected status code for metrics: %d.", recorder.Code);
+    }
+
+    body, err := io.ReadAll(recorder.Body);
+    if (err != nil) {
+        test.Fatalf("Failed to read metrics: '%v'.", err);
+    }
+
+    return string(body);
+}
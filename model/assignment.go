@@ -29,6 +29,10 @@ type Assignment struct {
 
     SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
 
+    // Let students watch the grader's stdout while their submission is being graded.
+    // Graders can always watch.
+    StreamOutput bool `json:"stream-output,omitempty"`
+
     docker.ImageInfo
 
     // Ignore these fields in JSON.
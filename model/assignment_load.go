@@ -43,3 +43,31 @@ func ReadAssignmentConfig(course *Course, path string) (*Assignment, error) {
 
     return &assignment, nil;
 }
+
+// Load an assignment from its JSON representation (as stored in a database) and add it to the course.
+// Unlike ReadAssignmentConfig(), the relative source dir must already be set.
+func ReadAssignmentJSON(course *Course, text string) (*Assignment, error) {
+    if (course == nil) {
+        return nil, fmt.Errorf("Cannot load an assignment without a course.");
+    }
+
+    var assignment Assignment;
+    err := util.JSONFromString(text, &assignment);
+    if (err != nil) {
+        return nil, fmt.Errorf("Could not load assignment JSON: '%w'.", err);
+    }
+
+    assignment.Course = course;
+
+    err = assignment.Validate();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to validate assignment JSON: '%w'.", err);
+    }
+
+    err = course.AddAssignment(&assignment);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to add assignment ('%s') to course: '%w'.", assignment.GetID(), err);
+    }
+
+    return &assignment, nil;
+}
@@ -0,0 +1,146 @@
+package model
+
+// The audit log is an append-only record of privileged actions:
+// API requests that require more than a student role and changes made with the command-line tools.
+// Records are never modified or removed (except when the entire database is cleared).
+
+import (
+    "strings"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+)
+
+const (
+    AUDIT_SOURCE_API = "api";
+    AUDIT_SOURCE_CMD = "cmd";
+
+    AUDIT_REDACTED_VALUE = "<redacted>";
+)
+
+// Parameters with a key that contains any of these (case insensitive) will have their values redacted.
+var auditRedactedKeys []string = []string{"pass", "token", "secret"};
+
+type AuditRecord struct {
+    Timestamp common.Timestamp `json:"timestamp"`
+    // Where the action came from (AUDIT_SOURCE_*).
+    Source string `json:"source"`
+    // Empty for actions that are not tied to a course.
+    CourseID string `json:"course-id"`
+    // The email of the user that made the request, or "cmd:<system user>" for command-line tools.
+    Actor string `json:"actor"`
+    // The API endpoint (without the API prefix, e.g. "user/add") or command name (e.g. "cmd/regrade").
+    Action string `json:"action"`
+    // The main user/assignment the action was performed on (if any).
+    Target string `json:"target"`
+    Parameters map[string]any `json:"parameters"`
+    Success bool `json:"success"`
+    // The locator of the error on failure.
+    Locator string `json:"locator,omitempty"`
+}
+
+// Filters for fetching audit records.
+// Empty (zero) fields match everything.
+type AuditQuery struct {
+    CourseID string
+    Actor string
+    Action string
+    // Bounds (inclusive) on the record's time.
+    After time.Time
+    Before time.Time
+    // Only return the most recent records (<= 0 for no limit).
+    Limit int
+}
+
+// Create a new (successful) record with redacted parameters.
+func NewAuditRecord(source string, courseID string, actor string, action string, target string, parameters map[string]any) *AuditRecord {
+    if (parameters == nil) {
+        parameters = make(map[string]any);
+    }
+
+    return &AuditRecord{
+        Timestamp: common.NowTimestamp(),
+        Source: source,
+        CourseID: courseID,
+        Actor: actor,
+        Action: action,
+        Target: target,
+        Parameters: RedactAuditParameters(parameters),
+        Success: true,
+    };
+}
+
+// Return a copy of the parameters with any sensitive values (passwords, tokens, etc) redacted.
+// Nested objects and arrays are also redacted.
+func RedactAuditParameters(parameters map[string]any) map[string]any {
+    return redactAuditValue(parameters).(map[string]any);
+}
+
+func redactAuditValue(value any) any {
+    switch typedValue := value.(type) {
+        case map[string]any:
+            result := make(map[string]any, len(typedValue));
+            for key, subvalue := range typedValue {
+                if (isRedactedAuditKey(key)) {
+                    result[key] = AUDIT_REDACTED_VALUE;
+                } else {
+                    result[key] = redactAuditValue(subvalue);
+                }
+            }
+
+            return result;
+        case []any:
+            result := make([]any, 0, len(typedValue));
+            for _, subvalue := range typedValue {
+                result = append(result, redactAuditValue(subvalue));
+            }
+
+            return result;
+        default:
+            return value;
+    }
+}
+
+func isRedactedAuditKey(key string) bool {
+    key = strings.ToLower(key);
+
+    for _, redactedKey := range auditRedactedKeys {
+        if (strings.Contains(key, redactedKey)) {
+            return true;
+        }
+    }
+
+    return false;
+}
+
+func (this *AuditRecord) Time() time.Time {
+    instance, _ := this.Timestamp.Time();
+    return instance;
+}
+
+// Check if a record passes the query's filters (the limit is not considered).
+func (this *AuditQuery) Matches(record *AuditRecord) bool {
+    if ((this.CourseID != "") && (this.CourseID != record.CourseID)) {
+        return false;
+    }
+
+    if ((this.Actor != "") && (this.Actor != record.Actor)) {
+        return false;
+    }
+
+    if ((this.Action != "") && (this.Action != record.Action)) {
+        return false;
+    }
+
+    instance := record.Time();
+
+    if (!this.After.IsZero() && instance.Before(this.After.Truncate(time.Second))) {
+        return false;
+    }
+
+    if (!this.Before.IsZero() && instance.After(this.Before)) {
+        return false;
+    }
+
+    return true;
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestRedactAuditParameters(test *testing.T) {
+    parameters := map[string]any{
+        "user-email": "admin@test.com",
+        "user-pass": "abc",
+        "new-pass": "def",
+        "User-Token": "ghi",
+        "count": 3.0,
+        "options": map[string]any{
+            "client-secret": "jkl",
+            "name": "foo",
+        },
+        "users": []any{
+            map[string]any{"email": "a@test.com", "pass": "mno"},
+            "b@test.com",
+        },
+    };
+
+    expected := map[string]any{
+        "user-email": "admin@test.com",
+        "user-pass": AUDIT_REDACTED_VALUE,
+        "new-pass": AUDIT_REDACTED_VALUE,
+        "User-Token": AUDIT_REDACTED_VALUE,
+        "count": 3.0,
+        "options": map[string]any{
+            "client-secret": AUDIT_REDACTED_VALUE,
+            "name": "foo",
+        },
+        "users": []any{
+            map[string]any{"email": "a@test.com", "pass": AUDIT_REDACTED_VALUE},
+            "b@test.com",
+        },
+    };
+
+    actual := RedactAuditParameters(parameters);
+    if (!reflect.DeepEqual(expected, actual)) {
+        test.Fatalf("Unexpected redaction. Expected: '%v', Actual: '%v'.", expected, actual);
+    }
+
+    // The original should not be modified.
+    if (parameters["user-pass"] != "abc") {
+        test.Fatalf("Original parameters were modified: '%v'.", parameters);
+    }
+}
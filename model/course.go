@@ -30,10 +30,14 @@ type Course struct {
     // A common submission limit that assignments can inherit.
     SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
 
+    // Changes to the default capabilities of specific users.
+    Permissions *CoursePermissions `json:"permissions,omitempty"`
+
     Backup []*tasks.BackupTask `json:"backup,omitempty"`
     CourseUpdate []*tasks.CourseUpdateTask `json:"course-update,omitempty"`
     Report []*tasks.ReportTask `json:"report,omitempty"`
     ScoringUpload []*tasks.ScoringUploadTask `json:"scoring-upload,omitempty"`
+    LMSSync []*tasks.LMSSyncTask `json:"lms-sync,omitempty"`
 
     // Internal fields the autograder will set.
     Assignments map[string]*Assignment `json:"-"`
@@ -117,6 +121,13 @@ func (this *Course) Validate() error {
         }
     }
 
+    if (this.Permissions != nil) {
+        err = this.Permissions.Validate();
+        if (err != nil) {
+            return fmt.Errorf("Failed to validate permissions: '%w'.", err);
+        }
+    }
+
     // Register tasks.
     this.scheduledTasks = make([]tasks.ScheduledTask, 0);
 
@@ -136,6 +147,10 @@ func (this *Course) Validate() error {
         this.scheduledTasks = append(this.scheduledTasks, task);
     }
 
+    for _, task := range this.LMSSync {
+        this.scheduledTasks = append(this.scheduledTasks, task);
+    }
+
     // Validate tasks.
     for _, task := range this.scheduledTasks {
         err = task.Validate(this);
@@ -69,3 +69,23 @@ func ReadCourseConfig(path string) (*Course, error) {
 
     return &course, nil;
 }
+
+// Load just the course (and validate) from its JSON representation.
+// Like ReadCourseConfig(), no assignments will be loaded.
+// This is useful for databases that store courses as JSON.
+func ReadCourseJSON(text string) (*Course, error) {
+    var course Course;
+    err := util.JSONFromString(text, &course);
+    if (err != nil) {
+        return nil, fmt.Errorf("Could not load course JSON: '%w'.", err);
+    }
+
+    course.Assignments = make(map[string]*Assignment);
+
+    err = course.Validate();
+    if (err != nil) {
+        return nil, fmt.Errorf("Could not validate course JSON: '%w'.", err);
+    }
+
+    return &course, nil;
+}
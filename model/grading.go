@@ -14,6 +14,9 @@ type GradingResult struct {
     OutputFilesGZip map[string][]byte `json:"output-files-gzip"`
     Stdout string `json:"stdout"`
     Stderr string `json:"stderr"`
+
+    // Set when grading was stopped for going over a limit (e.g. docker.LIMIT_REASON_TIMEOUT).
+    FailureReason string `json:"failure-reason,omitempty"`
 }
 
 type GradingInfo struct {
@@ -6,7 +6,11 @@ import (
 )
 
 const (
+    LMS_TYPE_BLACKBOARD = "blackboard"
+    LMS_TYPE_BRIGHTSPACE = "brightspace"
     LMS_TYPE_CANVAS = "canvas"
+    LMS_TYPE_LTI = "lti"
+    LMS_TYPE_MOODLE = "moodle"
     LMS_TYPE_TEST = "test"
 )
 
@@ -18,12 +22,26 @@ type LMSAdapter struct {
     APIToken string `json:"api-token,omitempty"`
     BaseURL string `json:"base-url,omitempty"`
 
+    // Application credentials for LMSs that do not use a single token
+    // (Blackboard: application key and secret, Brightspace: application ID and key).
+    AppKey string `json:"app-key,omitempty"`
+    AppSecret string `json:"app-secret,omitempty"`
+    // User credentials (Brightspace: user ID and key).
+    UserID string `json:"user-id,omitempty"`
+    UserKey string `json:"user-key,omitempty"`
+
+    // The platform this course launches from (for LTI launches and the "lti" type).
+    LTI *LTIPlatform `json:"lti,omitempty"`
+
     // Behavior options.
 
     SyncUserAttributes bool `json:"sync-user-attributes,omitempty"`
     SyncUserAdds bool `json:"sync-user-adds,omitempty"`
     SyncUserRemoves bool `json:"sync-user-removes,omitempty"`
 
+    // Only sync the users whose enrollments have changed since the last sync (see lms.sync.full.hours).
+    IncrementalUserSync bool `json:"incremental-user-sync,omitempty"`
+
     SyncAssignments bool `json:"sync-assignments,omitempty"`
 }
 
@@ -33,5 +51,16 @@ func (this *LMSAdapter) Validate() error {
     }
     this.Type = strings.ToLower(this.Type);
 
+    if ((this.Type == LMS_TYPE_LTI) && (this.LTI == nil)) {
+        return fmt.Errorf("LMS type '%s' requires LTI platform information.", LMS_TYPE_LTI);
+    }
+
+    if (this.LTI != nil) {
+        err := this.LTI.Validate();
+        if (err != nil) {
+            return fmt.Errorf("Failed to validate LTI platform: '%w'.", err);
+        }
+    }
+
     return nil;
 }
@@ -0,0 +1,51 @@
+package model
+
+import (
+    "fmt"
+    "strings"
+)
+
+// An LTI 1.3 platform (LMS) that the autograder is registered with as a tool.
+// The values come from the platform when the tool is registered (e.g. a Canvas developer key).
+type LTIPlatform struct {
+    Issuer string `json:"issuer"`
+    ClientID string `json:"client-id"`
+    // If set, launches from other deployments are rejected.
+    DeploymentID string `json:"deployment-id,omitempty"`
+
+    // The platform's OIDC authorization endpoint.
+    AuthURL string `json:"auth-url"`
+    // The platform's key set (used to verify launches).
+    JWKSURL string `json:"jwks-url"`
+    // The platform's OAuth 2 token endpoint (used for grade and roster services).
+    TokenURL string `json:"token-url,omitempty"`
+
+    // Service endpoints for the course.
+    // These are usually learned from launches, but can be set to use services before the first launch.
+    LineItemsURL string `json:"lineitems-url,omitempty"`
+    MembershipsURL string `json:"memberships-url,omitempty"`
+}
+
+func (this *LTIPlatform) Validate() error {
+    this.Issuer = strings.TrimSpace(this.Issuer);
+    this.ClientID = strings.TrimSpace(this.ClientID);
+    this.DeploymentID = strings.TrimSpace(this.DeploymentID);
+
+    if (this.Issuer == "") {
+        return fmt.Errorf("LTI platform issuer cannot be empty.");
+    }
+
+    if (this.ClientID == "") {
+        return fmt.Errorf("LTI platform client ID cannot be empty.");
+    }
+
+    if (this.AuthURL == "") {
+        return fmt.Errorf("LTI platform auth URL cannot be empty.");
+    }
+
+    if (this.JWKSURL == "") {
+        return fmt.Errorf("LTI platform JWKS URL cannot be empty.");
+    }
+
+    return nil;
+}
@@ -0,0 +1,246 @@
+package model
+
+// Permissions are expressed as named capabilities.
+// Each role has a default set of capabilities (see roleCapabilities),
+// and a course can change the capabilities of specific users (see CoursePermissions).
+// Some capabilities are scoped to assignments, and a user can be limited to only using them on specific assignments.
+// Server capabilities are not tied to any course (and cannot be granted by one), they are only held by server admins.
+
+import (
+    "fmt"
+    "slices"
+
+    "github.com/eriq-augustine/autograder/common"
+)
+
+type Capability string;
+
+const (
+    // Make (and view) your own submissions.
+    CapabilitySubmit Capability = "submit"
+    // View the users in a course.
+    CapabilityViewUsers Capability = "view-users"
+    // Add/remove users and manage other users' passwords and tokens.
+    CapabilityManageUsers Capability = "manage-users"
+    // View other users' submissions and scores.
+    CapabilityViewSubmissions Capability = "view-submissions"
+    CapabilityRemoveSubmissions Capability = "remove-submissions"
+    CapabilityUploadLMSScores Capability = "upload-lms-scores"
+    CapabilitySyncLMS Capability = "sync-lms"
+    CapabilityRegrade Capability = "regrade"
+    CapabilityUpdateCourse Capability = "update-course"
+    CapabilityViewQueue Capability = "view-queue"
+    CapabilityManageAuthThrottles Capability = "manage-auth-throttles"
+    CapabilityViewAuditLog Capability = "view-audit-log"
+
+    // View and clear the authentication throttles for IP addresses.
+    CapabilityManageIPAuthThrottles Capability = "manage-ip-auth-throttles"
+)
+
+// All known capabilities (in a stable order).
+var capabilities []Capability = []Capability{
+    CapabilitySubmit,
+    CapabilityViewUsers,
+    CapabilityManageUsers,
+    CapabilityViewSubmissions,
+    CapabilityRemoveSubmissions,
+    CapabilityUploadLMSScores,
+    CapabilitySyncLMS,
+    CapabilityRegrade,
+    CapabilityUpdateCourse,
+    CapabilityViewQueue,
+    CapabilityManageAuthThrottles,
+    CapabilityViewAuditLog,
+};
+
+// Capabilities that are held by server admins (see config server.admins) instead of course users.
+var serverCapabilities []Capability = []Capability{
+    CapabilityManageIPAuthThrottles,
+};
+
+// Capabilities that act on a specific assignment, and can therefore be limited to specific assignments.
+var assignmentCapabilities []Capability = []Capability{
+    CapabilitySubmit,
+    CapabilityViewSubmissions,
+    CapabilityRemoveSubmissions,
+    CapabilityUploadLMSScores,
+    CapabilityRegrade,
+};
+
+var studentCapabilities []Capability = []Capability{
+    CapabilitySubmit,
+};
+
+var graderCapabilities []Capability = append(slices.Clone(studentCapabilities),
+    CapabilityViewUsers,
+    CapabilityViewSubmissions,
+    CapabilityRemoveSubmissions,
+    CapabilityUploadLMSScores,
+);
+
+var adminCapabilities []Capability = append(slices.Clone(graderCapabilities),
+    CapabilityManageUsers,
+    CapabilitySyncLMS,
+    CapabilityRegrade,
+    CapabilityUpdateCourse,
+    CapabilityViewQueue,
+    CapabilityManageAuthThrottles,
+    CapabilityViewAuditLog,
+);
+
+// The default capabilities for each role.
+var roleCapabilities map[UserRole][]Capability = map[UserRole][]Capability{
+    RoleUnknown: []Capability{},
+    RoleOther: []Capability{},
+    RoleStudent: studentCapabilities,
+    RoleGrader: graderCapabilities,
+    RoleAdmin: adminCapabilities,
+    RoleOwner: adminCapabilities,
+};
+
+// Changes to the capabilities of specific users in a course.
+type CoursePermissions struct {
+    // {email: override, ...}.
+    Users map[string]*PermissionOverride `json:"users,omitempty"`
+}
+
+// Changes to the default capabilities of a user's role.
+type PermissionOverride struct {
+    // Capabilities to add to the role's defaults.
+    Grant []Capability `json:"grant,omitempty"`
+    // Capabilities to remove from the role's defaults (takes precedence over grants).
+    Revoke []Capability `json:"revoke,omitempty"`
+    // If not empty, then assignment capabilities can only be used on these assignments.
+    Assignments []string `json:"assignments,omitempty"`
+}
+
+func GetCapabilities() []Capability {
+    return slices.Clone(capabilities);
+}
+
+func (this Capability) IsAssignmentCapability() bool {
+    return slices.Contains(assignmentCapabilities, this);
+}
+
+func (this Capability) IsServerCapability() bool {
+    return slices.Contains(serverCapabilities, this);
+}
+
+// Check if a server user has a server capability.
+// The caller is responsible for making sure the server user actually authenticated as themselves.
+func (this *ServerUser) HasServerCapability(capability Capability) bool {
+    if (!capability.IsServerCapability()) {
+        return false;
+    }
+
+    return this.IsServerAdmin();
+}
+
+// Is this capability in the role's defaults.
+func RoleHasCapability(role UserRole, capability Capability) bool {
+    return slices.Contains(roleCapabilities[role], capability);
+}
+
+func validateCapabilities(capabilityList []Capability) error {
+    for _, capability := range capabilityList {
+        if (!slices.Contains(capabilities, capability)) {
+            return fmt.Errorf("Unknown capability: '%s'.", capability);
+        }
+    }
+
+    return nil;
+}
+
+func (this *CoursePermissions) Validate() error {
+    if (this.Users == nil) {
+        this.Users = make(map[string]*PermissionOverride);
+    }
+
+    for email, override := range this.Users {
+        if (override == nil) {
+            return fmt.Errorf("Permissions for user '%s' are empty.", email);
+        }
+
+        err := override.Validate();
+        if (err != nil) {
+            return fmt.Errorf("Invalid permissions for user '%s': '%w'.", email, err);
+        }
+    }
+
+    return nil;
+}
+
+func (this *PermissionOverride) Validate() error {
+    err := validateCapabilities(this.Grant);
+    if (err != nil) {
+        return err;
+    }
+
+    err = validateCapabilities(this.Revoke);
+    if (err != nil) {
+        return err;
+    }
+
+    for i, assignmentID := range this.Assignments {
+        this.Assignments[i], err = common.ValidateID(assignmentID);
+        if (err != nil) {
+            return fmt.Errorf("Invalid assignment ID '%s': '%w'.", assignmentID, err);
+        }
+    }
+
+    return nil;
+}
+
+// Get all the capabilities a user has in a course (ignoring assignment limits).
+func (this *Course) GetUserCapabilities(user *User) []Capability {
+    override := this.getPermissionOverride(user);
+
+    result := make([]Capability, 0, len(capabilities));
+    for _, capability := range capabilities {
+        hasCapability := RoleHasCapability(user.Role, capability);
+
+        if (override != nil) {
+            if (slices.Contains(override.Grant, capability)) {
+                hasCapability = true;
+            }
+
+            if (slices.Contains(override.Revoke, capability)) {
+                hasCapability = false;
+            }
+        }
+
+        if (hasCapability) {
+            result = append(result, capability);
+        }
+    }
+
+    return result;
+}
+
+// Check if a user has a capability in this course.
+// For assignment capabilities, |assignmentID| is the assignment being acted on (empty if the action is not on an assignment).
+// Users limited to specific assignments only have assignment capabilities when acting on one of those assignments.
+func (this *Course) HasCapability(user *User, capability Capability, assignmentID string) bool {
+    if (!slices.Contains(this.GetUserCapabilities(user), capability)) {
+        return false;
+    }
+
+    if (!capability.IsAssignmentCapability()) {
+        return true;
+    }
+
+    override := this.getPermissionOverride(user);
+    if ((override == nil) || (len(override.Assignments) == 0)) {
+        return true;
+    }
+
+    return slices.Contains(override.Assignments, assignmentID);
+}
+
+func (this *Course) getPermissionOverride(user *User) *PermissionOverride {
+    if ((this.Permissions == nil) || (user == nil)) {
+        return nil;
+    }
+
+    return this.Permissions.Users[user.Email];
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+    "slices"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/config"
+)
+
+func TestCourseUserCapabilities(test *testing.T) {
+    course := &Course{
+        ID: "test",
+        Permissions: &CoursePermissions{
+            Users: map[string]*PermissionOverride{
+                "ta@test.com": &PermissionOverride{
+                    Revoke: []Capability{CapabilityUploadLMSScores, CapabilityRemoveSubmissions},
+                },
+                "limited@test.com": &PermissionOverride{
+                    Grant: []Capability{CapabilityRegrade},
+                    Assignments: []string{"hw1"},
+                },
+            },
+        },
+    };
+
+    testCases := []struct{user *User; expected []Capability}{
+        {&User{Email: "other@test.com", Role: RoleOther}, []Capability{}},
+        {&User{Email: "student@test.com", Role: RoleStudent}, []Capability{CapabilitySubmit}},
+        {&User{Email: "ta@test.com", Role: RoleGrader}, []Capability{CapabilitySubmit, CapabilityViewUsers, CapabilityViewSubmissions}},
+        {
+            &User{Email: "limited@test.com", Role: RoleGrader},
+            []Capability{CapabilitySubmit, CapabilityViewUsers, CapabilityViewSubmissions, CapabilityRemoveSubmissions,
+                    CapabilityUploadLMSScores, CapabilityRegrade},
+        },
+        {&User{Email: "owner@test.com", Role: RoleOwner}, GetCapabilities()},
+    };
+
+    for i, testCase := range testCases {
+        actual := course.GetUserCapabilities(testCase.user);
+        if (!slices.Equal(testCase.expected, actual)) {
+            test.Errorf("Case %d: Unexpected capabilities. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual);
+        }
+    }
+
+    limited := &User{Email: "limited@test.com", Role: RoleGrader};
+
+    scopeTestCases := []struct{capability Capability; assignmentID string; expected bool}{
+        {CapabilityRegrade, "hw1", true},
+        {CapabilityRegrade, "hw0", false},
+        {CapabilityRegrade, "", false},
+        {CapabilityViewUsers, "", true},
+        {CapabilityViewUsers, "hw0", true},
+        {CapabilityUpdateCourse, "hw1", false},
+    };
+
+    for i, testCase := range scopeTestCases {
+        actual := course.HasCapability(limited, testCase.capability, testCase.assignmentID);
+        if (testCase.expected != actual) {
+            test.Errorf("Case %d: Unexpected result for '%s' on '%s'. Expected: %v, Actual: %v.",
+                    i, testCase.capability, testCase.assignmentID, testCase.expected, actual);
+        }
+    }
+}
+
+func TestCoursePermissionsValidate(test *testing.T) {
+    permissions := &CoursePermissions{Users: map[string]*PermissionOverride{
+        "a@test.com": &PermissionOverride{Grant: []Capability{"fly"}},
+    }};
+
+    if (permissions.Validate() == nil) {
+        test.Fatalf("Unknown capability did not fail validation.");
+    }
+
+    // Courses cannot grant server capabilities.
+    permissions = &CoursePermissions{Users: map[string]*PermissionOverride{
+        "a@test.com": &PermissionOverride{Grant: []Capability{CapabilityManageIPAuthThrottles}},
+    }};
+
+    if (permissions.Validate() == nil) {
+        test.Fatalf("Server capability did not fail validation.");
+    }
+
+    permissions = &CoursePermissions{Users: map[string]*PermissionOverride{
+        "a@test.com": &PermissionOverride{Grant: []Capability{CapabilityRegrade}, Assignments: []string{"HW1"}},
+    }};
+
+    err := permissions.Validate();
+    if (err != nil) {
+        test.Fatalf("Valid permissions failed validation: '%v'.", err);
+    }
+
+    if (permissions.Users["a@test.com"].Assignments[0] != "hw1") {
+        test.Fatalf("Assignment ID was not normalized: '%v'.", permissions.Users["a@test.com"].Assignments);
+    }
+}
+
+func TestServerUserCapabilities(test *testing.T) {
+    defer config.SERVER_ADMINS.Set("");
+    config.SERVER_ADMINS.Set("admin@test.com, Other@test.com");
+
+    testCases := []struct{email string; capability Capability; expected bool}{
+        {"admin@test.com", CapabilityManageIPAuthThrottles, true},
+        {"other@test.com", CapabilityManageIPAuthThrottles, true},
+        {"student@test.com", CapabilityManageIPAuthThrottles, false},
+
+        // Course capabilities are not server capabilities.
+        {"admin@test.com", CapabilityManageAuthThrottles, false},
+    };
+
+    for i, testCase := range testCases {
+        actual := NewServerUser(testCase.email, "").HasServerCapability(testCase.capability);
+        if (actual != testCase.expected) {
+            test.Errorf("Case %d: Unexpected capability. Expected: %v, Actual: %v.", i, testCase.expected, actual);
+        }
+    }
+}
@@ -0,0 +1,97 @@
+package model
+
+// Server users are identities that exist independently of any course.
+// A server user logs in once (with a single password) and can act in any course they are enrolled in.
+// Enrollment is represented by a course user with a matching email,
+// which still holds all the per-course information (role, LMS ID, etc).
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+type ServerUser struct {
+    Email string `json:"email"`
+    Name string `json:"name"`
+    Pass string `json:"pass"`
+    Salt string `json:"salt"`
+}
+
+// A user's membership in a course.
+type Enrollment struct {
+    CourseID string `json:"course-id"`
+    CourseName string `json:"course-name"`
+    Role UserRole `json:"role"`
+}
+
+func NewServerUser(email string, name string) *ServerUser {
+    return &ServerUser{
+        Email: email,
+        Name: name,
+    };
+}
+
+// Sets the password and generates a new salt.
+// The passed in passowrd should actually be a hash of the cleartext password.
+func (this *ServerUser) SetPassword(hashPass string) error {
+    pass, salt, err := hashPassword(hashPass);
+    if (err != nil) {
+        return err;
+    }
+
+    this.Pass = pass;
+    this.Salt = salt;
+
+    return nil;
+}
+
+// Set a random passowrd, and return the cleartext (not hash) password.
+func (this *ServerUser) SetRandomPassword() (string, error) {
+    pass, err := util.RandHex(DEFAULT_PASSWORD_LEN)
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to generate random password: '%s'.", err);
+    }
+
+    err = this.SetPassword(util.Sha256HexFromString(pass));
+    if (err != nil) {
+        return "", err;
+    }
+
+    return pass, nil;
+}
+
+// Server passwords are only ever set by the user themselves (never copied from a course user),
+// so a server user without a password cannot log in until they set one (e.g., with a password reset).
+func (this *ServerUser) HasPassword() bool {
+    return (this.Pass != "");
+}
+
+// Return true if the password matches the hash, false otherwise.
+// See User.CheckPassword().
+func (this *ServerUser) CheckPassword(hashPass string) bool {
+    if (!this.HasPassword()) {
+        return false;
+    }
+
+    return checkPassword(this.Email, this.Pass, this.Salt, hashPass);
+}
+
+// Return true if the two users share the same password hash.
+func (this *ServerUser) SamePassword(user *User) bool {
+    return (this.Pass == user.Pass) && (this.Salt == user.Salt);
+}
+
+// Server admins are listed in the config (server.admins).
+func (this *ServerUser) IsServerAdmin() bool {
+    for _, email := range strings.Split(config.SERVER_ADMINS.Get(), ",") {
+        email = strings.TrimSpace(email);
+        if ((email != "") && strings.EqualFold(email, this.Email)) {
+            return true;
+        }
+    }
+
+    return false;
+}
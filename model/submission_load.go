@@ -71,11 +71,30 @@ func LoadGradingResult(resultPath string) (*GradingResult, error) {
         return nil, fmt.Errorf("Unable to gzip files in submission output dir '%s': '%w'.", submissionOutputDir, err);
     }
 
-    return &GradingResult{
+    result := &GradingResult{
         Info: &gradingInfo,
         InputFilesGZip: inputFileContents,
         OutputFilesGZip: outputFileContents,
-    }, nil;
+    };
+
+    // Text output is optional.
+    stdoutPath := filepath.Join(baseSubmissionDir, common.SUBMISSION_STDOUT_FILENAME);
+    if (util.PathExists(stdoutPath)) {
+        result.Stdout, err = util.ReadFile(stdoutPath);
+        if (err != nil) {
+            return nil, fmt.Errorf("Unable to read submission stdout file '%s': '%w'.", stdoutPath, err);
+        }
+    }
+
+    stderrPath := filepath.Join(baseSubmissionDir, common.SUBMISSION_STDERR_FILENAME);
+    if (util.PathExists(stderrPath)) {
+        result.Stderr, err = util.ReadFile(stderrPath);
+        if (err != nil) {
+            return nil, fmt.Errorf("Unable to read submission stderr file '%s': '%w'.", stderrPath, err);
+        }
+    }
+
+    return result, nil;
 }
 
 func MustLoadGradingResult(resultPath string) *GradingResult {
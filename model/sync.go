@@ -1,5 +1,16 @@
 package model
 
+const (
+    // All LMS users were fetched and all users were checked.
+    USER_SYNC_STRATEGY_FULL = "full";
+    // All LMS users were fetched, but only users whose enrollments changed since the last sync were checked.
+    USER_SYNC_STRATEGY_DIFF = "diff";
+    // Only users whose enrollments changed since the last sync were fetched (and checked).
+    USER_SYNC_STRATEGY_INCREMENTAL = "incremental";
+    // Only specific users were fetched and checked.
+    USER_SYNC_STRATEGY_SELECTED = "selected";
+)
+
 type LMSSyncResult struct {
     UserSync *UserSyncResult `json:"user-sync"`
     AssignmentSync *AssignmentSyncResult `json:"assignment-sync"`
@@ -27,6 +38,9 @@ func NewAssignmentSyncResult() *AssignmentSyncResult {
 }
 
 type UserSyncResult struct {
+    // How users were synced (USER_SYNC_STRATEGY_*), empty for syncs that do not come from an LMS.
+    Strategy string
+
     Add []*User
     Mod []*User
     Del []*User
@@ -61,6 +75,12 @@ func NewUserSyncResult() *UserSyncResult {
     }
 }
 
+func NewUserSyncResultWithStrategy(strategy string) *UserSyncResult {
+    result := NewUserSyncResult();
+    result.Strategy = strategy;
+    return result;
+}
+
 func (this *UserSyncResult) Count() int {
     return len(this.Add) + len(this.Mod) + len(this.Del);
 }
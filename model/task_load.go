@@ -0,0 +1,27 @@
+package model
+
+import (
+    "path/filepath"
+    "time"
+
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const TASKS_FILENAME = "tasks.json";
+
+// Load a task completion log ({task id: completion time}) from a file adjacent to the course config (if it exists).
+func LoadStaticTaskLog(courseConfigPath string) (map[string]time.Time, error) {
+    taskLog := make(map[string]time.Time);
+
+    path := filepath.Join(filepath.Dir(courseConfigPath), TASKS_FILENAME);
+    if (!util.PathExists(path)) {
+        return taskLog, nil;
+    }
+
+    err := util.JSONFromFile(path, &taskLog);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return taskLog, nil;
+}
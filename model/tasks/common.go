@@ -21,6 +21,8 @@ type TaskCourse interface {
 
 type ScheduledTask interface {
     GetID() string
+    // The type of task (e.g. "backup").
+    GetName() string
     GetCourseID() string
     IsDisabled() bool
     GetTimes() []*common.ScheduledTime
@@ -47,6 +49,10 @@ func (this *BaseTask) GetID() string {
     return this.ID;
 }
 
+func (this *BaseTask) GetName() string {
+    return this.Name;
+}
+
 func (this *BaseTask) GetCourseID() string {
     return this.CourseID;
 }
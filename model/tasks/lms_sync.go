@@ -0,0 +1,30 @@
+package tasks
+
+import (
+    "fmt"
+)
+
+type LMSSyncTask struct {
+    *BaseTask
+
+    DryRun bool `json:"dry-run"`
+    SendEmails bool `json:"send-emails"`
+
+    // If set, a summary of user changes will be sent to these addresses (only when users change).
+    To []string `json:"to,omitempty"`
+}
+
+func (this *LMSSyncTask) Validate(course TaskCourse) error {
+    this.BaseTask.Name = "lms-sync";
+
+    err := this.BaseTask.Validate(course);
+    if (err != nil) {
+        return err;
+    }
+
+    if (!course.HasLMSAdapter()) {
+        return fmt.Errorf("LMS sync task course must have an LMS adapter.");
+    }
+
+    return nil;
+}
@@ -0,0 +1,88 @@
+package model
+
+// API tokens let a user authenticate without sending their password with every request.
+// Only a hash of a token is ever stored, the cleartext token is only seen when it is created.
+// Tokens are long random strings, so a fast hash (SHA-256) is enough (unlike passwords, which use argon2).
+
+import (
+    "crypto/subtle"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    API_TOKEN_ID_LEN = 16;
+    API_TOKEN_SECRET_LEN = 64;
+
+    // Cleartext tokens look like: "<id>.<secret>".
+    API_TOKEN_SEPARATOR = ".";
+)
+
+type APIToken struct {
+    ID string `json:"id"`
+    User string `json:"user"`
+    Name string `json:"name"`
+    // Hex encoding of the SHA-256 hash of the full cleartext token.
+    Hash string `json:"hash"`
+
+    CreationTime common.Timestamp `json:"creation-time"`
+    ExpirationTime common.Timestamp `json:"expiration-time"`
+}
+
+// Create a new token for a user that will expire after the given duration.
+// Returns the token and its cleartext (which is not stored anywhere).
+func NewAPIToken(user string, name string, duration time.Duration) (*APIToken, string, error) {
+    id, err := util.RandHex(API_TOKEN_ID_LEN);
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Failed to generate token id: '%w'.", err);
+    }
+
+    secret, err := util.RandHex(API_TOKEN_SECRET_LEN);
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Failed to generate token secret: '%w'.", err);
+    }
+
+    cleartext := id + API_TOKEN_SEPARATOR + secret;
+    now := time.Now();
+
+    token := &APIToken{
+        ID: id,
+        User: user,
+        Name: name,
+        Hash: util.Sha256HexFromString(cleartext),
+        CreationTime: common.TimestampFromTime(now),
+        ExpirationTime: common.TimestampFromTime(now.Add(duration)),
+    };
+
+    return token, cleartext, nil;
+}
+
+// Get the ID from a cleartext token.
+func ParseAPITokenID(cleartext string) (string, error) {
+    id, _, found := strings.Cut(cleartext, API_TOKEN_SEPARATOR);
+    if (!found || (id == "")) {
+        return "", fmt.Errorf("Malformed API token.");
+    }
+
+    return id, nil;
+}
+
+// Return true if the cleartext token matches this token (expiration is not checked).
+func (this *APIToken) Check(cleartext string) bool {
+    otherHash := util.Sha256HexFromString(cleartext);
+    return (subtle.ConstantTimeCompare([]byte(this.Hash), []byte(otherHash)) == 1);
+}
+
+// Tokens with a bad expiration time are considered expired.
+func (this *APIToken) IsExpired() bool {
+    expiration, err := this.ExpirationTime.Time();
+    if (err != nil) {
+        return true;
+    }
+
+    return !time.Now().Before(expiration);
+}
@@ -53,15 +53,13 @@ func NewUser(email string, name string, role UserRole) *User {
 // Sets the password and generates a new salt.
 // The passed in passowrd should actually be a hash of the cleartext password.
 func (this *User) SetPassword(hashPass string) error {
-    salt, err := util.RandBytes(SALT_LENGTH_BYTES);
+    pass, salt, err := hashPassword(hashPass);
     if (err != nil) {
-        return fmt.Errorf("Could not generate salt: '%w'.", err);
+        return err;
     }
 
-    pass := generateHash(hashPass, salt);
-
-    this.Salt = hex.EncodeToString(salt);
-    this.Pass = hex.EncodeToString(pass);
+    this.Pass = pass;
+    this.Salt = salt;
 
     return nil;
 }
@@ -85,21 +83,7 @@ func (this *User) SetRandomPassword() (string, error) {
 // Return true if the password matches the hash, false otherwise.
 // Any errors (which can only come from bad hex strings) will be logged and ignored (false will be returned).
 func (this *User) CheckPassword(hashPass string) bool {
-    thisHash, err := hex.DecodeString(this.Pass);
-    if (err != nil) {
-        log.Warn().Err(err).Str("user", this.Email).Msg("Bad password hash for user.");
-        return false;
-    }
-
-    salt, err := hex.DecodeString(this.Salt);
-    if (err != nil) {
-        log.Warn().Err(err).Str("user", this.Email).Msg("Bad salt for user.");
-        return false;
-    }
-
-    otherHash := generateHash(hashPass, salt);
-
-    return (subtle.ConstantTimeCompare(thisHash, otherHash) == 1);
+    return checkPassword(this.Email, this.Pass, this.Salt, hashPass);
 }
 
 // Merge another user's information into this user (email will not be merged).
@@ -132,6 +116,38 @@ func (this *User) Merge(other *User) bool {
     return changed;
 }
 
+// Hash a password with a new salt.
+// Returns the hex encodings of: (password hash, salt, error).
+func hashPassword(hashPass string) (string, string, error) {
+    salt, err := util.RandBytes(SALT_LENGTH_BYTES);
+    if (err != nil) {
+        return "", "", fmt.Errorf("Could not generate salt: '%w'.", err);
+    }
+
+    pass := generateHash(hashPass, salt);
+
+    return hex.EncodeToString(pass), hex.EncodeToString(salt), nil;
+}
+
+// Check a password against a stored (hex encoded) hash and salt.
+func checkPassword(email string, storedPass string, storedSalt string, hashPass string) bool {
+    thisHash, err := hex.DecodeString(storedPass);
+    if (err != nil) {
+        log.Warn().Err(err).Str("user", email).Msg("Bad password hash for user.");
+        return false;
+    }
+
+    salt, err := hex.DecodeString(storedSalt);
+    if (err != nil) {
+        log.Warn().Err(err).Str("user", email).Msg("Bad salt for user.");
+        return false;
+    }
+
+    otherHash := generateHash(hashPass, salt);
+
+    return (subtle.ConstantTimeCompare(thisHash, otherHash) == 1);
+}
+
 func generateHash(hashPass string, salt []byte) []byte {
     return argon2.IDKey([]byte(hashPass), salt, ARGON2_TIME, ARGON2_MEM_KB, ARGON2_THREADS, ARGON2_KEY_LEN_BYTES);
 }
@@ -0,0 +1,212 @@
+package oidc
+
+// A local OIDC provider for testing.
+// The provider does not have a login page,
+// the authorization endpoint immediately redirects back with a code for the current identity.
+
+import (
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/subtle"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "sync"
+    "time"
+
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const MOCK_KEY_ID = "mock-key";
+
+type MockProvider struct {
+    ClientID string
+    ClientSecret string
+
+    server *httptest.Server
+    key *rsa.PrivateKey
+
+    lock sync.Mutex
+    // The identity that the next login will be for.
+    email string
+    emailVerified bool
+    codes map[string]*mockAuthorization
+}
+
+type mockAuthorization struct {
+    email string
+    emailVerified bool
+    redirectURL string
+    nonce string
+    codeChallenge string
+}
+
+// Start a mock provider that will authenticate users as the given email.
+// Callers should Close() the provider when done.
+func NewMockProvider(clientID string, clientSecret string, email string) (*MockProvider, error) {
+    key, err := rsa.GenerateKey(rand.Reader, 2048);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to generate mock provider key: '%w'.", err);
+    }
+
+    provider := &MockProvider{
+        ClientID: clientID,
+        ClientSecret: clientSecret,
+        key: key,
+        email: email,
+        emailVerified: true,
+        codes: make(map[string]*mockAuthorization),
+    };
+
+    mux := http.NewServeMux();
+    mux.HandleFunc(DISCOVERY_PATH, provider.handleDiscovery);
+    mux.HandleFunc("/authorize", provider.handleAuthorize);
+    mux.HandleFunc("/token", provider.handleToken);
+    mux.HandleFunc("/jwks", provider.handleJWKS);
+
+    provider.server = httptest.NewServer(mux);
+
+    return provider, nil;
+}
+
+func (this *MockProvider) Issuer() string {
+    return this.server.URL;
+}
+
+func (this *MockProvider) Close() {
+    this.server.Close();
+}
+
+// Set the identity that future logins will be for.
+func (this *MockProvider) SetIdentity(email string, emailVerified bool) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    this.email = email;
+    this.emailVerified = emailVerified;
+}
+
+func (this *MockProvider) handleDiscovery(response http.ResponseWriter, request *http.Request) {
+    discovery := Discovery{
+        Issuer: this.Issuer(),
+        AuthorizationEndpoint: this.Issuer() + "/authorize",
+        TokenEndpoint: this.Issuer() + "/token",
+        JWKSURI: this.Issuer() + "/jwks",
+    };
+
+    writeMockJSON(response, http.StatusOK, discovery);
+}
+
+func (this *MockProvider) handleJWKS(response http.ResponseWriter, request *http.Request) {
+    keys := util.JWKS{Keys: []*util.JWK{util.NewRSAJWK(MOCK_KEY_ID, &this.key.PublicKey)}};
+    writeMockJSON(response, http.StatusOK, keys);
+}
+
+func (this *MockProvider) handleAuthorize(response http.ResponseWriter, request *http.Request) {
+    query := request.URL.Query();
+
+    if ((query.Get("client_id") != this.ClientID) || (query.Get("response_type") != "code")) {
+        http.Error(response, "Bad authorization request.", http.StatusBadRequest);
+        return;
+    }
+
+    if ((query.Get("code_challenge") == "") || (query.Get("code_challenge_method") != "S256")) {
+        http.Error(response, "Missing PKCE challenge.", http.StatusBadRequest);
+        return;
+    }
+
+    redirectURL, err := url.Parse(query.Get("redirect_uri"));
+    if ((err != nil) || (query.Get("redirect_uri") == "")) {
+        http.Error(response, "Bad redirect URI.", http.StatusBadRequest);
+        return;
+    }
+
+    code, err := util.RandHex(32);
+    if (err != nil) {
+        http.Error(response, "Failed to generate code.", http.StatusInternalServerError);
+        return;
+    }
+
+    this.lock.Lock();
+    this.codes[code] = &mockAuthorization{
+        email: this.email,
+        emailVerified: this.emailVerified,
+        redirectURL: query.Get("redirect_uri"),
+        nonce: query.Get("nonce"),
+        codeChallenge: query.Get("code_challenge"),
+    };
+    this.lock.Unlock();
+
+    redirectQuery := redirectURL.Query();
+    redirectQuery.Set("code", code);
+    redirectQuery.Set("state", query.Get("state"));
+    redirectURL.RawQuery = redirectQuery.Encode();
+
+    http.Redirect(response, request, redirectURL.String(), http.StatusFound);
+}
+
+func (this *MockProvider) handleToken(response http.ResponseWriter, request *http.Request) {
+    clientID, clientSecret, ok := request.BasicAuth();
+    if (ok) {
+        clientID, _ = url.QueryUnescape(clientID);
+        clientSecret, _ = url.QueryUnescape(clientSecret);
+    }
+
+    if (!ok || (clientID != this.ClientID) || (subtle.ConstantTimeCompare([]byte(clientSecret), []byte(this.ClientSecret)) != 1)) {
+        writeMockJSON(response, http.StatusUnauthorized, map[string]string{"error": "invalid_client"});
+        return;
+    }
+
+    err := request.ParseForm();
+    if ((err != nil) || (request.PostForm.Get("grant_type") != "authorization_code")) {
+        writeMockJSON(response, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"});
+        return;
+    }
+
+    code := request.PostForm.Get("code");
+
+    // Codes can only be used once.
+    this.lock.Lock();
+    authorization := this.codes[code];
+    delete(this.codes, code);
+    this.lock.Unlock();
+
+    if ((authorization == nil) ||
+            (authorization.redirectURL != request.PostForm.Get("redirect_uri")) ||
+            (authorization.codeChallenge != PKCEChallenge(request.PostForm.Get("code_verifier")))) {
+        writeMockJSON(response, http.StatusBadRequest, map[string]string{"error": "invalid_grant"});
+        return;
+    }
+
+    now := time.Now();
+    claims := map[string]any{
+        "iss": this.Issuer(),
+        "sub": util.Sha256HexFromString(authorization.email),
+        "aud": this.ClientID,
+        "exp": now.Add(time.Hour).Unix(),
+        "iat": now.Unix(),
+        "nonce": authorization.nonce,
+        "email": authorization.email,
+        "email_verified": authorization.emailVerified,
+    };
+
+    idToken, err := util.SignJWT(claims, this.key, MOCK_KEY_ID);
+    if (err != nil) {
+        writeMockJSON(response, http.StatusInternalServerError, map[string]string{"error": "server_error"});
+        return;
+    }
+
+    writeMockJSON(response, http.StatusOK, map[string]any{
+        "access_token": "mock-access-token",
+        "token_type": "Bearer",
+        "expires_in": 3600,
+        "id_token": idToken,
+    });
+}
+
+func writeMockJSON(response http.ResponseWriter, status int, data any) {
+    response.Header().Set("Content-Type", "application/json");
+    response.WriteHeader(status);
+    response.Write([]byte(util.MustToJSON(data)));
+}
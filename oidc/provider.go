@@ -0,0 +1,311 @@
+package oidc
+
+// A minimal OpenID Connect relying party for the authorization code flow.
+// Provider information is discovered from the issuer (/.well-known/openid-configuration),
+// and ID tokens are verified against the provider's published keys.
+
+import (
+    "crypto/sha256"
+    "encoding/base64"
+    "fmt"
+    "net/url"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/util"
+)
+
+const (
+    DISCOVERY_PATH = "/.well-known/openid-configuration";
+
+    // Allowed clock skew when checking token times.
+    CLOCK_SKEW = 2 * time.Minute;
+
+    PKCE_VERIFIER_LEN = 64;
+)
+
+type Discovery struct {
+    Issuer string `json:"issuer"`
+    AuthorizationEndpoint string `json:"authorization_endpoint"`
+    TokenEndpoint string `json:"token_endpoint"`
+    JWKSURI string `json:"jwks_uri"`
+}
+
+type IDTokenClaims struct {
+    Issuer string `json:"iss"`
+    Subject string `json:"sub"`
+    Audience util.JWTAudience `json:"aud"`
+    Expiration int64 `json:"exp"`
+    IssuedAt int64 `json:"iat"`
+    Nonce string `json:"nonce"`
+
+    Email string `json:"email"`
+    // Not all providers send this, see IsEmailVerified().
+    EmailVerified *bool `json:"email_verified"`
+    Name string `json:"name"`
+}
+
+type tokenResponse struct {
+    IDToken string `json:"id_token"`
+    Error string `json:"error"`
+    ErrorDescription string `json:"error_description"`
+}
+
+type Provider struct {
+    Issuer string
+    ClientID string
+    ClientSecret string
+    Scopes []string
+
+    lock sync.Mutex
+    discovery *Discovery
+    keys *util.JWKS
+}
+
+var defaultProvider *Provider = nil;
+var defaultProviderLock sync.Mutex;
+
+func NewProvider(issuer string, clientID string, clientSecret string, scopes []string) *Provider {
+    return &Provider{
+        Issuer: strings.TrimSuffix(issuer, "/"),
+        ClientID: clientID,
+        ClientSecret: clientSecret,
+        Scopes: scopes,
+    };
+}
+
+// Get the provider described by the config.
+// The provider is cached (so discovery only happens once), unless the config changes.
+// Returns an error if OIDC is not configured.
+func GetDefaultProvider() (*Provider, error) {
+    issuer := strings.TrimSuffix(config.OIDC_ISSUER.Get(), "/");
+    if (issuer == "") {
+        return nil, fmt.Errorf("OIDC login is not configured (no issuer).");
+    }
+
+    if (config.OIDC_CLIENT_ID.Get() == "") {
+        return nil, fmt.Errorf("OIDC login is not configured (no client ID).");
+    }
+
+    scopes := strings.Fields(config.OIDC_SCOPES.Get());
+
+    defaultProviderLock.Lock();
+    defer defaultProviderLock.Unlock();
+
+    if ((defaultProvider == nil) ||
+            (defaultProvider.Issuer != issuer) ||
+            (defaultProvider.ClientID != config.OIDC_CLIENT_ID.Get()) ||
+            (defaultProvider.ClientSecret != config.OIDC_CLIENT_SECRET.Get()) ||
+            (strings.Join(defaultProvider.Scopes, " ") != strings.Join(scopes, " "))) {
+        defaultProvider = NewProvider(issuer, config.OIDC_CLIENT_ID.Get(), config.OIDC_CLIENT_SECRET.Get(), scopes);
+    }
+
+    return defaultProvider, nil;
+}
+
+// Get the URL to send the user to in order to log in.
+// The code challenge should come from NewPKCE().
+func (this *Provider) AuthURL(redirectURL string, state string, nonce string, codeChallenge string) (string, error) {
+    discovery, err := this.getDiscovery();
+    if (err != nil) {
+        return "", err;
+    }
+
+    query := url.Values{};
+    query.Set("response_type", "code");
+    query.Set("client_id", this.ClientID);
+    query.Set("redirect_uri", redirectURL);
+    query.Set("scope", strings.Join(this.Scopes, " "));
+    query.Set("state", state);
+    query.Set("nonce", nonce);
+    query.Set("code_challenge", codeChallenge);
+    query.Set("code_challenge_method", "S256");
+
+    separator := "?";
+    if (strings.Contains(discovery.AuthorizationEndpoint, "?")) {
+        separator = "&";
+    }
+
+    return discovery.AuthorizationEndpoint + separator + query.Encode(), nil;
+}
+
+// Exchange an authorization code for a verified ID token.
+func (this *Provider) Exchange(code string, redirectURL string, codeVerifier string, nonce string) (*IDTokenClaims, error) {
+    discovery, err := this.getDiscovery();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    form := map[string]string{
+        "grant_type": "authorization_code",
+        "code": code,
+        "redirect_uri": redirectURL,
+        "code_verifier": codeVerifier,
+    };
+
+    basicAuth := url.QueryEscape(this.ClientID) + ":" + url.QueryEscape(this.ClientSecret);
+    headers := map[string][]string{
+        "Accept": []string{"application/json"},
+        "Authorization": []string{"Basic " + base64.StdEncoding.EncodeToString([]byte(basicAuth))},
+    };
+
+    body, _, err := common.PostWithHeaders(discovery.TokenEndpoint, form, headers);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to exchange authorization code: '%w'.", err);
+    }
+
+    var response tokenResponse;
+    err = util.JSONFromString(body, &response);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to parse token response: '%w'.", err);
+    }
+
+    if (response.Error != "") {
+        return nil, fmt.Errorf("Provider returned an error for the token request: '%s' ('%s').", response.Error, response.ErrorDescription);
+    }
+
+    if (response.IDToken == "") {
+        return nil, fmt.Errorf("Provider did not return an ID token.");
+    }
+
+    return this.VerifyIDToken(response.IDToken, nonce);
+}
+
+// Verify an ID token's signature and claims.
+func (this *Provider) VerifyIDToken(rawToken string, nonce string) (*IDTokenClaims, error) {
+    discovery, err := this.getDiscovery();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    keys, err := this.getKeys(false);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    var claims IDTokenClaims;
+    err = util.VerifyJWT(rawToken, keys, &claims);
+    if (err != nil) {
+        // The provider may have rotated its keys, refresh them once.
+        keys, err = this.getKeys(true);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        err = util.VerifyJWT(rawToken, keys, &claims);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to verify ID token: '%w'.", err);
+        }
+    }
+
+    if (claims.Issuer != discovery.Issuer) {
+        return nil, fmt.Errorf("ID token has the wrong issuer. Expected: '%s', Actual: '%s'.", discovery.Issuer, claims.Issuer);
+    }
+
+    if (!claims.Audience.Contains(this.ClientID)) {
+        return nil, fmt.Errorf("ID token is not for this client: '%v'.", claims.Audience);
+    }
+
+    now := time.Now();
+    if (now.Add(-CLOCK_SKEW).After(time.Unix(claims.Expiration, 0))) {
+        return nil, fmt.Errorf("ID token is expired.");
+    }
+
+    if ((claims.IssuedAt != 0) && now.Add(CLOCK_SKEW).Before(time.Unix(claims.IssuedAt, 0))) {
+        return nil, fmt.Errorf("ID token was issued in the future.");
+    }
+
+    if (claims.Nonce != nonce) {
+        return nil, fmt.Errorf("ID token has the wrong nonce.");
+    }
+
+    return &claims, nil;
+}
+
+func (this *Provider) getDiscovery() (*Discovery, error) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    if (this.discovery != nil) {
+        return this.discovery, nil;
+    }
+
+    body, err := common.Get(this.Issuer + DISCOVERY_PATH);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch OIDC discovery document for '%s': '%w'.", this.Issuer, err);
+    }
+
+    var discovery Discovery;
+    err = util.JSONFromString(body, &discovery);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to parse OIDC discovery document for '%s': '%w'.", this.Issuer, err);
+    }
+
+    if (strings.TrimSuffix(discovery.Issuer, "/") != this.Issuer) {
+        return nil, fmt.Errorf("OIDC discovery document has the wrong issuer. Expected: '%s', Actual: '%s'.", this.Issuer, discovery.Issuer);
+    }
+
+    if ((discovery.AuthorizationEndpoint == "") || (discovery.TokenEndpoint == "") || (discovery.JWKSURI == "")) {
+        return nil, fmt.Errorf("OIDC discovery document for '%s' is missing endpoints.", this.Issuer);
+    }
+
+    this.discovery = &discovery;
+    return this.discovery, nil;
+}
+
+func (this *Provider) getKeys(refresh bool) (*util.JWKS, error) {
+    discovery, err := this.getDiscovery();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    if ((this.keys != nil) && !refresh) {
+        return this.keys, nil;
+    }
+
+    body, err := common.Get(discovery.JWKSURI);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch OIDC keys for '%s': '%w'.", this.Issuer, err);
+    }
+
+    var keys util.JWKS;
+    err = util.JSONFromString(body, &keys);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to parse OIDC keys for '%s': '%w'.", this.Issuer, err);
+    }
+
+    this.keys = &keys;
+    return this.keys, nil;
+}
+
+// Has the provider verified the identity's email?
+// An explicit false is always rejected, and a missing claim is only accepted if |allowMissing| is set.
+func (this *IDTokenClaims) IsEmailVerified(allowMissing bool) bool {
+    if (this.EmailVerified == nil) {
+        return allowMissing;
+    }
+
+    return *this.EmailVerified;
+}
+
+// Create a PKCE (RFC 7636) code verifier and its S256 challenge.
+// Returns: (verifier, challenge, error).
+func NewPKCE() (string, string, error) {
+    verifier, err := util.RandHex(PKCE_VERIFIER_LEN);
+    if (err != nil) {
+        return "", "", fmt.Errorf("Failed to generate PKCE verifier: '%w'.", err);
+    }
+
+    return verifier, PKCEChallenge(verifier), nil;
+}
+
+func PKCEChallenge(verifier string) string {
+    hash := sha256.Sum256([]byte(verifier));
+    return base64.RawURLEncoding.EncodeToString(hash[:]);
+}
@@ -0,0 +1,88 @@
+package oidc
+
+import (
+    "testing"
+    "time"
+
+    "github.com/eriq-augustine/autograder/util"
+)
+
+func TestVerifyIDToken(test *testing.T) {
+    mock, err := NewMockProvider("client", "secret", "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to start mock provider: '%v'.", err);
+    }
+    defer mock.Close();
+
+    provider := NewProvider(mock.Issuer() + "/", "client", "secret", []string{"openid", "email"});
+
+    now := time.Now();
+    baseClaims := func() map[string]any {
+        return map[string]any{
+            "iss": mock.Issuer(),
+            "sub": "1234",
+            "aud": []string{"other", "client"},
+            "exp": now.Add(time.Hour).Unix(),
+            "iat": now.Unix(),
+            "nonce": "nonce",
+            "email": "student@test.com",
+        };
+    };
+
+    testCases := []struct{key string; value any; kid string; valid bool}{
+        {"", nil, MOCK_KEY_ID, true},
+        {"aud", "client", MOCK_KEY_ID, true},
+
+        {"iss", "ZZZ", MOCK_KEY_ID, false},
+        {"aud", "ZZZ", MOCK_KEY_ID, false},
+        {"exp", now.Add(-time.Hour).Unix(), MOCK_KEY_ID, false},
+        {"iat", now.Add(time.Hour).Unix(), MOCK_KEY_ID, false},
+        {"nonce", "ZZZ", MOCK_KEY_ID, false},
+        {"", nil, "ZZZ", false},
+    };
+
+    for i, testCase := range testCases {
+        claims := baseClaims();
+        if (testCase.key != "") {
+            claims[testCase.key] = testCase.value;
+        }
+
+        token, err := util.SignJWT(claims, mock.key, testCase.kid);
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to sign token: '%v'.", i, err);
+        }
+
+        idClaims, err := provider.VerifyIDToken(token, "nonce");
+        if (testCase.valid && (err != nil)) {
+            test.Errorf("Case %d: Valid token failed verification: '%v'.", i, err);
+        } else if (!testCase.valid && (err == nil)) {
+            test.Errorf("Case %d: Invalid token passed verification: '%+v'.", i, idClaims);
+        }
+
+        if ((err == nil) && (idClaims.Email != "student@test.com")) {
+            test.Errorf("Case %d: Unexpected email: '%s'.", i, idClaims.Email);
+        }
+    }
+}
+
+func TestIsEmailVerified(test *testing.T) {
+    verified := true;
+    unverified := false;
+
+    testCases := []struct{emailVerified *bool; allowMissing bool; expected bool}{
+        {&verified, false, true},
+        {&verified, true, true},
+        {&unverified, false, false},
+        {&unverified, true, false},
+        {nil, false, false},
+        {nil, true, true},
+    };
+
+    for i, testCase := range testCases {
+        claims := IDTokenClaims{EmailVerified: testCase.emailVerified};
+        actual := claims.IsEmailVerified(testCase.allowMissing);
+        if (testCase.expected != actual) {
+            test.Errorf("Case %d: Unexpected result. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual);
+        }
+    }
+}
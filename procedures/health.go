@@ -0,0 +1,227 @@
+package procedures
+
+// Checks that the server is ready to handle requests (e.g. for load balancers and watchdogs).
+
+import (
+    "fmt"
+    "slices"
+    "sync"
+    "time"
+
+    "github.com/rs/zerolog/log"
+    "golang.org/x/exp/maps"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/config"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/docker"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/task"
+)
+
+const (
+    CHECK_STATUS_OK = "ok";
+    CHECK_STATUS_FAILED = "failed";
+    CHECK_STATUS_SKIPPED = "skipped";
+)
+
+type ReadinessReport struct {
+    // True if no checks failed (skipped checks do not count against readiness).
+    Ready bool `json:"ready"`
+    Timestamp common.Timestamp `json:"timestamp"`
+    Checks []*ReadinessCheck `json:"checks"`
+}
+
+type ReadinessCheck struct {
+    Name string `json:"name"`
+    Status string `json:"status"`
+    Message string `json:"message,omitempty"`
+    DurationMS int64 `json:"duration-ms"`
+    // Check-specific information (e.g. which images are not built).
+    Details map[string]any `json:"details,omitempty"`
+}
+
+var (
+    cachedReport *ReadinessReport = nil;
+    cachedReportTime time.Time;
+    cachedReportLock sync.Mutex;
+)
+
+// Get a recent readiness report (see config.WEB_READY_CACHE_SECS),
+// only running the checks if the last report is too old.
+// Concurrent callers wait for the same run instead of running the checks themselves.
+// Failed checks are logged (with their details) whenever the checks are run.
+func GetReadiness() *ReadinessReport {
+    cachedReportLock.Lock();
+    defer cachedReportLock.Unlock();
+
+    maxAge := time.Duration(config.WEB_READY_CACHE_SECS.Get()) * time.Second;
+    if ((cachedReport != nil) && (time.Since(cachedReportTime) < maxAge)) {
+        return cachedReport;
+    }
+
+    cachedReport = CheckReadiness();
+    cachedReportTime = time.Now();
+
+    for _, check := range cachedReport.Checks {
+        if (check.Status == CHECK_STATUS_FAILED) {
+            log.Warn().Str("check", check.Name).Str("message", check.Message).Any("details", check.Details).Msg("Readiness check failed.");
+        }
+    }
+
+    return cachedReport;
+}
+
+// Run all the readiness checks.
+func CheckReadiness() *ReadinessReport {
+    report := &ReadinessReport{
+        Ready: true,
+        Timestamp: common.NowTimestamp(),
+        Checks: make([]*ReadinessCheck, 0, 4),
+    };
+
+    var courses map[string]*model.Course;
+    var err error;
+
+    report.add(runCheck("database", func(check *ReadinessCheck) {
+        courses, err = db.GetCourses();
+        if (err != nil) {
+            check.fail(fmt.Sprintf("Database is not responding: '%v'.", err));
+            return;
+        }
+
+        check.Details["courses"] = len(courses);
+    }));
+
+    report.add(runCheck("docker", func(check *ReadinessCheck) {
+        if (config.DOCKER_DISABLE.Get()) {
+            check.skip("Docker is disabled.");
+            return;
+        }
+
+        if (!docker.CanAccessDocker()) {
+            check.fail("Cannot access the Docker daemon.");
+        }
+    }));
+
+    report.add(runCheck("images", func(check *ReadinessCheck) {
+        if (config.DOCKER_DISABLE.Get()) {
+            check.skip("Docker is disabled.");
+            return;
+        }
+
+        if (courses == nil) {
+            check.skip("Database is not available.");
+            return;
+        }
+
+        checkImages(check, courses);
+    }));
+
+    report.add(runCheck("tasks", func(check *ReadinessCheck) {
+        if (config.NO_TASKS.Get()) {
+            check.skip("Tasks are disabled.");
+            return;
+        }
+
+        if (courses == nil) {
+            check.skip("Database is not available.");
+            return;
+        }
+
+        checkTasks(check, courses);
+    }));
+
+    return report;
+}
+
+func checkImages(check *ReadinessCheck, courses map[string]*model.Course) {
+    notBuilt := make([]string, 0);
+    buildErrors := make(map[string]string);
+
+    for _, course := range sortedCourses(courses) {
+        for _, assignment := range course.GetSortedAssignments() {
+            built, err := docker.IsImageBuilt(assignment);
+            if (err != nil) {
+                buildErrors[assignment.FullID()] = err.Error();
+            } else if (!built) {
+                notBuilt = append(notBuilt, assignment.FullID());
+            }
+        }
+    }
+
+    if ((len(notBuilt) == 0) && (len(buildErrors) == 0)) {
+        return;
+    }
+
+    check.Details["not-built"] = notBuilt;
+    if (len(buildErrors) > 0) {
+        check.Details["errors"] = buildErrors;
+    }
+
+    check.fail(fmt.Sprintf("%d assignment image(s) are not built.", len(notBuilt) + len(buildErrors)));
+}
+
+func checkTasks(check *ReadinessCheck, courses map[string]*model.Course) {
+    // {courseID: [taskID, ...], ...}.
+    unscheduled := make(map[string][]string);
+    count := 0;
+
+    for _, course := range sortedCourses(courses) {
+        taskIDs := task.GetUnscheduledTasks(course.GetID(), course.GetTasks());
+        if (len(taskIDs) > 0) {
+            unscheduled[course.GetID()] = taskIDs;
+            count += len(taskIDs);
+        }
+    }
+
+    if (count == 0) {
+        return;
+    }
+
+    check.Details["unscheduled"] = unscheduled;
+    check.fail(fmt.Sprintf("%d scheduled task(s) do not have timers registered.", count));
+}
+
+func runCheck(name string, checkFunc func(check *ReadinessCheck)) *ReadinessCheck {
+    check := &ReadinessCheck{
+        Name: name,
+        Status: CHECK_STATUS_OK,
+        Details: make(map[string]any),
+    };
+
+    startTime := time.Now();
+    checkFunc(check);
+    check.DurationMS = time.Since(startTime).Milliseconds();
+
+    return check;
+}
+
+func (this *ReadinessReport) add(check *ReadinessCheck) {
+    this.Checks = append(this.Checks, check);
+    if (check.Status == CHECK_STATUS_FAILED) {
+        this.Ready = false;
+    }
+}
+
+func (this *ReadinessCheck) fail(message string) {
+    this.Status = CHECK_STATUS_FAILED;
+    this.Message = message;
+}
+
+func (this *ReadinessCheck) skip(message string) {
+    this.Status = CHECK_STATUS_SKIPPED;
+    this.Message = message;
+}
+
+func sortedCourses(courses map[string]*model.Course) []*model.Course {
+    courseIDs := maps.Keys(courses);
+    slices.Sort(courseIDs);
+
+    result := make([]*model.Course, 0, len(courseIDs));
+    for _, courseID := range courseIDs {
+        result = append(result, courses[courseID]);
+    }
+
+    return result;
+}
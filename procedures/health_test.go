@@ -0,0 +1,84 @@
+package procedures
+
+import (
+    "testing"
+
+    "github.com/eriq-augustine/autograder/config"
+)
+
+func TestCheckReadiness(test *testing.T) {
+    oldDockerDisable := config.DOCKER_DISABLE.Get();
+    defer config.DOCKER_DISABLE.Set(oldDockerDisable);
+
+    oldNoTasks := config.NO_TASKS.Get();
+    defer config.NO_TASKS.Set(oldNoTasks);
+
+    config.DOCKER_DISABLE.Set(true);
+
+    testCases := []struct{noTasks bool; expected map[string]string}{
+        {true, map[string]string{
+            "database": CHECK_STATUS_OK,
+            "docker": CHECK_STATUS_SKIPPED,
+            "images": CHECK_STATUS_SKIPPED,
+            "tasks": CHECK_STATUS_SKIPPED,
+        }},
+        // The test courses have no tasks.
+        {false, map[string]string{
+            "database": CHECK_STATUS_OK,
+            "docker": CHECK_STATUS_SKIPPED,
+            "images": CHECK_STATUS_SKIPPED,
+            "tasks": CHECK_STATUS_OK,
+        }},
+    };
+
+    for i, testCase := range testCases {
+        config.NO_TASKS.Set(testCase.noTasks);
+
+        report := CheckReadiness();
+        if (!report.Ready) {
+            test.Errorf("Case %d: Server is not ready: '%+v'.", i, report);
+            continue;
+        }
+
+        if (len(report.Checks) != len(testCase.expected)) {
+            test.Errorf("Case %d: Unexpected number of checks. Expected: %d, Actual: %d.", i, len(testCase.expected), len(report.Checks));
+            continue;
+        }
+
+        for _, check := range report.Checks {
+            if (check.Status != testCase.expected[check.Name]) {
+                test.Errorf("Case %d: Unexpected status for check '%s'. Expected: '%s', Actual: '%s'.",
+                        i, check.Name, testCase.expected[check.Name], check.Status);
+            }
+        }
+    }
+}
+
+func TestGetReadinessCache(test *testing.T) {
+    oldDockerDisable := config.DOCKER_DISABLE.Get();
+    defer config.DOCKER_DISABLE.Set(oldDockerDisable);
+
+    oldCacheSecs := config.WEB_READY_CACHE_SECS.Get();
+    defer config.WEB_READY_CACHE_SECS.Set(oldCacheSecs);
+
+    config.DOCKER_DISABLE.Set(true);
+
+    config.WEB_READY_CACHE_SECS.Set(60);
+
+    first := GetReadiness();
+    second := GetReadiness();
+    if (first != second) {
+        test.Fatalf("Readiness report was not reused.");
+    }
+
+    config.WEB_READY_CACHE_SECS.Set(0);
+
+    third := GetReadiness();
+    if (third == second) {
+        test.Fatalf("Readiness report was reused with caching disabled.");
+    }
+
+    if (!third.Ready) {
+        test.Fatalf("Server is not ready: '%+v'.", third);
+    }
+}
@@ -12,6 +12,7 @@ import (
     "github.com/eriq-augustine/autograder/common"
     "github.com/eriq-augustine/autograder/config"
     "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/metrics"
     "github.com/eriq-augustine/autograder/model"
     "github.com/eriq-augustine/autograder/model/tasks"
 )
@@ -56,6 +57,8 @@ func Schedule(course *model.Course, target tasks.ScheduledTask) error {
             runFunc = RunReportTask;
         case *tasks.ScoringUploadTask:
             runFunc = RunScoringUploadTask;
+        case *tasks.LMSSyncTask:
+            runFunc = RunLMSSyncTask;
         case *tasks.TestTask:
             runFunc = RunTestTask;
         default:
@@ -73,6 +76,10 @@ func Schedule(course *model.Course, target tasks.ScheduledTask) error {
         // Special ID for catchup tasks.
         timerID := fmt.Sprintf("%s::catchup", target.GetID());
 
+        timersLock.Lock();
+        delete(stoppedTasks, timerID);
+        timersLock.Unlock();
+
         err := scheduleTask(course.GetID(), target, timerID, runFunc, nil);
         if (err != nil) {
             return fmt.Errorf("Failed to schedule catchup task (%s): '%w'.", target.GetID(), err);
@@ -80,8 +87,7 @@ func Schedule(course *model.Course, target tasks.ScheduledTask) error {
     }
 
     for i, when := range target.GetTimes() {
-        // ID unique to every (task, timer).
-        timerID := fmt.Sprintf("%s::%03d", target.GetID(), i);
+        timerID := getTimerID(target, i);
 
         timersLock.Lock();
         delete(stoppedTasks, timerID);
@@ -96,6 +102,11 @@ func Schedule(course *model.Course, target tasks.ScheduledTask) error {
     return nil;
 }
 
+// Get the ID unique to every (task, timer).
+func getTimerID(target tasks.ScheduledTask, timeIndex int) string {
+    return fmt.Sprintf("%s::%03d", target.GetID(), timeIndex);
+}
+
 // Check to see if it has been too long since this task has been run.
 // Do this by getting the minimum duration for all the task's timers,
 // and seeing if it has been at least that long since the task has been run.
@@ -285,6 +296,7 @@ func runTask(courseID string, target tasks.ScheduledTask, timerID string, runFun
     }
 
     reschedule, err := runFunc(course, target);
+    metrics.ObserveTaskRun(courseID, target.GetName(), err);
     if (err != nil) {
         log.Error().Err(err).Str("course-id", courseID).Str("task", taskID).Msg("Task run failed.");
         return true;
@@ -320,3 +332,31 @@ func getTimerInfo(courseID string, timerID string) *timerInfo {
 
     return info;
 }
+
+// Get the IDs of tasks that should have timers registered (enabled with scheduled times), but do not.
+// Returns an empty list if tasks are disabled (config.NO_TASKS).
+func GetUnscheduledTasks(courseID string, courseTasks []tasks.ScheduledTask) []string {
+    unscheduled := make([]string, 0);
+    if (config.NO_TASKS.Get()) {
+        return unscheduled;
+    }
+
+    timersLock.Lock();
+    defer timersLock.Unlock();
+
+    for _, target := range courseTasks {
+        if (target.IsDisabled()) {
+            continue;
+        }
+
+        for i, _ := range target.GetTimes() {
+            _, ok := timers[courseID][getTimerID(target, i)];
+            if (!ok) {
+                unscheduled = append(unscheduled, target.GetID());
+                break;
+            }
+        }
+    }
+
+    return unscheduled;
+}
@@ -0,0 +1,84 @@
+package task
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/eriq-augustine/autograder/email"
+    "github.com/eriq-augustine/autograder/lms/lmssync"
+    "github.com/eriq-augustine/autograder/model"
+    "github.com/eriq-augustine/autograder/model/tasks"
+)
+
+func RunLMSSyncTask(course *model.Course, rawTask tasks.ScheduledTask) (bool, error) {
+    task, ok := rawTask.(*tasks.LMSSyncTask);
+    if (!ok) {
+        return false, fmt.Errorf("Task is not an LMSSyncTask: %t (%v).", rawTask, rawTask);
+    }
+
+    if (task.Disable) {
+        return true, nil;
+    }
+
+    result, err := lmssync.SyncLMS(course, task.DryRun, task.SendEmails);
+    if (err != nil) {
+        return true, fmt.Errorf("Failed to sync course '%s' with LMS: '%w'.", course.GetID(), err);
+    }
+
+    if ((result == nil) || (result.UserSync == nil)) {
+        return true, nil;
+    }
+
+    log.Debug().Str("course", course.GetID()).Str("strategy", result.UserSync.Strategy).
+            Int("changed-users", result.UserSync.Count()).Bool("dry-run", task.DryRun).Msg("LMS sync completed sucessfully.");
+
+    if ((len(task.To) == 0) || (result.UserSync.Count() == 0)) {
+        return true, nil;
+    }
+
+    return true, sendLMSSyncSummary(course, task.To, result.UserSync, task.DryRun);
+}
+
+// Email a summary of the user changes from an LMS sync.
+// Passwords for new users are never included (new users get those through their own emails).
+func sendLMSSyncSummary(course *model.Course, to []string, result *model.UserSyncResult, dryRun bool) error {
+    subject := fmt.Sprintf("Autograder LMS Sync for %s", course.GetName());
+    if (dryRun) {
+        subject += " (Dry Run)";
+    }
+
+    var body strings.Builder;
+
+    body.WriteString(fmt.Sprintf("LMS sync for course '%s' changed %d user(s).\n", course.GetID(), result.Count()));
+    if (result.Strategy != "") {
+        body.WriteString(fmt.Sprintf("Sync strategy: %s.\n", result.Strategy));
+    }
+
+    if (dryRun) {
+        body.WriteString("This was a dry run, no changes were saved.\n");
+    }
+
+    writeSyncUsers(&body, "Added", result.Add);
+    writeSyncUsers(&body, "Modified", result.Mod);
+    writeSyncUsers(&body, "Removed", result.Del);
+
+    err := email.Send(to, subject, body.String(), false);
+    if (err != nil) {
+        return fmt.Errorf("Failed to send LMS sync summary for course '%s': '%w'.", course.GetName(), err);
+    }
+
+    return nil;
+}
+
+func writeSyncUsers(body *strings.Builder, label string, users []*model.User) {
+    if (len(users) == 0) {
+        return;
+    }
+
+    body.WriteString(fmt.Sprintf("\n%s Users (%d):\n", label, len(users)));
+    for _, user := range users {
+        body.WriteString(fmt.Sprintf("    %s (%s, %s)\n", user.Email, user.Name, user.Role.String()));
+    }
+}
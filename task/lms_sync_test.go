@@ -0,0 +1,79 @@
+package task
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+
+    "github.com/eriq-augustine/autograder/common"
+    "github.com/eriq-augustine/autograder/db"
+    "github.com/eriq-augustine/autograder/email"
+    "github.com/eriq-augustine/autograder/model/tasks"
+)
+
+func TestLMSSyncBase(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    course := db.MustGetTestCourse();
+
+    to := []string{"test1@test.com", "test2@test.com"};
+
+    task := &tasks.LMSSyncTask{
+        BaseTask: &tasks.BaseTask{
+            Disable: false,
+            When: []*common.ScheduledTime{},
+        },
+        DryRun: true,
+        To: to,
+    };
+
+    email.ClearTestMessages();
+
+    // The test LMS will add LMS IDs to all users.
+    _, err := RunLMSSyncTask(course, task);
+    if (err != nil) {
+        test.Fatalf("Failed to run LMS sync task: '%v'.", err);
+    }
+
+    messages := email.GetTestMessages();
+
+    if (len(messages) != 1) {
+        test.Fatalf("Did not find the correct number of messages. Expected: 1, Found: %d.", len(messages));
+    }
+
+    if (!reflect.DeepEqual(to, messages[0].To)) {
+        test.Fatalf("Unexpected message recipients. Expected: [%s], Found: [%s].",
+            strings.Join(to, ", "), strings.Join(messages[0].To, ", "));
+    }
+
+    if (!strings.Contains(messages[0].Body, "student@test.com")) {
+        test.Fatalf("Summary does not contain a modified user: '%s'.", messages[0].Body);
+    }
+
+    // Actually sync (which should not send a summary, since there are no recipients).
+    task.DryRun = false;
+    task.To = nil;
+    email.ClearTestMessages();
+
+    _, err = RunLMSSyncTask(course, task);
+    if (err != nil) {
+        test.Fatalf("Failed to run LMS sync task: '%v'.", err);
+    }
+
+    if (len(email.GetTestMessages()) != 0) {
+        test.Fatalf("Found messages when there are no recipients: '%v'.", email.GetTestMessages());
+    }
+
+    // No users changed, so no summary is sent.
+    task.To = to;
+
+    _, err = RunLMSSyncTask(course, task);
+    if (err != nil) {
+        test.Fatalf("Failed to run LMS sync task: '%v'.", err);
+    }
+
+    if (len(email.GetTestMessages()) != 0) {
+        test.Fatalf("Found messages when no users changed: '%v'.", email.GetTestMessages());
+    }
+}
@@ -0,0 +1,213 @@
+package util
+
+// Minimal support for JSON Web Tokens (JWT) and JSON Web Key Sets (JWKS).
+// Only RS256 (RSA + SHA-256) signatures are supported,
+// since that is what OIDC providers (and LTI platforms) are required to support.
+
+import (
+    "crypto"
+    "crypto/rsa"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "math/big"
+    "slices"
+    "strings"
+)
+
+const JWT_ALG_RS256 = "RS256";
+
+type JWTHeader struct {
+    Alg string `json:"alg"`
+    Kid string `json:"kid,omitempty"`
+    Typ string `json:"typ,omitempty"`
+}
+
+// A single RSA JSON Web Key.
+type JWK struct {
+    Kty string `json:"kty"`
+    Kid string `json:"kid,omitempty"`
+    Alg string `json:"alg,omitempty"`
+    Use string `json:"use,omitempty"`
+    N string `json:"n"`
+    E string `json:"e"`
+}
+
+type JWKS struct {
+    Keys []*JWK `json:"keys"`
+}
+
+func NewRSAJWK(kid string, key *rsa.PublicKey) *JWK {
+    return &JWK{
+        Kty: "RSA",
+        Kid: kid,
+        Alg: JWT_ALG_RS256,
+        Use: "sig",
+        N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+        E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+    };
+}
+
+func (this *JWK) RSAPublicKey() (*rsa.PublicKey, error) {
+    if (this.Kty != "RSA") {
+        return nil, fmt.Errorf("Unsupported key type: '%s'.", this.Kty);
+    }
+
+    n, err := base64.RawURLEncoding.DecodeString(this.N);
+    if (err != nil) {
+        return nil, fmt.Errorf("Bad key modulus: '%w'.", err);
+    }
+
+    e, err := base64.RawURLEncoding.DecodeString(this.E);
+    if (err != nil) {
+        return nil, fmt.Errorf("Bad key exponent: '%w'.", err);
+    }
+
+    exponent := new(big.Int).SetBytes(e);
+    if (!exponent.IsInt64() || (exponent.Int64() <= 1) || (exponent.Int64() > (1 << 31))) {
+        return nil, fmt.Errorf("Bad key exponent value.");
+    }
+
+    return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil;
+}
+
+// Get a key by ID.
+// If the ID is empty and there is only one key, then that key will be returned.
+// Returns nil if there is no matching key.
+func (this *JWKS) GetKey(kid string) *JWK {
+    if ((kid == "") && (len(this.Keys) == 1)) {
+        return this.Keys[0];
+    }
+
+    for _, key := range this.Keys {
+        if (key.Kid == kid) {
+            return key;
+        }
+    }
+
+    return nil;
+}
+
+// Sign claims with RS256.
+func SignJWT(claims any, key *rsa.PrivateKey, kid string) (string, error) {
+    header := JWTHeader{
+        Alg: JWT_ALG_RS256,
+        Kid: kid,
+        Typ: "JWT",
+    };
+
+    headerJSON, err := json.Marshal(header);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to serialize JWT header: '%w'.", err);
+    }
+
+    claimsJSON, err := json.Marshal(claims);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to serialize JWT claims: '%w'.", err);
+    }
+
+    signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON);
+
+    hash := sha256.Sum256([]byte(signingInput));
+    signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, hash[:]);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to sign JWT: '%w'.", err);
+    }
+
+    return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil;
+}
+
+// Parse a JWT without verifying it.
+// Returns: (header, raw claims, error).
+func ParseJWT(token string) (*JWTHeader, []byte, error) {
+    parts := strings.Split(token, ".");
+    if (len(parts) != 3) {
+        return nil, nil, fmt.Errorf("Malformed JWT, expected 3 parts, found %d.", len(parts));
+    }
+
+    headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0]);
+    if (err != nil) {
+        return nil, nil, fmt.Errorf("Malformed JWT header: '%w'.", err);
+    }
+
+    var header JWTHeader;
+    err = json.Unmarshal(headerJSON, &header);
+    if (err != nil) {
+        return nil, nil, fmt.Errorf("Malformed JWT header: '%w'.", err);
+    }
+
+    claims, err := base64.RawURLEncoding.DecodeString(parts[1]);
+    if (err != nil) {
+        return nil, nil, fmt.Errorf("Malformed JWT claims: '%w'.", err);
+    }
+
+    return &header, claims, nil;
+}
+
+// Verify a JWT's signature against a key set and unmarshal its claims into the given pointer.
+// Only the signature is checked here, callers are responsible for checking claims (e.g. "exp", "iss", "aud").
+func VerifyJWT(token string, keys *JWKS, claims any) error {
+    header, rawClaims, err := ParseJWT(token);
+    if (err != nil) {
+        return err;
+    }
+
+    if (header.Alg != JWT_ALG_RS256) {
+        return fmt.Errorf("Unsupported JWT algorithm: '%s'.", header.Alg);
+    }
+
+    jwk := keys.GetKey(header.Kid);
+    if (jwk == nil) {
+        return fmt.Errorf("Unknown JWT key: '%s'.", header.Kid);
+    }
+
+    key, err := jwk.RSAPublicKey();
+    if (err != nil) {
+        return fmt.Errorf("Bad JWT key '%s': '%w'.", header.Kid, err);
+    }
+
+    lastDot := strings.LastIndex(token, ".");
+    signature, err := base64.RawURLEncoding.DecodeString(token[(lastDot + 1):]);
+    if (err != nil) {
+        return fmt.Errorf("Malformed JWT signature: '%w'.", err);
+    }
+
+    hash := sha256.Sum256([]byte(token[:lastDot]));
+    err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature);
+    if (err != nil) {
+        return fmt.Errorf("Bad JWT signature: '%w'.", err);
+    }
+
+    err = json.Unmarshal(rawClaims, claims);
+    if (err != nil) {
+        return fmt.Errorf("Failed to unmarshal JWT claims: '%w'.", err);
+    }
+
+    return nil;
+}
+
+// The "aud" claim can either be a single string or a list of strings.
+type JWTAudience []string;
+
+func (this *JWTAudience) UnmarshalJSON(data []byte) error {
+    var single string;
+    err := json.Unmarshal(data, &single);
+    if (err == nil) {
+        *this = JWTAudience{single};
+        return nil;
+    }
+
+    var list []string;
+    err = json.Unmarshal(data, &list);
+    if (err != nil) {
+        return fmt.Errorf("JWT audience is not a string or list of strings: '%w'.", err);
+    }
+
+    *this = JWTAudience(list);
+    return nil;
+}
+
+func (this JWTAudience) Contains(audience string) bool {
+    return slices.Contains(this, audience);
+}
@@ -0,0 +1,107 @@
+package util
+
+import (
+    "crypto/rand"
+    "crypto/rsa"
+    "testing"
+)
+
+type testJWTClaims struct {
+    Subject string `json:"sub"`
+    Audience JWTAudience `json:"aud"`
+}
+
+func TestJWTRoundTrip(test *testing.T) {
+    key, err := rsa.GenerateKey(rand.Reader, 2048);
+    if (err != nil) {
+        test.Fatalf("Failed to generate key: '%v'.", err);
+    }
+
+    otherKey, err := rsa.GenerateKey(rand.Reader, 2048);
+    if (err != nil) {
+        test.Fatalf("Failed to generate other key: '%v'.", err);
+    }
+
+    keys := &JWKS{Keys: []*JWK{NewRSAJWK("a", &key.PublicKey), NewRSAJWK("b", &otherKey.PublicKey)}};
+
+    token, err := SignJWT(map[string]any{"sub": "alice", "aud": "client"}, key, "a");
+    if (err != nil) {
+        test.Fatalf("Failed to sign token: '%v'.", err);
+    }
+
+    var claims testJWTClaims;
+    err = VerifyJWT(token, keys, &claims);
+    if (err != nil) {
+        test.Fatalf("Failed to verify token: '%v'.", err);
+    }
+
+    if ((claims.Subject != "alice") || !claims.Audience.Contains("client")) {
+        test.Fatalf("Unexpected claims: '%+v'.", claims);
+    }
+
+    // Signed by the wrong key.
+    badToken, err := SignJWT(map[string]any{"sub": "alice"}, otherKey, "a");
+    if (err != nil) {
+        test.Fatalf("Failed to sign bad token: '%v'.", err);
+    }
+
+    // Unknown key.
+    unknownToken, err := SignJWT(map[string]any{"sub": "alice"}, key, "c");
+    if (err != nil) {
+        test.Fatalf("Failed to sign unknown token: '%v'.", err);
+    }
+
+    badTokens := []string{
+        badToken,
+        unknownToken,
+        token + "A",
+        token[:len(token) - 2],
+        "a.b",
+        "",
+    };
+
+    for i, badToken := range badTokens {
+        err = VerifyJWT(badToken, keys, &claims);
+        if (err == nil) {
+            test.Errorf("Case %d: Bad token was verified: '%s'.", i, badToken);
+        }
+    }
+}
+
+func TestJWTAudience(test *testing.T) {
+    testCases := []struct{input string; expected []string; hasError bool}{
+        {`"a"`, []string{"a"}, false},
+        {`["a", "b"]`, []string{"a", "b"}, false},
+        {`[]`, []string{}, false},
+        {`1`, nil, true},
+    };
+
+    for i, testCase := range testCases {
+        var audience JWTAudience;
+        err := JSONFromString(testCase.input, &audience);
+        if (testCase.hasError) {
+            if (err == nil) {
+                test.Errorf("Case %d: Did not get an error when expected.", i);
+            }
+
+            continue;
+        }
+
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to unmarshal: '%v'.", i, err);
+            continue;
+        }
+
+        if (len(audience) != len(testCase.expected)) {
+            test.Errorf("Case %d: Unexpected audience. Expected: '%v', Actual: '%v'.", i, testCase.expected, audience);
+            continue;
+        }
+
+        for j, value := range testCase.expected {
+            if (audience[j] != value) {
+                test.Errorf("Case %d: Unexpected audience. Expected: '%v', Actual: '%v'.", i, testCase.expected, audience);
+                break;
+            }
+        }
+    }
+}